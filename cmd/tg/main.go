@@ -0,0 +1,191 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/adarga-ai/go-tigergraph/tigergraphtest"
+)
+
+const usage = `usage: tg bench load -graph GRAPH -job LOADING_JOB (-data FILE | -vertex-type TYPE) [flags]
+
+bench load runs the loading job with batches of JSON lines for the given duration, through
+the same BatchLoader used in production, and reports the throughput and batch latencies.
+The connection defaults to TG_URL, TG_FILE_URL, TG_USERNAME and TG_PASSWORD.`
+
+// tg is a command line tool for working with TigerGraph through this client. So far it only
+// benchmarks ingestion, with "tg bench load".
+func main() {
+	if len(os.Args) < 3 || os.Args[1] != "bench" || os.Args[2] != "load" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2) //nolint:gomnd
+	}
+
+	if err := benchLoad(os.Args[3:]); err != nil {
+		fmt.Fprintln(os.Stderr, "bench load failed:", err)
+		os.Exit(1)
+	}
+}
+
+func benchLoad(args []string) error {
+	flags := flag.NewFlagSet("tg bench load", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+
+	tgURL := flags.String("url", os.Getenv("TG_URL"), "TigerGraph URL")
+	tgFileURL := flags.String("file-url", os.Getenv("TG_FILE_URL"), "TigerGraph file URL")
+	tgUsername := flags.String("username", os.Getenv("TG_USERNAME"), "TigerGraph username")
+	tgPassword := flags.String("password", os.Getenv("TG_PASSWORD"), "TigerGraph password")
+
+	graph := flags.String("graph", "", "graph to load into")
+	job := flags.String("job", "", "loading job to run")
+	concurrency := flags.Int("concurrency", 1, "number of batches loaded at a time")
+	batchSize := flags.Int("batch-size", tigergraph.DefaultBatchSize, "lines per batch, or the starting size with -tune")
+	duration := flags.Duration("duration", 30*time.Second, "how long to load for") //nolint:gomnd
+	tune := flags.Bool("tune", false, "auto-tune the batch size to keep latency between -tune-low and -tune-high")
+	tuneLow := flags.Duration("tune-low", time.Second, "batch latency below which the batch size grows")
+	tuneHigh := flags.Duration("tune-high", 5*time.Second, "batch latency above which the batch size shrinks") //nolint:gomnd
+
+	data := flags.String("data", "", "JSONL file of lines to load, cycled through")
+	vertexType := flags.String("vertex-type", "", "vertex type to generate lines of from the graph's schema, instead of -data")
+	lineCount := flags.Int("lines", 10000, "number of distinct lines to generate with -vertex-type") //nolint:gomnd
+	seed := flags.Int64("seed", 1, "seed for generating lines with -vertex-type")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *graph == "" || *job == "" || (*data == "") == (*vertexType == "") {
+		flags.Usage()
+		os.Exit(2) //nolint:gomnd
+	}
+
+	client := tigergraph.NewClient(
+		*tgURL,
+		tigergraph.WithFileURL(*tgFileURL),
+		tigergraph.WithCredentials(*tgUsername, *tgPassword),
+	)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	var lines []any
+	var err error
+	if *data != "" {
+		lines, err = readLines(*data)
+	} else {
+		lines, err = generateLines(ctx, client, *graph, *vertexType, *lineCount, *seed)
+	}
+	if err != nil {
+		return err
+	}
+
+	benchmark := tigergraphtest.LoadBenchmark{
+		Graph:       *graph,
+		LoadingJob:  *job,
+		Lines:       lines,
+		Concurrency: *concurrency,
+		BatchSize:   *batchSize,
+		Duration:    *duration,
+	}
+	if *tune {
+		benchmark.Tuning = &tigergraph.BatchTuning{TargetLatencyLow: *tuneLow, TargetLatencyHigh: *tuneHigh}
+	}
+
+	fmt.Printf("loading %s with %d distinct lines, concurrency %d, batch size %d, for %s\n",
+		*job, len(lines), *concurrency, *batchSize, *duration)
+
+	result, err := tigergraphtest.BenchmarkLoad(ctx, client, benchmark)
+	if err != nil {
+		return err
+	}
+
+	printResult(result)
+
+	if result.Failures > 0 {
+		return fmt.Errorf("%d of %d batches failed, first with: %w",
+			result.Failures, result.Failures+result.Batches, result.FirstError)
+	}
+
+	return nil
+}
+
+// readLines reads the JSON lines of the file
+func readLines(path string) ([]any, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []any
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for decoder.More() {
+		var line map[string]any
+		if err := decoder.Decode(&line); err != nil {
+			return nil, fmt.Errorf("failed to read line %d of %s: %w", len(lines)+1, path, err)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// generateLines generates lines of vertices of the vertex type, matching the graph's schema
+func generateLines(
+	ctx context.Context,
+	client *tigergraph.TigerGraphClient,
+	graph string,
+	vertexType string,
+	count int,
+	seed int64,
+) ([]any, error) {
+	metadata, err := client.GetGraphMetadata(ctx, graph)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.Results == nil {
+		return nil, fmt.Errorf("failed to get the schema of %s: %s", graph, metadata.Message)
+	}
+
+	dataset, err := tigergraphtest.Generate(metadata.Results, map[string]int{vertexType: count}, tigergraphtest.WithSeed(seed))
+	if err != nil {
+		return nil, err
+	}
+
+	return dataset.VertexLines(vertexType), nil
+}
+
+func printResult(result *tigergraphtest.LoadBenchmarkResult) {
+	fmt.Printf("elapsed      %s\n", result.Elapsed.Round(time.Millisecond))
+	fmt.Printf("lines        %d\n", result.Lines)
+	fmt.Printf("batches      %d\n", result.Batches)
+	fmt.Printf("failures     %d\n", result.Failures)
+	fmt.Printf("lines/sec    %.1f\n", result.LinesPerSecond)
+	fmt.Printf("latency p50  %s\n", result.P50.Round(time.Millisecond))
+	fmt.Printf("latency p90  %s\n", result.P90.Round(time.Millisecond))
+	fmt.Printf("latency p99  %s\n", result.P99.Round(time.Millisecond))
+	fmt.Printf("latency max  %s\n", result.Max.Round(time.Millisecond))
+}
@@ -40,7 +40,11 @@ func main() {
 	fmt.Println(tgURL, tgFileURL, tgUsername, tgPassword)
 
 	ctx := context.Background()
-	client := tigergraph.NewClient(tgURL, tgFileURL, tgUsername, tgPassword)
+	client := tigergraph.NewClient(
+		tgURL,
+		tigergraph.WithFileURL(tgFileURL),
+		tigergraph.WithCredentials(tgUsername, tgPassword),
+	)
 
 	// Run migration
 	err := client.Migrate(ctx, "TestGraph", "000", "", "./migrations", false)
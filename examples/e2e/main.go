@@ -0,0 +1,55 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+)
+
+// e2e runs SmokeTest against the TigerGraph instance given in the environment, e.g. as a
+// verification step after deploying or upgrading TigerGraph. It exits non-zero on failure.
+func main() {
+	tgURL := os.Getenv("TG_URL")
+	tgFileURL := os.Getenv("TG_FILE_URL")
+	tgUsername := os.Getenv("TG_USERNAME")
+	tgPassword := os.Getenv("TG_PASSWORD")
+
+	client := tigergraph.NewClient(
+		tgURL,
+		tigergraph.WithFileURL(tgFileURL),
+		tigergraph.WithCredentials(tgUsername, tgPassword),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	result, err := client.SmokeTest(ctx)
+
+	fmt.Println("scratch graph:", result.Graph)
+	for _, step := range result.Steps {
+		fmt.Printf("%-12s %s\n", step.Name, step.Duration)
+	}
+
+	if err != nil {
+		fmt.Println("smoke test failed: ", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("smoke test passed")
+}
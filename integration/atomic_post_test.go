@@ -0,0 +1,132 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicPosts(t *testing.T) { //nolint:funlen
+	upsertURL := tigergraph.UpsertURL + "/" + graphName
+	softDeleteURL := upsertURL + "?vertex_must_exist=true"
+
+	mockVersion := func(srv *MockTigerGraphServer, version string) {
+		srv.MockResponse(tigergraph.VersionURL, tigergraph.TigerGraphResponse[any]{
+			Message: "TigerGraph RESTPP: \n--- Version --- \nTigerGraph version: " + version + "\nproduct release_" + version,
+		})
+	}
+
+	recordAtomicHeader := func(headers *[]string) handlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			*headers = append(*headers, r.Header.Get(tigergraph.AtomicLevelHeader))
+			_, err := w.Write([]byte(`{"results": [{"accepted_vertices": 1, "accepted_edges": 1}]}`))
+			assert.Nil(t, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "sends the atomic header when requested",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockVersion(srv, "3.9.3")
+				headers := make([]string, 0)
+				srv.Mock(upsertURL, recordAtomicHeader(&headers))
+				srv.Mock(softDeleteURL, recordAtomicHeader(&headers))
+
+				ctx := context.Background()
+				_, err := client.Upsert(ctx, graphName, map[string]any{}, tigergraph.Atomic())
+				assert.Nil(t, err)
+				_, err = client.Upsert(ctx, graphName, map[string]any{})
+				assert.Nil(t, err)
+				_, err = client.SoftDeleteVertices(ctx, graphName, "Person", []string{"alice"}, tigergraph.Atomic())
+				assert.Nil(t, err)
+
+				assert.Equal(t, []string{tigergraph.AtomicLevelAtomic, "", tigergraph.AtomicLevelAtomic}, headers)
+
+				// The capability check is cached
+				assert.Len(t, srv.Calls[tigergraph.VersionURL], 1)
+			},
+		},
+		{
+			name: "checks the server version outside the graph's request slot",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.GraphLimiter = tigergraph.NewGraphLimiter(1, nil)
+				mockVersion(srv, "3.9.3")
+				headers := make([]string, 0)
+				srv.Mock(upsertURL, recordAtomicHeader(&headers))
+
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				defer cancel()
+
+				_, err := client.Upsert(ctx, graphName, map[string]any{}, tigergraph.Atomic())
+				assert.Nil(t, err)
+				assert.Equal(t, []string{tigergraph.AtomicLevelAtomic}, headers)
+			},
+		},
+		{
+			name: "fails without writing when the server does not support atomic writes",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockVersion(srv, "2.6.2")
+
+				supported, err := client.SupportsAtomicPosts(context.Background(), graphName)
+				assert.Nil(t, err)
+				assert.False(t, supported)
+
+				_, err = client.Upsert(context.Background(), graphName, map[string]any{}, tigergraph.Atomic())
+				assert.ErrorIs(t, err, tigergraph.ErrAtomicPostUnsupported)
+				assert.Len(t, srv.Calls[upsertURL], 0)
+			},
+		},
+		{
+			name: "fails when the server version is unknown",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(tigergraph.VersionURL, tigergraph.TigerGraphResponse[any]{Message: "unexpected"})
+
+				_, err := client.Upsert(context.Background(), graphName, map[string]any{}, tigergraph.Atomic())
+				assert.ErrorIs(t, err, tigergraph.ErrUnknownServerVersion)
+				assert.Len(t, srv.Calls[upsertURL], 0)
+			},
+		},
+		{
+			name: "reports the server version",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockVersion(srv, "3.10.1")
+
+				version, err := client.ServerVersion(context.Background(), graphName)
+				assert.Nil(t, err)
+				assert.Equal(t, "3.10.1", version)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
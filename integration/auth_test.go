@@ -15,6 +15,10 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -53,7 +57,8 @@ func TestClientAuth(t *testing.T) { //nolint:funlen
 			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
 				ctx := context.Background()
 				err := client.Auth(ctx, graphName)
-				assert.Equal(t, tigergraph.ErrNonOK, err)
+				assert.ErrorIs(t, err, tigergraph.ErrUnauthorized)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
 			},
 		},
 		{
@@ -63,7 +68,8 @@ func TestClientAuth(t *testing.T) { //nolint:funlen
 			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
 				ctx := context.Background()
 				err := client.Auth(ctx, graphName)
-				assert.Equal(t, tigergraph.ErrNonOK, err)
+				assert.ErrorIs(t, err, tigergraph.ErrUnauthorized)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
 			},
 		},
 		{
@@ -146,14 +152,165 @@ func TestClientAuth(t *testing.T) { //nolint:funlen
 			srv := NewMockServer(expectedUsername, expectedPassword)
 			defer srv.Close()
 
-			client := tigergraph.NewClient(
-				srv.HTTPServer.URL,
-				srv.HTTPServer.URL,
-				test.username,
-				test.password,
-			)
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(test.username, test.password))
 
 			test.action(t, client, srv)
 		})
 	}
 }
+
+type recordingTokenMetrics struct {
+	requested []string
+	refreshed []string
+	failed    []string
+	expired   []string
+}
+
+func (m *recordingTokenMetrics) TokenRequested(graph string) {
+	m.requested = append(m.requested, graph)
+}
+
+func (m *recordingTokenMetrics) TokenRefreshed(graph string, _ time.Duration, _ time.Time) {
+	m.refreshed = append(m.refreshed, graph)
+}
+
+func (m *recordingTokenMetrics) AuthFailed(graph string, _ error) {
+	m.failed = append(m.failed, graph)
+}
+
+func (m *recordingTokenMetrics) TokenExpired(graph string, _ time.Time) {
+	m.expired = append(m.expired, graph)
+}
+
+func TestClientAuthTokenMetrics(t *testing.T) {
+	t.Run("records successful and expired token requests", func(t *testing.T) {
+		srv := NewMockServer(expectedUsername, expectedPassword)
+		defer srv.Close()
+
+		srv.Mock(tigergraph.RequestTokenURL, makeDefaultRequestTokenHandler(
+			expectedUsername,
+			expectedPassword,
+			time.Now().Add(-5*time.Minute).Unix(),
+		))
+
+		metrics := &recordingTokenMetrics{}
+		client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+		client.TokenMetrics = metrics
+
+		ctx := context.Background()
+		assert.Nil(t, client.Auth(ctx, graphName))
+		assert.Nil(t, client.Auth(ctx, graphName))
+
+		assert.Equal(t, []string{graphName, graphName}, metrics.requested)
+		assert.Equal(t, []string{graphName, graphName}, metrics.refreshed)
+		assert.Equal(t, []string{graphName}, metrics.expired)
+		assert.Empty(t, metrics.failed)
+	})
+
+	t.Run("records auth failures", func(t *testing.T) {
+		srv := NewMockServer(expectedUsername, expectedPassword)
+		defer srv.Close()
+
+		metrics := &recordingTokenMetrics{}
+		client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, "wrong"))
+		client.TokenMetrics = metrics
+
+		err := client.Auth(context.Background(), graphName)
+		assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+
+		assert.Equal(t, []string{graphName}, metrics.requested)
+		assert.Equal(t, []string{graphName}, metrics.failed)
+		assert.Empty(t, metrics.refreshed)
+	})
+}
+
+func TestClientAuthConcurrent(t *testing.T) {
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	// Slow token requests give every goroutine time to ask for a token while one is in flight
+	tokenHandler := makeDefaultRequestTokenHandler(expectedUsername, expectedPassword, time.Now().Add(time.Hour).Unix())
+	srv.Mock(tigergraph.RequestTokenURL, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		tokenHandler(w, r)
+	})
+
+	graphs := []string{"GraphA", "GraphB", "GraphC"}
+	for _, graph := range graphs {
+		srv.MockResponse(fmt.Sprintf("/query/%s/people", graph), map[string]any{"results": []any{}})
+	}
+
+	client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10*len(graphs))
+	for i := 0; i < 10; i++ {
+		for _, graph := range graphs {
+			wg.Add(1)
+			go func(graph string) {
+				defer wg.Done()
+				var result map[string]any
+				errs <- client.Get(context.Background(), fmt.Sprintf("/query/%s/people", graph), graph, &result)
+			}(graph)
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, len(graphs), srv.CallCount(tigergraph.RequestTokenURL))
+	assert.Equal(t, graphs, client.Tokens.Graphs())
+}
+
+func TestClientAuthSecret(t *testing.T) {
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	basicAuthHandler := makeDefaultRequestTokenHandler(expectedUsername, expectedPassword, time.Now().Add(time.Hour).Unix())
+	srv.Mock(tigergraph.RequestTokenURL, func(w http.ResponseWriter, r *http.Request) {
+		var request tigergraph.RequestTokenRequest
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+
+		if request.Secret == "" {
+			basicAuthHandler(w, r)
+			return
+		}
+
+		_, _, hasBasicAuth := r.BasicAuth()
+		assert.False(t, hasBasicAuth)
+		assert.Empty(t, request.Graph)
+
+		if request.Secret != "graph-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		assert.Nil(t, json.NewEncoder(w).Encode(tigergraph.RequestTokenResponse{
+			ExpirationSecondsSinceEpoch: time.Now().Add(time.Hour).Unix(),
+			Results:                     tigergraph.RequestTokenResponseResults{Token: "secrettoken"},
+		}))
+	})
+
+	client := tigergraph.NewClient(
+		srv.HTTPServer.URL,
+		tigergraph.WithCredentials(expectedUsername, expectedPassword),
+		tigergraph.WithSecret(graphName, "graph-secret"),
+		tigergraph.WithSecret("WrongGraph", "wrong-secret"),
+	)
+
+	ctx := context.Background()
+	assert.Nil(t, client.Auth(ctx, graphName))
+	token, ok := client.Tokens.Get(graphName)
+	assert.True(t, ok)
+	assert.Equal(t, "secrettoken", token.Value)
+
+	// Graphs without a secret still use basic auth
+	assert.Nil(t, client.Auth(ctx, "OtherGraph"))
+	token, ok = client.Tokens.Get("OtherGraph")
+	assert.True(t, ok)
+	assert.Equal(t, "sometoken", token.Value)
+
+	assert.ErrorIs(t, client.Auth(ctx, "WrongGraph"), tigergraph.ErrNonOK)
+}
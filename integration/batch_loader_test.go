@@ -0,0 +1,260 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchLoader(t *testing.T) { //nolint:funlen
+	loadingJobURL := fmt.Sprintf("/ddl/%s?tag=%s&filename=f", graphName, "load_people")
+	metadataUpsertURL := tigergraph.UpsertURL + "/" + tigergraph.MetadataGraphName
+	batchSizesURL := fmt.Sprintf(tigergraph.VerticesURLTemplate, tigergraph.MetadataGraphName, tigergraph.BatchSizeVertexType)
+	metadataSchemaURL := tigergraph.GetGraphMetadataQueryURL + "?graph=ClientMetadata"
+
+	makeLines := func(n int) []any {
+		lines := make([]any, n)
+		for i := range lines {
+			lines[i] = map[string]int{"id": i}
+		}
+
+		return lines
+	}
+
+	// mockLoadingJob accepts every line it is sent, after the given delay
+	mockLoadingJob := func(t *testing.T, srv *MockTigerGraphServer, delay time.Duration) {
+		t.Helper()
+		srv.Mock(loadingJobURL, func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.Nil(t, err)
+			time.Sleep(delay)
+
+			assert.Nil(t, json.NewEncoder(w).Encode(tigergraph.LoadingJobResponse{
+				Results: []tigergraph.LoadingJobResponseResult{{
+					Statistics: tigergraph.LoadingJobStatistics{ValidLine: len(bytes.Split(body, []byte("\n")))},
+				}},
+			}))
+		})
+	}
+
+	// batchSizes returns the number of lines sent in each request to the loading job
+	batchSizes := func(t *testing.T, srv *MockTigerGraphServer) []int {
+		t.Helper()
+		sizes := make([]int, 0)
+		for _, call := range srv.Calls[loadingJobURL] {
+			body, err := io.ReadAll(call)
+			assert.Nil(t, err)
+			sizes = append(sizes, len(bytes.Split(body, []byte("\n"))))
+		}
+
+		return sizes
+	}
+
+	mockMetadata := func(t *testing.T, srv *MockTigerGraphServer, vertexTypes []string, records ...tigergraph.BatchSizeRecord) {
+		t.Helper()
+		metadata := &tigergraph.GraphMetadataResponseResult{GraphName: tigergraph.MetadataGraphName}
+		for _, vertexType := range vertexTypes {
+			metadata.VertexTypes = append(metadata.VertexTypes, tigergraph.GraphMetadataVertexType{Name: vertexType})
+		}
+		srv.MockResponse(metadataSchemaURL, tigergraph.GraphMetadataResponse{Results: metadata})
+
+		response := tigergraph.TigerGraphResponse[tigergraph.ResponseVertex[tigergraph.BatchSizeRecord]]{
+			Results: []tigergraph.ResponseVertex[tigergraph.BatchSizeRecord]{},
+		}
+		for _, record := range records {
+			response.Results = append(response.Results, tigergraph.ResponseVertex[tigergraph.BatchSizeRecord]{
+				VID:        record.ID,
+				Attributes: record,
+			})
+		}
+		srv.MockResponse(batchSizesURL, response)
+
+		srv.MockResponse(metadataUpsertURL, tigergraph.UpsertResponse{
+			Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+		})
+		srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+			_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+			assert.Nil(t, err)
+		})
+	}
+
+	// savedBatchSizes returns the batch size of each upsert to the metadata graph
+	savedBatchSizes := func(t *testing.T, srv *MockTigerGraphServer) []string {
+		t.Helper()
+		result := make([]string, 0)
+		for _, call := range srv.Calls[metadataUpsertURL] {
+			body, err := io.ReadAll(call)
+			assert.Nil(t, err)
+
+			var payload struct {
+				Vertices map[string]map[string]map[string]tigergraph.ValueWrapper `json:"vertices"`
+			}
+			assert.Nil(t, json.Unmarshal(body, &payload))
+
+			for id, attributes := range payload.Vertices[tigergraph.BatchSizeVertexType] {
+				result = append(result, fmt.Sprintf("%s %v", id, attributes["batch_size"].Value))
+			}
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "sends fixed size batches without tuning",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockLoadingJob(t, srv, 0)
+
+				loader := client.NewBatchLoader(graphName, "load_people", tigergraph.WithBatchSize(10))
+				assert.Nil(t, loader.Load(context.Background(), makeLines(25)))
+
+				assert.Equal(t, []int{10, 10, 5}, batchSizes(t, srv))
+				assert.Equal(t, 10, loader.BatchSize())
+				assert.Equal(t, 0, srv.CallCount(metadataSchemaURL))
+			},
+		},
+		{
+			name: "grows batches which are faster than the target",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockLoadingJob(t, srv, 0)
+
+				loader := client.NewBatchLoader(graphName, "load_people",
+					tigergraph.WithBatchSize(2),
+					tigergraph.WithBatchTuning(tigergraph.BatchTuning{
+						TargetLatencyLow:  time.Hour,
+						TargetLatencyHigh: 2 * time.Hour,
+						MinSize:           1,
+						MaxSize:           8,
+					}),
+				)
+				assert.Nil(t, loader.Load(context.Background(), makeLines(30)))
+
+				assert.Equal(t, []int{2, 4, 8, 8, 8}, batchSizes(t, srv))
+				assert.Equal(t, 8, loader.BatchSize())
+			},
+		},
+		{
+			name: "shrinks batches which are slower than the target",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockLoadingJob(t, srv, 5*time.Millisecond)
+
+				loader := client.NewBatchLoader(graphName, "load_people",
+					tigergraph.WithBatchSize(8),
+					tigergraph.WithBatchTuning(tigergraph.BatchTuning{
+						TargetLatencyHigh: time.Millisecond,
+						MinSize:           2,
+					}),
+				)
+				assert.Nil(t, loader.Load(context.Background(), makeLines(16)))
+
+				assert.Equal(t, []int{8, 4, 2, 2}, batchSizes(t, srv))
+				assert.Equal(t, 2, loader.BatchSize())
+			},
+		},
+		{
+			name: "starts from the persisted batch size and saves the new one",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockLoadingJob(t, srv, 0)
+				mockMetadata(t, srv, []string{tigergraph.BatchSizeVertexType},
+					tigergraph.BatchSizeRecord{ID: "other_load_people", Graph: "other", LoadingJob: "load_people", BatchSize: 100},
+					tigergraph.BatchSizeRecord{ID: graphName + "_load_people", Graph: graphName, LoadingJob: "load_people", BatchSize: 4},
+				)
+
+				loader := client.NewBatchLoader(graphName, "load_people",
+					tigergraph.WithBatchTuning(tigergraph.BatchTuning{
+						TargetLatencyLow:  time.Hour,
+						TargetLatencyHigh: 2 * time.Hour,
+						MinSize:           1,
+						Persist:           true,
+					}),
+				)
+				assert.Nil(t, loader.Load(context.Background(), makeLines(12)))
+
+				assert.Equal(t, []int{4, 8}, batchSizes(t, srv))
+				assert.Equal(t, []string{graphName + "_load_people 16"}, savedBatchSizes(t, srv))
+				assert.Equal(t, 0, srv.CallCount(tigergraph.FileURL))
+			},
+		},
+		{
+			name: "adds the vertex type with the metadata migrations before the first batch",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.OutdatedMetadataSchema = true
+				mockLoadingJob(t, srv, 0)
+				mockMetadata(t, srv, nil)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, tigergraph.CurrentMigrationVersionResponse{
+					Results: []tigergraph.CurrentMigrationVersionResponseResult{{LatestMigration: []tigergraph.MigrationVertex{}}},
+				})
+
+				loader := client.NewBatchLoader(graphName, "load_people",
+					tigergraph.WithBatchSize(5),
+					tigergraph.WithBatchTuning(tigergraph.BatchTuning{
+						TargetLatencyLow:  time.Hour,
+						TargetLatencyHigh: 2 * time.Hour,
+						Persist:           true,
+					}),
+				)
+				assert.Nil(t, loader.Load(context.Background(), makeLines(5)))
+
+				assert.Equal(t, 1, srv.CallCount(batchSizesURL))
+				// The built-in metadata migrations, the third of which adds the vertex type
+				if assert.Len(t, srv.Calls[tigergraph.FileURL], tigergraph.BuiltinMetadataSchemaVersion()) {
+					body, err := io.ReadAll(srv.Calls[tigergraph.FileURL][2])
+					assert.Nil(t, err)
+					assert.Equal(t, url.QueryEscape(tigergraph.BatchSizeSchemaGSQL), string(body))
+				}
+				assert.Equal(t, []string{graphName + "_load_people 10"}, savedBatchSizes(t, srv))
+			},
+		},
+		{
+			name: "stops at the first failed batch",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(loadingJobURL, tigergraph.LoadingJobResponse{
+					Results: []tigergraph.LoadingJobResponseResult{{Statistics: tigergraph.LoadingJobStatistics{ValidLine: 1}}},
+				})
+
+				loader := client.NewBatchLoader(graphName, "load_people", tigergraph.WithBatchSize(3))
+				err := loader.Load(context.Background(), makeLines(9))
+
+				assert.ErrorIs(t, err, tigergraph.ErrLoadingJobPartialFailure)
+				assert.ErrorContains(t, err, "lines 0 to 2")
+				assert.Equal(t, 1, srv.CallCount(loadingJobURL))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/adarga-ai/go-tigergraph/tigergraphtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBenchmarkLoad(t *testing.T) { //nolint:funlen
+	loadingJobURL := fmt.Sprintf("/ddl/%s?tag=%s&filename=f", graphName, "load_people")
+	lines := []any{
+		map[string]int{"id": 0},
+		map[string]int{"id": 1},
+		map[string]int{"id": 2},
+		map[string]int{"id": 3},
+		map[string]int{"id": 4},
+	}
+
+	tests := []struct {
+		name          string
+		lines         []any
+		status        int
+		concurrency   int
+		expectedErr   error
+		expectBatches bool
+		expectFailure error
+	}{
+		{name: "loads batches for the duration", lines: lines, status: http.StatusOK, concurrency: 2, expectBatches: true},
+		{name: "counts failed batches", lines: lines, status: http.StatusInternalServerError, concurrency: 1, expectFailure: tigergraph.ErrNonOK},
+		{name: "errors without lines", status: http.StatusOK, concurrency: 1, expectedErr: tigergraphtest.ErrNoLines},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			srv.Mock(loadingJobURL, func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.Nil(t, err)
+				time.Sleep(5 * time.Millisecond)
+
+				w.WriteHeader(test.status)
+				assert.Nil(t, json.NewEncoder(w).Encode(tigergraph.LoadingJobResponse{
+					Results: []tigergraph.LoadingJobResponseResult{{
+						Statistics: tigergraph.LoadingJobStatistics{ValidLine: len(bytes.Split(body, []byte("\n")))},
+					}},
+				}))
+			})
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			result, err := tigergraphtest.BenchmarkLoad(context.Background(), client, tigergraphtest.LoadBenchmark{
+				Graph:       graphName,
+				LoadingJob:  "load_people",
+				Lines:       test.lines,
+				Concurrency: test.concurrency,
+				BatchSize:   3,
+				Duration:    100 * time.Millisecond,
+			})
+
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.Nil(t, err)
+
+			if test.expectBatches {
+				assert.Greater(t, result.Batches, 1)
+				assert.Equal(t, 3*result.Batches, result.Lines)
+				assert.Equal(t, 0, result.Failures)
+				assert.Greater(t, result.LinesPerSecond, 0.0)
+				assert.GreaterOrEqual(t, result.P50, 5*time.Millisecond)
+				assert.LessOrEqual(t, result.P50, result.P90)
+				assert.LessOrEqual(t, result.P90, result.P99)
+				assert.LessOrEqual(t, result.P99, result.Max)
+
+				// Each batch holds the next lines, cycling back to the first line. Closing the server
+				// first waits for requests cut off by the end of the benchmark to be recorded.
+				srv.Close()
+				for _, call := range srv.Calls[loadingJobURL] {
+					body, err := io.ReadAll(call)
+					assert.Nil(t, err)
+
+					var ids []int
+					for _, line := range bytes.Split(body, []byte("\n")) {
+						var decoded map[string]int
+						assert.Nil(t, json.Unmarshal(line, &decoded))
+						ids = append(ids, decoded["id"])
+					}
+					assert.Len(t, ids, 3)
+					assert.Equal(t, (ids[0]+1)%len(lines), ids[1])
+					assert.Equal(t, (ids[1]+1)%len(lines), ids[2])
+				}
+			}
+
+			if test.expectFailure != nil {
+				assert.Greater(t, result.Failures, 0)
+				assert.ErrorIs(t, result.FirstError, test.expectFailure)
+				assert.Equal(t, 0, result.Lines)
+				assert.Equal(t, time.Duration(0), result.Max)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingRequestMetrics struct {
+	events []tigergraph.RequestEvent
+}
+
+func (r *recordingRequestMetrics) RequestCompleted(event tigergraph.RequestEvent) {
+	r.events = append(r.events, event)
+}
+
+func TestServerBusy(t *testing.T) { //nolint:funlen
+	upsertURL := tigergraph.UpsertURL + "/" + graphName
+
+	busyHandler := func(retryAfter string) handlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "retries busy responses and resends the body",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				attempts := 0
+				srv.Mock(upsertURL, func(w http.ResponseWriter, r *http.Request) {
+					attempts++
+					if attempts < 3 {
+						busyHandler("0")(w, r)
+						return
+					}
+
+					_, err := w.Write([]byte(`{"results": [{"accepted_vertices": 1}]}`))
+					assert.Nil(t, err)
+				})
+
+				result, err := client.Upsert(context.Background(), graphName, map[string]any{"vertices": map[string]any{}})
+				assert.Nil(t, err)
+				assert.Equal(t, 1, result.AcceptedVertices)
+
+				calls := srv.Calls[upsertURL]
+				assert.Len(t, calls, 3)
+				assert.Equal(t, calls[0], calls[2])
+			},
+		},
+		{
+			name: "returns ErrServerBusy when retries are exhausted",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.MaxBusyRetries = 2
+				srv.Mock(upsertURL, busyHandler("0"))
+
+				_, err := client.Upsert(context.Background(), graphName, map[string]any{})
+				assert.ErrorIs(t, err, tigergraph.ErrServerBusy)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Len(t, srv.Calls[upsertURL], 3)
+			},
+		},
+		{
+			name: "reports the attempts and duration of failed requests",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				metrics := &recordingRequestMetrics{}
+				client.RequestMetrics = metrics
+				client.MaxBusyRetries = 2
+				srv.Mock(upsertURL, busyHandler("0"))
+
+				_, err := client.Upsert(context.Background(), graphName, map[string]any{})
+
+				var requestErr *tigergraph.RequestError
+				assert.ErrorAs(t, err, &requestErr)
+				assert.Equal(t, http.MethodPost, requestErr.Method)
+				assert.Equal(t, upsertURL, requestErr.Path)
+				assert.Equal(t, 3, requestErr.Attempts)
+				assert.Positive(t, requestErr.Duration)
+				assert.Contains(t, err.Error(), "failed after 3 attempt(s) in")
+
+				// The token request and the upsert
+				assert.Len(t, metrics.events, 2)
+				event := metrics.events[1]
+				assert.Equal(t, upsertURL, event.Path)
+				assert.Equal(t, 3, event.Attempts)
+				assert.Equal(t, requestErr.Duration, event.Duration)
+				assert.ErrorIs(t, event.Err, tigergraph.ErrServerBusy)
+			},
+		},
+		{
+			name: "does not wait longer than MaxBusyWait",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.MaxBusyWait = time.Second
+				srv.Mock(upsertURL, busyHandler("120"))
+
+				_, err := client.Upsert(context.Background(), graphName, map[string]any{})
+				assert.ErrorIs(t, err, tigergraph.ErrServerBusy)
+				assert.Len(t, srv.Calls[upsertURL], 1)
+			},
+		},
+		{
+			name: "does not resend a body which cannot be read again",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				attempts := 0
+				srv.Mock(upsertURL, func(w http.ResponseWriter, r *http.Request) {
+					attempts++
+					if attempts < 2 {
+						busyHandler("0")(w, r)
+						return
+					}
+
+					_, err := w.Write([]byte(`{"results": [{"accepted_vertices": 1}]}`))
+					assert.Nil(t, err)
+				})
+
+				// Wrapping the reader hides it from http.NewRequest, which leaves GetBody unset
+				body := io.NopCloser(strings.NewReader(`{"vertices": {}}`))
+				req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.HTTPServer.URL+upsertURL, body)
+				assert.Nil(t, err)
+
+				var result tigergraph.TigerGraphResponse[any]
+				err = client.RequestInto(req, &result)
+				assert.ErrorIs(t, err, tigergraph.ErrBodyNotRewindable)
+				assert.ErrorIs(t, err, tigergraph.ErrServerBusy)
+				assert.Len(t, srv.Calls[upsertURL], 1)
+			},
+		},
+		{
+			name: "does not wait past the request deadline",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(upsertURL, busyHandler("5"))
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+
+				start := time.Now()
+				_, err := client.Upsert(ctx, graphName, map[string]any{})
+				assert.ErrorIs(t, err, tigergraph.ErrServerBusy)
+				assert.Len(t, srv.Calls[upsertURL], 1)
+				assert.Less(t, time.Since(start), time.Second)
+			},
+		},
+		{
+			name: "does not retry busy pings",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(tigergraph.PingURL, busyHandler("0"))
+
+				_, err := client.Ping(context.Background())
+				assert.ErrorIs(t, err, tigergraph.ErrServerBusy)
+				assert.Len(t, srv.Calls[tigergraph.PingURL], 1)
+			},
+		},
+		{
+			name: "busy GSQL server",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.MaxBusyRetries = 0
+				srv.Mock(tigergraph.FileURL, busyHandler("0"))
+
+				err := client.RunGSQL(context.Background(), "CREATE GRAPH Relationships()")
+				assert.ErrorIs(t, err, tigergraph.ErrServerBusy)
+				assert.ErrorIs(t, err, tigergraph.ErrRequestFailed)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(
+				srv.HTTPServer.URL,
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				tigergraph.WithBusyRetries(tigergraph.DefaultMaxBusyRetries),
+			)
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallCredentials(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/myquery"
+	tenantUsername := "tenant"
+	tenantPassword := "tenantpassword"
+
+	// mockTenant issues tokens to the client's user and the tenant, and expects the query to be
+	// called with the given bearer token
+	mockTenant := func(t *testing.T, srv *MockTigerGraphServer, token string) {
+		t.Helper()
+		srv.Mock(tigergraph.RequestTokenURL, func(w http.ResponseWriter, r *http.Request) {
+			username, _, _ := r.BasicAuth()
+			if username == tenantUsername {
+				makeDefaultRequestTokenHandler(tenantUsername, tenantPassword, time.Now().Add(time.Hour).Unix())(w, r)
+				return
+			}
+			makeDefaultRequestTokenHandler(expectedUsername, expectedPassword, time.Now().Add(time.Hour).Unix())(w, r)
+		})
+		srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, []string{"Bearer " + token}, r.Header.Values("Authorization"))
+			_, err := w.Write([]byte(`{"error": false, "results": []}`))
+			assert.Nil(t, err)
+		})
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "calls with credentials use a token requested with them, cached per user",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockTenant(t, srv, "sometoken")
+
+				var result tigergraph.TigerGraphResponse[any]
+				for i := 0; i < 2; i++ {
+					err := client.Get(
+						context.Background(),
+						queryURL,
+						graphName,
+						&result,
+						tigergraph.WithCallCredentials(tenantUsername, tenantPassword),
+					)
+					assert.Nil(t, err)
+				}
+
+				assert.Equal(t, 1, srv.CallCount(tigergraph.RequestTokenURL))
+				_, cached := client.Tokens.Get(graphName)
+				assert.False(t, cached)
+			},
+		},
+		{
+			name: "calls with wrong credentials fail",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockTenant(t, srv, "sometoken")
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Post(
+					context.Background(),
+					queryURL,
+					graphName,
+					map[string]any{},
+					&result,
+					tigergraph.WithCallCredentials(tenantUsername, "wrong"),
+				)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Equal(t, 0, srv.CallCount(queryURL))
+			},
+		},
+		{
+			name: "calls with a token send it without requesting one",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockTenant(t, srv, "tenanttoken")
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result, tigergraph.WithCallToken("tenanttoken")))
+
+				assert.Equal(t, 0, srv.CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+		{
+			name: "GSQL is run with the call's credentials",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+					username, password, _ := r.BasicAuth()
+					assert.Equal(t, tenantUsername, username)
+					assert.Equal(t, tenantPassword, password)
+					fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+				})
+
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS", tigergraph.WithCallCredentials(tenantUsername, tenantPassword)))
+			},
+		},
+		{
+			name: "rejected call credentials are not refreshed by the provider",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				provider := &rotatingCredentialsProvider{
+					current: tigergraph.Credentials{Username: expectedUsername, Password: expectedPassword},
+					next:    tigergraph.Credentials{Username: expectedUsername, Password: expectedPassword},
+				}
+				client.CredentialsProvider = provider
+				srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusUnauthorized)
+				})
+
+				err := client.RunGSQL(context.Background(), "LS", tigergraph.WithCallCredentials(tenantUsername, "wrong"))
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Equal(t, 0, provider.refreshes)
+				assert.Equal(t, 1, srv.CallCount(tigergraph.FileURL))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	queryURL := "/query/" + graphName + "/my_query"
+
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	healthy := false
+	srv.Mock(queryURL, func(w http.ResponseWriter, _ *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, err := w.Write([]byte(`{"error": false}`))
+		assert.Nil(t, err)
+	})
+
+	breaker := tigergraph.NewCircuitBreaker(3, 50*time.Millisecond)
+	client := tigergraph.NewClient(
+		srv.HTTPServer.URL,
+		tigergraph.WithCredentials(expectedUsername, expectedPassword),
+		tigergraph.WithCircuitBreaker(breaker),
+	)
+
+	ctx := context.Background()
+	var result tigergraph.TigerGraphResponse[any]
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, client.Get(ctx, queryURL, graphName, &result), tigergraph.ErrNonOK)
+	}
+	assert.Equal(t, tigergraph.CircuitOpen, breaker.State())
+
+	// Calls are short-circuited without reaching TigerGraph
+	assert.ErrorIs(t, client.Get(ctx, queryURL, graphName, &result), tigergraph.ErrCircuitOpen)
+	assert.ErrorIs(t, client.RunGSQL(ctx, "LS"), tigergraph.ErrCircuitOpen)
+	assert.Len(t, srv.Calls[queryURL], 3)
+	assert.Len(t, srv.Calls[tigergraph.FileURL], 0)
+
+	// Ping still reaches TigerGraph, and its answer leaves the circuit open
+	_, err := client.Ping(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, srv.Calls[tigergraph.PingURL], 1)
+	assert.Equal(t, tigergraph.CircuitOpen, breaker.State())
+
+	// The probe closes the circuit once TigerGraph recovers
+	healthy = true
+	time.Sleep(60 * time.Millisecond)
+	assert.Nil(t, client.Get(ctx, queryURL, graphName, &result))
+	assert.Equal(t, tigergraph.CircuitClosed, breaker.State())
+	assert.Len(t, srv.Calls[queryURL], 4)
+}
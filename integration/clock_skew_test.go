@@ -0,0 +1,79 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLogger struct {
+	warnings []string
+}
+
+func (r *recordingLogger) Warn(msg string, _ ...any) {
+	r.warnings = append(r.warnings, msg)
+}
+
+func TestClockSkew(t *testing.T) {
+	queryURL := "/query/" + graphName + "/my_query"
+
+	tests := []struct {
+		name             string
+		serverOffset     time.Duration
+		expectedWarnings int
+	}{
+		{name: "clocks agree", serverOffset: 0, expectedWarnings: 0},
+		{name: "server ahead", serverOffset: time.Hour, expectedWarnings: 1},
+		{name: "server behind", serverOffset: -time.Hour, expectedWarnings: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			srv.Mock(queryURL, func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Date", time.Now().Add(test.serverOffset).UTC().Format(http.TimeFormat))
+				_, err := w.Write([]byte(`{"error": false}`))
+				assert.Nil(t, err)
+			})
+
+			logger := &recordingLogger{}
+			client := tigergraph.NewClient(
+				srv.HTTPServer.URL,
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				tigergraph.WithLogger(logger),
+			)
+
+			_, known := client.ClockSkew()
+			assert.False(t, known)
+
+			var result tigergraph.TigerGraphResponse[any]
+			for i := 0; i < 2; i++ {
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+			}
+
+			skew, known := client.ClockSkew()
+			assert.True(t, known)
+			assert.InDelta(t, test.serverOffset.Seconds(), skew.Seconds(), 2)
+			assert.Len(t, logger.warnings, test.expectedWarnings)
+		})
+	}
+}
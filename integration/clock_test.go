@@ -0,0 +1,95 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	current time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.current
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.current = c.current.Add(d)
+}
+
+func TestClock(t *testing.T) { //nolint:funlen
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, clock *fakeClock)
+	}{
+		{
+			name: "tokens are refreshed once the clock passes their expiry",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, clock *fakeClock) {
+				ctx := context.Background()
+				assert.Nil(t, client.Auth(ctx, graphName))
+
+				// The mock server issues tokens lasting 5 minutes
+				clock.Advance(4 * time.Minute)
+				assert.Nil(t, client.Auth(ctx, graphName))
+				assert.Equal(t, 1, srv.CallCount(tigergraph.RequestTokenURL))
+
+				clock.Advance(2 * time.Minute)
+				assert.Nil(t, client.Auth(ctx, graphName))
+				assert.Equal(t, 2, srv.CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+		{
+			name: "soft deletes are timestamped by the clock",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, clock *fakeClock) {
+				softDeleteURL := tigergraph.UpsertURL + "/" + graphName + "?vertex_must_exist=true"
+				srv.MockResponse(softDeleteURL, tigergraph.UpsertResponse{
+					Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+				})
+
+				_, err := client.SoftDeleteVertices(context.Background(), graphName, "Person", []string{"1"})
+				assert.Nil(t, err)
+
+				calls := srv.Calls[softDeleteURL]
+				assert.Len(t, calls, 1)
+
+				body, err := io.ReadAll(calls[0])
+				assert.Nil(t, err)
+				assert.Contains(t, string(body), clock.Now().UTC().Format(tigergraph.TigerGraphDateTimeFormat))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			clock := &fakeClock{current: time.Now().Truncate(time.Second)}
+			client := tigergraph.NewClient(
+				srv.HTTPServer.URL,
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				tigergraph.WithClock(clock.Now),
+			)
+
+			test.action(t, client, srv, clock)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClose(t *testing.T) { //nolint:funlen
+	// mockTokens issues a token named after its graph and records the tokens deleted
+	mockTokens := func(t *testing.T, srv *MockTigerGraphServer, deleted *[]string, deleteStatus int) {
+		t.Helper()
+		issue := makeDefaultRequestTokenHandler(expectedUsername, expectedPassword, time.Now().Add(time.Hour).Unix())
+
+		srv.Mock(tigergraph.RequestTokenURL, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodDelete {
+				issue(w, r)
+				return
+			}
+
+			username, password, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, expectedUsername, username)
+			assert.Equal(t, expectedPassword, password)
+
+			body, err := io.ReadAll(r.Body)
+			assert.Nil(t, err)
+
+			var request tigergraph.RevokeTokenRequest
+			assert.Nil(t, json.Unmarshal(body, &request))
+			*deleted = append(*deleted, request.Token)
+
+			w.WriteHeader(deleteStatus)
+			_, err = w.Write([]byte(`{"error": false, "message": "Token deleted"}`))
+			assert.Nil(t, err)
+		})
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "revokes every cached token",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				var deleted []string
+				mockTokens(t, srv, &deleted, http.StatusOK)
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Nil(t, client.AuthGlobal(context.Background()))
+
+				assert.Nil(t, client.Close(context.Background()))
+				assert.Equal(t, []string{"sometoken", "sometoken"}, deleted)
+				assert.Empty(t, client.Tokens.Graphs())
+			},
+		},
+		{
+			name: "skips expired tokens",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				var deleted []string
+				mockTokens(t, srv, &deleted, http.StatusOK)
+
+				client.Tokens.Set(graphName, &tigergraph.Token{Value: "expired", Expires: time.Now().Add(-time.Minute)})
+				client.Tokens.Set("OtherGraph", &tigergraph.Token{Value: "live", Expires: time.Now().Add(time.Minute)})
+
+				assert.Nil(t, client.Close(context.Background()))
+				assert.Equal(t, []string{"live"}, deleted)
+				assert.Empty(t, client.Tokens.Graphs())
+			},
+		},
+		{
+			name: "revocation failures are returned and the cache is still cleared",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				var deleted []string
+				mockTokens(t, srv, &deleted, http.StatusForbidden)
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+
+				err := client.Close(context.Background())
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Contains(t, err.Error(), graphName)
+				assert.Empty(t, client.Tokens.Graphs())
+			},
+		},
+		{
+			name: "the client can be used after closing",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				var deleted []string
+				mockTokens(t, srv, &deleted, http.StatusOK)
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Nil(t, client.Close(context.Background()))
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+
+				assert.Equal(t, []string{graphName}, client.Tokens.Graphs())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudClient(t *testing.T) { //nolint:funlen
+	cloudUpsertURL := tigergraph.CloudRESTPPPath + tigergraph.UpsertURL + "/" + graphName
+
+	// mockCloudTokens issues tokens from the cloud token URL for the known secrets, naming the
+	// secret's graph or "global"
+	mockCloudTokens := func(t *testing.T, srv *MockTigerGraphServer) {
+		t.Helper()
+		srv.Mock(tigergraph.CloudTokenURL, func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.Nil(t, err)
+
+			var request tigergraph.RequestTokenRequest
+			assert.Nil(t, json.Unmarshal(body, &request))
+
+			response := tigergraph.CloudTokenResponse{Expiration: time.Now().Add(time.Hour).Unix()}
+			switch request.Secret {
+			case "graphsecret":
+				response.Token = graphName
+			case "globalsecret":
+				response.Token = "global"
+			default:
+				response.Error = true
+				response.Message = "invalid secret"
+			}
+			assert.Nil(t, json.NewEncoder(w).Encode(response))
+		})
+	}
+
+	// mockGSQL expects GSQL to be run with the given Authorization header
+	mockGSQL := func(t *testing.T, srv *MockTigerGraphServer, authorization string) {
+		t.Helper()
+		srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, authorization, r.Header.Get("Authorization"))
+			fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+		})
+	}
+
+	tests := []struct {
+		name   string
+		opts   []tigergraph.Option
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "RESTPP is called under the cloud path with cloud tokens",
+			opts: []tigergraph.Option{tigergraph.WithSecret(graphName, "graphsecret")},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockCloudTokens(t, srv)
+				srv.Mock(cloudUpsertURL, func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, "Bearer "+graphName, r.Header.Get("Authorization"))
+					assert.Nil(t, json.NewEncoder(w).Encode(tigergraph.UpsertResponse{
+						Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+					}))
+				})
+
+				payload := client.NewUpsertPayload().AddVertex("Person", "1", map[string]any{})
+				result, err := client.Upsert(context.Background(), graphName, payload)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, result.AcceptedVertices)
+
+				tokenCalls := srv.Calls[tigergraph.CloudTokenURL]
+				if assert.Len(t, tokenCalls, 1) {
+					body, err := io.ReadAll(tokenCalls[0])
+					assert.Nil(t, err)
+					assert.JSONEq(t, `{"secret": "graphsecret"}`, string(body))
+				}
+				assert.Equal(t, 0, srv.CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+		{
+			name: "GSQL is run with basic auth when credentials are given",
+			opts: []tigergraph.Option{tigergraph.WithCredentials(expectedUsername, expectedPassword)},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				request, err := http.NewRequest(http.MethodGet, "/", http.NoBody)
+				assert.Nil(t, err)
+				request.SetBasicAuth(expectedUsername, expectedPassword)
+				mockGSQL(t, srv, request.Header.Get("Authorization"))
+
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+				assert.Equal(t, 0, srv.CallCount(tigergraph.CloudTokenURL))
+			},
+		},
+		{
+			name: "GSQL is run with a global token without credentials",
+			opts: []tigergraph.Option{tigergraph.WithSecret(tigergraph.GlobalGraph, "globalsecret")},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockCloudTokens(t, srv)
+				mockGSQL(t, srv, "Bearer global")
+
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+				assert.Equal(t, 1, srv.CallCount(tigergraph.FileURL))
+			},
+		},
+		{
+			name: "failed cloud token requests return ErrTigerGraphError",
+			opts: []tigergraph.Option{tigergraph.WithSecret(graphName, "wrongsecret")},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockCloudTokens(t, srv)
+
+				err := client.Auth(context.Background(), graphName)
+				assert.ErrorIs(t, err, tigergraph.ErrTigerGraphError)
+				assert.ErrorContains(t, err, "invalid secret")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewCloudClient(srv.HTTPServer.URL+"/", test.opts...)
+			assert.True(t, client.Cloud)
+			assert.Equal(t, srv.HTTPServer.URL+tigergraph.CloudRESTPPPath, client.BaseURL)
+			assert.Equal(t, srv.HTTPServer.URL, client.BaseFileURL)
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCompression(t *testing.T) {
+	queryURL := "/query/" + graphName + "/my_query"
+	responseBody := `{"error": false, "message": "compressed", "results": []}`
+
+	acceptEncodings := make([]string, 0)
+	gzipHandler := func(w http.ResponseWriter, r *http.Request) {
+		acceptEncodings = append(acceptEncodings, r.Header.Get("Accept-Encoding"))
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			_, err := w.Write([]byte(responseBody))
+			assert.Nil(t, err)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		_, err := writer.Write([]byte(responseBody))
+		assert.Nil(t, err)
+		assert.Nil(t, writer.Close())
+	}
+
+	tests := []struct {
+		name                   string
+		disableCompression     bool
+		expectedAcceptEncoding string
+	}{
+		{name: "decompresses gzip responses", disableCompression: false, expectedAcceptEncoding: "gzip"},
+		{name: "compression disabled", disableCompression: true, expectedAcceptEncoding: ""},
+	}
+
+	t.Run("decompresses gzip GSQL responses", func(t *testing.T) {
+		srv := NewMockServer(expectedUsername, expectedPassword)
+		defer srv.Close()
+
+		srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+			w.Header().Set("Content-Encoding", "gzip")
+			writer := gzip.NewWriter(w)
+			_, err := writer.Write([]byte("Done.\n" + tigergraph.SuccessString + "\n"))
+			assert.Nil(t, err)
+			assert.Nil(t, writer.Close())
+		})
+
+		client := tigergraph.NewClient(
+			srv.HTTPServer.URL,
+			tigergraph.WithCredentials(expectedUsername, expectedPassword),
+			tigergraph.WithHeader("Accept-Encoding", "gzip"),
+		)
+
+		assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+	})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			acceptEncodings = acceptEncodings[:0]
+			opts := []tigergraph.Option{tigergraph.WithCredentials(expectedUsername, expectedPassword)}
+			if test.disableCompression {
+				opts = append(opts, tigergraph.WithoutResponseCompression())
+			}
+			client := tigergraph.NewClient(srv.HTTPServer.URL, opts...)
+			srv.Mock(queryURL, gzipHandler)
+
+			var result tigergraph.TigerGraphResponse[any]
+			err := client.Get(context.Background(), queryURL, graphName, &result)
+			assert.Nil(t, err)
+			assert.Equal(t, "compressed", result.Message)
+			assert.Equal(t, []string{test.expectedAcceptEncoding}, acceptEncodings)
+		})
+	}
+}
+
+func TestRequestCompression(t *testing.T) {
+	queryURL := "/query/" + graphName + "/my_query"
+	smallBody := []byte(`{"name": "small"}`)
+	largeBody := []byte(`{"name": "` + strings.Repeat("large", 100) + `"}`)
+
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	encodings := make([]string, 0)
+	bodies := make([]string, 0)
+	srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gzipReader, err := gzip.NewReader(r.Body)
+			assert.Nil(t, err)
+			reader = gzipReader
+		}
+
+		body, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		bodies = append(bodies, string(body))
+
+		_, err = w.Write([]byte(`{"error": false, "results": []}`))
+		assert.Nil(t, err)
+	})
+
+	client := tigergraph.NewClient(
+		srv.HTTPServer.URL,
+		tigergraph.WithCredentials(expectedUsername, expectedPassword),
+		tigergraph.WithRequestCompression(len(largeBody)),
+	)
+
+	var result tigergraph.TigerGraphResponse[any]
+	assert.Nil(t, client.PostRaw(context.Background(), queryURL, graphName, smallBody, &result))
+	assert.Nil(t, client.PostRaw(context.Background(), queryURL, graphName, largeBody, &result))
+
+	assert.Equal(t, []string{"", "gzip"}, encodings)
+	assert.Equal(t, []string{string(smallBody), string(largeBody)}, bodies)
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type rotatingCredentialsProvider struct {
+	current   tigergraph.Credentials
+	next      tigergraph.Credentials
+	refreshes int
+	err       error
+}
+
+func (p *rotatingCredentialsProvider) Credentials(_ context.Context) (tigergraph.Credentials, error) {
+	return p.current, nil
+}
+
+func (p *rotatingCredentialsProvider) Refresh(_ context.Context) (tigergraph.Credentials, error) {
+	p.refreshes++
+	if p.err != nil {
+		return tigergraph.Credentials{}, p.err
+	}
+
+	p.current = p.next
+	return p.current, nil
+}
+
+// secretTokenHandler issues tokens for requests with the given secret, rejects requests with
+// other secrets and falls back to the default handler for requests using basic auth.
+func secretTokenHandler(t *testing.T, secret string) handlerFunc {
+	basicAuthHandler := makeDefaultRequestTokenHandler(expectedUsername, expectedPassword, time.Now().Add(time.Hour).Unix())
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request tigergraph.RequestTokenRequest
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+
+		if request.Secret == "" {
+			basicAuthHandler(w, r)
+			return
+		}
+
+		_, _, hasBasicAuth := r.BasicAuth()
+		assert.False(t, hasBasicAuth)
+
+		if request.Secret != secret {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		assert.Nil(t, json.NewEncoder(w).Encode(tigergraph.RequestTokenResponse{
+			ExpirationSecondsSinceEpoch: time.Now().Add(time.Hour).Unix(),
+			Results:                     tigergraph.RequestTokenResponseResults{Token: "secrettoken"},
+		}))
+	}
+}
+
+func TestCredentialsProvider(t *testing.T) { //nolint:funlen
+	successResponse := fmt.Sprintf("Done.\n%s\n", tigergraph.SuccessString)
+	gsqlHandler := func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		if username != expectedUsername || password != expectedPassword {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(successResponse))
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "uses the provider's credentials",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				provider := &rotatingCredentialsProvider{
+					current: tigergraph.Credentials{Username: expectedUsername, Password: expectedPassword},
+				}
+				client.CredentialsProvider = provider
+				srv.Mock(tigergraph.FileURL, gsqlHandler)
+
+				assert.Nil(t, client.RunGSQL(context.Background(), "ls"))
+				assert.Equal(t, 0, provider.refreshes)
+			},
+		},
+		{
+			name: "requests tokens with the provider's secret for the graph",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.CredentialsProvider = &rotatingCredentialsProvider{current: tigergraph.Credentials{
+					Username: expectedUsername,
+					Password: expectedPassword,
+					Secrets:  map[string]string{graphName: "graph-secret"},
+				}}
+				srv.Mock(tigergraph.RequestTokenURL, secretTokenHandler(t, "graph-secret"))
+
+				ctx := context.Background()
+				assert.Nil(t, client.Auth(ctx, graphName))
+				token, ok := client.Tokens.Get(graphName)
+				assert.True(t, ok)
+				assert.Equal(t, "secrettoken", token.Value)
+
+				// Graphs without a secret in the provider's credentials use basic auth
+				assert.Nil(t, client.Auth(ctx, "OtherGraph"))
+				token, ok = client.Tokens.Get("OtherGraph")
+				assert.True(t, ok)
+				assert.Equal(t, "sometoken", token.Value)
+			},
+		},
+		{
+			name: "refreshes a rotated secret and requests the token again",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				provider := &rotatingCredentialsProvider{
+					current: tigergraph.Credentials{Secrets: map[string]string{graphName: "rotated-out"}},
+					next:    tigergraph.Credentials{Secrets: map[string]string{graphName: "graph-secret"}},
+				}
+				client.CredentialsProvider = provider
+				srv.Mock(tigergraph.RequestTokenURL, secretTokenHandler(t, "graph-secret"))
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Equal(t, 1, provider.refreshes)
+				assert.Len(t, srv.Calls[tigergraph.RequestTokenURL], 2)
+
+				token, ok := client.Tokens.Get(graphName)
+				assert.True(t, ok)
+				assert.Equal(t, "secrettoken", token.Value)
+			},
+		},
+		{
+			name: "refreshes rotated credentials and retries once",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				provider := &rotatingCredentialsProvider{
+					current: tigergraph.Credentials{Username: expectedUsername, Password: "rotated-out"},
+					next:    tigergraph.Credentials{Username: expectedUsername, Password: expectedPassword},
+				}
+				client.CredentialsProvider = provider
+				srv.Mock(tigergraph.FileURL, gsqlHandler)
+
+				assert.Nil(t, client.RunGSQL(context.Background(), "ls"))
+				assert.Equal(t, 1, provider.refreshes)
+				assert.Len(t, srv.Calls[tigergraph.FileURL], 2)
+			},
+		},
+		{
+			name: "token requests also refresh credentials",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				provider := &rotatingCredentialsProvider{
+					current: tigergraph.Credentials{Username: expectedUsername, Password: "rotated-out"},
+					next:    tigergraph.Credentials{Username: expectedUsername, Password: expectedPassword},
+				}
+				client.CredentialsProvider = provider
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Equal(t, 1, provider.refreshes)
+			},
+		},
+		{
+			name: "fails when refreshed credentials are also rejected",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				provider := &rotatingCredentialsProvider{
+					current: tigergraph.Credentials{Username: expectedUsername, Password: "rotated-out"},
+					next:    tigergraph.Credentials{Username: expectedUsername, Password: "still-wrong"},
+				}
+				client.CredentialsProvider = provider
+				srv.Mock(tigergraph.FileURL, gsqlHandler)
+
+				err := client.RunGSQL(context.Background(), "ls")
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Equal(t, 1, provider.refreshes)
+				assert.Len(t, srv.Calls[tigergraph.FileURL], 2)
+			},
+		},
+		{
+			name: "refresh failure is returned",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				refreshErr := errors.New("vault unavailable")
+				client.CredentialsProvider = &rotatingCredentialsProvider{
+					current: tigergraph.Credentials{Username: expectedUsername, Password: "rotated-out"},
+					err:     refreshErr,
+				}
+				srv.Mock(tigergraph.FileURL, gsqlHandler)
+
+				err := client.RunGSQL(context.Background(), "ls")
+				assert.ErrorIs(t, err, refreshErr)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials("", ""))
+
+			test.action(t, client, srv)
+		})
+	}
+}
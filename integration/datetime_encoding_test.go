@@ -0,0 +1,125 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatetimeEncoding(t *testing.T) { //nolint:funlen
+	metadataURL := tigergraph.GetGraphMetadataQueryURL + "?graph=" + graphName
+	restoreURL := tigergraph.UpsertURL + "/" + graphName + "?vertex_must_exist=true"
+
+	metadataWithDefault := func(defaultValue *string) tigergraph.GraphMetadataResponse {
+		return tigergraph.GraphMetadataResponse{
+			Results: &tigergraph.GraphMetadataResponseResult{
+				GraphName: graphName,
+				VertexTypes: []tigergraph.GraphMetadataVertexType{
+					{
+						Name: "Person",
+						Attributes: []tigergraph.GraphMetadataAttribute{
+							{AttributeName: "name", AttributeType: tigergraph.GraphMetadataAttributeType{Name: "STRING"}},
+							{
+								AttributeName: "deleted_at",
+								AttributeType: tigergraph.GraphMetadataAttributeType{Name: "DATETIME"},
+								DefaultValue:  defaultValue,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	epochDefault := "0"
+	stringDefault := tigergraph.DefaultDateTime
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "detects epoch encoding from the schema",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(metadataURL, metadataWithDefault(&epochDefault))
+				srv.MockResponse(restoreURL, tigergraph.UpsertResponse{
+					Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+				})
+
+				encoding, err := client.DetectDatetimeEncoding(context.Background(), graphName)
+				assert.Nil(t, err)
+				assert.Equal(t, tigergraph.DatetimeEpoch, encoding)
+				assert.Equal(t, tigergraph.DatetimeEpoch, client.DatetimeEncoding)
+
+				_, err = client.RestoreVertices(context.Background(), graphName, "Person", []string{"1"})
+				assert.Nil(t, err)
+
+				if !assert.Len(t, srv.Calls[restoreURL], 1) {
+					return
+				}
+				body, err := io.ReadAll(srv.Calls[restoreURL][0])
+				assert.Nil(t, err)
+				assert.JSONEq(t, `{"vertices": {"Person": {"1": {"deleted_at": {"value": 0}}}}}`, string(body))
+			},
+		},
+		{
+			name: "detects string encoding from the schema",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.DatetimeEncoding = tigergraph.DatetimeEpoch
+				srv.MockResponse(metadataURL, metadataWithDefault(&stringDefault))
+
+				encoding, err := client.DetectDatetimeEncoding(context.Background(), graphName)
+				assert.Nil(t, err)
+				assert.Equal(t, tigergraph.DatetimeString, encoding)
+				assert.Equal(t, tigergraph.DatetimeString, client.DatetimeEncoding)
+			},
+		},
+		{
+			name: "keeps the configured encoding without DATETIME defaults",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.DatetimeEncoding = tigergraph.DatetimeEpoch
+				srv.MockResponse(metadataURL, metadataWithDefault(nil))
+
+				encoding, err := client.DetectDatetimeEncoding(context.Background(), graphName)
+				assert.Nil(t, err)
+				assert.Equal(t, tigergraph.DatetimeEpoch, encoding)
+			},
+		},
+		{
+			name: "returns an error when the schema cannot be read",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(metadataURL, tigergraph.GraphMetadataResponse{Error: true, Message: "graph does not exist"})
+
+				encoding, err := client.DetectDatetimeEncoding(context.Background(), graphName)
+				assert.ErrorContains(t, err, "graph does not exist")
+				assert.Equal(t, tigergraph.DatetimeString, encoding)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
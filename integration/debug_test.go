@@ -0,0 +1,175 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingDebug struct {
+	mu        sync.Mutex
+	exchanges []tigergraph.DebugExchange
+}
+
+func (r *recordingDebug) record(exchange tigergraph.DebugExchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exchanges = append(r.exchanges, exchange)
+}
+
+func TestDebug(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/my_query"
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, debug *recordingDebug)
+	}{
+		{
+			name: "dumps every request and response with credentials redacted",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, debug *recordingDebug) {
+				srv.Mock(queryURL+"?secret=hunter2&name=bob", func(w http.ResponseWriter, _ *http.Request) {
+					_, err := w.Write([]byte(`{"results": [{"name": "bob"}]}`))
+					assert.Nil(t, err)
+				})
+
+				var result tigergraph.TigerGraphResponse[map[string]string]
+				assert.Nil(t, client.Get(context.Background(), queryURL+"?secret=hunter2&name=bob", graphName, &result))
+				assert.Equal(t, "bob", result.Results[0]["name"])
+
+				if !assert.Len(t, debug.exchanges, 2) {
+					return
+				}
+
+				tokenExchange := debug.exchanges[0]
+				assert.Equal(t, http.MethodPost, tokenExchange.Method)
+				assert.JSONEq(t, `{"graph": "`+graphName+`"}`, tokenExchange.RequestBody)
+				assert.Equal(t, []string{"[REDACTED]"}, tokenExchange.RequestHeader["Authorization"])
+				assert.Contains(t, tokenExchange.ResponseBody, `"token":"[REDACTED]"`)
+				assert.NotContains(t, tokenExchange.ResponseBody, "sometoken")
+
+				queryExchange := debug.exchanges[1]
+				assert.Equal(t, http.MethodGet, queryExchange.Method)
+				assert.Contains(t, queryExchange.URL, queryURL)
+				assert.Contains(t, queryExchange.URL, "name=bob")
+				assert.NotContains(t, queryExchange.URL, "hunter2")
+				assert.Equal(t, []string{"[REDACTED]"}, queryExchange.RequestHeader["Authorization"])
+				assert.Equal(t, http.StatusOK, queryExchange.StatusCode)
+				assert.Equal(t, `{"results": [{"name": "bob"}]}`, queryExchange.ResponseBody)
+				assert.False(t, queryExchange.Truncated)
+			},
+		},
+		{
+			name: "gzipped responses are dumped decompressed",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, debug *recordingDebug) {
+				srv.Mock(queryURL, func(w http.ResponseWriter, _ *http.Request) {
+					w.Header().Set("Content-Encoding", "gzip")
+					writer := gzip.NewWriter(w)
+					_, err := writer.Write([]byte(`{"results": []}`))
+					assert.Nil(t, err)
+					assert.Nil(t, writer.Close())
+				})
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+
+				if assert.Len(t, debug.exchanges, 2) {
+					assert.Equal(t, `{"results": []}`, debug.exchanges[1].ResponseBody)
+				}
+			},
+		},
+		{
+			name: "long responses are truncated but read in full",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, debug *recordingDebug) {
+				long := strings.Repeat("a", tigergraph.MaxDebugBodySize)
+				srv.Mock(queryURL, func(w http.ResponseWriter, _ *http.Request) {
+					_, err := w.Write([]byte(`{"results": ["` + long + `"]}`))
+					assert.Nil(t, err)
+				})
+
+				var result tigergraph.TigerGraphResponse[string]
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+				assert.Equal(t, long, result.Results[0])
+
+				if assert.Len(t, debug.exchanges, 2) {
+					assert.True(t, debug.exchanges[1].Truncated)
+					assert.Len(t, debug.exchanges[1].ResponseBody, tigergraph.MaxDebugBodySize)
+				}
+			},
+		},
+		{
+			name: "GSQL requests are dumped with basic auth redacted",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, debug *recordingDebug) {
+				srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+					_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+					assert.Nil(t, err)
+				})
+
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+
+				if assert.Len(t, debug.exchanges, 1) {
+					assert.Equal(t, "LS", debug.exchanges[0].RequestBody)
+					assert.Equal(t, []string{"[REDACTED]"}, debug.exchanges[0].RequestHeader["Authorization"])
+					assert.Contains(t, debug.exchanges[0].ResponseBody, tigergraph.SuccessString)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			debug := &recordingDebug{}
+			client := tigergraph.NewClient(
+				srv.HTTPServer.URL,
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				tigergraph.WithDebugFunc(debug.record),
+			)
+
+			test.action(t, client, srv, debug)
+		})
+	}
+}
+
+func TestWithDebugLogs(t *testing.T) {
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	logger := &recordingLevelLogger{}
+	client := tigergraph.NewClient(
+		srv.HTTPServer.URL,
+		tigergraph.WithCredentials(expectedUsername, expectedPassword),
+		tigergraph.WithLogger(logger),
+		tigergraph.WithLogLevels(tigergraph.LogLevels{}),
+		tigergraph.WithDebug(),
+	)
+
+	assert.Nil(t, client.Auth(context.Background(), graphName))
+
+	if assert.Len(t, logger.messages, 1) {
+		assert.Equal(t, "info", logger.messages[0].level)
+		assert.Equal(t, "TigerGraph request dump", logger.messages[0].msg)
+		assert.Contains(t, logger.messages[0].args, "response_body")
+	}
+}
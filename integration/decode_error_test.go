@@ -0,0 +1,54 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeError(t *testing.T) {
+	queryURL := "/query/" + graphName + "/people"
+
+	type person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+	srv.Mock(queryURL, func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write([]byte(`{"error": false, "results": [{"name": "a", "age": "unknown"}]}`))
+		assert.Nil(t, err)
+	})
+
+	client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+	var result tigergraph.TigerGraphResponse[person]
+	err := client.Get(context.Background(), queryURL, graphName, &result)
+
+	assert.ErrorIs(t, err, tigergraph.ErrDecodeFailed)
+	var decodeErr *tigergraph.DecodeError
+	if assert.True(t, errors.As(err, &decodeErr)) {
+		assert.Equal(t, "results[0].age", decodeErr.Path)
+		assert.Equal(t, "int", decodeErr.Expected)
+		assert.Equal(t, "string", decodeErr.Value)
+	}
+	assert.ErrorContains(t, err, "failed to decode response at results[0].age: expected int, got string")
+}
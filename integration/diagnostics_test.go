@@ -0,0 +1,186 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubLogSource struct {
+	logs map[string][]string
+	err  error
+}
+
+func (s *stubLogSource) RecentLogs(_ context.Context, component string, limit int) ([]string, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	lines := s.logs[component]
+	if len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+
+	return lines, nil
+}
+
+func TestDiagnostics(t *testing.T) { //nolint:funlen
+	queryURL := fmt.Sprintf("/query/%s/find_people", graphName)
+	loadingJobURL := fmt.Sprintf("/ddl/%s?tag=%s&filename=f", graphName, "load_people")
+	logURL := func(component string) string {
+		return fmt.Sprintf("%s?component=%s&limit=%d", tigergraph.LogURL, component, tigergraph.DefaultDiagnosticScanLines)
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "attaches log lines mentioning a failed query",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				srv.Mock(queryURL, func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				})
+				srv.Mock(logURL(tigergraph.LogComponentRESTPP), func(w http.ResponseWriter, r *http.Request) {
+					username, password, ok := r.BasicAuth()
+					assert.True(t, ok)
+					assert.Equal(t, expectedUsername, username)
+					assert.Equal(t, expectedPassword, password)
+					_, err := w.Write([]byte(`{"error": false, "results": [
+						"I0601 other_query ok",
+						"E0601 find_people: vertex type Person not found"
+					]}`))
+					assert.Nil(t, err)
+				})
+				srv.MockResponse(logURL(tigergraph.LogComponentGPE), map[string]any{"results": []string{"I0601 idle"}})
+
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithDiagnostics(tigergraph.Diagnostics{}),
+				)
+
+				var result map[string]any
+				err := client.Get(context.Background(), queryURL, graphName, &result)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+
+				var diagnosticErr *tigergraph.DiagnosticError
+				if assert.ErrorAs(t, err, &diagnosticErr) {
+					assert.Equal(t, map[string][]string{
+						tigergraph.LogComponentRESTPP: {"E0601 find_people: vertex type Person not found"},
+					}, diagnosticErr.Logs)
+					assert.Nil(t, diagnosticErr.LogErr)
+				}
+				assert.ErrorContains(t, err, "recent restpp log lines:\n  E0601 find_people")
+
+				var opErr *tigergraph.OperationError
+				assert.ErrorAs(t, err, &opErr)
+			},
+		},
+		{
+			name: "attaches log lines mentioning a failed loading job from a custom source",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				srv.MockResponse(loadingJobURL, tigergraph.LoadingJobResponse{
+					Results: []tigergraph.LoadingJobResponseResult{{Statistics: tigergraph.LoadingJobStatistics{ValidLine: 0}}},
+				})
+
+				lines := []string{}
+				for i := 0; i < 5; i++ {
+					lines = append(lines, fmt.Sprintf("load_people line %d rejected", i))
+				}
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithDiagnostics(tigergraph.Diagnostics{
+						Source:     &stubLogSource{logs: map[string][]string{tigergraph.LogComponentRESTPP: lines}},
+						Components: []string{tigergraph.LogComponentRESTPP},
+						Lines:      2,
+					}),
+				)
+
+				err := client.RunLoadingJobJSONL(context.Background(), graphName, "load_people", []any{map[string]int{"id": 1}})
+				assert.ErrorIs(t, err, tigergraph.ErrLoadingJobPartialFailure)
+
+				var diagnosticErr *tigergraph.DiagnosticError
+				if assert.ErrorAs(t, err, &diagnosticErr) {
+					assert.Equal(t, []string{"load_people line 3 rejected", "load_people line 4 rejected"},
+						diagnosticErr.Logs[tigergraph.LogComponentRESTPP])
+				}
+				assert.Equal(t, 0, srv.CallCount(logURL(tigergraph.LogComponentRESTPP)))
+			},
+		},
+		{
+			name: "log failures are recorded without hiding the original error",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				srv.Mock(queryURL, func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+				})
+				logErr := errors.New("logs unavailable")
+
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithDiagnostics(tigergraph.Diagnostics{Source: &stubLogSource{err: logErr}}),
+				)
+
+				var result map[string]any
+				err := client.Post(context.Background(), queryURL, graphName, map[string]any{}, &result)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+
+				var diagnosticErr *tigergraph.DiagnosticError
+				if assert.ErrorAs(t, err, &diagnosticErr) {
+					assert.Empty(t, diagnosticErr.Logs)
+					assert.ErrorIs(t, diagnosticErr.LogErr, logErr)
+				}
+			},
+		},
+		{
+			name: "other calls and clients without diagnostics are unaffected",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithDiagnostics(tigergraph.Diagnostics{}),
+				)
+
+				var result map[string]any
+				err := client.Get(context.Background(), "/graph/"+graphName+"/vertices/Person", graphName, &result)
+				var diagnosticErr *tigergraph.DiagnosticError
+				assert.False(t, errors.As(err, &diagnosticErr))
+
+				plainClient := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+				err = plainClient.Get(context.Background(), queryURL, graphName, &result)
+				assert.False(t, errors.As(err, &diagnosticErr))
+
+				assert.Equal(t, 0, srv.CallCount(logURL(tigergraph.LogComponentRESTPP)))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			test.action(t, srv)
+		})
+	}
+}
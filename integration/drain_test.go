@@ -0,0 +1,152 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrain(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/slow_query"
+	upsertURL := tigergraph.UpsertURL + "/" + graphName
+
+	// mockSlowQuery mocks a query which responds once release is closed, closing started when
+	// it is called
+	mockSlowQuery := func(srv *MockTigerGraphServer) (started chan struct{}, release chan struct{}) {
+		started = make(chan struct{})
+		release = make(chan struct{})
+		srv.Mock(queryURL, func(w http.ResponseWriter, _ *http.Request) {
+			close(started)
+			<-release
+			_, _ = w.Write([]byte(`{"error": false, "results": []}`))
+		})
+		return started, release
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "waits for requests in flight and rejects new requests",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				started, release := mockSlowQuery(srv)
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+
+				queryErr := make(chan error)
+				go func() {
+					var result tigergraph.TigerGraphResponse[any]
+					queryErr <- client.Get(context.Background(), queryURL, graphName, &result)
+				}()
+				<-started
+
+				drainErr := make(chan error)
+				go func() {
+					drainErr <- client.Drain(context.Background())
+				}()
+
+				assert.Eventually(t, func() bool {
+					return client.Auth(context.Background(), "OtherGraph") != nil
+				}, time.Second, time.Millisecond)
+				err := client.Auth(context.Background(), "OtherGraph")
+				assert.ErrorIs(t, err, tigergraph.ErrClientClosed)
+
+				select {
+				case <-drainErr:
+					t.Error("drain finished with a request in flight")
+				default:
+				}
+
+				close(release)
+				assert.Nil(t, <-queryErr)
+				assert.Nil(t, <-drainErr)
+			},
+		},
+		{
+			name: "gives up when the context is done",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				started, release := mockSlowQuery(srv)
+				defer close(release)
+
+				go func() {
+					var result tigergraph.TigerGraphResponse[any]
+					_ = client.Get(context.Background(), queryURL, graphName, &result)
+				}()
+				<-started
+
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+
+				err := client.Drain(ctx)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				assert.ErrorContains(t, err, "1 request(s) still in flight")
+			},
+		},
+		{
+			name: "flushes the write-ahead log and allows Close",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(upsertURL, tigergraph.UpsertResponse{
+					Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+				})
+				srv.Mock(tigergraph.RequestTokenURL, func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodDelete {
+						assert.Nil(t, json.NewEncoder(w).Encode(tigergraph.RequestTokenResponse{}))
+						return
+					}
+					makeDefaultRequestTokenHandler(expectedUsername, expectedPassword, time.Now().Add(time.Hour).Unix())(w, r)
+				})
+
+				wal, err := tigergraph.OpenWAL(t.TempDir())
+				assert.Nil(t, err)
+				client.WAL = wal
+				assert.Nil(t, wal.Append(tigergraph.WALEntry{
+					Key:     "a",
+					Kind:    tigergraph.WALEntryUpsert,
+					Graph:   graphName,
+					Payload: json.RawMessage(`{}`),
+				}))
+
+				assert.Nil(t, client.Drain(context.Background()))
+				assert.Equal(t, 1, srv.CallCount(upsertURL))
+				pending, err := wal.Len()
+				assert.Nil(t, err)
+				assert.Equal(t, 0, pending)
+
+				assert.Nil(t, client.Close(context.Background()))
+				assert.Equal(t, 2, srv.CallCount(tigergraph.RequestTokenURL))
+
+				_, err = client.Upsert(context.Background(), graphName, map[string]any{})
+				assert.ErrorIs(t, err, tigergraph.ErrClientClosed)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdgeDegree(t *testing.T) { //nolint:funlen
+	edgesURL := "/graph/" + graphName + "/edges/Person/1/"
+	metadataURL := tigergraph.GetGraphMetadataQueryURL + "?graph=" + graphName
+
+	metadataResponse := tigergraph.GraphMetadataResponse{
+		Results: &tigergraph.GraphMetadataResponseResult{
+			GraphName: graphName,
+			EdgeTypes: []tigergraph.GraphMetadataEdgeType{
+				{Name: "Knows", IsDirected: true, Config: map[string]string{"REVERSE_EDGE": "KnownBy"}},
+				{Name: "Follows", IsDirected: true, Config: map[string]string{}},
+				{Name: "Friend", IsDirected: false},
+			},
+		},
+	}
+
+	countResponse := func(counts ...int) tigergraph.TigerGraphResponse[tigergraph.EdgeCountResult] {
+		response := tigergraph.TigerGraphResponse[tigergraph.EdgeCountResult]{}
+		for _, count := range counts {
+			response.Results = append(response.Results, tigergraph.EdgeCountResult{Count: count})
+		}
+		return response
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "outgoing degree",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(edgesURL+"Knows?count_only=true", countResponse(3))
+
+				degree, err := client.GetVertexDegree(
+					context.Background(), graphName, "Person", "1", "Knows", tigergraph.EdgeDirectionOutgoing,
+				)
+				assert.Nil(t, err)
+				assert.Equal(t, 3, degree)
+			},
+		},
+		{
+			name: "incoming degree uses the reverse edge type from the schema",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(metadataURL, metadataResponse)
+				srv.MockResponse(edgesURL+"KnownBy?count_only=true", countResponse(2))
+
+				degree, err := client.GetVertexDegree(
+					context.Background(), graphName, "Person", "1", "Knows", tigergraph.EdgeDirectionIncoming,
+				)
+				assert.Nil(t, err)
+				assert.Equal(t, 2, degree)
+			},
+		},
+		{
+			name: "incoming degree of an undirected edge type counts the edge type itself",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(metadataURL, metadataResponse)
+				srv.MockResponse(edgesURL+"Friend?count_only=true", countResponse(4))
+
+				degree, err := client.GetVertexDegree(
+					context.Background(), graphName, "Person", "1", "Friend", tigergraph.EdgeDirectionIncoming,
+				)
+				assert.Nil(t, err)
+				assert.Equal(t, 4, degree)
+			},
+		},
+		{
+			name: "incoming degree of a directed edge type without a reverse edge",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(metadataURL, metadataResponse)
+
+				_, err := client.GetVertexDegree(
+					context.Background(), graphName, "Person", "1", "Follows", tigergraph.EdgeDirectionIncoming,
+				)
+				assert.ErrorIs(t, err, tigergraph.ErrNoReverseEdge)
+			},
+		},
+		{
+			name: "incoming degree of an edge type which is not in the schema",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(metadataURL, metadataResponse)
+
+				_, err := client.GetVertexDegree(
+					context.Background(), graphName, "Person", "1", "Likes", tigergraph.EdgeDirectionIncoming,
+				)
+				assert.ErrorIs(t, err, tigergraph.ErrUnknownEdgeType)
+				assert.NotErrorIs(t, err, tigergraph.ErrNoReverseEdge)
+			},
+		},
+		{
+			name: "degrees of several vertices look up the schema once",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(metadataURL, metadataResponse)
+				srv.MockResponse(edgesURL+"KnownBy?count_only=true", countResponse(2))
+				srv.MockResponse("/graph/"+graphName+"/edges/Person/2/KnownBy?count_only=true", countResponse(5))
+				srv.MockResponse("/graph/"+graphName+"/edges/Person/3/KnownBy?count_only=true", countResponse(0))
+
+				degrees, err := client.GetVertexDegrees(
+					context.Background(), graphName, "Person", []string{"1", "2", "3"}, "Knows", tigergraph.EdgeDirectionIncoming,
+				)
+				assert.Nil(t, err)
+				assert.Equal(t, map[string]int{"1": 2, "2": 5, "3": 0}, degrees)
+				assert.Equal(t, 1, srv.CallCount(metadataURL))
+			},
+		},
+		{
+			name: "degrees of several vertices fail when one lookup fails",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(edgesURL+"Knows?count_only=true", countResponse(2))
+				srv.Mock("/graph/"+graphName+"/edges/Person/2/Knows?count_only=true", func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+				})
+
+				degrees, err := client.GetVertexDegrees(
+					context.Background(), graphName, "Person", []string{"1", "2"}, "Knows", tigergraph.EdgeDirectionOutgoing,
+				)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Contains(t, err.Error(), "vertex 2")
+				assert.Nil(t, degrees)
+			},
+		},
+		{
+			name: "any edge type sums every result",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(edgesURL+"_?count_only=true", countResponse(2, 5))
+
+				degrees, err := client.GetVertexDegrees(
+					context.Background(), graphName, "Person", []string{"1"}, tigergraph.AnyEdgeType, tigergraph.EdgeDirectionOutgoing,
+				)
+				assert.Nil(t, err)
+				assert.Equal(t, map[string]int{"1": 7}, degrees)
+			},
+		},
+		{
+			name: "edge exists",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(edgesURL+"Knows/Person/2?count_only=true", countResponse(1))
+				srv.MockResponse(edgesURL+"Knows/Person/3?count_only=true", countResponse(0))
+
+				ctx := context.Background()
+				exists, err := client.EdgeExists(ctx, graphName, "Person", "1", "Knows", "Person", "2")
+				assert.Nil(t, err)
+				assert.True(t, exists)
+
+				exists, err = client.EdgeExists(ctx, graphName, "Person", "1", "Knows", "Person", "3")
+				assert.Nil(t, err)
+				assert.False(t, exists)
+			},
+		},
+		{
+			name: "error response",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(edgesURL+"Knows?count_only=true", func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+				})
+
+				_, err := client.GetVertexDegree(
+					context.Background(), graphName, "Person", "1", "Knows", tigergraph.EdgeDirectionOutgoing,
+				)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type callAttributes struct {
+	At string `json:"at"`
+}
+
+func TestGetEdges(t *testing.T) { //nolint:funlen
+	edgesURL := "/graph/" + graphName + "/edges/Person/1/"
+
+	callEdge := func(to string, at string) tigergraph.ResponseEdge[callAttributes] {
+		return tigergraph.ResponseEdge[callAttributes]{
+			EType:      "Called",
+			FromID:     "1",
+			FromType:   "Person",
+			ToID:       to,
+			ToType:     "Person",
+			Directed:   true,
+			Attributes: callAttributes{At: at},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "returns every edge between the same vertices",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				edges := []tigergraph.ResponseEdge[callAttributes]{
+					callEdge("2", "2023-06-01 09:00:00"),
+					callEdge("2", "2023-06-02 09:00:00"),
+				}
+				srv.MockResponse(edgesURL+"Called/Person/2", tigergraph.TigerGraphResponse[tigergraph.ResponseEdge[callAttributes]]{
+					Results: edges,
+				})
+
+				result, err := tigergraph.GetEdges[callAttributes](
+					context.Background(), client, graphName, "Person", "1", "Called", tigergraph.ToVertex("Person", "2"),
+				)
+				assert.Nil(t, err)
+				assert.Equal(t, edges, result)
+				assert.True(t, result[0].SameEndpoints(result[1]))
+			},
+		},
+		{
+			name: "filters by discriminator",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				edges := []tigergraph.ResponseEdge[callAttributes]{callEdge("2", "2023-06-02 09:00:00")}
+				query := url.Values{}
+				query.Set("filter", `at=="2023-06-02 09:00:00"`)
+				query.Set("limit", "1")
+				srv.MockResponse(edgesURL+"Called/Person/2?"+query.Encode(), tigergraph.TigerGraphResponse[tigergraph.ResponseEdge[callAttributes]]{
+					Results: edges,
+				})
+
+				result, err := tigergraph.GetEdges[callAttributes](
+					context.Background(), client, graphName, "Person", "1", "Called",
+					tigergraph.ToVertex("Person", "2"),
+					tigergraph.WithDiscriminator(map[string]any{"at": "2023-06-02 09:00:00"}),
+					tigergraph.EdgeLimit(1),
+				)
+				assert.Nil(t, err)
+				assert.Equal(t, edges, result)
+			},
+		},
+		{
+			name: "upserts several discriminated edges between the same vertices",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(tigergraph.UpsertURL+"/"+graphName, tigergraph.UpsertResponse{
+					Results: []tigergraph.UpsertResponseResult{{AcceptedEdges: 2}},
+				})
+
+				payload := tigergraph.NewUpsertPayload().
+					AddEdge("Person", "1", "Called", "Person", "2", map[string]any{"at": "2023-06-01 09:00:00"}).
+					AddEdge("Person", "1", "Called", "Person", "2", map[string]any{"at": "2023-06-02 09:00:00"})
+
+				result, err := client.Upsert(context.Background(), graphName, payload)
+				assert.Nil(t, err)
+				assert.Equal(t, 2, result.AcceptedEdges)
+			},
+		},
+		{
+			name: "error response",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(edgesURL+"Called", func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+				})
+
+				_, err := tigergraph.GetEdges[callAttributes](context.Background(), client, graphName, "Person", "1", "Called")
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/adarga-ai/go-tigergraph/tigergraphtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedUpserts(t *testing.T) { //nolint:funlen
+	schema := &tigergraph.GraphMetadataResponseResult{
+		GraphName: graphName,
+		VertexTypes: []tigergraph.GraphMetadataVertexType{
+			{
+				Name:       "Person",
+				PrimaryID:  tigergraph.GraphMetadataVertexTypePrimaryID{AttributeType: tigergraph.GraphMetadataAttributeType{Name: "STRING"}},
+				Attributes: []tigergraph.GraphMetadataAttribute{{AttributeName: "born", AttributeType: tigergraph.GraphMetadataAttributeType{Name: "DATETIME"}}},
+			},
+		},
+		EdgeTypes: []tigergraph.GraphMetadataEdgeType{
+			{Name: "knows", IsDirected: true, FromVertexTypeName: "Person", ToVertexTypeName: "Person"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		batchSize int
+		expected  []string
+	}{
+		{name: "upserts vertices then edges in batches", batchSize: 2, expected: []string{"vertices", "vertices", "edges", "edges"}},
+		{name: "upserts everything at once", batchSize: 0, expected: []string{"vertices+edges"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			batches := make([]string, 0)
+			srv.Mock(tigergraph.UpsertURL+"/"+graphName, func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]map[string]map[string]any
+				assert.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+
+				batch := ""
+				if vertices, ok := body["vertices"]; ok {
+					batch = "vertices"
+					for _, attributes := range vertices["Person"] {
+						// Times are sent as DATETIME strings, as by any other upsert
+						born := attributes.(map[string]any)["born"].(map[string]any)["value"]
+						assert.IsType(t, "", born)
+					}
+				}
+				if _, ok := body["edges"]; ok {
+					if batch != "" {
+						batch += "+"
+					}
+					batch += "edges"
+				}
+				batches = append(batches, batch)
+
+				_, err := w.Write([]byte(`{"error": false, "results": [{}]}`))
+				assert.Nil(t, err)
+			})
+
+			dataset, err := tigergraphtest.Generate(schema, map[string]int{"Person": 3, "knows": 4}, tigergraphtest.WithSeed(1))
+			assert.Nil(t, err)
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+			assert.Nil(t, tigergraphtest.Upsert(context.Background(), client, graphName, dataset, test.batchSize))
+			assert.Equal(t, test.expected, batches)
+		})
+	}
+}
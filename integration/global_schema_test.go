@@ -0,0 +1,129 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobalSchemaChanges(t *testing.T) { //nolint:funlen
+	globalGSQL := "CREATE VERTEX Person (PRIMARY_ID id STRING)\nCREATE GRAPH People(Person)"
+	successResponseString := fmt.Sprintf("Installing query...\n\n%s\n", tigergraph.SuccessString)
+
+	mockSuccessfulGSQL := func(t *testing.T, srv *MockTigerGraphServer) {
+		t.Helper()
+		srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+			_, err := w.Write([]byte(successResponseString))
+			assert.Nil(t, err)
+		})
+	}
+
+	tests := []struct {
+		name   string
+		opts   []tigergraph.Option
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "global schema changes are refused before they are run",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockSuccessfulGSQL(t, srv)
+
+				err := client.RunGSQL(context.Background(), globalGSQL)
+				assert.ErrorIs(t, err, tigergraph.ErrGlobalSchemaChange)
+				assert.ErrorContains(t, err, "CREATE VERTEX Person at line 1")
+
+				var globalErr *tigergraph.GlobalSchemaChangeError
+				if assert.ErrorAs(t, err, &globalErr) {
+					assert.Len(t, globalErr.Statements, 1)
+				}
+				assert.Equal(t, 0, srv.CallCount(tigergraph.FileURL))
+			},
+		},
+		{
+			name: "graph-local schema changes are run",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockSuccessfulGSQL(t, srv)
+
+				err := client.RunGSQL(context.Background(), `CREATE SCHEMA_CHANGE JOB j FOR GRAPH People {
+    ADD VERTEX Company (PRIMARY_ID id STRING);
+}
+RUN SCHEMA_CHANGE JOB j`)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, srv.CallCount(tigergraph.FileURL))
+			},
+		},
+		{
+			name: "a single call may change the global schema",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockSuccessfulGSQL(t, srv)
+
+				err := client.RunGSQL(context.Background(), globalGSQL, tigergraph.AllowGlobalSchemaChange())
+				assert.Nil(t, err)
+				assert.Equal(t, 1, srv.CallCount(tigergraph.FileURL))
+			},
+		},
+		{
+			name: "the client may allow global schema changes",
+			opts: []tigergraph.Option{tigergraph.WithGlobalSchemaChanges()},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockSuccessfulGSQL(t, srv)
+
+				err := client.RunGSQL(context.Background(), globalGSQL)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, srv.CallCount(tigergraph.FileURL))
+			},
+		},
+		{
+			name: "migrate runs none of the migrations if any changes the global schema",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				dir := t.TempDir()
+				assert.Nil(t, os.WriteFile(filepath.Join(dir, "000_first.up.gsql"), []byte("CREATE GRAPH People()"), 0o600))
+				assert.Nil(t, os.WriteFile(filepath.Join(dir, "001_second.up.gsql"), []byte(globalGSQL), 0o600))
+
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+					Results: &tigergraph.GraphMetadataResponseResult{GraphName: tigergraph.MetadataGraphName},
+				})
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, tigergraph.CurrentMigrationVersionResponse{
+					Results: []tigergraph.CurrentMigrationVersionResponseResult{{}},
+				})
+				mockSuccessfulGSQL(t, srv)
+
+				err := client.Migrate(context.Background(), "People", "001", "", dir, false)
+				assert.ErrorIs(t, err, tigergraph.ErrGlobalSchemaChange)
+				assert.ErrorContains(t, err, "001_second.up.gsql")
+				assert.Equal(t, 0, srv.CallCount(tigergraph.FileURL))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			opts := append([]tigergraph.Option{tigergraph.WithCredentials(expectedUsername, expectedPassword)}, test.opts...)
+			client := tigergraph.NewClient(srv.HTTPServer.URL, opts...)
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobalToken(t *testing.T) { //nolint:funlen
+	adminURL := "/gsqlserver/gsql/users"
+
+	// mockTokens issues a token naming the graph it is bound to, or "global"
+	mockTokens := func(t *testing.T, srv *MockTigerGraphServer) {
+		t.Helper()
+		srv.Mock(tigergraph.RequestTokenURL, func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.Nil(t, err)
+
+			var request map[string]string
+			assert.Nil(t, json.Unmarshal(body, &request))
+
+			token := "global"
+			if graph, ok := request["graph"]; ok {
+				token = graph
+			}
+
+			assert.Nil(t, json.NewEncoder(w).Encode(tigergraph.RequestTokenResponse{
+				ExpirationSecondsSinceEpoch: time.Now().Add(time.Hour).Unix(),
+				Results:                     tigergraph.RequestTokenResponseResults{Token: token},
+			}))
+		})
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "global tokens are requested without a graph and cached apart from graph tokens",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockTokens(t, srv)
+
+				assert.Nil(t, client.AuthGlobal(context.Background()))
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Nil(t, client.AuthGlobal(context.Background()))
+
+				calls := srv.Calls[tigergraph.RequestTokenURL]
+				if !assert.Len(t, calls, 2) {
+					return
+				}
+				body, err := io.ReadAll(calls[0])
+				assert.Nil(t, err)
+				assert.JSONEq(t, `{}`, string(body))
+
+				globalToken, ok := client.Tokens.Get(tigergraph.GlobalGraph)
+				assert.True(t, ok)
+				assert.Equal(t, "global", globalToken.Value)
+
+				graphToken, ok := client.Tokens.Get(graphName)
+				assert.True(t, ok)
+				assert.Equal(t, graphName, graphToken.Value)
+			},
+		},
+		{
+			name: "admin requests use the global token",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockTokens(t, srv)
+				srv.Mock(adminURL, func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, "Bearer global", r.Header.Get("Authorization"))
+					_, err := w.Write([]byte(`{"error": false, "results": []}`))
+					assert.Nil(t, err)
+				})
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.GetGlobal(context.Background(), adminURL, &result))
+				assert.Nil(t, client.PostGlobal(context.Background(), adminURL, map[string]string{}, &result))
+
+				assert.Equal(t, 2, srv.CallCount(adminURL))
+				assert.Equal(t, 1, srv.CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
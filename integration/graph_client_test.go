@@ -0,0 +1,105 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForGraph(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/my_query"
+	upsertURL := tigergraph.UpsertURL + "/" + graphName
+	loadingJobURL := fmt.Sprintf("/ddl/%s?tag=%s&filename=f", graphName, "test_loading_job")
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "calls are made for the bound graph",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(queryURL, tigergraph.TigerGraphResponse[any]{})
+				srv.MockResponse(upsertURL, tigergraph.UpsertResponse{
+					Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+				})
+				srv.MockResponse(loadingJobURL, tigergraph.LoadingJobResponse{
+					Results: []tigergraph.LoadingJobResponseResult{
+						{Statistics: tigergraph.LoadingJobStatistics{ValidLine: 1}},
+					},
+				})
+
+				graph := client.ForGraph(graphName)
+				assert.Equal(t, graphName, graph.Graph())
+				assert.Same(t, client, graph.Client())
+
+				ctx := context.Background()
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, graph.Get(ctx, queryURL, &result))
+				assert.Nil(t, graph.Post(ctx, queryURL, map[string]any{}, &result))
+
+				upserted, err := graph.Upsert(ctx, client.NewUpsertPayload().AddVertex("Person", "1", map[string]any{"name": "Ada"}))
+				assert.Nil(t, err)
+				assert.Equal(t, 1, upserted.AcceptedVertices)
+
+				assert.Nil(t, graph.RunLoadingJobJSONL(ctx, "test_loading_job", []any{map[string]any{"id": "1"}}))
+
+				assert.Equal(t, 2, srv.CallCount(queryURL))
+				assert.Equal(t, 1, srv.CallCount(upsertURL))
+				assert.Equal(t, 1, srv.CallCount(loadingJobURL))
+			},
+		},
+		{
+			name: "bound clients share the parent's tokens",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(queryURL, tigergraph.TigerGraphResponse[any]{})
+
+				ctx := context.Background()
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(ctx, queryURL, graphName, &result))
+				assert.Nil(t, client.ForGraph(graphName).Get(ctx, queryURL, &result))
+				assert.Nil(t, client.ForGraph(graphName).Get(ctx, queryURL, &result))
+
+				assert.Equal(t, 1, len(srv.Calls[tigergraph.RequestTokenURL]))
+			},
+		},
+		{
+			name: "errors name the bound graph",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.ForGraph(graphName).Get(context.Background(), "/query/"+graphName+"/missing", &result)
+
+				var opErr *tigergraph.OperationError
+				if assert.ErrorAs(t, err, &opErr) {
+					assert.Equal(t, graphName, opErr.Graph)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
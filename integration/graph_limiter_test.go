@@ -0,0 +1,77 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphLimiter(t *testing.T) {
+	analyticsGraph := "Analytics"
+	lookupGraph := "Lookup"
+	heavyQueryURL := "/query/" + analyticsGraph + "/heavy_query"
+	lookupQueryURL := "/query/" + lookupGraph + "/lookup"
+
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	unblock := make(chan struct{})
+	srv.Mock(heavyQueryURL, func(w http.ResponseWriter, _ *http.Request) {
+		<-unblock
+		_, err := w.Write([]byte(`{"error": false}`))
+		assert.Nil(t, err)
+	})
+	srv.MockResponse(lookupQueryURL, tigergraph.TigerGraphResponse[any]{})
+
+	limiter := tigergraph.NewGraphLimiter(0, map[string]int{analyticsGraph: 1})
+	client := tigergraph.NewClient(
+		srv.HTTPServer.URL,
+		tigergraph.WithCredentials(expectedUsername, expectedPassword),
+		tigergraph.WithGraphLimiter(limiter),
+	)
+
+	ctx := context.Background()
+	assert.Nil(t, client.Auth(ctx, analyticsGraph))
+	assert.Nil(t, client.Auth(ctx, lookupGraph))
+
+	done := make(chan error)
+	go func() {
+		var result tigergraph.TigerGraphResponse[any]
+		done <- client.Get(ctx, heavyQueryURL, analyticsGraph, &result)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return srv.CallCount(heavyQueryURL) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 1, limiter.InFlight(analyticsGraph))
+
+	// A second heavy query waits for the first
+	var result tigergraph.TigerGraphResponse[any]
+	err := client.Get(ctx, heavyQueryURL, analyticsGraph, &result, tigergraph.WithRequestTimeout(20*time.Millisecond))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, srv.CallCount(heavyQueryURL))
+
+	// Other graphs are not affected
+	assert.Nil(t, client.Get(ctx, lookupQueryURL, lookupGraph, &result))
+
+	close(unblock)
+	assert.Nil(t, <-done)
+	assert.Equal(t, 0, limiter.InFlight(analyticsGraph))
+}
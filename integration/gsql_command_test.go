@@ -0,0 +1,113 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type gsqlCommandResponse struct {
+	Error   bool   `json:"error"`
+	Message string `json:"message"`
+}
+
+func TestGSQLCommand(t *testing.T) { //nolint:funlen
+	commandURL := "/gsqlserver/gsql/command"
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "success, sends form encoded body with basic auth",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(commandURL, func(w http.ResponseWriter, r *http.Request) {
+					username, password, ok := r.BasicAuth()
+					assert.True(t, ok)
+					assert.Equal(t, expectedUsername, username)
+					assert.Equal(t, expectedPassword, password)
+					assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+
+					_, err := w.Write([]byte(`{"error": false, "message": "ok"}`))
+					assert.Nil(t, err)
+				})
+
+				var result gsqlCommandResponse
+				err := client.GSQLCommand(context.Background(), commandURL, url.Values{"command": {"ls"}}, &result)
+				assert.Nil(t, err)
+				assert.Equal(t, "ok", result.Message)
+
+				calls := srv.Calls[commandURL]
+				assert.Len(t, calls, 1)
+
+				body, err := io.ReadAll(calls[0])
+				assert.Nil(t, err)
+				assert.Equal(t, "command=ls", string(body))
+			},
+		},
+		{
+			name: "session cookies are sent on subsequent commands",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				var receivedSessions []string
+				srv.Mock(commandURL, func(w http.ResponseWriter, r *http.Request) {
+					cookie, err := r.Cookie("session")
+					if err == nil {
+						receivedSessions = append(receivedSessions, cookie.Value)
+					}
+
+					http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+					_, err = w.Write([]byte(`{"error": false}`))
+					assert.Nil(t, err)
+				})
+
+				var result gsqlCommandResponse
+				ctx := context.Background()
+				assert.Nil(t, client.GSQLCommand(ctx, commandURL, nil, &result))
+				assert.Nil(t, client.GSQLCommand(ctx, commandURL, nil, &result))
+
+				assert.Equal(t, []string{"abc"}, receivedSessions)
+			},
+		},
+		{
+			name: "non OK http response code",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(commandURL, func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				})
+
+				var result gsqlCommandResponse
+				err := client.GSQLCommand(context.Background(), commandURL, nil, &result)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type gsqlSessionRequest struct {
+	gsql    string
+	session string
+}
+
+func TestGSQLSession(t *testing.T) { //nolint:funlen
+	tests := []struct {
+		name     string
+		opts     []tigergraph.Option
+		action   func(t *testing.T, client *tigergraph.TigerGraphClient)
+		expected []gsqlSessionRequest
+	}{
+		{
+			name: "keeps no state without a session",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				assert.Nil(t, client.RunGSQL(context.Background(), "USE GRAPH "+graphName))
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+			},
+			expected: []gsqlSessionRequest{
+				{gsql: "USE GRAPH " + graphName},
+				{gsql: "LS"},
+			},
+		},
+		{
+			name: "keeps cookies and the active graph across calls",
+			opts: []tigergraph.Option{tigergraph.WithGSQLSession(tigergraph.NewGSQLSession(""))},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				assert.Nil(t, client.RunGSQL(context.Background(), "USE GRAPH "+graphName))
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+				assert.Equal(t, graphName, client.GSQLSession.Graph())
+			},
+			expected: []gsqlSessionRequest{
+				{gsql: "USE GRAPH " + graphName},
+				{gsql: "USE GRAPH " + graphName + "\nLS", session: "1"},
+			},
+		},
+		{
+			name: "starts in the session's graph until USE GLOBAL",
+			opts: []tigergraph.Option{tigergraph.WithGSQLSession(tigergraph.NewGSQLSession(graphName))},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS\nuse global;"))
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+				assert.Equal(t, "", client.GSQLSession.Graph())
+			},
+			expected: []gsqlSessionRequest{
+				{gsql: "USE GRAPH " + graphName + "\nLS\nuse global;"},
+				{gsql: "LS", session: "1"},
+			},
+		},
+		{
+			name: "runs single calls in their own session",
+			opts: []tigergraph.Option{tigergraph.WithGSQLSession(tigergraph.NewGSQLSession(graphName))},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				other := tigergraph.NewGSQLSession("OtherGraph")
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS", tigergraph.InGSQLSession(other)))
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+			},
+			expected: []gsqlSessionRequest{
+				{gsql: "USE GRAPH OtherGraph\nLS"},
+				{gsql: "USE GRAPH " + graphName + "\nLS"},
+			},
+		},
+		{
+			name: "failed GSQL leaves the active graph unchanged",
+			opts: []tigergraph.Option{tigergraph.WithGSQLSession(tigergraph.NewGSQLSession(graphName))},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				assert.ErrorIs(t, client.RunGSQL(context.Background(), "USE GRAPH Missing\nfail"), tigergraph.ErrGSQLFailure)
+				assert.Equal(t, graphName, client.GSQLSession.Graph())
+			},
+			expected: []gsqlSessionRequest{
+				{gsql: "USE GRAPH " + graphName + "\nUSE GRAPH Missing\nfail"},
+			},
+		},
+		{
+			name: "reset sessions start afresh",
+			opts: []tigergraph.Option{tigergraph.WithGSQLSession(tigergraph.NewGSQLSession(graphName))},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+				client.GSQLSession.Reset()
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+			},
+			expected: []gsqlSessionRequest{
+				{gsql: "USE GRAPH " + graphName + "\nLS"},
+				{gsql: "LS"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			requests := make([]gsqlSessionRequest, 0)
+			srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.Nil(t, err)
+				gsql, err := url.QueryUnescape(string(body))
+				assert.Nil(t, err)
+
+				request := gsqlSessionRequest{gsql: gsql}
+				if cookie, err := r.Cookie("session"); err == nil {
+					request.session = cookie.Value
+				}
+				requests = append(requests, request)
+
+				http.SetCookie(w, &http.Cookie{Name: "session", Value: "1"})
+				if strings.HasSuffix(gsql, "fail") {
+					fmt.Fprintf(w, "Graph Missing does not exist.\n__GSQL__RETURN__CODE__,1\n")
+					return
+				}
+				fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+			})
+
+			opts := append([]tigergraph.Option{tigergraph.WithCredentials(expectedUsername, expectedPassword)}, test.opts...)
+			client := tigergraph.NewClient(srv.HTTPServer.URL, opts...)
+
+			test.action(t, client)
+			assert.Equal(t, test.expected, requests)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaders(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/my_query"
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, headers *[]http.Header)
+	}{
+		{
+			name: "sends default headers with every request",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, _ *MockTigerGraphServer, headers *[]http.Header) {
+				ctx := context.Background()
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(ctx, queryURL, graphName, &result))
+				assert.Nil(t, client.Post(ctx, queryURL, graphName, map[string]any{}, &result))
+				assert.Nil(t, client.RunGSQL(ctx, "LS"))
+
+				assert.Len(t, *headers, 3)
+				for _, header := range *headers {
+					assert.Equal(t, "trace-1", header.Get("X-Trace-Id"))
+					assert.Equal(t, "", header.Get("GSQL-TIMEOUT"))
+				}
+			},
+		},
+		{
+			name: "per-call headers override default headers",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, _ *MockTigerGraphServer, headers *[]http.Header) {
+				ctx := context.Background()
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(
+					ctx, queryURL, graphName, &result,
+					tigergraph.WithRequestHeader("X-Trace-Id", "trace-2"),
+					tigergraph.WithRequestHeader("GSQL-TIMEOUT", "1000"),
+				))
+				assert.Nil(t, client.PostRaw(
+					ctx, queryURL, graphName, []byte(`{}`), &result,
+					tigergraph.WithRequestHeader("RESPONSE-LIMIT", "100"),
+				))
+
+				assert.Len(t, *headers, 2)
+				assert.Equal(t, "trace-2", (*headers)[0].Get("X-Trace-Id"))
+				assert.Equal(t, "1000", (*headers)[0].Get("GSQL-TIMEOUT"))
+				assert.Equal(t, "trace-1", (*headers)[1].Get("X-Trace-Id"))
+				assert.Equal(t, "100", (*headers)[1].Get("RESPONSE-LIMIT"))
+			},
+		},
+		{
+			name: "default headers do not replace the authorization header",
+			action: func(t *testing.T, _ *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, headers *[]http.Header) {
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithHeader("Authorization", "Bearer gateway"),
+				)
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+
+				assert.Len(t, *headers, 1)
+				assert.NotEqual(t, "Bearer gateway", (*headers)[0].Get("Authorization"))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			headers := make([]http.Header, 0)
+			srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+				headers = append(headers, r.Header.Clone())
+				_, err := w.Write([]byte(`{"error": false, "results": []}`))
+				assert.Nil(t, err)
+			})
+			srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+				headers = append(headers, r.Header.Clone())
+				_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+				assert.Nil(t, err)
+			})
+
+			client := tigergraph.NewClient(
+				srv.HTTPServer.URL,
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				tigergraph.WithHeader("X-Trace-Id", "trace-1"),
+			)
+
+			test.action(t, client, srv, &headers)
+		})
+	}
+}
+
+func TestRoutingHeaders(t *testing.T) { //nolint:funlen
+	edgesURL := fmt.Sprintf(tigergraph.EdgesURLTemplate, graphName, "Person", "1", "knows")
+	ctx := context.Background()
+	replica := tigergraph.WithGSQLReplica(2)
+
+	tests := []struct {
+		name     string
+		url      string
+		response string
+		action   func(client *tigergraph.TigerGraphClient) error
+	}{
+		{
+			name:     "upserts",
+			url:      tigergraph.UpsertURL + "/" + graphName,
+			response: `{"error": false, "results": [{"accepted_vertices": 1}]}`,
+			action: func(client *tigergraph.TigerGraphClient) error {
+				_, err := client.Upsert(ctx, graphName, map[string]any{}, replica)
+				return err
+			},
+		},
+		{
+			name:     "loading jobs",
+			url:      fmt.Sprintf(tigergraph.LoadingJobURLTemplate, graphName, "load_people"),
+			response: `{"error": false, "results": [{"statistics": {"validLine": 1}}]}`,
+			action: func(client *tigergraph.TigerGraphClient) error {
+				return client.RunLoadingJobJSONL(ctx, graphName, "load_people", []any{map[string]any{"id": "1"}}, replica)
+			},
+		},
+		{
+			name:     "edges",
+			url:      edgesURL,
+			response: `{"error": false, "results": []}`,
+			action: func(client *tigergraph.TigerGraphClient) error {
+				_, err := tigergraph.GetEdges[any](ctx, client, graphName, "Person", "1", "knows", tigergraph.EdgeRequestOptions(replica))
+				return err
+			},
+		},
+		{
+			name:     "vertex degrees",
+			url:      edgesURL + "?count_only=true",
+			response: `{"error": false, "results": [{"e_type": "knows", "count": 1}]}`,
+			action: func(client *tigergraph.TigerGraphClient) error {
+				_, err := client.GetVertexDegree(ctx, graphName, "Person", "1", "knows", tigergraph.EdgeDirectionOutgoing, replica)
+				return err
+			},
+		},
+		{
+			name:     "vertices by ID",
+			url:      fmt.Sprintf(tigergraph.QueryURLTemplate, graphName, tigergraph.VerticesByIDsQueryName),
+			response: `{"error": false, "results": [{"vertices": []}]}`,
+			action: func(client *tigergraph.TigerGraphClient) error {
+				_, err := tigergraph.GetVerticesByIDs[any](ctx, client, graphName, "Person", []string{"1"}, replica)
+				return err
+			},
+		},
+		{
+			name:     "vertices",
+			url:      fmt.Sprintf(tigergraph.VerticesURLTemplate, graphName, "Person"),
+			response: `{"error": false, "results": []}`,
+			action: func(client *tigergraph.TigerGraphClient) error {
+				_, err := tigergraph.GetVertices[tigergraph.SoftDeleteFields](
+					ctx, client, graphName, "Person", tigergraph.ReadRequestOptions(replica),
+				)
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			headers := make([]http.Header, 0)
+			srv.Mock(test.url, func(w http.ResponseWriter, r *http.Request) {
+				headers = append(headers, r.Header.Clone())
+				_, err := w.Write([]byte(test.response))
+				assert.Nil(t, err)
+			})
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			assert.Nil(t, test.action(client))
+			assert.Len(t, headers, 1)
+			for _, header := range headers {
+				assert.Equal(t, "2", header.Get(tigergraph.ReplicaHeader))
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockGSQLVersion(srv *MockTigerGraphServer) {
+	srv.Mock(tigergraph.GSQLVersionURL, func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != expectedUsername || password != expectedPassword {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestHealthcheck(t *testing.T) { //nolint:funlen
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "all checks pass",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockGSQLVersion(srv)
+
+				report := client.Healthcheck(context.Background(), graphName)
+				assert.True(t, report.Healthy)
+				assert.Empty(t, report.Failed())
+
+				names := []string{}
+				for _, check := range report.Checks {
+					names = append(names, check.Name)
+					assert.Equal(t, tigergraph.HealthOK, check.Status)
+				}
+				assert.Equal(t, []string{"ping", "gsql", "token " + graphName}, names)
+
+				assert.Len(t, srv.Calls[tigergraph.PingURL], 1)
+				assert.Len(t, srv.Calls[tigergraph.GSQLVersionURL], 1)
+				assert.Len(t, srv.Calls[tigergraph.RequestTokenURL], 1)
+			},
+		},
+		{
+			name: "token check requests a new token",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockGSQLVersion(srv)
+
+				store := tigergraph.NewTokenCache()
+				client.TokenStore = store
+				cached := &tigergraph.Token{Value: "cached", Expires: time.Now().Add(time.Hour)}
+				client.Tokens.Set(graphName, cached)
+				store.Set(tigergraph.TokenStoreKey(srv.HTTPServer.URL, expectedUsername, graphName), cached)
+
+				report := client.Healthcheck(context.Background(), graphName)
+				assert.True(t, report.Healthy)
+				assert.Len(t, srv.Calls[tigergraph.RequestTokenURL], 1)
+
+				token, ok := client.Tokens.Get(graphName)
+				assert.True(t, ok)
+				assert.Equal(t, "sometoken", token.Value)
+			},
+		},
+		{
+			name: "token check skipped without graphs",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockGSQLVersion(srv)
+
+				report := client.Healthcheck(context.Background())
+				assert.True(t, report.Healthy)
+				assert.Len(t, report.Checks, 2)
+				assert.Len(t, srv.Calls[tigergraph.RequestTokenURL], 0)
+			},
+		},
+		{
+			name: "runs every check when the ping fails",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockGSQLVersion(srv)
+				srv.Mock(tigergraph.PingURL, func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				})
+
+				report := client.Healthcheck(context.Background(), graphName)
+				assert.False(t, report.Healthy)
+
+				failed := report.Failed()
+				if assert.Len(t, failed, 1) {
+					assert.Equal(t, "ping", failed[0].Name)
+					assert.Equal(t, tigergraph.HealthFailed, failed[0].Status)
+					assert.ErrorIs(t, failed[0].Err, tigergraph.ErrNonOK)
+					assert.NotEmpty(t, failed[0].Error)
+				}
+			},
+		},
+		{
+			name: "bad credentials fail the gsql and token checks",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockGSQLVersion(srv)
+				client.BasicAuthPassword = "wrong"
+
+				report := client.Healthcheck(context.Background(), graphName)
+				assert.False(t, report.Healthy)
+
+				names := []string{}
+				for _, check := range report.Failed() {
+					names = append(names, check.Name)
+				}
+				assert.Equal(t, []string{"gsql", "token " + graphName}, names)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
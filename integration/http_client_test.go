@@ -0,0 +1,124 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingTransport struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	r.paths = append(r.paths, req.URL.Path)
+	r.mu.Unlock()
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestHTTPClient(t *testing.T) {
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	transport := &recordingTransport{}
+	client := tigergraph.NewClient(
+		srv.HTTPServer.URL,
+		tigergraph.WithCredentials(expectedUsername, expectedPassword),
+		tigergraph.WithHTTPClient(&http.Client{Transport: transport}),
+	)
+
+	queryURL := "/query/" + graphName + "/my_query"
+	srv.MockResponse(queryURL, tigergraph.TigerGraphResponse[any]{Message: "ok"})
+	srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+		assert.Nil(t, err)
+	})
+
+	ctx := context.Background()
+	var result tigergraph.TigerGraphResponse[any]
+	assert.Nil(t, client.Get(ctx, queryURL, graphName, &result))
+	assert.Nil(t, client.Post(ctx, queryURL, graphName, map[string]string{}, &result))
+	assert.Nil(t, client.RunGSQL(ctx, "LS"))
+
+	assert.Equal(t, []string{tigergraph.RequestTokenURL, queryURL, queryURL, tigergraph.FileURL}, transport.paths)
+}
+
+func TestHTTPClientDialer(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []tigergraph.Option
+	}{
+		{name: "uses the HTTP client as it is"},
+		{name: "keeps the dialer when configuring a copy of the transport", opts: []tigergraph.Option{
+			tigergraph.WithConnectionPool(tigergraph.ConnectionPool{MaxConnsPerHost: 4}),
+		}},
+		{name: "keeps the dialer when bounding the connect timeout", opts: []tigergraph.Option{
+			tigergraph.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}),
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			// The dialer sends every connection to the mock server, as a tunnel or a custom
+			// resolver would, so the client's base URL does not resolve by itself
+			var mu sync.Mutex
+			dialed := make([]string, 0)
+			dialer := &net.Dialer{}
+			httpClient := &http.Client{Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network string, addr string) (net.Conn, error) {
+					mu.Lock()
+					dialed = append(dialed, addr)
+					mu.Unlock()
+
+					return dialer.DialContext(ctx, network, srv.HTTPServer.Listener.Addr().String())
+				},
+			}}
+
+			opts := append([]tigergraph.Option{
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				tigergraph.WithHTTPClient(httpClient),
+			}, test.opts...)
+			client := tigergraph.NewClient("http://tigergraph.invalid:9000", opts...)
+
+			queryURL := "/query/" + graphName + "/my_query"
+			srv.MockResponse(queryURL, tigergraph.TigerGraphResponse[any]{Message: "ok"})
+
+			var result tigergraph.TigerGraphResponse[any]
+			assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.NotEmpty(t, dialed)
+			assert.Equal(t, "tigergraph.invalid:9000", dialed[0])
+			if len(test.opts) == 0 {
+				assert.Same(t, httpClient, client.HTTPClient)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+// countryNames is a consumer which only needs to run queries
+type countryNames struct {
+	querier tigergraph.Querier
+}
+
+func (c *countryNames) list(ctx context.Context) ([]string, error) {
+	var response tigergraph.TigerGraphResponse[string]
+	if err := c.querier.Get(ctx, "/query/"+graphName+"/country_names", graphName, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Results, nil
+}
+
+// fakeQuerier implements Querier by embedding it, overriding only Get
+type fakeQuerier struct {
+	tigergraph.Querier
+	names []string
+	err   error
+}
+
+func (f *fakeQuerier) Get(_ context.Context, _ string, _ string, result interface{}, _ ...tigergraph.RequestOption) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	result.(*tigergraph.TigerGraphResponse[string]).Results = f.names
+	return nil
+}
+
+func TestInterfaces(t *testing.T) {
+	errFake := errors.New("fake failure")
+
+	tests := []struct {
+		name          string
+		querier       func(srv *MockTigerGraphServer) tigergraph.Querier
+		expectedNames []string
+		expectedErr   error
+	}{
+		{
+			name: "the client is a Querier",
+			querier: func(srv *MockTigerGraphServer) tigergraph.Querier {
+				srv.MockResponse("/query/"+graphName+"/country_names", tigergraph.TigerGraphResponse[string]{
+					Results: []string{"France", "Spain"},
+				})
+				return tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+			},
+			expectedNames: []string{"France", "Spain"},
+		},
+		{
+			name: "fakes can replace the client",
+			querier: func(*MockTigerGraphServer) tigergraph.Querier {
+				return &fakeQuerier{names: []string{"Italy"}}
+			},
+			expectedNames: []string{"Italy"},
+		},
+		{
+			name: "fakes can return errors",
+			querier: func(*MockTigerGraphServer) tigergraph.Querier {
+				return &fakeQuerier{err: errFake}
+			},
+			expectedErr: errFake,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			consumer := &countryNames{querier: test.querier(srv)}
+			names, err := consumer.list(context.Background())
+
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expectedNames, names)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasureLatency(t *testing.T) {
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+	ctx := context.Background()
+
+	t.Run("invalid sample count", func(t *testing.T) {
+		stats, err := client.MeasureLatency(ctx, 0)
+		assert.ErrorIs(t, err, tigergraph.ErrInvalidSampleCount)
+		assert.Nil(t, stats)
+	})
+
+	t.Run("echo endpoint failing", func(t *testing.T) {
+		srv.Reset()
+		srv.Mock(tigergraph.EchoURL, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		stats, err := client.MeasureLatency(ctx, 3)
+		assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+		assert.Nil(t, stats)
+		assert.Nil(t, client.LastLatency())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		srv.Reset()
+		srv.MockResponse(tigergraph.EchoURL, tigergraph.EchoResponse{Message: "Hello GSQL"})
+
+		stats, err := client.MeasureLatency(ctx, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, stats.Samples)
+		assert.LessOrEqual(t, stats.Min, stats.Mean)
+		assert.LessOrEqual(t, stats.Mean, stats.Max)
+		assert.Len(t, srv.Calls[tigergraph.EchoURL], 3)
+		assert.Equal(t, stats, client.LastLatency())
+	})
+
+	t.Run("sends each request once with a token", func(t *testing.T) {
+		srv.Reset()
+		srv.Mock(tigergraph.EchoURL, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer sometoken", r.Header.Get("Authorization"))
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		retrying := tigergraph.NewClient(
+			srv.HTTPServer.URL,
+			tigergraph.WithCredentials(expectedUsername, expectedPassword),
+			tigergraph.WithRetryPolicy(&tigergraph.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+		)
+
+		_, err := retrying.MeasureLatency(ctx, 2)
+		assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+		assert.Len(t, srv.Calls[tigergraph.EchoURL], 1)
+	})
+
+	t.Run("concurrent measurements", func(t *testing.T) {
+		srv.Reset()
+		srv.MockResponse(tigergraph.EchoURL, tigergraph.EchoResponse{Message: "Hello GSQL"})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.MeasureLatency(ctx, 2)
+				assert.Nil(t, err)
+				assert.NotNil(t, client.LastLatency())
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 2, client.LastLatency().Samples)
+	})
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type loggedMessage struct {
+	level string
+	msg   string
+	args  []any
+}
+
+type recordingLevelLogger struct {
+	messages []loggedMessage
+}
+
+func (r *recordingLevelLogger) record(level string, msg string, args []any) {
+	r.messages = append(r.messages, loggedMessage{level: level, msg: msg, args: args})
+}
+
+func (r *recordingLevelLogger) Debug(msg string, args ...any) { r.record("debug", msg, args) }
+func (r *recordingLevelLogger) Info(msg string, args ...any)  { r.record("info", msg, args) }
+func (r *recordingLevelLogger) Warn(msg string, args ...any)  { r.record("warn", msg, args) }
+func (r *recordingLevelLogger) Error(msg string, args ...any) { r.record("error", msg, args) }
+
+func TestLogging(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/my_query"
+
+	tests := []struct {
+		name   string
+		levels *tigergraph.LogLevels
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, logger *recordingLevelLogger)
+	}{
+		{
+			name: "logs requests and GSQL output at debug by default",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, logger *recordingLevelLogger) {
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+
+				// The token request, the query and the GSQL request, then the GSQL output
+				assert.Len(t, logger.messages, 4)
+				for _, message := range logger.messages {
+					assert.Equal(t, "debug", message.level)
+				}
+
+				assert.Equal(t, "TigerGraph request completed", logger.messages[1].msg)
+				assert.Equal(t, []any{
+					"method", http.MethodGet,
+					"path", queryURL,
+					"status", http.StatusOK,
+					"attempts", 1,
+				}, logger.messages[1].args[:8])
+
+				assert.Equal(t, "GSQL output", logger.messages[3].msg)
+				assert.Contains(t, logger.messages[3].args[1], tigergraph.SuccessString)
+			},
+		},
+		{
+			name:   "levels are configurable",
+			levels: &tigergraph.LogLevels{Request: tigergraph.LogLevelInfo},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, logger *recordingLevelLogger) {
+				assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+
+				assert.Len(t, logger.messages, 1)
+				assert.Equal(t, "info", logger.messages[0].level)
+				assert.Equal(t, "TigerGraph request completed", logger.messages[0].msg)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			srv.MockResponse(queryURL, tigergraph.TigerGraphResponse[any]{})
+			srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+				_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+				assert.Nil(t, err)
+			})
+
+			logger := &recordingLevelLogger{}
+			opts := []tigergraph.Option{
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				tigergraph.WithLogger(logger),
+			}
+			if test.levels != nil {
+				opts = append(opts, tigergraph.WithLogLevels(*test.levels))
+			}
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, opts...)
+
+			test.action(t, client, logger)
+		})
+	}
+}
+
+func TestLoggingWithWarnOnlyLogger(t *testing.T) {
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	logger := &recordingLogger{}
+	client := tigergraph.NewClient(
+		srv.HTTPServer.URL,
+		tigergraph.WithCredentials(expectedUsername, expectedPassword),
+		tigergraph.WithLogger(logger),
+		tigergraph.WithLogLevels(tigergraph.LogLevels{Request: tigergraph.LogLevelWarn, GSQL: tigergraph.LogLevelDebug}),
+	)
+
+	assert.Nil(t, client.Auth(context.Background(), graphName))
+	assert.Equal(t, []string{"TigerGraph request completed"}, logger.warnings)
+}
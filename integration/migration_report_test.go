@@ -0,0 +1,209 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationReport(t *testing.T) { //nolint:funlen
+	exampleGraphName := "MyGraph"
+	migrationDir := "../testutils/migrations/v1"
+	successResponseString := fmt.Sprintf("Installing query...\n\n%s\n", tigergraph.SuccessString)
+	migrationUpsertURL := tigergraph.UpsertURL + "/" + tigergraph.MetadataGraphName
+
+	digest := func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	fileDigest := func(t *testing.T, name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(migrationDir, name))
+		assert.Nil(t, err)
+		return digest(data)
+	}
+
+	// mockMigrations mocks an initialised metadata graph with the given latest migration, and
+	// a GSQL server which fails from the given call onwards, or never if it is zero
+	mockMigrations := func(t *testing.T, srv *MockTigerGraphServer, latest []tigergraph.MigrationVertex, failFrom int32) {
+		t.Helper()
+		srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+			Results: &tigergraph.GraphMetadataResponseResult{GraphName: tigergraph.MetadataGraphName},
+		})
+		srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, tigergraph.CurrentMigrationVersionResponse{
+			Results: []tigergraph.CurrentMigrationVersionResponseResult{{LatestMigration: latest}},
+		})
+		srv.MockResponse(migrationUpsertURL, tigergraph.UpsertResponse{
+			Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+		})
+
+		var calls int32
+		srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+			if failFrom > 0 && atomic.AddInt32(&calls, 1) >= failFrom {
+				_, err := w.Write([]byte("Semantic Check Fails: oops\n\n"))
+				assert.Nil(t, err)
+				return
+			}
+
+			_, err := w.Write([]byte(successResponseString))
+			assert.Nil(t, err)
+		})
+	}
+
+	decodeReport := func(t *testing.T, data []byte) tigergraph.MigrationReport {
+		t.Helper()
+		var report tigergraph.MigrationReport
+		assert.Nil(t, json.Unmarshal(data, &report))
+		return report
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "records each step run up with its digests",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				mockMigrations(t, srv, []tigergraph.MigrationVertex{}, 0)
+				var buf bytes.Buffer
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithMigrationReport(&buf),
+				)
+
+				assert.Nil(t, client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false))
+
+				report := decodeReport(t, buf.Bytes())
+				assert.Equal(t, exampleGraphName, report.Graph)
+				assert.Equal(t, "001", report.TargetVersion)
+				assert.Equal(t, "", report.StartVersion)
+				assert.Equal(t, "001", report.ResultVersion)
+				assert.Equal(t, tigergraph.MigrationUp, report.Mode)
+				assert.Equal(t, tigergraph.MigrationReportSucceeded, report.Status)
+				assert.False(t, report.FinishedAt.Before(report.StartedAt))
+
+				if assert.Len(t, report.Steps, 2) {
+					for i, name := range []string{"000_first.up.gsql", "001_second.up.gsql"} {
+						step := report.Steps[i]
+						assert.Equal(t, fmt.Sprintf("%03d", i), step.MigrationNumber)
+						assert.Equal(t, filepath.Join(migrationDir, name), step.File)
+						assert.Equal(t, tigergraph.MigrationReportSucceeded, step.Status)
+						assert.Equal(t, fileDigest(t, name), step.GSQLDigest)
+						assert.Equal(t, digest([]byte(successResponseString)), step.TranscriptDigest)
+						assert.NotEmpty(t, step.Duration)
+					}
+				}
+			},
+		},
+		{
+			name: "records the failed step and the version reached",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				mockMigrations(t, srv, []tigergraph.MigrationVertex{}, 2)
+				path := filepath.Join(t.TempDir(), "report.json")
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithMigrationReportFile(path),
+				)
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false)
+				assert.ErrorIs(t, err, tigergraph.ErrTigerGraphSchemaSetUpFailed)
+
+				data, readErr := os.ReadFile(path)
+				assert.Nil(t, readErr)
+				report := decodeReport(t, data)
+				assert.Equal(t, tigergraph.MigrationReportFailed, report.Status)
+				assert.Equal(t, err.Error(), report.Error)
+				assert.Equal(t, "000", report.ResultVersion)
+
+				if assert.Len(t, report.Steps, 2) {
+					assert.Equal(t, tigergraph.MigrationReportSucceeded, report.Steps[0].Status)
+					assert.Equal(t, tigergraph.MigrationReportFailed, report.Steps[1].Status)
+					assert.Contains(t, report.Steps[1].Error, "semantic failure")
+				}
+			},
+		},
+		{
+			name: "records the version reached when migrating down",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				mockMigrations(t, srv, []tigergraph.MigrationVertex{{
+					Attributes: tigergraph.MigrationVertexAttributes{MigrationNumber: "001", Mode: tigergraph.MigrationUp, GraphName: exampleGraphName},
+				}}, 0)
+				var buf bytes.Buffer
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithMigrationReport(&buf),
+				)
+
+				assert.Nil(t, client.Migrate(context.Background(), exampleGraphName, "000", "", migrationDir, false))
+
+				report := decodeReport(t, buf.Bytes())
+				assert.Equal(t, "001", report.StartVersion)
+				assert.Equal(t, "000", report.ResultVersion)
+				assert.Equal(t, tigergraph.MigrationDown, report.Mode)
+				if assert.Len(t, report.Steps, 1) {
+					assert.Equal(t, fileDigest(t, "001_second.down.gsql"), report.Steps[0].GSQLDigest)
+				}
+			},
+		},
+		{
+			name: "dry runs record planned steps",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				mockMigrations(t, srv, []tigergraph.MigrationVertex{}, 0)
+				var buf bytes.Buffer
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithMigrationReport(&buf),
+				)
+
+				assert.Nil(t, client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, true))
+
+				report := decodeReport(t, buf.Bytes())
+				assert.True(t, report.DryRun)
+				assert.Equal(t, "", report.ResultVersion)
+				if assert.Len(t, report.Steps, 2) {
+					assert.Equal(t, tigergraph.MigrationReportPlanned, report.Steps[0].Status)
+					assert.Empty(t, report.Steps[0].GSQLDigest)
+				}
+				assert.Equal(t, 0, srv.CallCount(tigergraph.FileURL))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			test.action(t, srv)
+		})
+	}
+}
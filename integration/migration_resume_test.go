@@ -0,0 +1,295 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumableMigrations(t *testing.T) { //nolint:funlen
+	exampleGraphName := "MyGraph"
+	migrationDir := "../testutils/migrations/v1"
+	firstMigrationFile := migrationDir + "/000_first.up.gsql"
+
+	successResponseString := fmt.Sprintf("Installing query...\n\n%s\n", tigergraph.SuccessString)
+	metadataUpsertURL := tigergraph.UpsertURL + "/" + tigergraph.MetadataGraphName
+	plansURL := fmt.Sprintf(tigergraph.VerticesURLTemplate, tigergraph.MetadataGraphName, tigergraph.MigrationPlanVertexType)
+
+	oneAcceptedUpsertResponse := tigergraph.UpsertResponse{
+		Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+	}
+
+	metadataWithPlans := tigergraph.GraphMetadataResponse{
+		Results: &tigergraph.GraphMetadataResponseResult{
+			GraphName:   tigergraph.MetadataGraphName,
+			VertexTypes: []tigergraph.GraphMetadataVertexType{{Name: tigergraph.MigrationPlanVertexType}},
+		},
+	}
+
+	latestMigrationResponse := func(vertices ...tigergraph.MigrationVertex) tigergraph.CurrentMigrationVersionResponse {
+		return tigergraph.CurrentMigrationVersionResponse{
+			Results: []tigergraph.CurrentMigrationVersionResponseResult{{LatestMigration: vertices}},
+		}
+	}
+
+	plansResponse := func(plans ...tigergraph.MigrationPlan) tigergraph.TigerGraphResponse[tigergraph.ResponseVertex[tigergraph.MigrationPlan]] {
+		response := tigergraph.TigerGraphResponse[tigergraph.ResponseVertex[tigergraph.MigrationPlan]]{
+			Results: []tigergraph.ResponseVertex[tigergraph.MigrationPlan]{},
+		}
+		for _, plan := range plans {
+			response.Results = append(response.Results, tigergraph.ResponseVertex[tigergraph.MigrationPlan]{
+				VID:        plan.Graph,
+				Attributes: plan,
+			})
+		}
+
+		return response
+	}
+
+	interruptedPlan := tigergraph.MigrationPlan{
+		Graph:         exampleGraphName,
+		TargetVersion: "000",
+		Mode:          "up",
+		Steps:         []string{"000"},
+		Files:         []string{firstMigrationFile},
+		InProgress:    "000",
+		Status:        tigergraph.MigrationPlanRunning,
+	}
+
+	// upserts returns, for each upsert to the metadata graph, the plan's status, completed steps
+	// and step in progress, or the migration number committed
+	upserts := func(t *testing.T, srv *MockTigerGraphServer) []string {
+		t.Helper()
+		result := make([]string, 0)
+		for _, call := range srv.Calls[metadataUpsertURL] {
+			body, err := io.ReadAll(call)
+			assert.Nil(t, err)
+
+			var payload struct {
+				Vertices struct {
+					Migration     map[string]map[string]tigergraph.ValueWrapper `json:"Migration"`
+					MigrationPlan map[string]map[string]tigergraph.ValueWrapper `json:"MigrationPlan"`
+				} `json:"vertices"`
+			}
+			assert.Nil(t, json.Unmarshal(body, &payload))
+
+			for _, attributes := range payload.Vertices.Migration {
+				result = append(result, fmt.Sprintf("commit %v", attributes["migration_number"].Value))
+			}
+			for _, attributes := range payload.Vertices.MigrationPlan {
+				result = append(result, fmt.Sprintf(
+					"plan %v %v %q",
+					attributes["status"].Value,
+					attributes["completed"].Value,
+					attributes["in_progress"].Value,
+				))
+			}
+		}
+
+		return result
+	}
+
+	mockSuccessfulGSQL := func(srv *MockTigerGraphServer) {
+		srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(successResponseString))
+			assert.Nil(t, err)
+		})
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "migrate persists the plan before running it",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", metadataWithPlans)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse())
+				srv.MockResponse(plansURL, plansResponse())
+				srv.MockResponse(metadataUpsertURL, oneAcceptedUpsertResponse)
+				mockSuccessfulGSQL(srv)
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false)
+				assert.Nil(t, err)
+
+				assert.Equal(t, 2, len(srv.Calls[tigergraph.FileURL]))
+				assert.Equal(t, []string{
+					`plan running 0 ""`,
+					`plan running 0 "000"`,
+					"commit 000",
+					`plan running 1 ""`,
+					`plan running 1 "001"`,
+					"commit 001",
+					`plan completed 2 ""`,
+				}, upserts(t, srv))
+			},
+		},
+		{
+			name: "migrate adds the plan vertex type to the metadata graph",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.OutdatedMetadataSchema = true
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+					Results: &tigergraph.GraphMetadataResponseResult{GraphName: tigergraph.MetadataGraphName},
+				})
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse())
+				srv.MockResponse(plansURL, plansResponse())
+				srv.MockResponse(metadataUpsertURL, oneAcceptedUpsertResponse)
+				mockSuccessfulGSQL(srv)
+
+				err := client.Migrate(context.Background(), exampleGraphName, "000", "", migrationDir, false)
+				assert.Nil(t, err)
+
+				// The built-in metadata migrations, the first of which adds the plan vertex type,
+				// then the migration
+				if !assert.Equal(t, tigergraph.BuiltinMetadataSchemaVersion()+1, len(srv.Calls[tigergraph.FileURL])) {
+					return
+				}
+				body, err := io.ReadAll(srv.Calls[tigergraph.FileURL][0])
+				assert.Nil(t, err)
+				assert.Equal(t, url.QueryEscape(tigergraph.MigrationPlanSchemaGSQL), string(body))
+			},
+		},
+		{
+			name: "migrate finishes a running plan before computing a new one",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", metadataWithPlans)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse(tigergraph.MigrationVertex{
+					Attributes: tigergraph.MigrationVertexAttributes{MigrationNumber: "000", Mode: "up", GraphName: exampleGraphName},
+				}))
+				srv.MockResponse(plansURL, plansResponse(interruptedPlan))
+				srv.MockResponse(metadataUpsertURL, oneAcceptedUpsertResponse)
+				mockSuccessfulGSQL(srv)
+
+				err := client.Migrate(context.Background(), exampleGraphName, "000", "", migrationDir, false)
+				assert.Nil(t, err)
+
+				// The interrupted step had been committed, so only the plan is updated
+				assert.Zero(t, len(srv.Calls[tigergraph.FileURL]))
+				assert.Equal(t, []string{`plan completed 1 ""`}, upserts(t, srv))
+			},
+		},
+		{
+			name: "resume runs the remaining steps of running plans",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				plan := interruptedPlan
+				plan.InProgress = ""
+				completed := plan
+				completed.Graph = "OtherGraph"
+				completed.Status = tigergraph.MigrationPlanCompleted
+
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", metadataWithPlans)
+				srv.MockResponse(plansURL, plansResponse(plan, completed))
+				srv.MockResponse(metadataUpsertURL, oneAcceptedUpsertResponse)
+				mockSuccessfulGSQL(srv)
+
+				err := client.ResumeMigration(context.Background())
+				assert.Nil(t, err)
+
+				if !assert.Equal(t, 1, len(srv.Calls[tigergraph.FileURL])) {
+					return
+				}
+				body, err := io.ReadAll(srv.Calls[tigergraph.FileURL][0])
+				assert.Nil(t, err)
+				assert.Equal(t, "example+000+up", string(body))
+				assert.Equal(t, []string{`plan running 0 "000"`, "commit 000", `plan completed 1 ""`}, upserts(t, srv))
+			},
+		},
+		{
+			name: "resume does not rerun an interrupted step which was not committed",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", metadataWithPlans)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse())
+				srv.MockResponse(plansURL, plansResponse(interruptedPlan))
+				srv.MockResponse(metadataUpsertURL, oneAcceptedUpsertResponse)
+				mockSuccessfulGSQL(srv)
+
+				err := client.ResumeMigration(context.Background())
+				assert.ErrorIs(t, err, tigergraph.ErrMigrationStepInterrupted)
+
+				var interruptedErr *tigergraph.MigrationStepInterruptedError
+				if assert.ErrorAs(t, err, &interruptedErr) {
+					assert.Equal(t, "000", interruptedErr.MigrationNumber)
+					assert.Equal(t, firstMigrationFile, interruptedErr.File)
+				}
+				assert.Zero(t, len(srv.Calls[tigergraph.FileURL]))
+
+				assert.Nil(t, client.AbandonMigration(context.Background(), exampleGraphName))
+				assert.Equal(t, []string{`plan abandoned 0 ""`}, upserts(t, srv))
+			},
+		},
+		{
+			name: "a failed step can be run again",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", metadataWithPlans)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse())
+				srv.MockResponse(plansURL, plansResponse())
+				srv.MockResponse(metadataUpsertURL, oneAcceptedUpsertResponse)
+				srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+					_, err := w.Write([]byte("Semantic Check Fails: bad migration\n__GSQL__RETURN__CODE__,1\n"))
+					assert.Nil(t, err)
+				})
+
+				err := client.Migrate(context.Background(), exampleGraphName, "000", "", migrationDir, false)
+				assert.ErrorIs(t, err, tigergraph.ErrTigerGraphSchemaSetUpFailed)
+				assert.Equal(t, []string{`plan running 0 ""`, `plan running 0 "000"`, `plan running 0 ""`}, upserts(t, srv))
+			},
+		},
+		{
+			name: "resume adds the plan vertex type if it is missing",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.OutdatedMetadataSchema = true
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+					Results: &tigergraph.GraphMetadataResponseResult{GraphName: tigergraph.MetadataGraphName},
+				})
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse())
+				srv.MockResponse(plansURL, plansResponse())
+				srv.MockResponse(metadataUpsertURL, oneAcceptedUpsertResponse)
+				mockSuccessfulGSQL(srv)
+
+				assert.Nil(t, client.ResumeMigration(context.Background()))
+				assert.Equal(t, 1, srv.CallCount(plansURL))
+
+				if assert.NotEmpty(t, srv.Calls[tigergraph.FileURL]) {
+					body, err := io.ReadAll(srv.Calls[tigergraph.FileURL][0])
+					assert.Nil(t, err)
+					assert.Equal(t, url.QueryEscape(tigergraph.MigrationPlanSchemaGSQL), string(body))
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(
+				srv.HTTPServer.URL,
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				tigergraph.WithResumableMigrations(),
+			)
+
+			test.action(t, client, srv)
+		})
+	}
+}
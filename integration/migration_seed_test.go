@@ -0,0 +1,276 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationSeeds(t *testing.T) { //nolint:funlen
+	exampleGraphName := "MyGraph"
+	successResponseString := fmt.Sprintf("Installing query...\n\n%s\n", tigergraph.SuccessString)
+	metadataUpsertURL := tigergraph.UpsertURL + "/" + tigergraph.MetadataGraphName
+	seedsURL := fmt.Sprintf(tigergraph.VerticesURLTemplate, tigergraph.MetadataGraphName, tigergraph.MigrationSeedVertexType)
+	loadingJobURL := fmt.Sprintf("/ddl/%s?tag=%s&filename=f", exampleGraphName, "load_countries")
+	seedID := exampleGraphName + "_001_seeds/countries.jsonl"
+
+	// writeMigrations writes two migrations, the second of which seeds countries
+	writeMigrations := func(t *testing.T, manifest string) string {
+		t.Helper()
+		dir := t.TempDir()
+		files := map[string]string{
+			"000_init.up.gsql":         "CREATE GRAPH MyGraph()",
+			"000_init.down.gsql":       "DROP GRAPH MyGraph",
+			"001_countries.up.gsql":    "USE GRAPH MyGraph\nCREATE LOADING JOB load_countries FOR GRAPH MyGraph {}",
+			"001_countries.down.gsql":  "USE GRAPH MyGraph\nDROP JOB load_countries",
+			"001_countries.seeds.json": manifest,
+			"seeds/countries.jsonl":    "{\"code\": \"GB\"}\n\n{\"code\": \"FR\"}\n",
+		}
+		assert.Nil(t, os.Mkdir(filepath.Join(dir, "seeds"), 0o700))
+		for name, contents := range files {
+			assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600))
+		}
+
+		return dir
+	}
+	validManifest := `{"seeds": [{"loading_job": "load_countries", "file": "seeds/countries.jsonl"}]}`
+
+	latestMigrationResponse := func(vertices ...tigergraph.MigrationVertex) tigergraph.CurrentMigrationVersionResponse {
+		return tigergraph.CurrentMigrationVersionResponse{
+			Results: []tigergraph.CurrentMigrationVersionResponseResult{{LatestMigration: vertices}},
+		}
+	}
+
+	type seedsResponseBody = tigergraph.TigerGraphResponse[tigergraph.ResponseVertex[tigergraph.MigrationSeedRecord]]
+	seedsResponse := func(records ...tigergraph.MigrationSeedRecord) seedsResponseBody {
+		response := seedsResponseBody{
+			Results: []tigergraph.ResponseVertex[tigergraph.MigrationSeedRecord]{},
+		}
+		for _, record := range records {
+			response.Results = append(response.Results, tigergraph.ResponseVertex[tigergraph.MigrationSeedRecord]{
+				VID:        record.ID,
+				Attributes: record,
+			})
+		}
+
+		return response
+	}
+
+	// upserts returns, for each upsert to the metadata graph, the migration committed or the
+	// seed recorded
+	upserts := func(t *testing.T, srv *MockTigerGraphServer) []string {
+		t.Helper()
+		result := make([]string, 0)
+		for _, call := range srv.Calls[metadataUpsertURL] {
+			body, err := io.ReadAll(call)
+			assert.Nil(t, err)
+
+			var payload struct {
+				Vertices struct {
+					Migration     map[string]map[string]tigergraph.ValueWrapper `json:"Migration"`
+					MigrationSeed map[string]map[string]tigergraph.ValueWrapper `json:"MigrationSeed"`
+				} `json:"vertices"`
+			}
+			assert.Nil(t, json.Unmarshal(body, &payload))
+
+			for _, attributes := range payload.Vertices.Migration {
+				result = append(result, fmt.Sprintf("commit %v %v", attributes["migration_number"].Value, attributes["mode"].Value))
+			}
+			for id, attributes := range payload.Vertices.MigrationSeed {
+				result = append(result, fmt.Sprintf("seed %s %v %v", id, attributes["status"].Value, attributes["lines"].Value))
+			}
+		}
+
+		return result
+	}
+
+	mockMetadata := func(srv *MockTigerGraphServer) {
+		srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+			Results: &tigergraph.GraphMetadataResponseResult{
+				GraphName:   tigergraph.MetadataGraphName,
+				VertexTypes: []tigergraph.GraphMetadataVertexType{{Name: tigergraph.MigrationSeedVertexType}},
+			},
+		})
+		srv.MockResponse(metadataUpsertURL, tigergraph.UpsertResponse{
+			Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+		})
+		srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+			_, err := w.Write([]byte(successResponseString))
+			assert.Nil(t, err)
+		})
+	}
+
+	mockLoadingJob := func(srv *MockTigerGraphServer) {
+		srv.MockResponse(loadingJobURL, tigergraph.LoadingJobResponse{
+			Results: []tigergraph.LoadingJobResponseResult{{Statistics: tigergraph.LoadingJobStatistics{ValidLine: 2}}},
+		})
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "seeds are loaded after their migration is committed",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				dir := writeMigrations(t, validManifest)
+				mockMetadata(srv)
+				mockLoadingJob(srv)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse())
+				srv.MockResponse(seedsURL, seedsResponse())
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", dir, false)
+				assert.Nil(t, err)
+
+				assert.Equal(t, []string{
+					"commit 000 up",
+					"commit 001 up",
+					"seed " + seedID + " applied 2",
+				}, upserts(t, srv))
+
+				calls := srv.Calls[loadingJobURL]
+				if assert.Len(t, calls, 1) {
+					body, err := io.ReadAll(calls[0])
+					assert.Nil(t, err)
+					assert.Equal(t, "{\"code\":\"GB\"}\n{\"code\":\"FR\"}", string(body))
+				}
+			},
+		},
+		{
+			name: "seeds which were not loaded are loaded before new migrations",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				dir := writeMigrations(t, validManifest)
+				mockMetadata(srv)
+				mockLoadingJob(srv)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse(tigergraph.MigrationVertex{
+					Attributes: tigergraph.MigrationVertexAttributes{MigrationNumber: "001", Mode: tigergraph.MigrationUp, GraphName: exampleGraphName},
+				}))
+				srv.MockResponse(seedsURL, seedsResponse())
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", dir, false)
+				assert.Nil(t, err)
+
+				assert.Equal(t, 0, srv.CallCount(tigergraph.FileURL))
+				assert.Equal(t, 1, srv.CallCount(loadingJobURL))
+				assert.Equal(t, []string{"seed " + seedID + " applied 2"}, upserts(t, srv))
+			},
+		},
+		{
+			name: "seeds which were loaded are not loaded again",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				dir := writeMigrations(t, validManifest)
+				mockMetadata(srv)
+				mockLoadingJob(srv)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse(tigergraph.MigrationVertex{
+					Attributes: tigergraph.MigrationVertexAttributes{MigrationNumber: "001", Mode: tigergraph.MigrationUp, GraphName: exampleGraphName},
+				}))
+				srv.MockResponse(seedsURL, seedsResponse(
+					tigergraph.MigrationSeedRecord{ID: seedID, Graph: exampleGraphName, Status: tigergraph.MigrationSeedApplied},
+					// Another graph's seed with the same file does not count
+					tigergraph.MigrationSeedRecord{ID: "Other_001_seeds/countries.jsonl", Graph: "Other", Status: tigergraph.MigrationSeedApplied},
+				))
+
+				assert.Nil(t, client.ApplyMigrationSeeds(context.Background(), exampleGraphName, dir))
+
+				assert.Equal(t, 0, srv.CallCount(loadingJobURL))
+				assert.Empty(t, upserts(t, srv))
+			},
+		},
+		{
+			name: "migrating down marks seeds reverted",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				dir := writeMigrations(t, validManifest)
+				mockMetadata(srv)
+				mockLoadingJob(srv)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse(tigergraph.MigrationVertex{
+					Attributes: tigergraph.MigrationVertexAttributes{MigrationNumber: "001", Mode: tigergraph.MigrationUp, GraphName: exampleGraphName},
+				}))
+				srv.MockResponse(seedsURL, seedsResponse(
+					tigergraph.MigrationSeedRecord{ID: seedID, Graph: exampleGraphName, Status: tigergraph.MigrationSeedApplied},
+				))
+
+				err := client.Migrate(context.Background(), exampleGraphName, "000", "", dir, false)
+				assert.Nil(t, err)
+
+				assert.Equal(t, 0, srv.CallCount(loadingJobURL))
+				assert.Equal(t, []string{"commit 001 down", "seed " + seedID + " reverted 0"}, upserts(t, srv))
+			},
+		},
+		{
+			name: "failed seeds leave the migration committed",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				dir := writeMigrations(t, validManifest)
+				mockMetadata(srv)
+				srv.Mock(loadingJobURL, func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				})
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse())
+				srv.MockResponse(seedsURL, seedsResponse())
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", dir, false)
+				assert.ErrorIs(t, err, tigergraph.ErrMigrationSeedFailed)
+				assert.ErrorContains(t, err, "seeds/countries.jsonl")
+
+				assert.Equal(t, []string{"commit 000 up", "commit 001 up"}, upserts(t, srv))
+			},
+		},
+		{
+			name: "invalid manifests are reported",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				dir := writeMigrations(t, `{"seeds": [{"file": "seeds/countries.jsonl"}]}`)
+				mockMetadata(srv)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse())
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", dir, false)
+				assert.ErrorIs(t, err, tigergraph.ErrInvalidSeedManifest)
+				assert.Equal(t, 0, srv.CallCount(loadingJobURL))
+			},
+		},
+		{
+			name: "dry runs load no seeds",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				dir := writeMigrations(t, validManifest)
+				mockMetadata(srv)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, latestMigrationResponse(tigergraph.MigrationVertex{
+					Attributes: tigergraph.MigrationVertexAttributes{MigrationNumber: "001", Mode: tigergraph.MigrationUp, GraphName: exampleGraphName},
+				}))
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", dir, true)
+				assert.Nil(t, err)
+				assert.Equal(t, 0, srv.CallCount(seedsURL))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/adarga-ai/go-tigergraph/tigergraph"
 	"github.com/stretchr/testify/assert"
@@ -63,12 +64,19 @@ func TestIsInitialised(t *testing.T) { //nolint:funlen
 				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
 					Error:   true,
 					Message: "You are not authenticated",
+					Code:    "REST-10016",
 				})
 
 				ctx := context.Background()
 				result, err := client.CheckIsInitialised(ctx)
 				assert.ErrorIs(t, err, tigergraph.ErrUnknownInitialisationCheckFailure)
 				assert.False(t, result)
+
+				var checkErr *tigergraph.InitialisationCheckError
+				assert.ErrorAs(t, err, &checkErr)
+				assert.Equal(t, "You are not authenticated", checkErr.Message)
+				assert.Equal(t, "REST-10016", checkErr.Code)
+				assert.Contains(t, err.Error(), "You are not authenticated")
 			},
 		},
 		{
@@ -109,12 +117,7 @@ func TestIsInitialised(t *testing.T) { //nolint:funlen
 			srv := NewMockServer(expectedUsername, expectedPassword)
 			defer srv.Close()
 
-			client := tigergraph.NewClient(
-				srv.HTTPServer.URL,
-				srv.HTTPServer.URL,
-				expectedUsername,
-				expectedPassword,
-			)
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
 
 			test.action(t, client, srv)
 		})
@@ -128,7 +131,7 @@ func TestMigrate(t *testing.T) { //nolint:funlen
 	successResponseString := fmt.Sprintf("Installing query...\n\n%s\n", tigergraph.SuccessString)
 	migrationUpsertURL := tigergraph.UpsertURL + "/" + tigergraph.MetadataGraphName
 
-	assertUpsertPayload := func(t *testing.T, b []byte, migrationNumber string, mode string) {
+	assertUpsertPayload := func(t *testing.T, b []byte, migrationNumber string, mode tigergraph.MigrationMode) {
 		t.Helper()
 		var asStruct tigergraph.MigrationUpsertPayload
 		err := json.Unmarshal(b, &asStruct)
@@ -141,7 +144,7 @@ func TestMigrate(t *testing.T) { //nolint:funlen
 		}
 	}
 
-	makeLatestMigrationVertexResponse := func(version string, mode string) tigergraph.CurrentMigrationVersionResponse {
+	makeLatestMigrationVertexResponse := func(version string, mode tigergraph.MigrationMode) tigergraph.CurrentMigrationVersionResponse {
 		return tigergraph.CurrentMigrationVersionResponse{
 			Results: []tigergraph.CurrentMigrationVersionResponseResult{
 				{
@@ -207,6 +210,78 @@ func TestMigrate(t *testing.T) { //nolint:funlen
 				assert.Zero(t, len(srv.Calls[migrationUpsertURL]))
 			},
 		},
+		{
+			name: "fails without panicking when the latest migration query returns no results",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+					Results: &tigergraph.GraphMetadataResponseResult{
+						GraphName: tigergraph.MetadataGraphName,
+					},
+				})
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, tigergraph.CurrentMigrationVersionResponse{})
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false)
+				assert.ErrorIs(t, err, tigergraph.ErrNotOneResult)
+				assert.Zero(t, len(srv.Calls[tigergraph.FileURL]))
+			},
+		},
+		{
+			name: "does not run any migrations if a planned migration file is missing",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+					Results: &tigergraph.GraphMetadataResponseResult{
+						GraphName: tigergraph.MetadataGraphName,
+					},
+				})
+
+				// No migrations have been run, but there is no file for 002
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, emptyLatestMigrationVertexResponse)
+
+				ctx := context.Background()
+				err := client.Migrate(
+					ctx,
+					exampleGraphName,
+					"002",
+					"",
+					migrationDir,
+					false,
+				)
+				assert.ErrorIs(t, err, tigergraph.ErrInvalidMigrationPlan)
+
+				var planErr *tigergraph.MigrationPlanError
+				assert.ErrorAs(t, err, &planErr)
+				assert.Equal(t, []string{"002"}, planErr.Missing)
+
+				assert.Zero(t, len(srv.Calls[tigergraph.FileURL]))
+				assert.Zero(t, len(srv.Calls[migrationUpsertURL]))
+			},
+		},
+		{
+			name: "migration steps are bounded by the migration step timeout, not the request timeout",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.Timeouts.Request = 25 * time.Millisecond
+				client.Timeouts.MigrationStep = time.Second
+
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+					Results: &tigergraph.GraphMetadataResponseResult{
+						GraphName: tigergraph.MetadataGraphName,
+					},
+				})
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, emptyLatestMigrationVertexResponse)
+				srv.MockResponse(migrationUpsertURL, oneAcceptedUpsertVertexResponse)
+
+				// The migration takes longer than the request timeout
+				srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+					time.Sleep(100 * time.Millisecond)
+					_, err := w.Write([]byte(successResponseString))
+					assert.Nil(t, err)
+				})
+
+				err := client.Migrate(context.Background(), exampleGraphName, "000", "", migrationDir, false)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(srv.Calls[tigergraph.FileURL]))
+			},
+		},
 		{
 			name: "runs the initialisation gsql and then first migration if not initialised",
 			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
@@ -456,7 +531,7 @@ func TestMigrate(t *testing.T) { //nolint:funlen
 					false,
 				)
 
-				assert.Equal(t, tigergraph.ErrUnknownInitialisationCheckFailure, err)
+				assert.ErrorIs(t, err, tigergraph.ErrUnknownInitialisationCheckFailure)
 				assert.Equal(t, 0, len(srv.Calls[tigergraph.FileURL]))
 				assert.Equal(t, 0, len(srv.Calls[migrationUpsertURL]))
 				assert.Equal(t, 0, len(srv.Calls[tigergraph.GetCurrentMigrationVersionURL]))
@@ -720,8 +795,12 @@ func TestMigrate(t *testing.T) { //nolint:funlen
 					},
 				})
 
-				// Note "don" instead of "down"
-				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, makeLatestMigrationVertexResponse("001", "don"))
+				// Note "don" instead of "down". MigrationMode refuses to marshal it, so the response
+				// is written by hand.
+				srv.Mock(tigergraph.GetCurrentMigrationVersionURL, func(w http.ResponseWriter, _ *http.Request) {
+					_, err := w.Write([]byte(`{"results": [{"latest_migration": [{"attributes": {"migration_number": "001", "mode": "don"}}]}]}`))
+					assert.Nil(t, err)
+				})
 
 				ctx := context.Background()
 				err := client.Migrate(
@@ -801,14 +880,287 @@ func TestMigrate(t *testing.T) { //nolint:funlen
 			srv := NewMockServer(expectedUsername, expectedPassword)
 			defer srv.Close()
 
-			client := tigergraph.NewClient(
-				srv.HTTPServer.URL,
-				srv.HTTPServer.URL,
-				expectedUsername,
-				expectedPassword,
-			)
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
+
+func TestMetadataSchemaMigrations(t *testing.T) { //nolint:funlen
+	exampleGraphName := "MyGraph"
+	migrationDir := "../testutils/migrations/v1"
+	successResponseString := fmt.Sprintf("Done.\n\n%s\n", tigergraph.SuccessString)
+	migrationUpsertURL := tigergraph.UpsertURL + "/" + tigergraph.MetadataGraphName
+
+	// Responds with the given latest migration per graph name in the request body
+	mockLatestMigrations := func(srv *MockTigerGraphServer, latest map[string]string) {
+		srv.Mock(tigergraph.GetCurrentMigrationVersionURL, func(w http.ResponseWriter, r *http.Request) {
+			var body tigergraph.CurrentMigrationVersionPostBody
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+
+			result := tigergraph.CurrentMigrationVersionResponseResult{LatestMigration: []tigergraph.MigrationVertex{}}
+			if number, found := latest[body.GraphName]; found {
+				result.LatestMigration = append(result.LatestMigration, tigergraph.MigrationVertex{
+					Attributes: tigergraph.MigrationVertexAttributes{MigrationNumber: number, Mode: "up", GraphName: body.GraphName},
+				})
+			}
+
+			responseBytes, err := json.Marshal(tigergraph.CurrentMigrationVersionResponse{
+				Results: []tigergraph.CurrentMigrationVersionResponseResult{result},
+			})
+			assert.Nil(t, err)
+			_, err = w.Write(responseBytes)
+			assert.Nil(t, err)
+		})
+	}
+
+	mockCommon := func(srv *MockTigerGraphServer, initialised bool) {
+		if initialised {
+			srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+				Results: &tigergraph.GraphMetadataResponseResult{GraphName: tigergraph.MetadataGraphName},
+			})
+		} else {
+			srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+				Error:   true,
+				Message: tigergraph.ExpectedFailurePrefix,
+			})
+		}
+
+		srv.MockResponse(migrationUpsertURL, tigergraph.UpsertResponse{
+			Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+		})
+		srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(successResponseString))
+			assert.Nil(t, err)
+		})
+	}
+
+	extraAttribute := tigergraph.MetadataMigration{Version: 1, GSQL: "ADD ATTRIBUTE"}
+
+	// The vertex types added by the built-in metadata migrations
+	builtinVertexTypes := []tigergraph.GraphMetadataVertexType{
+		{Name: tigergraph.MigrationPlanVertexType},
+		{Name: tigergraph.MigrationSeedVertexType},
+		{Name: tigergraph.BatchSizeVertexType},
+		{Name: tigergraph.QueryAliasVertexType},
+	}
+
+	// committedVersions returns the metadata schema name and version of each migration committed
+	committedVersions := func(t *testing.T, srv *MockTigerGraphServer) []string {
+		t.Helper()
+		versions := make([]string, 0)
+		for _, call := range srv.Calls[migrationUpsertURL] {
+			var payload tigergraph.MigrationUpsertPayload
+			assert.Nil(t, json.NewDecoder(call).Decode(&payload))
+			for _, v := range payload.Vertices.Migration {
+				versions = append(versions, v.GraphName.Value+" "+v.MigrationNumber.Value)
+			}
+		}
+
+		return versions
+	}
+
+	builtinVersions := func() []string {
+		versions := make([]string, 0)
+		for version := 1; version <= tigergraph.BuiltinMetadataSchemaVersion(); version++ {
+			versions = append(versions, fmt.Sprintf("%s %03d", tigergraph.BuiltinMetadataSchemaName, version))
+		}
+
+		return versions
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "runs pending metadata migrations and records the metadata version",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockCommon(srv, true)
+				mockLatestMigrations(srv, map[string]string{exampleGraphName: "001"})
+				client.MetadataMigrations = []tigergraph.MetadataMigration{extraAttribute}
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false)
+				assert.Nil(t, err)
+
+				assert.Len(t, srv.Calls[tigergraph.FileURL], 1)
+				callBytes, err := io.ReadAll(srv.Calls[tigergraph.FileURL][0])
+				assert.Nil(t, err)
+				assert.Equal(t, url.QueryEscape("ADD ATTRIBUTE"), string(callBytes))
+
+				assert.Len(t, srv.Calls[migrationUpsertURL], 1)
+				upsertBytes, err := io.ReadAll(srv.Calls[migrationUpsertURL][0])
+				assert.Nil(t, err)
+
+				var payload tigergraph.MigrationUpsertPayload
+				assert.Nil(t, json.Unmarshal(upsertBytes, &payload))
+				for _, v := range payload.Vertices.Migration {
+					assert.Equal(t, tigergraph.MetadataGraphName, v.GraphName.Value)
+					assert.Equal(t, "001", v.MigrationNumber.Value)
+				}
+			},
+		},
+		{
+			name: "runs the built-in metadata migrations, versioned apart from the client's",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.OutdatedMetadataSchema = true
+				mockCommon(srv, true)
+				mockLatestMigrations(srv, map[string]string{exampleGraphName: "001"})
+				client.MetadataMigrations = []tigergraph.MetadataMigration{extraAttribute}
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false)
+				assert.Nil(t, err)
+
+				calls := srv.Calls[tigergraph.FileURL]
+				if assert.Len(t, calls, tigergraph.BuiltinMetadataSchemaVersion()+1) {
+					callBytes, err := io.ReadAll(calls[len(calls)-1])
+					assert.Nil(t, err)
+					assert.Equal(t, url.QueryEscape("ADD ATTRIBUTE"), string(callBytes))
+				}
+
+				expected := append(builtinVersions(), tigergraph.MetadataGraphName+" 001")
+				assert.Equal(t, expected, committedVersions(t, srv))
+			},
+		},
+		{
+			name: "records built-in metadata migrations of vertex types added by earlier clients",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.OutdatedMetadataSchema = true
+				mockCommon(srv, true)
+				srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+					Results: &tigergraph.GraphMetadataResponseResult{
+						GraphName:   tigergraph.MetadataGraphName,
+						VertexTypes: builtinVertexTypes,
+					},
+				})
+				mockLatestMigrations(srv, map[string]string{exampleGraphName: "001"})
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false)
+				assert.Nil(t, err)
+				assert.Len(t, srv.Calls[tigergraph.FileURL], 0)
+				assert.Equal(t, builtinVersions(), committedVersions(t, srv))
+			},
+		},
+		{
+			name: "skips metadata migrations which have already been applied",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockCommon(srv, true)
+				mockLatestMigrations(srv, map[string]string{exampleGraphName: "001", tigergraph.MetadataGraphName: "001"})
+				client.MetadataMigrations = []tigergraph.MetadataMigration{extraAttribute}
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false)
+				assert.Nil(t, err)
+				assert.Len(t, srv.Calls[tigergraph.FileURL], 0)
+				assert.Len(t, srv.Calls[migrationUpsertURL], 0)
+			},
+		},
+		{
+			name: "uses custom init GSQL",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockCommon(srv, false)
+				mockLatestMigrations(srv, map[string]string{exampleGraphName: "001"})
+				client.MetadataInitGSQL = "CUSTOM INIT"
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false)
+				assert.Nil(t, err)
+
+				assert.Len(t, srv.Calls[tigergraph.FileURL], 1)
+				callBytes, err := io.ReadAll(srv.Calls[tigergraph.FileURL][0])
+				assert.Nil(t, err)
+				assert.Equal(t, url.QueryEscape("CUSTOM INIT"), string(callBytes))
+			},
+		},
+		{
+			name: "rejects metadata migrations that are not consecutive",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockCommon(srv, true)
+				mockLatestMigrations(srv, map[string]string{exampleGraphName: "001"})
+				client.MetadataMigrations = []tigergraph.MetadataMigration{{Version: 2, GSQL: "ADD ATTRIBUTE"}}
+
+				err := client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false)
+				assert.ErrorIs(t, err, tigergraph.ErrInvalidMetadataMigrations)
+				assert.Len(t, srv.Calls[tigergraph.FileURL], 0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
 
 			test.action(t, client, srv)
 		})
 	}
 }
+
+func TestLatestMigrationQueryName(t *testing.T) { //nolint:funlen
+	exampleGraphName := "MyGraph"
+	migrationDir := "../testutils/migrations/v1"
+	customQueryURL := "/query/team_get_latest_migration"
+	endpointsURL := fmt.Sprintf(tigergraph.EndpointsURLTemplate, tigergraph.MetadataGraphName)
+	successResponseString := fmt.Sprintf("Done.\n\n%s\n", tigergraph.SuccessString)
+
+	setUp := func(srv *MockTigerGraphServer, client *tigergraph.TigerGraphClient) {
+		client.LatestMigrationQueryName = "team_get_latest_migration"
+
+		srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+			Results: &tigergraph.GraphMetadataResponseResult{GraphName: tigergraph.MetadataGraphName},
+		})
+		srv.MockResponse(customQueryURL, tigergraph.CurrentMigrationVersionResponse{
+			Results: []tigergraph.CurrentMigrationVersionResponseResult{
+				{
+					LatestMigration: []tigergraph.MigrationVertex{
+						{Attributes: tigergraph.MigrationVertexAttributes{MigrationNumber: "001", Mode: "up"}},
+					},
+				},
+			},
+		})
+		srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(successResponseString))
+			assert.Nil(t, err)
+		})
+	}
+
+	t.Run("installs the query when it is missing", func(t *testing.T) {
+		srv := NewMockServer(expectedUsername, expectedPassword)
+		defer srv.Close()
+
+		client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+		setUp(srv, client)
+
+		err := client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false)
+		assert.Nil(t, err)
+
+		assert.Len(t, srv.Calls[tigergraph.FileURL], 1)
+		callBytes, err := io.ReadAll(srv.Calls[tigergraph.FileURL][0])
+		assert.Nil(t, err)
+
+		installGSQL, err := url.QueryUnescape(string(callBytes))
+		assert.Nil(t, err)
+		assert.Contains(t, installGSQL, "CREATE OR REPLACE QUERY team_get_latest_migration (")
+		assert.NotContains(t, installGSQL, "{{QUERY_NAME}}")
+
+		assert.Len(t, srv.Calls[customQueryURL], 1)
+		assert.Len(t, srv.Calls[tigergraph.GetCurrentMigrationVersionURL], 0)
+	})
+
+	t.Run("does not reinstall an installed query", func(t *testing.T) {
+		srv := NewMockServer(expectedUsername, expectedPassword)
+		defer srv.Close()
+
+		client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+		setUp(srv, client)
+		srv.MockResponse(endpointsURL, map[string]any{
+			"POST /query/ClientMetadata/team_get_latest_migration": map[string]any{},
+		})
+
+		err := client.Migrate(context.Background(), exampleGraphName, "001", "", migrationDir, false)
+		assert.Nil(t, err)
+		assert.Len(t, srv.Calls[tigergraph.FileURL], 0)
+		assert.Len(t, srv.Calls[customQueryURL], 1)
+	})
+}
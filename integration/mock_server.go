@@ -16,9 +16,12 @@ package integration
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/adarga-ai/go-tigergraph/tigergraph"
@@ -35,6 +38,14 @@ type MockTigerGraphServer struct {
 	Username     string
 	Password     string
 	mockHandlers map[string]handlerFunc
+
+	// OutdatedMetadataSchema stops the server answering latest migration queries for the
+	// built-in metadata schema itself. By default they are answered with the latest built-in
+	// version, without being recorded in Calls, so that tests need not mock them.
+	OutdatedMetadataSchema bool
+
+	// mu guards Calls and mockHandlers while requests are handled concurrently
+	mu sync.Mutex
 }
 
 // NewMockServer creates a new *MockTigerGraphServer ready to receive requests.
@@ -52,10 +63,17 @@ func NewMockServer(username, password string) *MockTigerGraphServer {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// The request body has to be copied because reading it closes the ReadCloser
 		bodyBytes, _ := io.ReadAll(r.Body)
-		result.Calls[r.URL.String()] = append(result.Calls[r.URL.String()], bytes.NewBuffer(bodyBytes))
 		r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 
+		if result.answerBuiltinMetadataSchema(w, r, bodyBytes) {
+			return
+		}
+
+		result.mu.Lock()
+		result.Calls[r.URL.String()] = append(result.Calls[r.URL.String()], bytes.NewBuffer(bodyBytes))
 		handler, found := result.mockHandlers[r.URL.String()]
+		result.mu.Unlock()
+
 		if !found {
 			w.WriteHeader(http.StatusNotFound)
 			return
@@ -80,6 +98,41 @@ func (ms *MockTigerGraphServer) setInitialMocks() {
 			w.WriteHeader(http.StatusOK)
 		},
 	}
+
+	// The metadata graph has the latest migration query installed by default
+	ms.MockResponse(fmt.Sprintf(tigergraph.EndpointsURLTemplate, tigergraph.MetadataGraphName), map[string]any{
+		"POST /query/" + tigergraph.MetadataGraphName + "/" + tigergraph.DefaultLatestMigrationQueryName: map[string]any{},
+	})
+}
+
+// answerBuiltinMetadataSchema responds to a latest migration query for the built-in metadata
+// schema with its latest version, unless the server has an OutdatedMetadataSchema.
+func (ms *MockTigerGraphServer) answerBuiltinMetadataSchema(w http.ResponseWriter, r *http.Request, body []byte) bool {
+	if ms.OutdatedMetadataSchema || r.Method != http.MethodPost || !strings.HasPrefix(r.URL.Path, "/query/") {
+		return false
+	}
+
+	var query tigergraph.CurrentMigrationVersionPostBody
+	if err := json.Unmarshal(body, &query); err != nil || query.GraphName != tigergraph.BuiltinMetadataSchemaName {
+		return false
+	}
+
+	result := tigergraph.CurrentMigrationVersionResponseResult{LatestMigration: []tigergraph.MigrationVertex{}}
+	if version := tigergraph.BuiltinMetadataSchemaVersion(); version > 0 {
+		result.LatestMigration = append(result.LatestMigration, tigergraph.MigrationVertex{
+			Attributes: tigergraph.MigrationVertexAttributes{
+				GraphName:       query.GraphName,
+				MigrationNumber: fmt.Sprintf("%03d", version),
+				Mode:            tigergraph.MigrationUp,
+			},
+		})
+	}
+
+	_ = json.NewEncoder(w).Encode(tigergraph.CurrentMigrationVersionResponse{
+		Results: []tigergraph.CurrentMigrationVersionResponseResult{result},
+	})
+
+	return true
 }
 
 // Close closes the mock server.
@@ -97,9 +150,21 @@ func (ms *MockTigerGraphServer) Reset() {
 // Mock allows an arbitrary handler to be set for a given URL.
 // This is useful for e.g. returning a different response code
 func (ms *MockTigerGraphServer) Mock(url string, f handlerFunc) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	ms.mockHandlers[url] = f
 }
 
+// CallCount returns the number of calls made to the given URL. Unlike reading Calls, it is
+// safe to use while requests are in flight.
+func (ms *MockTigerGraphServer) CallCount(url string) int {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	return len(ms.Calls[url])
+}
+
 // MockResponse sets the mock server to respond with a given response on the supplied url.
 func (ms *MockTigerGraphServer) MockResponse(url string, response interface{}) {
 	ms.Mock(url, func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationErrors(t *testing.T) { //nolint:funlen
+	badRequest := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "upsert errors name the operation, graph and endpoint",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(tigergraph.UpsertURL+"/"+graphName, badRequest)
+
+				_, err := client.Upsert(context.Background(), graphName, tigergraph.NewUpsertPayload())
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+
+				var opErr *tigergraph.OperationError
+				assert.True(t, errors.As(err, &opErr))
+				assert.Equal(t, "upsert", opErr.Op)
+				assert.Equal(t, graphName, opErr.Graph)
+				assert.Equal(t, "/graph/"+graphName, opErr.Endpoint)
+				assert.Equal(t, "upsert graph="+graphName+" endpoint=/graph/"+graphName+": "+tigergraph.ErrNonOK.Error(), err.Error())
+			},
+		},
+		{
+			name: "upsert error responses are wrapped too",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(tigergraph.UpsertURL+"/"+graphName, tigergraph.UpsertResponse{Error: true, Message: "bad vertex"})
+
+				_, err := client.Upsert(context.Background(), graphName, tigergraph.NewUpsertPayload())
+				assert.ErrorContains(t, err, "upsert graph="+graphName+" endpoint=/graph/"+graphName+": ")
+				assert.ErrorContains(t, err, "bad vertex")
+			},
+		},
+		{
+			name: "query strings are left out and the operation can be named",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				queryURL := "/query/" + graphName + "/my_query"
+				srv.Mock(queryURL+"?name=secret", badRequest)
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), queryURL+"?name=secret", graphName, &result)
+				assert.Equal(t, "get graph="+graphName+" endpoint="+queryURL+": "+tigergraph.ErrNonOK.Error(), err.Error())
+
+				err = client.Get(
+					context.Background(), queryURL+"?name=secret", graphName, &result, tigergraph.WithOperation("find person"),
+				)
+				assert.Equal(t, "find person graph="+graphName+" endpoint="+queryURL+": "+tigergraph.ErrNonOK.Error(), err.Error())
+			},
+		},
+		{
+			name: "gsql errors have no graph",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(tigergraph.FileURL, badRequest)
+
+				err := client.RunGSQL(context.Background(), "LS")
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.ErrorContains(t, err, "gsql endpoint="+tigergraph.FileURL+": ")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
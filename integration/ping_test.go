@@ -0,0 +1,118 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPing(t *testing.T) { //nolint:funlen
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "returns the server's reply",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(tigergraph.PingURL, func(w http.ResponseWriter, r *http.Request) {
+					assert.Empty(t, r.Header.Get("Authorization"))
+					_, err := w.Write([]byte(`{"error": false, "message": "pong", "results": null}`))
+					assert.Nil(t, err)
+				})
+
+				result, err := client.Ping(context.Background())
+				assert.Nil(t, err)
+				assert.Equal(t, "pong", result.Message)
+				assert.Positive(t, result.Latency)
+				assert.Equal(t, 0, srv.CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+		{
+			name: "accepts an empty body",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				result, err := client.Ping(context.Background())
+				assert.Nil(t, err)
+				assert.Empty(t, result.Message)
+			},
+		},
+		{
+			name: "error responses",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(tigergraph.PingURL, func(w http.ResponseWriter, _ *http.Request) {
+					_, err := w.Write([]byte(`{"error": true, "message": "not ready"}`))
+					assert.Nil(t, err)
+				})
+
+				_, err := client.Ping(context.Background())
+				assert.ErrorIs(t, err, tigergraph.ErrTigerGraphError)
+				assert.ErrorContains(t, err, "not ready")
+			},
+		},
+		{
+			name: "gives up after the ping timeout",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(tigergraph.PingURL, func(w http.ResponseWriter, r *http.Request) {
+					select {
+					case <-r.Context().Done():
+					case <-time.After(time.Second):
+					}
+				})
+
+				client.Timeouts.Ping = 50 * time.Millisecond
+
+				start := time.Now()
+				_, err := client.Ping(context.Background())
+				assert.Less(t, time.Since(start), 500*time.Millisecond)
+				assert.ErrorIs(t, err, tigergraph.ErrDeadlineExceeded)
+
+				var deadlineErr *tigergraph.DeadlineExceededError
+				if assert.ErrorAs(t, err, &deadlineErr) {
+					assert.Equal(t, 50*time.Millisecond, deadlineErr.Timeout)
+					assert.False(t, deadlineErr.CallerDeadline)
+				}
+			},
+		},
+		{
+			name: "the timeout can be overridden per call",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(tigergraph.PingURL, func(w http.ResponseWriter, r *http.Request) {
+					select {
+					case <-r.Context().Done():
+					case <-time.After(time.Second):
+					}
+				})
+
+				_, err := client.Ping(context.Background(), tigergraph.WithRequestTimeout(50*time.Millisecond))
+				assert.ErrorIs(t, err, tigergraph.ErrDeadlineExceeded)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
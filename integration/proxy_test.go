@@ -0,0 +1,110 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxy(t *testing.T) { //nolint:funlen
+	// The TigerGraph hosts are never reached directly, only through the proxy
+	restppURL := "http://restpp.tigergraph.invalid:9000"
+	gsqlURL := "http://gsql.tigergraph.invalid:14240"
+	queryURL := "/query/" + graphName + "/my_query"
+
+	var mu sync.Mutex
+	proxied := make([]string, 0)
+
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	srv.MockResponse(queryURL, tigergraph.TigerGraphResponse[any]{Message: "proxied"})
+	srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+		_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+		assert.Nil(t, err)
+	})
+
+	// A forward proxy receives the absolute URL, which it sends on to the mock server
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, r.URL.Host+r.URL.Path)
+		mu.Unlock()
+
+		forwarded, err := http.NewRequestWithContext(r.Context(), r.Method, srv.HTTPServer.URL+r.URL.RequestURI(), r.Body)
+		assert.Nil(t, err)
+		forwarded.Header = r.Header.Clone()
+
+		resp, err := http.DefaultTransport.RoundTrip(forwarded)
+		assert.Nil(t, err)
+		defer resp.Body.Close()
+
+		w.WriteHeader(resp.StatusCode)
+		_, err = io.Copy(w, resp.Body)
+		assert.Nil(t, err)
+	}))
+	defer proxy.Close()
+
+	tests := []struct {
+		name   string
+		option tigergraph.Option
+	}{
+		{name: "proxy URL", option: tigergraph.WithProxyURL(proxy.URL)},
+		{
+			name: "proxy func",
+			option: tigergraph.WithProxy(func(*http.Request) (*url.URL, error) {
+				return url.Parse(proxy.URL)
+			}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			proxied = proxied[:0]
+
+			client := tigergraph.NewClient(
+				restppURL,
+				tigergraph.WithFileURL(gsqlURL),
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				test.option,
+			)
+
+			var result tigergraph.TigerGraphResponse[any]
+			assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+			assert.Equal(t, "proxied", result.Message)
+			assert.Nil(t, client.RunGSQL(context.Background(), "LS"))
+
+			assert.Equal(t, []string{
+				"restpp.tigergraph.invalid:9000" + tigergraph.RequestTokenURL,
+				"restpp.tigergraph.invalid:9000" + queryURL,
+				"gsql.tigergraph.invalid:14240" + tigergraph.FileURL,
+			}, proxied)
+		})
+	}
+
+	t.Run("invalid proxy URL", func(t *testing.T) {
+		client := tigergraph.NewClient(restppURL, tigergraph.WithProxyURL("://"))
+		err := client.RunGSQL(context.Background(), "LS")
+		assert.ErrorIs(t, err, tigergraph.ErrInvalidOption)
+	})
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryAllowList(t *testing.T) { //nolint:funlen
+	allowedURL := "/query/" + graphName + "/allowed_query"
+	otherURL := "/query/" + graphName + "/other_query"
+	response := tigergraph.TigerGraphResponse[any]{}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "allowed queries are run",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(allowedURL+"?limit=1", response)
+				srv.MockResponse(allowedURL, response)
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), allowedURL+"?limit=1", graphName, &result))
+				assert.Nil(t, client.Post(context.Background(), allowedURL, graphName, map[string]any{}, &result))
+			},
+		},
+		{
+			name: "other queries are rejected without a request",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(otherURL, response)
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), otherURL, graphName, &result)
+				assert.ErrorIs(t, err, tigergraph.ErrQueryNotAllowed)
+
+				err = client.PostRaw(context.Background(), allowedURL+"/../other_query", graphName, []byte(`{}`), &result)
+				assert.ErrorIs(t, err, tigergraph.ErrQueryNotAllowed)
+
+				assert.Zero(t, srv.CallCount(otherURL))
+				assert.Zero(t, srv.CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+		{
+			name: "other endpoints and the library's own queries are allowed",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, tigergraph.CurrentMigrationVersionResponse{
+					Results: []tigergraph.CurrentMigrationVersionResponseResult{{}},
+				})
+
+				_, err := client.GetCurrentMigrationNumber(context.Background(), graphName)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, srv.CallCount(tigergraph.GetCurrentMigrationVersionURL))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(
+				srv.HTTPServer.URL,
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				tigergraph.WithQueryAllowList("allowed_query"),
+			)
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseQuery(t *testing.T) { //nolint:funlen
+	metadataUpsertURL := tigergraph.UpsertURL + "/" + tigergraph.MetadataGraphName
+	aliasesURL := fmt.Sprintf(tigergraph.VerticesURLTemplate, tigergraph.MetadataGraphName, tigergraph.QueryAliasVertexType)
+	metadataSchemaURL := tigergraph.GetGraphMetadataQueryURL + "?graph=ClientMetadata"
+	endpointsURL := fmt.Sprintf(tigergraph.EndpointsURLTemplate, graphName)
+	queryGSQL := "USE GRAPH {{GRAPH_NAME}}\nCREATE QUERY {{QUERY_NAME}}() FOR GRAPH {{GRAPH_NAME}} { PRINT 1; }\nINSTALL QUERY {{QUERY_NAME}}"
+
+	// mockServer mocks the metadata graph with the given alias records, the graph with the
+	// given installed queries and a GSQL server which succeeds
+	mockServer := func(t *testing.T, srv *MockTigerGraphServer, installed []string, records ...tigergraph.QueryAliasRecord) {
+		t.Helper()
+		srv.MockResponse(metadataSchemaURL, tigergraph.GraphMetadataResponse{
+			Results: &tigergraph.GraphMetadataResponseResult{GraphName: tigergraph.MetadataGraphName},
+		})
+
+		response := tigergraph.TigerGraphResponse[tigergraph.ResponseVertex[tigergraph.QueryAliasRecord]]{
+			Results: []tigergraph.ResponseVertex[tigergraph.QueryAliasRecord]{},
+		}
+		for _, record := range records {
+			response.Results = append(response.Results, tigergraph.ResponseVertex[tigergraph.QueryAliasRecord]{
+				VID:        record.ID,
+				Attributes: record,
+			})
+		}
+		srv.MockResponse(aliasesURL, response)
+
+		endpoints := map[string]any{}
+		for _, queryName := range installed {
+			endpoints["POST /query/"+graphName+"/"+queryName] = map[string]any{}
+		}
+		srv.MockResponse(endpointsURL, endpoints)
+
+		srv.MockResponse(metadataUpsertURL, tigergraph.UpsertResponse{
+			Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+		})
+		srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+			_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+			assert.Nil(t, err)
+		})
+	}
+
+	// gsqlRun returns the GSQL sent to the GSQL server
+	gsqlRun := func(t *testing.T, srv *MockTigerGraphServer) []string {
+		t.Helper()
+		result := make([]string, 0)
+		for _, call := range srv.Calls[tigergraph.FileURL] {
+			body, err := io.ReadAll(call)
+			assert.Nil(t, err)
+			gsql, err := url.QueryUnescape(string(body))
+			assert.Nil(t, err)
+			result = append(result, gsql)
+		}
+
+		return result
+	}
+
+	// savedAliases returns the query each upsert to the metadata graph pointed an alias at
+	savedAliases := func(t *testing.T, srv *MockTigerGraphServer) []string {
+		t.Helper()
+		result := make([]string, 0)
+		for _, call := range srv.Calls[metadataUpsertURL] {
+			body, err := io.ReadAll(call)
+			assert.Nil(t, err)
+
+			var payload struct {
+				Vertices map[string]map[string]map[string]tigergraph.ValueWrapper `json:"vertices"`
+			}
+			assert.Nil(t, json.Unmarshal(body, &payload))
+
+			for id, attributes := range payload.Vertices[tigergraph.QueryAliasVertexType] {
+				result = append(result, fmt.Sprintf("%s %v", id, attributes["query_name"].Value))
+			}
+		}
+
+		return result
+	}
+
+	aliasID := graphName + "_people"
+	v1 := tigergraph.QueryAliasRecord{ID: aliasID, Graph: graphName, Alias: "people", QueryName: "people_v1", Version: 1}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "installs, validates and repoints the alias, then drops the previous version",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockServer(t, srv, []string{"people_v1"}, v1)
+
+				validated := ""
+				result, err := client.ReleaseQuery(context.Background(), tigergraph.QueryRelease{
+					Graph:   graphName,
+					Alias:   "people",
+					Version: 2,
+					GSQL:    queryGSQL,
+					Validate: func(_ context.Context, queryName string) error {
+						validated = queryName
+						return nil
+					},
+				})
+				assert.Nil(t, err)
+				assert.Equal(t, &tigergraph.QueryReleaseResult{
+					QueryName:         "people_v2",
+					PreviousQueryName: "people_v1",
+					PreviousDropped:   true,
+				}, result)
+				assert.Equal(t, "people_v2", validated)
+
+				gsql := gsqlRun(t, srv)
+				if assert.Len(t, gsql, 2) {
+					assert.Contains(t, gsql[0], "CREATE QUERY people_v2() FOR GRAPH "+graphName)
+					assert.Contains(t, gsql[0], "INSTALL QUERY people_v2")
+					assert.Equal(t, "USE GRAPH "+graphName+"\nDROP QUERY people_v1", gsql[1])
+				}
+				assert.Equal(t, []string{aliasID + " people_v2"}, savedAliases(t, srv))
+			},
+		},
+		{
+			name: "the first release adds the alias vertex type with the metadata migrations",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.OutdatedMetadataSchema = true
+				mockServer(t, srv, nil)
+				srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, tigergraph.CurrentMigrationVersionResponse{
+					Results: []tigergraph.CurrentMigrationVersionResponseResult{{LatestMigration: []tigergraph.MigrationVertex{}}},
+				})
+
+				result, err := client.ReleaseQuery(context.Background(), tigergraph.QueryRelease{
+					Graph:   graphName,
+					Alias:   "people",
+					Version: 1,
+					GSQL:    queryGSQL,
+				})
+				assert.Nil(t, err)
+				assert.Equal(t, "people_v1", result.QueryName)
+				assert.Empty(t, result.PreviousQueryName)
+				assert.False(t, result.PreviousDropped)
+
+				// The built-in metadata migrations, the fourth of which adds the alias vertex
+				// type, then the query
+				gsql := gsqlRun(t, srv)
+				if assert.Len(t, gsql, tigergraph.BuiltinMetadataSchemaVersion()+1) {
+					assert.Equal(t, tigergraph.QueryAliasSchemaGSQL, gsql[3])
+					assert.Contains(t, gsql[len(gsql)-1], "CREATE QUERY people_v1() FOR GRAPH "+graphName)
+				}
+				assert.Equal(t, 1, srv.CallCount(aliasesURL))
+				assert.Equal(t, []string{aliasID + " people_v1"}, savedAliases(t, srv))
+			},
+		},
+		{
+			name: "a version which fails validation is dropped and the alias is left alone",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockServer(t, srv, []string{"people_v1"}, v1)
+				validationErr := errors.New("unexpected result")
+
+				_, err := client.ReleaseQuery(context.Background(), tigergraph.QueryRelease{
+					Graph:   graphName,
+					Alias:   "people",
+					Version: 2,
+					GSQL:    queryGSQL,
+					Validate: func(context.Context, string) error {
+						return validationErr
+					},
+				})
+				assert.ErrorIs(t, err, tigergraph.ErrQueryValidationFailed)
+				assert.ErrorIs(t, err, validationErr)
+
+				gsql := gsqlRun(t, srv)
+				if assert.Len(t, gsql, 2) {
+					assert.Equal(t, "USE GRAPH "+graphName+"\nDROP QUERY people_v2", gsql[1])
+				}
+				assert.Empty(t, savedAliases(t, srv))
+			},
+		},
+		{
+			name: "an installed version is not reinstalled and the previous one can be kept",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockServer(t, srv, []string{"people_v1", "people_v2"}, v1)
+
+				result, err := client.ReleaseQuery(context.Background(), tigergraph.QueryRelease{
+					Graph:        graphName,
+					Alias:        "people",
+					Version:      2,
+					GSQL:         queryGSQL,
+					KeepPrevious: true,
+				})
+				assert.Nil(t, err)
+				assert.False(t, result.PreviousDropped)
+				assert.Empty(t, gsqlRun(t, srv))
+				assert.Equal(t, []string{aliasID + " people_v2"}, savedAliases(t, srv))
+			},
+		},
+		{
+			name: "resolves aliases to the active version",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockServer(t, srv, []string{"people_v1"}, v1,
+					tigergraph.QueryAliasRecord{ID: "Other_people", Graph: "Other", Alias: "people", QueryName: "people_v7", Version: 7},
+				)
+
+				queryName, err := client.ActiveQueryName(context.Background(), graphName, "people")
+				assert.Nil(t, err)
+				assert.Equal(t, "people_v1", queryName)
+
+				_, err = client.ActiveQueryName(context.Background(), graphName, "companies")
+				assert.ErrorIs(t, err, tigergraph.ErrQueryAliasNotFound)
+				assert.ErrorContains(t, err, "companies")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
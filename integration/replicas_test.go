@@ -0,0 +1,143 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadReplicas(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/my_query"
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, srv *MockTigerGraphServer, replicas []*MockTigerGraphServer)
+	}{
+		{
+			name: "reads go to replicas in turn and writes to the primary",
+			action: func(t *testing.T, srv *MockTigerGraphServer, replicas []*MockTigerGraphServer) {
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithReadReplicas(replicas[0].HTTPServer.URL, replicas[1].HTTPServer.URL),
+				)
+				for _, s := range append(replicas, srv) {
+					s.MockResponse(queryURL, tigergraph.TigerGraphResponse[any]{})
+				}
+
+				ctx := context.Background()
+				var result tigergraph.TigerGraphResponse[any]
+				for i := 0; i < 4; i++ {
+					assert.Nil(t, client.Get(ctx, queryURL, graphName, &result))
+				}
+				assert.Nil(t, client.Post(ctx, queryURL, graphName, map[string]any{}, &result))
+				assert.Nil(t, client.Post(ctx, queryURL, graphName, map[string]any{}, &result, tigergraph.WithRoute(tigergraph.RouteReplica)))
+				assert.Nil(t, client.Get(ctx, queryURL, graphName, &result, tigergraph.WithRoute(tigergraph.RoutePrimary)))
+
+				assert.Equal(t, 3, replicas[0].CallCount(queryURL))
+				assert.Equal(t, 2, replicas[1].CallCount(queryURL))
+				assert.Equal(t, 2, srv.CallCount(queryURL))
+
+				// Tokens are only requested from the primary
+				assert.Zero(t, replicas[0].CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+		{
+			name: "unreachable replicas are skipped and the call sent to the primary",
+			action: func(t *testing.T, srv *MockTigerGraphServer, replicas []*MockTigerGraphServer) {
+				down := replicas[0].HTTPServer.URL
+				replicas[0].Close()
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithReadReplicas(down, replicas[1].HTTPServer.URL),
+				)
+				srv.MockResponse(queryURL, tigergraph.TigerGraphResponse[any]{})
+				replicas[1].MockResponse(queryURL, tigergraph.TigerGraphResponse[any]{})
+
+				ctx := context.Background()
+				var result tigergraph.TigerGraphResponse[any]
+				for i := 0; i < 3; i++ {
+					assert.Nil(t, client.Get(ctx, queryURL, graphName, &result))
+				}
+
+				assert.Equal(t, 1, srv.CallCount(queryURL))
+				assert.Equal(t, 2, replicas[1].CallCount(queryURL))
+				assert.Equal(t, map[string]bool{down: false, replicas[1].HTTPServer.URL: true}, client.ReplicaHealth())
+			},
+		},
+		{
+			name: "server errors from a replica are returned, not retried on the primary",
+			action: func(t *testing.T, srv *MockTigerGraphServer, replicas []*MockTigerGraphServer) {
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithReadReplicas(replicas[0].HTTPServer.URL),
+				)
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), queryURL, graphName, &result)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Zero(t, srv.CallCount(queryURL))
+			},
+		},
+		{
+			name: "CheckReplicas marks replicas healthy or unhealthy",
+			action: func(t *testing.T, srv *MockTigerGraphServer, replicas []*MockTigerGraphServer) {
+				now := time.Now()
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithReadReplicas(replicas[0].HTTPServer.URL, replicas[1].HTTPServer.URL),
+					tigergraph.WithReplicaRetryInterval(time.Minute),
+					tigergraph.WithClock(func() time.Time { return now }),
+				)
+				replicas[0].MockResponse(tigergraph.EchoURL, tigergraph.EchoResponse{Message: "Hello GSQL"})
+
+				err := client.CheckReplicas(context.Background())
+				assert.ErrorContains(t, err, replicas[1].HTTPServer.URL)
+				assert.Equal(t, map[string]bool{
+					replicas[0].HTTPServer.URL: true,
+					replicas[1].HTTPServer.URL: false,
+				}, client.ReplicaHealth())
+
+				// Unhealthy replicas are used again after the retry interval
+				now = now.Add(time.Minute)
+				assert.True(t, client.ReplicaHealth()[replicas[1].HTTPServer.URL])
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			replicas := []*MockTigerGraphServer{
+				NewMockServer(expectedUsername, expectedPassword),
+				NewMockServer(expectedUsername, expectedPassword),
+			}
+			for _, replica := range replicas {
+				defer replica.Close()
+			}
+
+			test.action(t, srv, replicas)
+		})
+	}
+}
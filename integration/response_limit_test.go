@@ -0,0 +1,119 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseLimit(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/big_query"
+	verticesURL := "/graph/" + graphName + "/vertices/Person"
+	bigResponse := `{"error": false, "results": [{"padding": "` + strings.Repeat("x", 1000) + `"}]}`
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "query responses over the limit return ErrResponseLimitExceeded",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.QueryResponseLimit = 100
+				headers := make([]string, 0)
+				srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+					headers = append(headers, r.Header.Get(tigergraph.ResponseLimitHeader))
+					_, err := w.Write([]byte(bigResponse))
+					assert.Nil(t, err)
+				})
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), queryURL, graphName, &result)
+				assert.ErrorIs(t, err, tigergraph.ErrResponseLimitExceeded)
+
+				err = client.Post(context.Background(), queryURL, graphName, map[string]any{}, &result)
+				assert.ErrorIs(t, err, tigergraph.ErrResponseLimitExceeded)
+
+				assert.Equal(t, []string{"100", "100"}, headers)
+			},
+		},
+		{
+			name: "responses within the limit are read",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.QueryResponseLimit = 100
+				srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+					_, err := w.Write([]byte(bigResponse))
+					assert.Nil(t, err)
+				})
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result, tigergraph.WithResponseLimit(2000)))
+				assert.Len(t, result.Results, 1)
+			},
+		},
+		{
+			name: "the client limit applies only to installed queries",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.QueryResponseLimit = 100
+				headers := make([]string, 0)
+				srv.Mock(verticesURL, func(w http.ResponseWriter, r *http.Request) {
+					headers = append(headers, r.Header.Get(tigergraph.ResponseLimitHeader))
+					_, err := w.Write([]byte(bigResponse))
+					assert.Nil(t, err)
+				})
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), verticesURL, graphName, &result))
+
+				err := client.Get(context.Background(), verticesURL, graphName, &result, tigergraph.WithResponseLimit(100))
+				assert.ErrorIs(t, err, tigergraph.ErrResponseLimitExceeded)
+
+				assert.Equal(t, []string{"", "100"}, headers)
+			},
+		},
+		{
+			name: "the limit applies to decompressed responses",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Encoding", "gzip")
+					writer := gzip.NewWriter(w)
+					_, err := writer.Write([]byte(bigResponse))
+					assert.Nil(t, err)
+					assert.Nil(t, writer.Close())
+				})
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), queryURL, graphName, &result, tigergraph.WithResponseLimit(500))
+				assert.ErrorIs(t, err, tigergraph.ErrResponseLimitExceeded)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
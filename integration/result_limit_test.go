@@ -0,0 +1,110 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxResultVertices(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/people"
+
+	// peopleResponse is a query response with the given number of vertices
+	peopleResponse := func(n int) string {
+		vertices := make([]string, n)
+		for i := range vertices {
+			vertices[i] = fmt.Sprintf(`{"v_id": "%d", "v_type": "Person", "attributes": {"name": "v_id"}}`, i)
+		}
+		return `{"error": false, "results": [{"people": [` + strings.Join(vertices, ", ") + `]}]}`
+	}
+
+	tests := []struct {
+		name        string
+		vertices    int
+		gzip        bool
+		opts        []tigergraph.RequestOption
+		expectedErr error
+	}{
+		{
+			name:     "results within the limit are decoded",
+			vertices: 3,
+			opts:     []tigergraph.RequestOption{tigergraph.MaxResultVertices(3)},
+		},
+		{
+			name:        "results over the limit return ErrResultTooLarge",
+			vertices:    4,
+			opts:        []tigergraph.RequestOption{tigergraph.MaxResultVertices(3)},
+			expectedErr: tigergraph.ErrResultTooLarge,
+		},
+		{
+			name:        "compressed results are counted once decompressed",
+			vertices:    4,
+			gzip:        true,
+			opts:        []tigergraph.RequestOption{tigergraph.MaxResultVertices(3)},
+			expectedErr: tigergraph.ErrResultTooLarge,
+		},
+		{
+			name:     "results are not limited by default",
+			vertices: 1000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+				if !test.gzip {
+					_, err := w.Write([]byte(peopleResponse(test.vertices)))
+					assert.Nil(t, err)
+					return
+				}
+
+				w.Header().Set("Content-Encoding", "gzip")
+				writer := gzip.NewWriter(w)
+				_, err := writer.Write([]byte(peopleResponse(test.vertices)))
+				assert.Nil(t, err)
+				assert.Nil(t, writer.Close())
+			})
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			for _, call := range []func(result any) error{
+				func(result any) error {
+					return client.Get(context.Background(), queryURL, graphName, result, test.opts...)
+				},
+				func(result any) error {
+					return client.Post(context.Background(), queryURL, graphName, map[string]any{}, result, test.opts...)
+				},
+			} {
+				var result tigergraph.TigerGraphResponse[map[string][]tigergraph.ResponseVertex[map[string]any]]
+				err := call(&result)
+
+				assert.ErrorIs(t, err, test.expectedErr)
+				if test.expectedErr == nil && assert.Len(t, result.Results, 1) {
+					assert.Len(t, result.Results[0]["people"], test.vertices)
+				}
+			}
+		})
+	}
+}
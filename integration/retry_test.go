@@ -0,0 +1,139 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) { //nolint:funlen
+	upsertURL := tigergraph.UpsertURL + "/" + graphName
+	policy := &tigergraph.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	failingHandler := func(failures int, status int) handlerFunc {
+		attempts := 0
+		return func(w http.ResponseWriter, _ *http.Request) {
+			attempts++
+			if attempts <= failures {
+				w.WriteHeader(status)
+				return
+			}
+
+			_, err := w.Write([]byte(`{"results": [{"accepted_vertices": 1}]}`))
+			assert.Nil(t, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "retries transient statuses and resends the body",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.RetryPolicy = policy
+				srv.Mock(upsertURL, failingHandler(2, http.StatusBadGateway))
+
+				result, err := client.Upsert(context.Background(), graphName, map[string]any{"vertices": map[string]any{}})
+				assert.Nil(t, err)
+				assert.Equal(t, 1, result.AcceptedVertices)
+
+				calls := srv.Calls[upsertURL]
+				assert.Len(t, calls, 3)
+				assert.Equal(t, calls[0], calls[2])
+			},
+		},
+		{
+			name: "gives up after MaxAttempts",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.RetryPolicy = policy
+				srv.Mock(upsertURL, failingHandler(5, http.StatusInternalServerError))
+
+				_, err := client.Upsert(context.Background(), graphName, map[string]any{})
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Len(t, srv.Calls[upsertURL], 3)
+			},
+		},
+		{
+			name: "does not retry other statuses",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.RetryPolicy = policy
+				srv.Mock(upsertURL, failingHandler(5, http.StatusBadRequest))
+
+				_, err := client.Upsert(context.Background(), graphName, map[string]any{})
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Len(t, srv.Calls[upsertURL], 1)
+			},
+		},
+		{
+			name: "does not retry without a policy",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(upsertURL, failingHandler(1, http.StatusBadGateway))
+
+				_, err := client.Upsert(context.Background(), graphName, map[string]any{})
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Len(t, srv.Calls[upsertURL], 1)
+			},
+		},
+		{
+			name: "does not retry GSQL",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.RetryPolicy = policy
+				srv.Mock(tigergraph.FileURL, failingHandler(1, http.StatusBadGateway))
+
+				err := client.RunGSQL(context.Background(), "LS")
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Len(t, srv.Calls[tigergraph.FileURL], 1)
+			},
+		},
+		{
+			name: "retries network errors",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				unreachable := httptest.NewServer(http.NotFoundHandler())
+				unreachable.Close()
+
+				metrics := &recordingRequestMetrics{}
+				client.BaseURL = unreachable.URL
+				client.RetryPolicy = policy
+				client.RequestMetrics = metrics
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), "/echo", graphName, &result)
+				assert.NotNil(t, err)
+
+				var requestErr *tigergraph.RequestError
+				assert.ErrorAs(t, err, &requestErr)
+				assert.Len(t, metrics.events, 3)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevokeToken(t *testing.T) { //nolint:funlen
+	// mockRevocation issues tokens with basic auth and records each revocation request
+	mockRevocation := func(t *testing.T, srv *MockTigerGraphServer, revoked *[]tigergraph.RevokeTokenRequest, status int) {
+		t.Helper()
+		issue := makeDefaultRequestTokenHandler(expectedUsername, expectedPassword, time.Now().Add(time.Hour).Unix())
+
+		srv.Mock(tigergraph.RequestTokenURL, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodDelete {
+				issue(w, r)
+				return
+			}
+
+			var request tigergraph.RevokeTokenRequest
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+			*revoked = append(*revoked, request)
+
+			_, _, hasBasicAuth := r.BasicAuth()
+			assert.Equal(t, request.Secret == "", hasBasicAuth)
+
+			w.WriteHeader(status)
+			_, err := w.Write([]byte(`{"error": false}`))
+			assert.Nil(t, err)
+		})
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "revokes the graph's token and clears it from the cache",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				var revoked []tigergraph.RevokeTokenRequest
+				mockRevocation(t, srv, &revoked, http.StatusOK)
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Nil(t, client.Auth(context.Background(), "OtherGraph"))
+
+				assert.Nil(t, client.RevokeToken(context.Background(), graphName))
+				assert.Equal(t, []tigergraph.RevokeTokenRequest{{Token: "sometoken"}}, revoked)
+				assert.Equal(t, []string{"OtherGraph"}, client.Tokens.Graphs())
+			},
+		},
+		{
+			name: "does nothing without a cached token",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				var revoked []tigergraph.RevokeTokenRequest
+				mockRevocation(t, srv, &revoked, http.StatusOK)
+
+				assert.Nil(t, client.RevokeToken(context.Background(), graphName))
+				assert.Empty(t, revoked)
+			},
+		},
+		{
+			name: "tokens requested with a secret are revoked with it",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				var revoked []tigergraph.RevokeTokenRequest
+				mockRevocation(t, srv, &revoked, http.StatusOK)
+
+				client.Secrets = map[string]string{graphName: "graph-secret"}
+				client.Tokens.Set(graphName, &tigergraph.Token{Value: "secrettoken", Expires: time.Now().Add(time.Hour)})
+
+				assert.Nil(t, client.RevokeToken(context.Background(), graphName))
+				assert.Equal(t, []tigergraph.RevokeTokenRequest{{Token: "secrettoken", Secret: "graph-secret"}}, revoked)
+			},
+		},
+		{
+			name: "failures are returned and the cache entry is still cleared",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				var revoked []tigergraph.RevokeTokenRequest
+				mockRevocation(t, srv, &revoked, http.StatusForbidden)
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+
+				err := client.RevokeToken(context.Background(), graphName)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Empty(t, client.Tokens.Graphs())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -146,6 +146,54 @@ func TestClientRunGSQL(t *testing.T) { //nolint:funlen
 				assert.Len(t, calls, 1)
 			},
 		},
+		{
+			name:     "large GSQL is split and run in parts",
+			username: expectedUsername,
+			password: expectedPassword,
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				gsqlBody := "USE GRAPH Relationships\nDROP QUERY q1\nDROP QUERY q2\nDROP QUERY q3\n"
+				client.MaxGSQLBodySize = len(url.QueryEscape("USE GRAPH Relationships\nDROP QUERY q1\n"))
+
+				srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+					_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+					assert.Nil(t, err)
+				})
+
+				err := client.RunGSQL(context.Background(), gsqlBody)
+				assert.Nil(t, err)
+
+				calls := srv.Calls[tigergraph.FileURL]
+				assert.Len(t, calls, 3)
+				assert.Equal(t, bytes.NewBufferString(url.QueryEscape("USE GRAPH Relationships\nDROP QUERY q1\n")), calls[0])
+				assert.Equal(t, bytes.NewBufferString(url.QueryEscape("USE GRAPH Relationships\nDROP QUERY q2\n")), calls[1])
+				assert.Equal(t, bytes.NewBufferString(url.QueryEscape("USE GRAPH Relationships\nDROP QUERY q3\n")), calls[2])
+			},
+		},
+		{
+			name:     "split GSQL stops at the first failing part",
+			username: expectedUsername,
+			password: expectedPassword,
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				gsqlBody := "USE GRAPH Relationships\nDROP QUERY q1\nDROP QUERY q2\nDROP QUERY q3\n"
+				client.MaxGSQLBodySize = len(url.QueryEscape("USE GRAPH Relationships\nDROP QUERY q1\n"))
+
+				calls := 0
+				srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+					calls++
+					if calls == 2 {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+					assert.Nil(t, err)
+				})
+
+				err := client.RunGSQL(context.Background(), gsqlBody)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.ErrorContains(t, err, "GSQL part 2 of 3, starting at line 3, failed after 1 part(s) succeeded")
+				assert.Equal(t, 2, calls)
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -153,12 +201,7 @@ func TestClientRunGSQL(t *testing.T) { //nolint:funlen
 			srv := NewMockServer(expectedUsername, expectedPassword)
 			defer srv.Close()
 
-			client := tigergraph.NewClient(
-				srv.HTTPServer.URL,
-				srv.HTTPServer.URL,
-				test.username,
-				test.password,
-			)
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(test.username, test.password))
 
 			test.action(t, client, srv)
 		})
@@ -236,12 +236,7 @@ func TestLoadingJobJSONL(t *testing.T) { //nolint:funlen
 			srv := NewMockServer(expectedUsername, expectedPassword)
 			defer srv.Close()
 
-			client := tigergraph.NewClient(
-				srv.HTTPServer.URL,
-				srv.HTTPServer.URL,
-				test.username,
-				test.password,
-			)
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(test.username, test.password))
 
 			test.action(t, client, srv)
 		})
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadingJobRaw(t *testing.T) { //nolint:funlen
+	testLoadingJobURL := fmt.Sprintf("/ddl/%s?tag=%s&filename=f", graphName, "test_loading_job")
+	avroBytes := []byte{0x4f, 0x62, 0x6a, 0x01, 0x00, 0xff}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "sends the body as is with its content type",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(testLoadingJobURL, func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, "avro/binary", r.Header.Get("Content-Type"))
+					assert.Equal(t, "Bearer sometoken", r.Header.Get("Authorization"))
+					assert.Nil(t, json.NewEncoder(w).Encode(tigergraph.LoadingJobResponse{
+						Results: []tigergraph.LoadingJobResponseResult{
+							{Statistics: tigergraph.LoadingJobStatistics{ValidLine: 3}},
+						},
+					}))
+				})
+
+				statistics, err := client.RunLoadingJobRaw(
+					context.Background(), graphName, "test_loading_job", bytes.NewReader(avroBytes), "avro/binary",
+				)
+				assert.Nil(t, err)
+				assert.Equal(t, 3, statistics.ValidLine)
+
+				calls := srv.Calls[testLoadingJobURL]
+				if assert.Len(t, calls, 1) {
+					body, err := io.ReadAll(calls[0])
+					assert.Nil(t, err)
+					assert.Equal(t, avroBytes, body)
+				}
+			},
+		},
+		{
+			name: "failure counters fail the job and the statistics are returned",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(testLoadingJobURL, tigergraph.LoadingJobResponse{
+					Results: []tigergraph.LoadingJobResponseResult{
+						{Statistics: tigergraph.LoadingJobStatistics{ValidLine: 2, RejectLine: 1}},
+					},
+				})
+
+				statistics, err := client.RunLoadingJobRaw(
+					context.Background(), graphName, "test_loading_job", bytes.NewReader(avroBytes), "avro/binary",
+				)
+				assert.ErrorIs(t, err, tigergraph.ErrLoadingJobPartialFailure)
+				assert.ErrorContains(t, err, "rejectLine=1")
+				if assert.NotNil(t, statistics) {
+					assert.Equal(t, 1, statistics.RejectLine)
+				}
+
+				var opErr *tigergraph.OperationError
+				if assert.ErrorAs(t, err, &opErr) {
+					assert.Equal(t, "run loading job", opErr.Op)
+				}
+			},
+		},
+		{
+			name: "a response without one result fails",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(testLoadingJobURL, tigergraph.LoadingJobResponse{})
+
+				_, err := client.RunLoadingJobRaw(
+					context.Background(), graphName, "test_loading_job", bytes.NewReader(avroBytes), "avro/binary",
+				)
+				assert.ErrorIs(t, err, tigergraph.ErrLoadingJobRequestFailed)
+			},
+		},
+		{
+			name: "request options are applied",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(testLoadingJobURL, func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, "trace", r.Header.Get("X-Trace"))
+					assert.Nil(t, json.NewEncoder(w).Encode(tigergraph.LoadingJobResponse{
+						Results: []tigergraph.LoadingJobResponseResult{{}},
+					}))
+				})
+
+				_, err := client.RunLoadingJobRaw(
+					context.Background(), graphName, "test_loading_job", bytes.NewReader(avroBytes), "text/csv",
+					tigergraph.WithRequestHeader("X-Trace", "trace"),
+				)
+				assert.Nil(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecrets(t *testing.T) { //nolint:funlen
+	// mockGSQL responds to GSQL with the given output, recording the GSQL run
+	mockGSQL := func(t *testing.T, srv *MockTigerGraphServer, output string, gsql *[]string) {
+		t.Helper()
+		srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.Nil(t, err)
+			unescaped, err := url.QueryUnescape(string(body))
+			assert.Nil(t, err)
+			*gsql = append(*gsql, unescaped)
+
+			fmt.Fprintf(w, "%s\n%s\n", output, tigergraph.SuccessString)
+		})
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, logger *recordingLevelLogger)
+	}{
+		{
+			name: "creates secrets without logging them",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, logger *recordingLevelLogger) {
+				var gsql []string
+				mockGSQL(t, srv, `The secret: 3n1s7c0v2k9qm5pd has been created for user "tigergraph".`, &gsql)
+
+				secret, err := client.CreateSecret(context.Background(), graphName, "ingest")
+				assert.Nil(t, err)
+				assert.Equal(t, "3n1s7c0v2k9qm5pd", secret)
+				assert.Equal(t, []string{"USE GRAPH " + graphName + "\nCREATE SECRET ingest"}, gsql)
+
+				for _, message := range logger.messages {
+					assert.NotContains(t, fmt.Sprint(message.args...), secret)
+				}
+			},
+		},
+		{
+			name: "creates secrets with generated aliases",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, _ *recordingLevelLogger) {
+				var gsql []string
+				mockGSQL(t, srv, `The secret: 3n1s7c0v2k9qm5pd has been created for user "tigergraph".`, &gsql)
+
+				_, err := client.CreateSecret(context.Background(), graphName, "")
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"USE GRAPH " + graphName + "\nCREATE SECRET"}, gsql)
+			},
+		},
+		{
+			name: "fails if no secret was created",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, _ *recordingLevelLogger) {
+				var gsql []string
+				mockGSQL(t, srv, "Nothing happened.", &gsql)
+
+				_, err := client.CreateSecret(context.Background(), graphName, "ingest")
+				assert.ErrorIs(t, err, tigergraph.ErrSecretNotCreated)
+			},
+		},
+		{
+			name: "shows secrets",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, _ *recordingLevelLogger) {
+				var gsql []string
+				mockGSQL(t, srv, "- Secret: 3n1s****k9qm\n  - Alias: ingest\n  - GraphName: "+graphName+
+					"\n- Secret: 8h2j****kq4f\n  - Alias: AUTO_GENERATED_ALIAS_4kpcoh5", &gsql)
+
+				secrets, err := client.ShowSecrets(context.Background(), graphName)
+				assert.Nil(t, err)
+				assert.Equal(t, []tigergraph.GSQLSecret{
+					{Secret: "3n1s****k9qm", Alias: "ingest", Graph: graphName},
+					{Secret: "8h2j****kq4f", Alias: "AUTO_GENERATED_ALIAS_4kpcoh5", Graph: graphName},
+				}, secrets)
+				assert.Equal(t, []string{"USE GRAPH " + graphName + "\nSHOW SECRET"}, gsql)
+			},
+		},
+		{
+			name: "drops secrets",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer, _ *recordingLevelLogger) {
+				var gsql []string
+				mockGSQL(t, srv, "Successfully dropped secrets: ingest.", &gsql)
+
+				assert.Nil(t, client.DropSecret(context.Background(), graphName, "ingest"))
+				assert.Equal(t, []string{"USE GRAPH " + graphName + "\nDROP SECRET ingest"}, gsql)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			logger := &recordingLevelLogger{}
+			client := tigergraph.NewClient(
+				srv.HTTPServer.URL,
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				tigergraph.WithLogger(logger),
+			)
+
+			test.action(t, client, srv, logger)
+		})
+	}
+}
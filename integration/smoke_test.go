@@ -0,0 +1,156 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSmokeTest(t *testing.T) { //nolint:funlen
+	now := time.Unix(1700000000, 0)
+	smokeGraph := fmt.Sprintf("%s%d", tigergraph.SmokeTestGraphPrefix, now.UnixNano())
+	loadingJobURL := fmt.Sprintf(tigergraph.LoadingJobURLTemplate, smokeGraph, tigergraph.SmokeTestLoadingJob)
+	queryURL := fmt.Sprintf(tigergraph.QueryURLTemplate, smokeGraph, tigergraph.SmokeTestQuery)
+	migrationUpsertURL := tigergraph.UpsertURL + "/" + tigergraph.MetadataGraphName
+
+	gsqlSucceeds := func(w http.ResponseWriter, _ *http.Request) {
+		_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+		assert.Nil(t, err)
+	}
+
+	readGSQL := func(t *testing.T, srv *MockTigerGraphServer) []string {
+		t.Helper()
+		commands := make([]string, 0)
+		for _, call := range srv.Calls[tigergraph.FileURL] {
+			body, err := io.ReadAll(call)
+			assert.Nil(t, err)
+			command, err := url.QueryUnescape(string(body))
+			assert.Nil(t, err)
+			commands = append(commands, command)
+		}
+		return commands
+	}
+
+	// mockScratchGraph answers the requests made for the scratch graph up to the query
+	mockScratchGraph := func(srv *MockTigerGraphServer) {
+		srv.Mock(tigergraph.FileURL, gsqlSucceeds)
+		srv.MockResponse(tigergraph.GetGraphMetadataQueryURL+"?graph=ClientMetadata", tigergraph.GraphMetadataResponse{
+			Results: &tigergraph.GraphMetadataResponseResult{GraphName: tigergraph.MetadataGraphName},
+		})
+		srv.MockResponse(tigergraph.GetCurrentMigrationVersionURL, tigergraph.CurrentMigrationVersionResponse{
+			Results: []tigergraph.CurrentMigrationVersionResponseResult{{LatestMigration: []tigergraph.MigrationVertex{}}},
+		})
+		srv.MockResponse(migrationUpsertURL, tigergraph.UpsertResponse{
+			Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+		})
+		srv.MockResponse(loadingJobURL, tigergraph.LoadingJobResponse{
+			Results: []tigergraph.LoadingJobResponseResult{{Statistics: tigergraph.LoadingJobStatistics{ValidLine: 3}}},
+		})
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "migrates, loads, queries and drops a scratch graph",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockScratchGraph(srv)
+				srv.MockResponse(queryURL, tigergraph.TigerGraphResponse[tigergraph.SmokeTestTotals]{
+					Results: []tigergraph.SmokeTestTotals{{Count: 3, Total: 6}},
+				})
+
+				result, err := client.SmokeTest(context.Background())
+				assert.Nil(t, err)
+				assert.Equal(t, smokeGraph, result.Graph)
+
+				steps := make([]string, 0)
+				for _, step := range result.Steps {
+					steps = append(steps, step.Name)
+				}
+				assert.Equal(t, []string{"create graph", "migrate", "load", "query", "drop graph"}, steps)
+
+				commands := readGSQL(t, srv)
+				assert.Len(t, commands, 4)
+				assert.Equal(t, "CREATE GRAPH "+smokeGraph+"()", commands[0])
+				assert.Contains(t, commands[1], "CREATE LOADING JOB smoke_test_load FOR GRAPH "+smokeGraph+" {")
+				assert.Contains(t, commands[2], "INSTALL QUERY smoke_test_totals")
+				assert.NotContains(t, commands[2], "{{GRAPH_NAME}}")
+				assert.Equal(t, "DROP GRAPH "+smokeGraph+" CASCADE", commands[3])
+
+				// Both migrations were recorded for the scratch graph
+				assert.Len(t, srv.Calls[migrationUpsertURL], 2)
+
+				assert.Len(t, srv.Calls[loadingJobURL], 1)
+				lines, err := io.ReadAll(srv.Calls[loadingJobURL][0])
+				assert.Nil(t, err)
+				assert.Equal(t, 3, strings.Count(string(lines), "\n")+1)
+			},
+		},
+		{
+			name: "drops the scratch graph when the query reads back different data",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockScratchGraph(srv)
+				srv.MockResponse(queryURL, tigergraph.TigerGraphResponse[tigergraph.SmokeTestTotals]{
+					Results: []tigergraph.SmokeTestTotals{{Count: 2, Total: 3}},
+				})
+
+				result, err := client.SmokeTest(context.Background())
+				assert.ErrorIs(t, err, tigergraph.ErrSmokeTestFailed)
+				assert.ErrorContains(t, err, "query: ")
+				assert.Len(t, result.Steps, 4)
+
+				commands := readGSQL(t, srv)
+				assert.Equal(t, "DROP GRAPH "+smokeGraph+" CASCADE", commands[len(commands)-1])
+			},
+		},
+		{
+			name: "stops if the scratch graph cannot be created",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+				})
+
+				result, err := client.SmokeTest(context.Background())
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Empty(t, result.Steps)
+				assert.Equal(t, 1, srv.CallCount(tigergraph.FileURL))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(
+				srv.HTTPServer.URL,
+				tigergraph.WithCredentials(expectedUsername, expectedPassword),
+				tigergraph.WithClock(func() time.Time { return now }),
+			)
+
+			test.action(t, client, srv)
+		})
+	}
+}
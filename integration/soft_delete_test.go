@@ -0,0 +1,154 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type softDeletablePerson struct {
+	tigergraph.SoftDeleteFields
+	Name string `json:"name"`
+}
+
+func TestSoftDelete(t *testing.T) { //nolint:funlen
+	softDeleteURL := tigergraph.UpsertURL + "/" + graphName + "?vertex_must_exist=true"
+	verticesURL := "/graph/" + graphName + "/vertices/Person"
+
+	peopleResponse := tigergraph.TigerGraphResponse[tigergraph.ResponseVertex[softDeletablePerson]]{
+		Results: []tigergraph.ResponseVertex[softDeletablePerson]{
+			{
+				VID:        "1",
+				Attributes: softDeletablePerson{Name: "Alice"},
+			},
+			{
+				VID: "2",
+				Attributes: softDeletablePerson{
+					SoftDeleteFields: tigergraph.SoftDeleteFields{DeletedAt: "2023-01-01 00:00:00"},
+					Name:             "Bob",
+				},
+			},
+			{
+				VID: "3",
+				Attributes: softDeletablePerson{
+					SoftDeleteFields: tigergraph.SoftDeleteFields{DeletedAt: tigergraph.NotDeletedDateTime},
+					Name:             "Carol",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "soft delete sets deleted_at on existing vertices",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(softDeleteURL, tigergraph.UpsertResponse{
+					Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 2}},
+				})
+
+				result, err := client.SoftDeleteVertices(context.Background(), graphName, "Person", []string{"1", "2"})
+				assert.Nil(t, err)
+				assert.Equal(t, 2, result.AcceptedVertices)
+
+				calls := srv.Calls[softDeleteURL]
+				assert.Len(t, calls, 1)
+
+				body, err := io.ReadAll(calls[0])
+				assert.Nil(t, err)
+
+				var payload struct {
+					Vertices map[string]map[string]map[string]struct {
+						Value string `json:"value"`
+					} `json:"vertices"`
+				}
+				assert.Nil(t, json.Unmarshal(body, &payload))
+				assert.Len(t, payload.Vertices["Person"], 2)
+				assert.NotEqual(t, tigergraph.NotDeletedDateTime, payload.Vertices["Person"]["1"]["deleted_at"].Value)
+			},
+		},
+		{
+			name: "soft delete fails without panicking when TigerGraph returns no results",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(softDeleteURL, tigergraph.UpsertResponse{})
+
+				result, err := client.SoftDeleteVertices(context.Background(), graphName, "Person", []string{"1"})
+				assert.ErrorIs(t, err, tigergraph.ErrNotOneResult)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "restore resets deleted_at",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(softDeleteURL, tigergraph.UpsertResponse{
+					Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+				})
+
+				_, err := client.RestoreVertices(context.Background(), graphName, "Person", []string{"1"})
+				assert.Nil(t, err)
+
+				body, err := io.ReadAll(srv.Calls[softDeleteURL][0])
+				assert.Nil(t, err)
+				assert.Contains(t, string(body), tigergraph.NotDeletedDateTime)
+			},
+		},
+		{
+			name: "get vertices filters deleted vertices by default",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(verticesURL, peopleResponse)
+
+				vertices, err := tigergraph.GetVertices[softDeletablePerson](context.Background(), client, graphName, "Person")
+				assert.Nil(t, err)
+				assert.Len(t, vertices, 2)
+				assert.Equal(t, "1", vertices[0].VID)
+				assert.Equal(t, "3", vertices[1].VID)
+			},
+		},
+		{
+			name: "get vertices returns deleted vertices with IncludeDeleted",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(verticesURL, peopleResponse)
+
+				vertices, err := tigergraph.GetVertices[softDeletablePerson](
+					context.Background(),
+					client,
+					graphName,
+					"Person",
+					tigergraph.IncludeDeleted(),
+				)
+				assert.Nil(t, err)
+				assert.Len(t, vertices, 3)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
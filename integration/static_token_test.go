@@ -0,0 +1,111 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticToken(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/myquery"
+
+	// mockQuery expects requests to the query to carry the given bearer token
+	mockQuery := func(t *testing.T, srv *MockTigerGraphServer, token string) {
+		t.Helper()
+		srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer "+token, r.Header.Get("Authorization"))
+			_, err := w.Write([]byte(`{"error": false, "results": []}`))
+			assert.Nil(t, err)
+		})
+	}
+
+	tests := []struct {
+		name   string
+		opts   []tigergraph.Option
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "static tokens are used without requesting a token",
+			opts: []tigergraph.Option{tigergraph.WithStaticToken(graphName, "platformtoken")},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockQuery(t, srv, "platformtoken")
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+
+				assert.Equal(t, 0, srv.CallCount(tigergraph.RequestTokenURL))
+				_, cached := client.Tokens.Get(graphName)
+				assert.False(t, cached)
+			},
+		},
+		{
+			name: "other graphs still request tokens",
+			opts: []tigergraph.Option{tigergraph.WithStaticToken("OtherGraph", "platformtoken")},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockQuery(t, srv, "sometoken")
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+
+				assert.Equal(t, 1, srv.CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+		{
+			name: "graphs without a static token fail when token requests are disabled",
+			opts: []tigergraph.Option{
+				tigergraph.WithStaticToken(tigergraph.GlobalGraph, "globaltoken"),
+				tigergraph.WithoutTokenRequests(),
+			},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				mockQuery(t, srv, "globaltoken")
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), queryURL, graphName, &result)
+				assert.ErrorIs(t, err, tigergraph.ErrNoToken)
+				assert.Nil(t, client.AuthGlobal(context.Background()))
+
+				assert.Equal(t, 0, srv.CallCount(queryURL))
+				assert.Equal(t, 0, srv.CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+		{
+			name: "static tokens are not revoked on close",
+			opts: []tigergraph.Option{tigergraph.WithStaticToken(graphName, "platformtoken")},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Nil(t, client.Close(context.Background()))
+
+				assert.Equal(t, 0, srv.CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			opts := append([]tigergraph.Option{tigergraph.WithCredentials(expectedUsername, expectedPassword)}, test.opts...)
+			client := tigergraph.NewClient(srv.HTTPServer.URL, opts...)
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusErrors(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/my_query"
+
+	tests := []struct {
+		name       string
+		url        string
+		status     int
+		action     func(client *tigergraph.TigerGraphClient) error
+		expected   error
+		unexpected []error
+	}{
+		{
+			name:       "unauthorized queries",
+			url:        queryURL,
+			status:     http.StatusUnauthorized,
+			action:     getQuery(queryURL),
+			expected:   tigergraph.ErrUnauthorized,
+			unexpected: []error{tigergraph.ErrForbidden},
+		},
+		{
+			name:       "forbidden queries",
+			url:        queryURL,
+			status:     http.StatusForbidden,
+			action:     getQuery(queryURL),
+			expected:   tigergraph.ErrForbidden,
+			unexpected: []error{tigergraph.ErrUnauthorized},
+		},
+		{
+			name:       "other non-OK queries",
+			url:        queryURL,
+			status:     http.StatusInternalServerError,
+			action:     getQuery(queryURL),
+			expected:   tigergraph.ErrNonOK,
+			unexpected: []error{tigergraph.ErrUnauthorized, tigergraph.ErrForbidden},
+		},
+		{
+			name:   "forbidden GSQL",
+			url:    tigergraph.FileURL,
+			status: http.StatusForbidden,
+			action: func(client *tigergraph.TigerGraphClient) error {
+				return client.RunGSQL(context.Background(), "ls")
+			},
+			expected:   tigergraph.ErrForbidden,
+			unexpected: []error{tigergraph.ErrUnauthorized},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			srv.Mock(test.url, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+			})
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			err := test.action(client)
+			assert.ErrorIs(t, err, test.expected)
+			assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+			for _, unexpected := range test.unexpected {
+				assert.NotErrorIs(t, err, unexpected)
+			}
+		})
+	}
+}
+
+func getQuery(queryURL string) func(client *tigergraph.TigerGraphClient) error {
+	return func(client *tigergraph.TigerGraphClient) error {
+		var result tigergraph.TigerGraphResponse[any]
+		return client.Get(context.Background(), queryURL, graphName, &result)
+	}
+}
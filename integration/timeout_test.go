@@ -0,0 +1,228 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeouts(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/slow_query"
+	delay := 100 * time.Millisecond
+
+	slowHandler := func(body string) handlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+
+			_, err := w.Write([]byte(body))
+			assert.Nil(t, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "client timeout applies to Get and Post",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.Timeouts.Request = 10 * time.Millisecond
+				srv.Mock(queryURL, slowHandler(`{"error": false}`))
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), queryURL, graphName, &result)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+				err = client.Post(context.Background(), queryURL, graphName, map[string]any{}, &result)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+		{
+			name: "client timeout applies to RunGSQL",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.Timeouts.Request = 10 * time.Millisecond
+				srv.Mock(tigergraph.FileURL, slowHandler(fmt.Sprintf("Done.\n%s\n", tigergraph.SuccessString)))
+
+				err := client.RunGSQL(context.Background(), "LS")
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				assert.ErrorIs(t, err, tigergraph.ErrRequestFailed)
+			},
+		},
+		{
+			name: "per call timeout overrides the client timeout",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.Timeouts.Request = 10 * time.Millisecond
+				srv.Mock(queryURL, slowHandler(`{"error": false}`))
+				srv.Mock(tigergraph.FileURL, slowHandler(fmt.Sprintf("Done.\n%s\n", tigergraph.SuccessString)))
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), queryURL, graphName, &result, tigergraph.WithRequestTimeout(time.Second))
+				assert.Nil(t, err)
+
+				err = client.RunGSQL(context.Background(), "LS", tigergraph.WithRequestTimeout(0))
+				assert.Nil(t, err)
+			},
+		},
+		{
+			name: "per call timeout without a client timeout",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(queryURL, slowHandler(`{"error": false}`))
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), queryURL, graphName, &result)
+				assert.Nil(t, err)
+
+				err = client.Get(context.Background(), queryURL, graphName, &result, tigergraph.WithRequestTimeout(10*time.Millisecond))
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+		{
+			name: "deadline errors carry the operation and timeout",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(queryURL, slowHandler(`{"error": false}`))
+				client.Timeouts.Request = 10 * time.Millisecond
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), queryURL, graphName, &result, tigergraph.WithOperation("find people"))
+				assert.ErrorIs(t, err, tigergraph.ErrDeadlineExceeded)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+				var deadlineErr *tigergraph.DeadlineExceededError
+				if assert.ErrorAs(t, err, &deadlineErr) {
+					assert.Equal(t, "find people", deadlineErr.Op)
+					assert.Equal(t, 10*time.Millisecond, deadlineErr.Timeout)
+					assert.False(t, deadlineErr.CallerDeadline)
+				}
+				assert.ErrorContains(t, err, "find people graph="+graphName+" endpoint="+queryURL+": timeout of 10ms exceeded")
+
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+				err = client.Post(ctx, queryURL, graphName, map[string]any{}, &result, tigergraph.WithRequestTimeout(time.Minute))
+				if assert.ErrorAs(t, err, &deadlineErr) {
+					assert.Equal(t, "post", deadlineErr.Op)
+					assert.Equal(t, time.Minute, deadlineErr.Timeout)
+					assert.True(t, deadlineErr.CallerDeadline)
+				}
+			},
+		},
+		{
+			name: "server errors are not deadline errors",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+					_, err := w.Write([]byte(`{"error": true, "message": "bad query"}`))
+					assert.Nil(t, err)
+				})
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), queryURL, graphName, &result)
+				assert.NotNil(t, err)
+				assert.NotErrorIs(t, err, tigergraph.ErrDeadlineExceeded)
+			},
+		},
+		{
+			name: "query timeout is sent in the GSQL-TIMEOUT header",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.Timeouts.Query = 2 * time.Second
+				headers := make([]string, 0)
+				srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+					headers = append(headers, r.Header.Get(tigergraph.QueryTimeoutHeader))
+					_, err := w.Write([]byte(`{"error": false}`))
+					assert.Nil(t, err)
+				})
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+				assert.Nil(t, client.Post(
+					context.Background(), queryURL, graphName, map[string]any{}, &result,
+					tigergraph.WithRequestHeader(tigergraph.QueryTimeoutHeader, "500"),
+				))
+
+				assert.Equal(t, []string{"2000", "500"}, headers)
+			},
+		},
+		{
+			name: "context deadline is sent in the GSQL-TIMEOUT header",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.Timeouts.Query = 2 * time.Second
+				headers := make([]string, 0)
+				srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+					headers = append(headers, r.Header.Get(tigergraph.QueryTimeoutHeader))
+					_, err := w.Write([]byte(`{"error": false}`))
+					assert.Nil(t, err)
+				})
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(ctx, queryURL, graphName, &result))
+				assert.Nil(t, client.Get(ctx, queryURL, graphName, &result, tigergraph.WithQueryTimeout(3*time.Second)))
+				assert.Nil(t, client.Get(ctx, queryURL, graphName, &result, tigergraph.WithQueryTimeout(0)))
+
+				longCtx, cancelLong := context.WithTimeout(context.Background(), time.Hour)
+				defer cancelLong()
+				assert.Nil(t, client.Get(longCtx, queryURL, graphName, &result))
+
+				if !assert.Len(t, headers, 4) {
+					return
+				}
+				deadlineTimeout, err := strconv.Atoi(headers[0])
+				assert.Nil(t, err)
+				assert.LessOrEqual(t, deadlineTimeout, 1000)
+				assert.Greater(t, deadlineTimeout, 0)
+				assert.Equal(t, []string{"3000", "", "2000"}, headers[1:])
+			},
+		},
+		{
+			name: "client request timeout is not sent in the GSQL-TIMEOUT header",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				headers := make([]string, 0)
+				srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+					headers = append(headers, r.Header.Get(tigergraph.QueryTimeoutHeader))
+					_, err := w.Write([]byte(`{"error": false}`))
+					assert.Nil(t, err)
+				})
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result, tigergraph.WithRequestTimeout(time.Minute)))
+
+				assert.Equal(t, []string{""}, headers)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type passthroughTransport struct{}
+
+func (passthroughTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTLSOptions(t *testing.T) { //nolint:funlen
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	tests := []struct {
+		name          string
+		options       []tigergraph.Option
+		expectedError error
+		expectedOK    bool
+	}{
+		{
+			name:       "untrusted certificate",
+			options:    []tigergraph.Option{},
+			expectedOK: false,
+		},
+		{
+			name:       "trusted CA certificate",
+			options:    []tigergraph.Option{tigergraph.WithCACert(caPEM)},
+			expectedOK: true,
+		},
+		{
+			name:       "TLS config",
+			options:    []tigergraph.Option{tigergraph.WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12})},
+			expectedOK: true,
+		},
+		{
+			name:       "skip verification",
+			options:    []tigergraph.Option{tigergraph.WithInsecureSkipVerify()},
+			expectedOK: true,
+		},
+		{
+			name: "applies to a custom HTTP client",
+			options: []tigergraph.Option{
+				tigergraph.WithHTTPClient(&http.Client{Transport: &http.Transport{}}),
+				tigergraph.WithCACert(caPEM),
+			},
+			expectedOK: true,
+		},
+		{
+			name:          "invalid CA certificate",
+			options:       []tigergraph.Option{tigergraph.WithCACert([]byte("not a certificate"))},
+			expectedError: tigergraph.ErrInvalidCACert,
+		},
+		{
+			name: "custom transport cannot be configured",
+			options: []tigergraph.Option{
+				tigergraph.WithHTTPClient(&http.Client{Transport: passthroughTransport{}}),
+				tigergraph.WithInsecureSkipVerify(),
+			},
+			expectedError: tigergraph.ErrInvalidOption,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := tigergraph.NewClient(srv.URL, test.options...)
+
+			err := client.Warmup(context.Background())
+			switch {
+			case test.expectedError != nil:
+				assert.ErrorIs(t, err, test.expectedError)
+				assert.ErrorIs(t, err, tigergraph.ErrInvalidOption)
+			case test.expectedOK:
+				assert.Nil(t, err)
+			default:
+				assert.NotNil(t, err)
+			}
+		})
+	}
+
+	// A pool from the caller's TLS config is copied before CA certificates are added to it
+	callerPool := x509.NewCertPool()
+	client := tigergraph.NewClient(
+		srv.URL,
+		tigergraph.WithTLSConfig(&tls.Config{RootCAs: callerPool, MinVersion: tls.VersionTLS12}),
+		tigergraph.WithCACert(caPEM),
+	)
+	assert.Nil(t, client.Warmup(context.Background()))
+	assert.True(t, callerPool.Equal(x509.NewCertPool()))
+
+	// The shared defaults are never modified
+	assert.Nil(t, http.DefaultClient.Transport)
+	if defaultTLSConfig := http.DefaultTransport.(*http.Transport).TLSClientConfig; defaultTLSConfig != nil {
+		assert.Nil(t, defaultTLSConfig.RootCAs)
+		assert.False(t, defaultTLSConfig.InsecureSkipVerify)
+	}
+}
+
+// newClientCertificate creates a self-signed client certificate, returning it PEM encoded
+// with its key
+func newClientCertificate(t *testing.T) (*x509.Certificate, []byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "go-tigergraph"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	return certificate,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestClientCertificates(t *testing.T) { //nolint:funlen
+	clientCert, certPEM, keyPEM := newClientCertificate(t)
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	assert.Nil(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	assert.Nil(t, os.WriteFile(certFile, certPEM, 0o600))
+	assert.Nil(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs, MinVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	tests := []struct {
+		name          string
+		options       []tigergraph.Option
+		expectedError error
+		expectedOK    bool
+	}{
+		{
+			name:       "no client certificate",
+			options:    []tigergraph.Option{},
+			expectedOK: false,
+		},
+		{
+			name:       "client certificate",
+			options:    []tigergraph.Option{tigergraph.WithClientCertificate(keyPair)},
+			expectedOK: true,
+		},
+		{
+			name:       "client certificate files",
+			options:    []tigergraph.Option{tigergraph.WithClientCertificateFiles(certFile, keyFile)},
+			expectedOK: true,
+		},
+		{
+			name:       "client certificate PEM",
+			options:    []tigergraph.Option{tigergraph.WithClientCertificatePEM(certPEM, keyPEM)},
+			expectedOK: true,
+		},
+		{
+			name:          "missing client certificate files",
+			options:       []tigergraph.Option{tigergraph.WithClientCertificateFiles(filepath.Join(dir, "missing.crt"), keyFile)},
+			expectedError: tigergraph.ErrInvalidClientCertificate,
+		},
+		{
+			name:          "invalid client certificate PEM",
+			options:       []tigergraph.Option{tigergraph.WithClientCertificatePEM(certPEM, []byte("not a key"))},
+			expectedError: tigergraph.ErrInvalidClientCertificate,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := append([]tigergraph.Option{tigergraph.WithCACert(caPEM)}, test.options...)
+			client := tigergraph.NewClient(srv.URL, options...)
+
+			err := client.Warmup(context.Background())
+			switch {
+			case test.expectedError != nil:
+				assert.ErrorIs(t, err, test.expectedError)
+				assert.ErrorIs(t, err, tigergraph.ErrInvalidOption)
+			case test.expectedOK:
+				assert.Nil(t, err)
+			default:
+				assert.NotNil(t, err)
+			}
+		})
+	}
+}
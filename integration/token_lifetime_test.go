@@ -0,0 +1,123 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenLifetime(t *testing.T) { //nolint:funlen
+	tests := []struct {
+		name     string
+		opts     []tigergraph.Option
+		action   func(t *testing.T, client *tigergraph.TigerGraphClient)
+		expected []string
+	}{
+		{
+			name: "leaves the lifetime to the server by default",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+			},
+			expected: []string{""},
+		},
+		{
+			name: "requests tokens with the client's lifetime",
+			opts: []tigergraph.Option{tigergraph.WithTokenLifetime(15 * time.Minute)},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+			},
+			expected: []string{"900"},
+		},
+		{
+			name: "requests tokens with a secret with the client's lifetime",
+			opts: []tigergraph.Option{
+				tigergraph.WithTokenLifetime(time.Hour),
+				tigergraph.WithSecret(graphName, "secret"),
+			},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+			},
+			expected: []string{"3600"},
+		},
+		{
+			name: "rounds lifetimes up to whole seconds",
+			opts: []tigergraph.Option{tigergraph.WithTokenLifetime(time.Millisecond)},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+			},
+			expected: []string{"1"},
+		},
+		{
+			name: "requests a new token with the lifetime given to Auth",
+			opts: []tigergraph.Option{tigergraph.WithTokenLifetime(time.Hour)},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				ctx := context.Background()
+				assert.Nil(t, client.Auth(ctx, graphName))
+				assert.Nil(t, client.Auth(ctx, graphName, tigergraph.TokenLifetime(7*24*time.Hour)))
+
+				// The new token is cached
+				assert.Nil(t, client.Auth(ctx, graphName))
+			},
+			expected: []string{"3600", "604800"},
+		},
+		{
+			name: "requests global tokens with the lifetime given to AuthGlobal",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				assert.Nil(t, client.AuthGlobal(context.Background(), tigergraph.TokenLifetime(time.Minute)))
+			},
+			expected: []string{"60"},
+		},
+		{
+			name: "ignores lifetimes for static tokens",
+			opts: []tigergraph.Option{tigergraph.WithStaticToken(graphName, "statictoken")},
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient) {
+				assert.Nil(t, client.Auth(context.Background(), graphName, tigergraph.TokenLifetime(time.Minute)))
+			},
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			lifetimes := []string{}
+			defaultHandler := makeDefaultRequestTokenHandler(expectedUsername, expectedPassword, time.Now().Add(time.Hour).Unix())
+			srv.Mock(tigergraph.RequestTokenURL, func(w http.ResponseWriter, r *http.Request) {
+				body := map[string]string{}
+				assert.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+				lifetimes = append(lifetimes, body["lifetime"])
+
+				if body["secret"] != "" {
+					r.SetBasicAuth(expectedUsername, expectedPassword)
+				}
+				defaultHandler(w, r)
+			})
+
+			opts := append([]tigergraph.Option{tigergraph.WithCredentials(expectedUsername, expectedPassword)}, test.opts...)
+			client := tigergraph.NewClient(srv.HTTPServer.URL, opts...)
+
+			test.action(t, client)
+			assert.Equal(t, test.expected, lifetimes)
+		})
+	}
+}
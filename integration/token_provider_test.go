@@ -0,0 +1,140 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenProvider(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/myquery"
+	errProvider := errors.New("identity provider unavailable")
+
+	// mockQuery expects requests to the query to carry the given bearer token
+	mockQuery := func(t *testing.T, srv *MockTigerGraphServer, token string) {
+		t.Helper()
+		srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer "+token, r.Header.Get("Authorization"))
+			_, err := w.Write([]byte(`{"error": false, "results": []}`))
+			assert.Nil(t, err)
+		})
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "tokens come from the provider and are cached until they expire",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				mockQuery(t, srv, "jwt-"+graphName)
+				var calls int32
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithTokenProvider(tigergraph.TokenProviderFunc(
+						func(_ context.Context, graph string) (string, time.Time, error) {
+							atomic.AddInt32(&calls, 1)
+							return "jwt-" + graph, time.Now().Add(time.Hour), nil
+						},
+					)),
+				)
+
+				var result tigergraph.TigerGraphResponse[any]
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+				assert.Nil(t, client.Get(context.Background(), queryURL, graphName, &result))
+
+				assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+				assert.Equal(t, 0, srv.CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+		{
+			name: "expired tokens are replaced from the provider",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				var calls int32
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithTokenProvider(tigergraph.TokenProviderFunc(
+						func(context.Context, string) (string, time.Time, error) {
+							atomic.AddInt32(&calls, 1)
+							return "jwt", time.Now().Add(-time.Minute), nil
+						},
+					)),
+				)
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+
+				assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+			},
+		},
+		{
+			name: "provider errors fail the request",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				mockQuery(t, srv, "")
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithTokenProvider(tigergraph.TokenProviderFunc(
+						func(context.Context, string) (string, time.Time, error) {
+							return "", time.Time{}, errProvider
+						},
+					)),
+				)
+
+				var result tigergraph.TigerGraphResponse[any]
+				err := client.Get(context.Background(), queryURL, graphName, &result)
+
+				assert.ErrorIs(t, err, errProvider)
+				assert.Equal(t, 0, srv.CallCount(queryURL))
+			},
+		},
+		{
+			name: "provided tokens are used when token requests are disabled and are not revoked",
+			action: func(t *testing.T, srv *MockTigerGraphServer) {
+				client := tigergraph.NewClient(
+					srv.HTTPServer.URL,
+					tigergraph.WithoutTokenRequests(),
+					tigergraph.WithTokenProvider(tigergraph.TokenProviderFunc(
+						func(context.Context, string) (string, time.Time, error) {
+							return "jwt", time.Now().Add(time.Hour), nil
+						},
+					)),
+				)
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Nil(t, client.Close(context.Background()))
+
+				_, cached := client.Tokens.Get(graphName)
+				assert.False(t, cached)
+				assert.Equal(t, 0, srv.CallCount(tigergraph.RequestTokenURL))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			test.action(t, srv)
+		})
+	}
+}
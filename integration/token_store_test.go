@@ -0,0 +1,223 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+// unavailableTokenStore fails every call, as a store whose server is down would
+type unavailableTokenStore struct{}
+
+var errTokenStoreUnavailable = errors.New("token store unavailable")
+
+func (unavailableTokenStore) GetToken(context.Context, string) (*tigergraph.Token, error) {
+	return nil, errTokenStoreUnavailable
+}
+
+func (unavailableTokenStore) SetToken(context.Context, string, *tigergraph.Token) error {
+	return errTokenStoreUnavailable
+}
+
+func (unavailableTokenStore) DeleteToken(context.Context, string) error {
+	return errTokenStoreUnavailable
+}
+
+func TestTokenStore(t *testing.T) { //nolint:funlen
+	// countRequests counts token requests and revocations, which share the token URL
+	countRequests := func(srv *MockTigerGraphServer, requests *int, revocations *int) {
+		issue := makeDefaultRequestTokenHandler(expectedUsername, expectedPassword, time.Now().Add(time.Hour).Unix())
+		srv.Mock(tigergraph.RequestTokenURL, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodDelete {
+				*revocations++
+				_, err := w.Write([]byte(`{"error": false}`))
+				assert.Nil(t, err)
+				return
+			}
+
+			*requests++
+			issue(w, r)
+		})
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, srv *MockTigerGraphServer, newClient func(opts ...tigergraph.Option) *tigergraph.TigerGraphClient)
+	}{
+		{
+			name: "clients sharing a store share tokens",
+			action: func(t *testing.T, srv *MockTigerGraphServer, newClient func(opts ...tigergraph.Option) *tigergraph.TigerGraphClient) {
+				var requests, revocations int
+				countRequests(srv, &requests, &revocations)
+
+				store := tigergraph.NewTokenCache()
+				first := newClient(tigergraph.WithTokenStore(store))
+				second := newClient(tigergraph.WithTokenStore(store))
+
+				assert.Nil(t, first.Auth(context.Background(), graphName))
+				assert.Nil(t, second.Auth(context.Background(), graphName))
+				assert.Equal(t, 1, requests)
+
+				token, ok := second.Tokens.Get(graphName)
+				assert.True(t, ok)
+				assert.Equal(t, "sometoken", token.Value)
+			},
+		},
+		{
+			name: "clients of different servers sharing a store do not share tokens",
+			action: func(t *testing.T, srv *MockTigerGraphServer, newClient func(opts ...tigergraph.Option) *tigergraph.TigerGraphClient) {
+				var requests, revocations int
+				countRequests(srv, &requests, &revocations)
+
+				other := NewMockServer(expectedUsername, expectedPassword)
+				defer other.Close()
+				var otherRequests, otherRevocations int
+				countRequests(other, &otherRequests, &otherRevocations)
+
+				store := tigergraph.NewTokenCache()
+				first := newClient(tigergraph.WithTokenStore(store))
+				second := tigergraph.NewClient(
+					other.HTTPServer.URL,
+					tigergraph.WithCredentials(expectedUsername, expectedPassword),
+					tigergraph.WithTokenStore(store),
+				)
+
+				assert.Nil(t, first.Auth(context.Background(), graphName))
+				assert.Nil(t, second.Auth(context.Background(), graphName))
+				assert.Equal(t, 1, requests)
+				assert.Equal(t, 1, otherRequests)
+				assert.ElementsMatch(t, []string{
+					tigergraph.TokenStoreKey(srv.HTTPServer.URL, expectedUsername, graphName),
+					tigergraph.TokenStoreKey(other.HTTPServer.URL, expectedUsername, graphName),
+				}, store.Graphs())
+			},
+		},
+		{
+			name: "expired tokens in the store are replaced",
+			action: func(t *testing.T, srv *MockTigerGraphServer, newClient func(opts ...tigergraph.Option) *tigergraph.TigerGraphClient) {
+				var requests, revocations int
+				countRequests(srv, &requests, &revocations)
+
+				store := tigergraph.NewTokenCache()
+				key := tigergraph.TokenStoreKey(srv.HTTPServer.URL, expectedUsername, graphName)
+				store.Set(key, &tigergraph.Token{Value: "expired", Expires: time.Now().Add(-time.Minute)})
+				client := newClient(tigergraph.WithTokenStore(store))
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Equal(t, 1, requests)
+
+				token, ok := store.Get(key)
+				assert.True(t, ok)
+				assert.Equal(t, "sometoken", token.Value)
+			},
+		},
+		{
+			name: "tokens requested with a lifetime are stored",
+			action: func(t *testing.T, srv *MockTigerGraphServer, newClient func(opts ...tigergraph.Option) *tigergraph.TigerGraphClient) {
+				var requests, revocations int
+				countRequests(srv, &requests, &revocations)
+
+				store := tigergraph.NewTokenCache()
+				key := tigergraph.TokenStoreKey(srv.HTTPServer.URL, expectedUsername, graphName)
+				client := newClient(tigergraph.WithTokenStore(store))
+
+				assert.Nil(t, client.Auth(context.Background(), graphName, tigergraph.TokenLifetime(time.Hour)))
+				_, ok := store.Get(key)
+				assert.True(t, ok)
+			},
+		},
+		{
+			name: "unavailable stores are logged and bypassed",
+			action: func(t *testing.T, srv *MockTigerGraphServer, newClient func(opts ...tigergraph.Option) *tigergraph.TigerGraphClient) {
+				var requests, revocations int
+				countRequests(srv, &requests, &revocations)
+
+				logger := &recordingLevelLogger{}
+				client := newClient(tigergraph.WithTokenStore(unavailableTokenStore{}), tigergraph.WithLogger(logger))
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Equal(t, 1, requests)
+
+				warnings := make([]string, 0)
+				for _, message := range logger.messages {
+					if message.level == "warn" {
+						warnings = append(warnings, message.msg)
+					}
+				}
+				assert.Equal(t, []string{"failed to get token from token store", "failed to store token in token store"}, warnings)
+			},
+		},
+		{
+			name: "close leaves shared tokens unrevoked",
+			action: func(t *testing.T, srv *MockTigerGraphServer, newClient func(opts ...tigergraph.Option) *tigergraph.TigerGraphClient) {
+				var requests, revocations int
+				countRequests(srv, &requests, &revocations)
+
+				store := tigergraph.NewTokenCache()
+				key := tigergraph.TokenStoreKey(srv.HTTPServer.URL, expectedUsername, graphName)
+				client := newClient(tigergraph.WithTokenStore(store))
+
+				assert.Nil(t, client.Auth(context.Background(), graphName))
+				assert.Nil(t, client.Close(context.Background()))
+				assert.Equal(t, 0, revocations)
+
+				_, cached := client.Tokens.Get(graphName)
+				assert.False(t, cached)
+				_, stored := store.Get(key)
+				assert.True(t, stored)
+			},
+		},
+		{
+			name: "revoking removes tokens from the store",
+			action: func(t *testing.T, srv *MockTigerGraphServer, newClient func(opts ...tigergraph.Option) *tigergraph.TigerGraphClient) {
+				var requests, revocations int
+				countRequests(srv, &requests, &revocations)
+
+				store := tigergraph.NewTokenCache()
+				key := tigergraph.TokenStoreKey(srv.HTTPServer.URL, expectedUsername, graphName)
+				first := newClient(tigergraph.WithTokenStore(store))
+				second := newClient(tigergraph.WithTokenStore(store))
+				assert.Nil(t, first.Auth(context.Background(), graphName))
+
+				// The second client has not cached the token, but revokes it from the store
+				assert.Nil(t, second.RevokeToken(context.Background(), graphName))
+				assert.Equal(t, 1, revocations)
+
+				_, stored := store.Get(key)
+				assert.False(t, stored)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			newClient := func(opts ...tigergraph.Option) *tigergraph.TigerGraphClient {
+				opts = append([]tigergraph.Option{tigergraph.WithCredentials(expectedUsername, expectedPassword)}, opts...)
+				return tigergraph.NewClient(srv.HTTPServer.URL, opts...)
+			}
+
+			test.action(t, srv, newClient)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingUpsertMetrics struct {
+	events []tigergraph.UpsertEvent
+}
+
+func (m *recordingUpsertMetrics) UpsertCompleted(event tigergraph.UpsertEvent) {
+	m.events = append(m.events, event)
+}
+
+func TestUpsert(t *testing.T) { //nolint:funlen
+	upsertURL := tigergraph.UpsertURL + "/" + graphName
+
+	payload := map[string]any{
+		"vertices": map[string]any{
+			"Person": map[string]any{
+				"1": map[string]any{
+					"name": map[string]any{"value": "Alice"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "success",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(upsertURL, tigergraph.UpsertResponse{
+					Results: []tigergraph.UpsertResponseResult{
+						{AcceptedVertices: 1},
+					},
+				})
+
+				result, err := client.Upsert(context.Background(), graphName, payload)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, result.AcceptedVertices)
+				assert.Len(t, srv.Calls[upsertURL], 1)
+			},
+		},
+		{
+			name: "reports accepted and skipped counts to upsert metrics",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(upsertURL, tigergraph.UpsertResponse{
+					Results: []tigergraph.UpsertResponseResult{
+						{AcceptedVertices: 1, SkippedEdges: 2},
+					},
+				})
+
+				metrics := &recordingUpsertMetrics{}
+				client.UpsertMetrics = metrics
+
+				_, err := client.Upsert(context.Background(), graphName, payload)
+				assert.Nil(t, err)
+				assert.Equal(t, []tigergraph.UpsertEvent{
+					{Graph: graphName, AcceptedVertices: 1, SkippedEdges: 2},
+				}, metrics.events)
+			},
+		},
+		{
+			name: "error in the response",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(upsertURL, tigergraph.UpsertResponse{Error: true, Message: "invalid attribute"})
+
+				result, err := client.Upsert(context.Background(), graphName, payload)
+				assert.ErrorIs(t, err, tigergraph.ErrTigerGraphError)
+				assert.Contains(t, err.Error(), "invalid attribute")
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "response without results",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(upsertURL, tigergraph.UpsertResponse{})
+
+				result, err := client.Upsert(context.Background(), graphName, payload)
+				assert.ErrorIs(t, err, tigergraph.ErrNotOneResult)
+				assert.Nil(t, result)
+			},
+		},
+		{
+			name: "payload larger than the maximum request size is not sent",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				size, err := tigergraph.EstimatePayloadSize(payload)
+				assert.Nil(t, err)
+
+				client.MaxRequestSize = size - 1
+
+				result, err := client.Upsert(context.Background(), graphName, payload)
+				assert.ErrorIs(t, err, tigergraph.ErrPayloadTooLarge)
+				assert.Nil(t, result)
+				assert.Len(t, srv.Calls[upsertURL], 0)
+			},
+		},
+		{
+			name: "payload equal to the maximum request size is sent",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(upsertURL, tigergraph.UpsertResponse{
+					Results: []tigergraph.UpsertResponseResult{
+						{AcceptedVertices: 1},
+					},
+				})
+
+				size, err := tigergraph.EstimatePayloadSize(payload)
+				assert.Nil(t, err)
+
+				client.MaxRequestSize = size
+
+				_, err = client.Upsert(context.Background(), graphName, payload)
+				assert.Nil(t, err)
+				assert.Len(t, srv.Calls[upsertURL], 1)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
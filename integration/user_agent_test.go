@@ -0,0 +1,57 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAgent(t *testing.T) {
+	queryURL := "/query/" + graphName + "/my_query"
+	expectedUserAgent := fmt.Sprintf("go-tigergraph/%s (reporting-service)", tigergraph.LibraryVersion())
+
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	userAgents := make([]string, 0)
+	srv.Mock(queryURL, func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		_, err := w.Write([]byte(`{"error": false, "results": []}`))
+		assert.Nil(t, err)
+	})
+	srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+		assert.Nil(t, err)
+	})
+
+	client := tigergraph.NewClient(
+		srv.HTTPServer.URL,
+		tigergraph.WithCredentials(expectedUsername, expectedPassword),
+		tigergraph.WithAppName("reporting-service"),
+	)
+
+	ctx := context.Background()
+	var result tigergraph.TigerGraphResponse[any]
+	assert.Nil(t, client.Get(ctx, queryURL, graphName, &result))
+	assert.Nil(t, client.RunGSQL(ctx, "LS"))
+
+	assert.Equal(t, []string{expectedUserAgent, expectedUserAgent}, userAgents)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+type personAttributes struct {
+	Name string `json:"name"`
+}
+
+func TestGetVerticesByIDs(t *testing.T) { //nolint:funlen
+	queryURL := "/query/" + graphName + "/" + tigergraph.VerticesByIDsQueryName
+	endpointsURL := fmt.Sprintf(tigergraph.EndpointsURLTemplate, graphName)
+
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "returns found vertices and missing IDs",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(queryURL, tigergraph.TigerGraphResponse[tigergraph.VerticesByIDsResponseResult[personAttributes]]{
+					Results: []tigergraph.VerticesByIDsResponseResult[personAttributes]{
+						{
+							Vertices: []tigergraph.ResponseVertex[personAttributes]{
+								{VID: "alice", VType: "Person", Attributes: personAttributes{Name: "Alice"}},
+								{VID: "carol", VType: "Person", Attributes: personAttributes{Name: "Carol"}},
+							},
+						},
+					},
+				})
+
+				result, err := tigergraph.GetVerticesByIDs[personAttributes](
+					context.Background(),
+					client,
+					graphName,
+					"Person",
+					[]string{"alice", "bob", "carol", "dave", "bob"},
+				)
+				assert.Nil(t, err)
+				assert.Len(t, result.Vertices, 2)
+				assert.Equal(t, "Carol", result.Vertices["carol"].Attributes.Name)
+				assert.Equal(t, []string{"bob", "dave"}, result.Missing)
+
+				assert.Len(t, srv.Calls[queryURL], 1)
+				var body tigergraph.VerticesByIDsPostBody
+				assert.Nil(t, json.NewDecoder(srv.Calls[queryURL][0]).Decode(&body))
+				assert.Equal(t, "Person", body.VertexType)
+				assert.Equal(t, []string{"alice", "bob", "carol", "dave", "bob"}, body.IDs)
+			},
+		},
+		{
+			name: "does not make a request without IDs",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				result, err := tigergraph.GetVerticesByIDs[personAttributes](context.Background(), client, graphName, "Person", nil)
+				assert.Nil(t, err)
+				assert.Empty(t, result.Vertices)
+				assert.Empty(t, result.Missing)
+				assert.Len(t, srv.Calls[queryURL], 0)
+			},
+		},
+		{
+			name: "installs the query when it is missing",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(endpointsURL, map[string]any{})
+				srv.Mock(tigergraph.FileURL, func(w http.ResponseWriter, _ *http.Request) {
+					_, err := fmt.Fprintf(w, "Done.\n%s\n", tigergraph.SuccessString)
+					assert.Nil(t, err)
+				})
+
+				assert.Nil(t, client.InstallVerticesByIDsQuery(context.Background(), graphName))
+				assert.Len(t, srv.Calls[tigergraph.FileURL], 1)
+
+				callBytes, err := io.ReadAll(srv.Calls[tigergraph.FileURL][0])
+				assert.Nil(t, err)
+				gsql, err := url.QueryUnescape(string(callBytes))
+				assert.Nil(t, err)
+				assert.Contains(t, gsql, "CREATE OR REPLACE QUERY "+tigergraph.VerticesByIDsQueryName+" (")
+				assert.Contains(t, gsql, "FOR GRAPH "+graphName+"\n")
+				assert.NotContains(t, gsql, "{{")
+			},
+		},
+		{
+			name: "does not reinstall the query",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.MockResponse(endpointsURL, map[string]any{"POST " + queryURL: map[string]any{}})
+
+				assert.Nil(t, client.InstallVerticesByIDsQuery(context.Background(), graphName))
+				assert.Len(t, srv.Calls[tigergraph.FileURL], 0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWAL(t *testing.T) { //nolint:funlen
+	upsertURL := tigergraph.UpsertURL + "/" + graphName
+	loadingJobURL := fmt.Sprintf("/ddl/%s?tag=%s&filename=f", graphName, "test_loading_job")
+
+	mockWrites := func(srv *MockTigerGraphServer) {
+		srv.MockResponse(upsertURL, tigergraph.UpsertResponse{
+			Results: []tigergraph.UpsertResponseResult{{AcceptedVertices: 1}},
+		})
+		srv.MockResponse(loadingJobURL, tigergraph.LoadingJobResponse{
+			Results: []tigergraph.LoadingJobResponseResult{
+				{Statistics: tigergraph.LoadingJobStatistics{ValidLine: 1}},
+			},
+		})
+	}
+
+	// A server which has been shut down, so that TigerGraph is unreachable
+	unreachable := httptest.NewServer(nil)
+	unreachable.Close()
+
+	t.Run("writes are sent directly when TigerGraph is reachable", func(t *testing.T) {
+		srv := NewMockServer(expectedUsername, expectedPassword)
+		defer srv.Close()
+		mockWrites(srv)
+
+		wal, err := tigergraph.OpenWAL(t.TempDir())
+		assert.Nil(t, err)
+
+		client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+		client.WAL = wal
+
+		buffered, err := client.BufferedUpsert(context.Background(), graphName, "a", map[string]any{})
+		assert.Nil(t, err)
+		assert.False(t, buffered)
+		assert.Len(t, srv.Calls[upsertURL], 1)
+
+		pending, err := wal.Len()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, pending)
+	})
+
+	t.Run("writes are buffered while unreachable and replayed in order without duplicates", func(t *testing.T) {
+		srv := NewMockServer(expectedUsername, expectedPassword)
+		defer srv.Close()
+		mockWrites(srv)
+
+		wal, err := tigergraph.OpenWAL(t.TempDir())
+		assert.Nil(t, err)
+
+		client := tigergraph.NewClient(unreachable.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+		client.WAL = wal
+
+		ctx := context.Background()
+		buffered, err := client.BufferedUpsert(ctx, graphName, "a", map[string]any{"id": "a"})
+		assert.Nil(t, err)
+		assert.True(t, buffered)
+
+		buffered, err = client.BufferedRunLoadingJobJSONL(ctx, graphName, "test_loading_job", "b", []any{map[string]any{"id": "b"}})
+		assert.Nil(t, err)
+		assert.True(t, buffered)
+
+		buffered, err = client.BufferedUpsert(ctx, graphName, "a", map[string]any{"id": "a"})
+		assert.Nil(t, err)
+		assert.True(t, buffered)
+
+		pending, err := wal.Len()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, pending)
+
+		client.BaseURL = srv.HTTPServer.URL
+		replayed, err := client.ReplayWAL(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, replayed)
+
+		assert.Len(t, srv.Calls[upsertURL], 1)
+		upsertBody, err := io.ReadAll(srv.Calls[upsertURL][0])
+		assert.Nil(t, err)
+		assert.Equal(t, `{"id":"a"}`, string(upsertBody))
+
+		assert.Len(t, srv.Calls[loadingJobURL], 1)
+		loadingJobBody, err := io.ReadAll(srv.Calls[loadingJobURL][0])
+		assert.Nil(t, err)
+		assert.Equal(t, `{"id":"b"}`, string(loadingJobBody))
+
+		pending, err = wal.Len()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, pending)
+	})
+
+	t.Run("keys replayed before the WAL was truncated are still skipped", func(t *testing.T) {
+		srv := NewMockServer(expectedUsername, expectedPassword)
+		defer srv.Close()
+		mockWrites(srv)
+
+		dir := t.TempDir()
+		wal, err := tigergraph.OpenWAL(dir)
+		assert.Nil(t, err)
+
+		client := tigergraph.NewClient(unreachable.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+		client.WAL = wal
+
+		ctx := context.Background()
+		buffered, err := client.BufferedUpsert(ctx, graphName, "a", map[string]any{"id": "a"})
+		assert.Nil(t, err)
+		assert.True(t, buffered)
+
+		client.BaseURL = srv.HTTPServer.URL
+		replayed, err := client.ReplayWAL(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, replayed)
+
+		// The keys are loaded again when the WAL is reopened, e.g. after a restart
+		client.WAL, err = tigergraph.OpenWAL(dir)
+		assert.Nil(t, err)
+
+		client.BaseURL = unreachable.URL
+		buffered, err = client.BufferedUpsert(ctx, graphName, "a", map[string]any{"id": "a"})
+		assert.Nil(t, err)
+		assert.False(t, buffered)
+
+		pending, err := client.WAL.Len()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, pending)
+
+		client.BaseURL = srv.HTTPServer.URL
+		replayed, err = client.ReplayWAL(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, replayed)
+		assert.Len(t, srv.Calls[upsertURL], 1)
+	})
+
+	t.Run("keys of writes sent directly are skipped when buffered again", func(t *testing.T) {
+		srv := NewMockServer(expectedUsername, expectedPassword)
+		defer srv.Close()
+		mockWrites(srv)
+
+		wal, err := tigergraph.OpenWAL(t.TempDir())
+		assert.Nil(t, err)
+
+		client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+		client.WAL = wal
+
+		ctx := context.Background()
+		buffered, err := client.BufferedUpsert(ctx, graphName, "a", map[string]any{"id": "a"})
+		assert.Nil(t, err)
+		assert.False(t, buffered)
+
+		client.BaseURL = unreachable.URL
+		buffered, err = client.BufferedUpsert(ctx, graphName, "a", map[string]any{"id": "a"})
+		assert.Nil(t, err)
+		assert.False(t, buffered)
+
+		pending, err := wal.Len()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, pending)
+		assert.Len(t, srv.Calls[upsertURL], 1)
+	})
+
+	t.Run("rejected entries can be dead lettered so that replay continues", func(t *testing.T) {
+		srv := NewMockServer(expectedUsername, expectedPassword)
+		defer srv.Close()
+		mockWrites(srv)
+
+		wal, err := tigergraph.OpenWAL(t.TempDir())
+		assert.Nil(t, err)
+
+		client := tigergraph.NewClient(unreachable.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+		client.WAL = wal
+
+		ctx := context.Background()
+		_, err = client.BufferedRunLoadingJobJSONL(ctx, graphName, "missing_job", "poison", []any{map[string]any{"id": "p"}})
+		assert.Nil(t, err)
+		_, err = client.BufferedUpsert(ctx, graphName, "a", map[string]any{"id": "a"})
+		assert.Nil(t, err)
+
+		// The loading job is not mocked, so TigerGraph rejects it on every replay
+		client.BaseURL = srv.HTTPServer.URL
+		replayed, err := client.ReplayWAL(ctx)
+		assert.Equal(t, 0, replayed)
+
+		var replayErr *tigergraph.WALReplayError
+		assert.ErrorAs(t, err, &replayErr)
+		assert.Equal(t, "poison", replayErr.Entry.Key)
+		assert.True(t, replayErr.Rejected())
+
+		assert.Nil(t, wal.DeadLetter(replayErr.Entry.Key))
+		assert.ErrorIs(t, wal.DeadLetter(replayErr.Entry.Key), tigergraph.ErrWALEntryNotFound)
+
+		replayed, err = client.ReplayWAL(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, replayed)
+		assert.Len(t, srv.Calls[upsertURL], 1)
+
+		deadLetters, err := wal.DeadLetters()
+		assert.Nil(t, err)
+		assert.Len(t, deadLetters, 1)
+		assert.Equal(t, "missing_job", deadLetters[0].LoadingJob)
+	})
+
+	t.Run("rejected writes are not buffered", func(t *testing.T) {
+		srv := NewMockServer(expectedUsername, expectedPassword)
+		defer srv.Close()
+
+		wal, err := tigergraph.OpenWAL(t.TempDir())
+		assert.Nil(t, err)
+
+		client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+		client.WAL = wal
+
+		buffered, err := client.BufferedUpsert(context.Background(), graphName, "a", map[string]any{})
+		assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+		assert.False(t, buffered)
+
+		pending, err := wal.Len()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, pending)
+	})
+
+	t.Run("no WAL configured", func(t *testing.T) {
+		client := tigergraph.NewClient(unreachable.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+		_, err := client.BufferedUpsert(context.Background(), graphName, "a", map[string]any{})
+		assert.ErrorIs(t, err, tigergraph.ErrWALNotConfigured)
+	})
+}
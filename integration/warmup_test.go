@@ -0,0 +1,117 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package integration
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarmup(t *testing.T) {
+	tests := []struct {
+		name   string
+		action func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer)
+	}{
+		{
+			name: "pings the server and fetches a token per graph",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				err := client.Warmup(context.Background(), graphName, "OtherGraph")
+				assert.Nil(t, err)
+
+				assert.Len(t, srv.Calls[tigergraph.PingURL], 1)
+				assert.Len(t, srv.Calls[tigergraph.RequestTokenURL], 2)
+				assert.Contains(t, client.Tokens.Graphs(), graphName)
+				assert.Contains(t, client.Tokens.Graphs(), "OtherGraph")
+			},
+		},
+		{
+			name: "ping failure",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				srv.Mock(tigergraph.PingURL, func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				})
+
+				err := client.Warmup(context.Background(), graphName)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+				assert.Len(t, srv.Calls[tigergraph.RequestTokenURL], 0)
+			},
+		},
+		{
+			name: "token failure",
+			action: func(t *testing.T, client *tigergraph.TigerGraphClient, srv *MockTigerGraphServer) {
+				client.BasicAuthPassword = "wrong"
+
+				err := client.Warmup(context.Background(), graphName)
+				assert.ErrorIs(t, err, tigergraph.ErrNonOK)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := NewMockServer(expectedUsername, expectedPassword)
+			defer srv.Close()
+
+			client := tigergraph.NewClient(srv.HTTPServer.URL, tigergraph.WithCredentials(expectedUsername, expectedPassword))
+
+			test.action(t, client, srv)
+		})
+	}
+}
+
+func TestWarmupOpensPooledConnections(t *testing.T) {
+	srv := NewMockServer(expectedUsername, expectedPassword)
+	defer srv.Close()
+
+	var mu sync.Mutex
+	dials := map[string]int{}
+	dialer := &net.Dialer{}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network string, addr string) (net.Conn, error) {
+			mu.Lock()
+			dials[addr]++
+			mu.Unlock()
+
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}
+
+	// Different host names for the same server give the RESTPP and GSQL URLs separate connections
+	fileURL := strings.Replace(srv.HTTPServer.URL, "127.0.0.1", "localhost", 1)
+	client := tigergraph.NewClient(
+		srv.HTTPServer.URL,
+		tigergraph.WithFileURL(fileURL),
+		tigergraph.WithCredentials(expectedUsername, expectedPassword),
+		tigergraph.WithHTTPClient(&http.Client{Transport: transport}),
+	)
+
+	err := client.Warmup(context.Background(), graphName)
+	assert.Nil(t, err)
+
+	// The ping and the token request reuse the connections opened to each server
+	assert.Len(t, srv.Calls["/"], 2)
+	assert.Len(t, srv.Calls[tigergraph.PingURL], 1)
+	assert.Len(t, srv.Calls[tigergraph.RequestTokenURL], 1)
+	assert.Equal(t, map[string]int{
+		strings.TrimPrefix(srv.HTTPServer.URL, "http://"): 1,
+		strings.TrimPrefix(fileURL, "http://"):            1,
+	}, dials)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// VersionURL is the RESTPP URL which reports the TigerGraph version
+	VersionURL = "/version"
+
+	// AtomicLevelHeader is the header which asks RESTPP to apply a write all-or-nothing
+	AtomicLevelHeader = "gsql-atomic-level"
+
+	// AtomicLevelAtomic is the AtomicLevelHeader value for all-or-nothing writes
+	AtomicLevelAtomic = "atomic"
+
+	// MinAtomicPostMajorVersion is the first major version of TigerGraph supporting AtomicLevelHeader
+	MinAtomicPostMajorVersion = 3
+
+	atomicSupportUnknown     = 0
+	atomicSupported          = 1
+	atomicSupportUnavailable = 2
+)
+
+var (
+	// ErrAtomicPostUnsupported means an atomic write was requested from a TigerGraph server
+	// which cannot apply writes atomically
+	ErrAtomicPostUnsupported = errors.New("TigerGraph server does not support atomic writes")
+
+	// ErrUnknownServerVersion means the TigerGraph version could not be found in the version response
+	ErrUnknownServerVersion = errors.New("unable to determine TigerGraph version")
+
+	serverVersionRegexp = regexp.MustCompile(`TigerGraph version: *(\d+)\.(\d+)\.(\d+)`)
+)
+
+// ServerVersion returns the TigerGraph version reported by RESTPP, e.g. "3.9.3". A graph is
+// needed to authenticate the request.
+func (c *TigerGraphClient) ServerVersion(ctx context.Context, graph string) (string, error) {
+	var response TigerGraphResponse[any]
+	if err := c.Get(ctx, VersionURL, graph, &response, WithOperation("get version")); err != nil {
+		return "", err
+	}
+
+	match := serverVersionRegexp.FindStringSubmatch(response.Message)
+	if match == nil {
+		return "", fmt.Errorf("%w: %q", ErrUnknownServerVersion, response.Message)
+	}
+
+	return fmt.Sprintf("%s.%s.%s", match[1], match[2], match[3]), nil
+}
+
+// SupportsAtomicPosts reports whether the server can apply a write all-or-nothing when asked
+// to with the Atomic option. The result is cached by the client.
+func (c *TigerGraphClient) SupportsAtomicPosts(ctx context.Context, graph string) (bool, error) {
+	switch atomic.LoadInt32(&c.atomicSupport) {
+	case atomicSupported:
+		return true, nil
+	case atomicSupportUnavailable:
+		return false, nil
+	}
+
+	version, err := c.ServerVersion(ctx, graph)
+	if err != nil {
+		return false, err
+	}
+
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrUnknownServerVersion, version)
+	}
+
+	supported := major >= MinAtomicPostMajorVersion
+	if supported {
+		atomic.StoreInt32(&c.atomicSupport, atomicSupported)
+	} else {
+		atomic.StoreInt32(&c.atomicSupport, atomicSupportUnavailable)
+	}
+
+	return supported, nil
+}
+
+// Atomic asks RESTPP to apply a POST, e.g. an Upsert of a group of vertices and edges, all or
+// nothing. The call fails with ErrAtomicPostUnsupported if the server cannot do this.
+func Atomic() RequestOption {
+	return func(o *requestOptions) {
+		o.atomic = true
+	}
+}
+
+// requireAtomicPosts returns ErrAtomicPostUnsupported if the server cannot apply atomic writes.
+func (c *TigerGraphClient) requireAtomicPosts(ctx context.Context, graph string) error {
+	supported, err := c.SupportsAtomicPosts(ctx, graph)
+	if err != nil {
+		return fmt.Errorf("failed to check for atomic write support: %w", err)
+	}
+
+	if !supported {
+		return ErrAtomicPostUnsupported
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// BatchSizeVertexType is the metadata vertex type which records the batch size learned for
+	// each loading job of each graph
+	BatchSizeVertexType = "LoadingJobBatchSize"
+
+	// DefaultBatchSize is the number of lines a BatchLoader sends per request unless set with
+	// WithBatchSize, and the size auto-tuning starts from when nothing has been learned.
+	DefaultBatchSize = 1000
+
+	// DefaultMinBatchSize is the smallest batch auto-tuning shrinks to, unless set in BatchTuning
+	DefaultMinBatchSize = 10
+
+	// DefaultMaxBatchSize is the largest batch auto-tuning grows to, unless set in BatchTuning
+	DefaultMaxBatchSize = 100000
+)
+
+// BatchSizeSchemaGSQL adds the LoadingJobBatchSize vertex type to the metadata graph
+//
+//go:embed gsql/batch_size_schema.gsql
+var BatchSizeSchemaGSQL string
+
+// BatchTuning keeps the latency of each batch a BatchLoader sends within a target band. After
+// each batch the size is doubled if the request took less than TargetLatencyLow, and halved if
+// it took more than TargetLatencyHigh, within MinSize and MaxSize.
+type BatchTuning struct {
+	TargetLatencyLow  time.Duration
+	TargetLatencyHigh time.Duration
+
+	// MinSize and MaxSize bound the batch size. Zero means DefaultMinBatchSize and
+	// DefaultMaxBatchSize.
+	MinSize int
+	MaxSize int
+
+	// Persist stores the learned batch size in the metadata graph, per graph and loading job,
+	// so that the next BatchLoader for the job starts from it rather than relearning it.
+	Persist bool
+}
+
+// BatchSizeRecord records the batch size learned for a loading job, stored in the metadata graph.
+type BatchSizeRecord struct {
+	ID         string `json:"id"`
+	Graph      string `json:"graph_name"`
+	LoadingJob string `json:"loading_job"`
+	BatchSize  int    `json:"batch_size"`
+}
+
+// BatchLoader runs a loading job with JSONL lines in batches, optionally tuning the batch size
+// to the server's response times. It is safe for concurrent use, though batches are sent one
+// at a time.
+type BatchLoader struct {
+	client     *TigerGraphClient
+	graph      string
+	loadingJob string
+	tuning     *BatchTuning
+
+	mu       sync.Mutex
+	size     int
+	restored bool
+}
+
+// BatchOption configures a BatchLoader
+type BatchOption func(*BatchLoader)
+
+// WithBatchSize sets the number of lines sent per request, or the starting size when tuning.
+func WithBatchSize(size int) BatchOption {
+	return func(b *BatchLoader) {
+		b.size = size
+	}
+}
+
+// WithBatchTuning turns on auto-tuning of the batch size.
+func WithBatchTuning(tuning BatchTuning) BatchOption {
+	return func(b *BatchLoader) {
+		if tuning.MinSize <= 0 {
+			tuning.MinSize = DefaultMinBatchSize
+		}
+		if tuning.MaxSize <= 0 {
+			tuning.MaxSize = DefaultMaxBatchSize
+		}
+		b.tuning = &tuning
+	}
+}
+
+// NewBatchLoader returns a BatchLoader for the loading job of the graph.
+func (c *TigerGraphClient) NewBatchLoader(graph string, loadingJob string, opts ...BatchOption) *BatchLoader {
+	b := &BatchLoader{
+		client:     c,
+		graph:      graph,
+		loadingJob: loadingJob,
+		size:       DefaultBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.size <= 0 {
+		b.size = DefaultBatchSize
+	}
+
+	return b
+}
+
+// BatchSize returns the number of lines the next batch will hold.
+func (b *BatchLoader) BatchSize() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.size
+}
+
+// Load sends the lines to the loading job with RunLoadingJobJSONL, a batch at a time, stopping
+// at the first batch which fails. When tuning is persisted, the learned batch size is read
+// before the first batch and saved once the lines are loaded; failing to save it is logged
+// rather than failing the load.
+func (b *BatchLoader) Load(ctx context.Context, lines []any) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	persist := b.tuning != nil && b.tuning.Persist
+	if persist && !b.restored {
+		if err := b.restoreBatchSize(ctx); err != nil {
+			return err
+		}
+		b.restored = true
+	}
+
+	startSize := b.size
+
+	for start := 0; start < len(lines); {
+		end := start + b.size
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		requestStart := time.Now()
+		if err := b.client.RunLoadingJobJSONL(ctx, b.graph, b.loadingJob, lines[start:end]); err != nil {
+			return fmt.Errorf("failed to load lines %d to %d: %w", start, end-1, err)
+		}
+
+		b.tune(time.Since(requestStart))
+		start = end
+	}
+
+	if persist && b.size != startSize {
+		if err := b.saveBatchSize(ctx); err != nil {
+			b.client.logger().Warn(
+				"failed to save learned batch size",
+				"graph", b.graph,
+				"loading_job", b.loadingJob,
+				"error", err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// tune grows or shrinks the batch size to bring the latency into the target band
+func (b *BatchLoader) tune(latency time.Duration) {
+	if b.tuning == nil {
+		return
+	}
+
+	switch {
+	case latency < b.tuning.TargetLatencyLow:
+		b.size *= 2
+	case b.tuning.TargetLatencyHigh > 0 && latency > b.tuning.TargetLatencyHigh:
+		b.size /= 2
+	}
+
+	b.clampSize()
+}
+
+// clampSize keeps the batch size between the tuning's MinSize and MaxSize
+func (b *BatchLoader) clampSize() {
+	if b.size < b.tuning.MinSize {
+		b.size = b.tuning.MinSize
+	}
+	if b.size > b.tuning.MaxSize {
+		b.size = b.tuning.MaxSize
+	}
+}
+
+func (b *BatchLoader) recordID() string {
+	return fmt.Sprintf("%s_%s", b.graph, b.loadingJob)
+}
+
+// restoreBatchSize starts from the batch size learned for the loading job, if there is one,
+// first bringing the metadata schema up to date so that batch sizes can be stored
+func (b *BatchLoader) restoreBatchSize(ctx context.Context) error {
+	if err := b.client.ensureMetadataSchema(ctx); err != nil {
+		return err
+	}
+
+	records, err := b.client.GetBatchSizes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if record.ID == b.recordID() && record.BatchSize > 0 {
+			b.size = record.BatchSize
+			b.clampSize()
+		}
+	}
+
+	return nil
+}
+
+// GetBatchSizes returns the batch sizes learned by BatchLoaders which persist their tuning.
+func (c *TigerGraphClient) GetBatchSizes(ctx context.Context) ([]BatchSizeRecord, error) {
+	queryURL := fmt.Sprintf(VerticesURLTemplate, MetadataGraphName, BatchSizeVertexType)
+	response := &TigerGraphResponse[ResponseVertex[BatchSizeRecord]]{}
+
+	err := c.Get(ctx, queryURL, MetadataGraphName, response, WithOperation("get batch sizes"))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error {
+		return nil, newOperationError("get batch sizes", MetadataGraphName, queryURL, fmt.Errorf(
+			"TigerGraph returned an error when getting batch sizes. Message: %s: %w",
+			response.Message,
+			ErrTigerGraphError,
+		))
+	}
+
+	records := make([]BatchSizeRecord, 0, len(response.Results))
+	for _, vertex := range response.Results {
+		records = append(records, vertex.Attributes)
+	}
+
+	return records, nil
+}
+
+// saveBatchSize upserts the learned batch size
+func (b *BatchLoader) saveBatchSize(ctx context.Context) error {
+	payload := b.client.NewUpsertPayload().AddVertex(BatchSizeVertexType, b.recordID(), map[string]any{
+		"graph_name":  b.graph,
+		"loading_job": b.loadingJob,
+		"batch_size":  b.size,
+		"updated_at":  b.client.now(),
+	})
+
+	res, err := b.client.Upsert(ctx, MetadataGraphName, payload, WithOperation("save batch size"))
+	if err != nil {
+		return err
+	}
+
+	if res.AcceptedVertices != 1 {
+		return fmt.Errorf(
+			"upsert of batch size returned an unexpected number of accepted vertices. accepted: %d but expected only 1. error type: %w",
+			res.AcceptedVertices,
+			ErrTigerGraphSchemaSetUpFailed,
+		)
+	}
+
+	return nil
+}
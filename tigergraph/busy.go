@@ -0,0 +1,175 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxBusyRetries is a reasonable number of busy retries to pass to WithBusyRetries.
+	// Busy responses are not retried unless retries are enabled.
+	DefaultMaxBusyRetries = 3
+
+	// DefaultMaxBusyWait is the longest the client will wait before retrying a busy response
+	DefaultMaxBusyWait = 30 * time.Second
+
+	// defaultBusyWait is used when a busy response does not include a valid Retry-After header
+	defaultBusyWait = time.Second
+)
+
+// ErrServerBusy represents TigerGraph responding with 503 Service Unavailable, e.g. during
+// a rebuild, after the client has exhausted its busy retries. It also matches ErrNonOK.
+var ErrServerBusy = errors.New("TigerGraph is busy")
+
+// ErrBodyNotRewindable represents a request which could not be retried because its body had
+// already been read and the request has no GetBody to read it again, e.g. one built with an
+// io.NopCloser around the body. Requests made with http.NewRequest from a bytes.Buffer,
+// bytes.Reader or strings.Reader can always be retried.
+var ErrBodyNotRewindable = errors.New("the request body cannot be read again to retry the request")
+
+// WithBusyRetries enables retrying requests which receive 503 Service Unavailable, up to
+// maxRetries times, honouring the Retry-After header between attempts.
+func WithBusyRetries(maxRetries int) Option {
+	return func(c *TigerGraphClient) {
+		c.MaxBusyRetries = maxRetries
+	}
+}
+
+// doWithBusyRetries performs an HTTP request. Requests which receive a 503 response are retried
+// after the delay given in the Retry-After header, up to MaxBusyRetries times, as long as the
+// delay ends before the request's deadline. The number of attempts made is returned alongside
+// the response.
+func (c *TigerGraphClient) doWithBusyRetries(req *http.Request) (*http.Response, int, error) {
+	maxRetries := c.MaxBusyRetries
+	if isPingRequest(req) {
+		maxRetries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(req)
+		if err != nil {
+			return nil, attempt + 1, err
+		}
+
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			return resp, attempt + 1, nil
+		}
+
+		wait := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if !canRewind(req) {
+			return nil, attempt + 1, fmt.Errorf(
+				"server asked to retry after %s, but %w: %w: %w",
+				wait,
+				ErrBodyNotRewindable,
+				ErrServerBusy,
+				ErrNonOK,
+			)
+		}
+
+		if attempt >= maxRetries || wait > c.maxBusyWait() || waitsPastDeadline(req.Context(), wait) {
+			return nil, attempt + 1, fmt.Errorf(
+				"gave up after %d attempts, server asked to retry after %s: %w: %w",
+				attempt+1,
+				wait,
+				ErrServerBusy,
+				ErrNonOK,
+			)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, attempt + 1, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req, err = rewindRequest(req); err != nil {
+			return nil, attempt + 1, err
+		}
+	}
+}
+
+// waitsPastDeadline reports whether waiting for the given delay would end after ctx's deadline,
+// in which case there is no point waiting to retry
+func waitsPastDeadline(ctx context.Context, wait time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Now().Add(wait).After(deadline)
+}
+
+func (c *TigerGraphClient) maxBusyWait() time.Duration {
+	if c.MaxBusyWait == 0 {
+		return DefaultMaxBusyWait
+	}
+
+	return c.MaxBusyWait
+}
+
+// canRewind reports whether the request can be sent again, because it has no body or can read
+// its body again with GetBody.
+func canRewind(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewindRequest returns a copy of the request with a fresh body so that it can be sent again,
+// or ErrBodyNotRewindable if its body cannot be read again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if !canRewind(req) {
+		return nil, ErrBodyNotRewindable
+	}
+
+	retry := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return retry, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	retry.Body = body
+
+	return retry, nil
+}
+
+// parseRetryAfter reads a Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return defaultBusyWait
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(now)
+		if wait < 0 {
+			return 0
+		}
+		return wait
+	}
+
+	return defaultBusyWait
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "missing header", value: "", expected: defaultBusyWait},
+		{name: "seconds", value: "5", expected: 5 * time.Second},
+		{name: "http date", value: now.Add(10 * time.Second).Format(http.TimeFormat), expected: 10 * time.Second},
+		{name: "http date in the past", value: now.Add(-10 * time.Second).Format(http.TimeFormat), expected: 0},
+		{name: "negative seconds", value: "-1", expected: defaultBusyWait},
+		{name: "garbage", value: "soon", expected: defaultBusyWait},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, parseRetryAfter(testCase.value, now))
+		})
+	}
+}
+
+func TestRewindRequest(t *testing.T) {
+	t.Run("reads the body again", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "http://tg:9000/graph/g", strings.NewReader("body"))
+		assert.Nil(t, err)
+		_, err = io.ReadAll(req.Body)
+		assert.Nil(t, err)
+
+		retry, err := rewindRequest(req)
+		assert.Nil(t, err)
+		body, err := io.ReadAll(retry.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, "body", string(body))
+	})
+
+	t.Run("without a body", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "http://tg:9000/graph/g", nil)
+		assert.Nil(t, err)
+
+		_, err = rewindRequest(req)
+		assert.Nil(t, err)
+	})
+
+	t.Run("body which cannot be read again", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "http://tg:9000/graph/g", io.NopCloser(strings.NewReader("body")))
+		assert.Nil(t, err)
+
+		_, err = rewindRequest(req)
+		assert.ErrorIs(t, err, ErrBodyNotRewindable)
+	})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// callCredentials caches the tokens requested with credentials given to calls, per username and
+// password, so that each tenant of a multi-tenant service requests a token per graph only once
+type callCredentials struct {
+	mu     sync.Mutex
+	caches map[string]*TokenCache
+}
+
+// WithCallCredentials makes a single call as the given TigerGraph user in place of the client's
+// credentials, e.g. for a multi-tenant service where each request carries its own credentials.
+// GSQL is run with them as basic auth, and RESTPP calls use a token requested with them, which
+// the client caches per user and graph.
+func WithCallCredentials(username string, password string) RequestOption {
+	return func(o *requestOptions) {
+		o.credentials = &Credentials{Username: username, Password: password}
+	}
+}
+
+// WithCallToken makes a single call with the given token in place of the client's tokens and
+// credentials. The token is sent as given, to RESTPP and the GSQL server.
+func WithCallToken(token string) RequestOption {
+	return func(o *requestOptions) {
+		o.token = token
+	}
+}
+
+// applyCallTokenAuth authenticates a RESTPP request with the call's token or credentials if it
+// has them, or else the client's token for the graph.
+func (c *TigerGraphClient) applyCallTokenAuth(req *http.Request, graph string, options requestOptions) error {
+	if options.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", options.token))
+		return nil
+	}
+
+	if options.credentials == nil {
+		return c.ApplyTokenAuth(req, graph)
+	}
+
+	token, err := c.callCredentialsToken(req.Context(), graph, *options.credentials)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.Value))
+	return nil
+}
+
+// applyCallGSQLAuth replaces the authentication of a GSQL server request with the call's
+// token or credentials, if it has them.
+func applyCallGSQLAuth(req *http.Request, options requestOptions) {
+	switch {
+	case options.token != "":
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", options.token))
+	case options.credentials != nil:
+		req.SetBasicAuth(options.credentials.Username, options.credentials.Password)
+	}
+}
+
+// callCredentialsToken returns a non-expired token for the graph requested with the credentials
+func (c *TigerGraphClient) callCredentialsToken(ctx context.Context, graph string, credentials Credentials) (*Token, error) {
+	return c.cachedToken(ctx, c.callCredentials.cache(credentials), graph, func(ctx context.Context) (*Token, error) {
+		body := &RequestTokenRequest{Graph: graph, Lifetime: lifetimeSeconds(c.TokenLifetime)}
+		return c.requestTokenWith(ctx, graph, body, func(req *http.Request) error {
+			req.SetBasicAuth(credentials.Username, credentials.Password)
+			return nil
+		})
+	})
+}
+
+// cache returns the token cache for the credentials, keyed by a digest of the password so that
+// changed passwords are not given tokens requested with old ones
+func (cc *callCredentials) cache(credentials Credentials) *TokenCache {
+	digest := sha256.Sum256([]byte(credentials.Password))
+	key := credentials.Username + ":" + hex.EncodeToString(digest[:])
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.caches == nil {
+		cc.caches = make(map[string]*TokenCache)
+	}
+	cache, ok := cc.caches[key]
+	if !ok {
+		cache = NewTokenCache()
+		cc.caches[key] = cache
+	}
+
+	return cache
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without making a request while the client's circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open, TigerGraph is assumed to be unavailable")
+
+// CircuitState is the state of a CircuitBreaker
+type CircuitState string
+
+const (
+	// CircuitClosed means requests are sent as normal
+	CircuitClosed CircuitState = "closed"
+
+	// CircuitOpen means requests fail immediately with ErrCircuitOpen
+	CircuitOpen CircuitState = "open"
+
+	// CircuitHalfOpen means a single probe request is being sent to find out if TigerGraph has recovered
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreaker stops a client sending requests which are doomed to fail while TigerGraph is
+// down. It opens after FailureThreshold consecutive failures, i.e. network errors or 5xx
+// responses. Once OpenDuration has passed a single probe request is let through, closing the
+// circuit if it succeeds and reopening it if it fails.
+//
+// A CircuitBreaker is safe to use from multiple goroutines and may be shared between clients.
+type CircuitBreaker struct {
+	FailureThreshold int
+	OpenDuration     time.Duration
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// NewCircuitBreaker creates a closed CircuitBreaker.
+func NewCircuitBreaker(failureThreshold int, openDuration time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		OpenDuration:     openDuration,
+		state:            CircuitClosed,
+		now:              time.Now,
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// allow returns ErrCircuitOpen if a request must not be sent. When the circuit has been open
+// for OpenDuration, the first caller is allowed through as the probe.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		retryIn := b.OpenDuration - b.now().Sub(b.openedAt)
+		if retryIn > 0 {
+			return fmt.Errorf("%w: next probe in %s", ErrCircuitOpen, retryIn.Round(time.Millisecond))
+		}
+		b.state = CircuitHalfOpen
+		return nil
+	case CircuitHalfOpen:
+		return fmt.Errorf("%w: waiting for probe request", ErrCircuitOpen)
+	default:
+		return nil
+	}
+}
+
+// record updates the circuit with the outcome of a request let through by allow.
+func (b *CircuitBreaker) record(resp *http.Response, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if errors.Is(err, context.Canceled) {
+		// The caller gave up, which says nothing about TigerGraph
+		if b.state == CircuitHalfOpen {
+			b.state = CircuitOpen
+		}
+		return
+	}
+
+	if err == nil && resp.StatusCode < http.StatusInternalServerError {
+		b.state = CircuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.FailureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// WithCircuitBreaker sets the CircuitBreaker requests are made through.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(c *TigerGraphClient) {
+		c.CircuitBreaker = breaker
+	}
+}
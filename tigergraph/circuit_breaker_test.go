@@ -0,0 +1,70 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) { //nolint:funlen
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	ok := &http.Response{StatusCode: http.StatusOK}
+	failed := &http.Response{StatusCode: http.StatusInternalServerError}
+	networkErr := &url.Error{Op: "Get", URL: "http://tg:9000", Err: context.DeadlineExceeded}
+
+	// Client errors and successes do not count as failures
+	assert.Nil(t, breaker.allow())
+	breaker.record(&http.Response{StatusCode: http.StatusBadRequest}, nil)
+	breaker.record(failed, nil)
+	breaker.record(ok, nil)
+	breaker.record(failed, nil)
+	assert.Equal(t, CircuitClosed, breaker.State())
+
+	// Opens after consecutive failures
+	breaker.record(nil, networkErr)
+	assert.Equal(t, CircuitOpen, breaker.State())
+	assert.ErrorIs(t, breaker.allow(), ErrCircuitOpen)
+
+	// Lets one probe through after OpenDuration
+	now = now.Add(time.Minute)
+	assert.Nil(t, breaker.allow())
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+	assert.ErrorIs(t, breaker.allow(), ErrCircuitOpen)
+
+	// A failed probe reopens the circuit
+	breaker.record(failed, nil)
+	assert.Equal(t, CircuitOpen, breaker.State())
+	assert.ErrorIs(t, breaker.allow(), ErrCircuitOpen)
+
+	// A cancelled probe does not close or restart the wait
+	now = now.Add(time.Minute)
+	assert.Nil(t, breaker.allow())
+	breaker.record(nil, &url.Error{Op: "Get", URL: "http://tg:9000", Err: context.Canceled})
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	// A successful probe closes the circuit
+	assert.Nil(t, breaker.allow())
+	breaker.record(ok, nil)
+	assert.Equal(t, CircuitClosed, breaker.State())
+	assert.Nil(t, breaker.allow())
+}
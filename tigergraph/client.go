@@ -22,6 +22,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -32,7 +33,8 @@ var (
 	// ErrGet represents a failure to make a GET request
 	ErrGet = errors.New("failed to make GET request to TigerGraph")
 
-	// ErrNonOK represents a non-OK status code (200) was returned
+	// ErrNonOK represents a non-OK status code (200) was returned. Authentication failures also
+	// match ErrUnauthorized or ErrForbidden.
 	ErrNonOK = errors.New("TigerGraph returned non-OK status code")
 
 	// ErrBodyReadFailed  represents a failure to read the TigerGraph response body
@@ -46,14 +48,24 @@ var (
 
 	// ErrNotOneResult represents a response shape that does not contain exactly one result
 	ErrNotOneResult = errors.New("TigerGraph did not respond with exactly one result")
+
+	// ErrPayloadTooLarge represents a request body that exceeds the client's configured MaxRequestSize
+	ErrPayloadTooLarge = errors.New("request payload exceeds the maximum request size")
 )
 
 const (
 	// PingURL is the URL to make a ping request
 	PingURL = "/api/ping"
 
+	// QueryURLTemplate is the RESTPP URL for running an installed query. It must be formatted
+	// with the graph name and query name.
+	QueryURLTemplate = "/query/%s/%s"
+
 	// TigerGraphDateTimeFormat is the date format used by TigerGraph
 	TigerGraphDateTimeFormat = "2006-01-02 15:04:05"
+
+	// DefaultDateTime is the value TigerGraph gives DATETIME attributes which have not been set
+	DefaultDateTime = "1970-01-01 00:00:00"
 )
 
 // Token is used to track active TigerGraph tokens on the client
@@ -68,68 +80,434 @@ type TigerGraphClient struct {
 	BaseFileURL       string
 	BasicAuthUsername string
 	BasicAuthPassword string
-	Tokens            map[string]*Token
+
+	// Tokens caches a RESTPP token per graph, and is safe for concurrent use
+	Tokens *TokenCache
+
+	// HTTPClient is used to make every request to TigerGraph, allowing timeouts, transports
+	// and proxies to be configured. Defaults to http.DefaultClient, copied by NewClient to
+	// apply the connect timeout.
+	HTTPClient *http.Client
+
+	// CredentialsProvider supplies basic auth credentials in place of BasicAuthUsername and
+	// BasicAuthPassword, and is asked to refresh them when TigerGraph rejects them.
+	CredentialsProvider CredentialsProvider
+
+	// Diagnostics, if set, attaches log lines to the errors of failed installed queries and
+	// loading jobs. Set with WithDiagnostics.
+	Diagnostics *Diagnostics
+
+	// Secrets holds a GSQL secret per graph. Tokens for a graph with a secret are requested
+	// with the secret rather than with basic auth. Set with WithSecret.
+	Secrets map[string]string
+
+	// StaticTokens holds a pre-provisioned token per graph, used as given in place of
+	// requesting one. Set with WithStaticToken.
+	StaticTokens map[string]string
+
+	// GSQLSession, if set, keeps the cookies and active graph of RunGSQL and GSQLCommand
+	// calls across requests. Set with WithGSQLSession.
+	GSQLSession *GSQLSession
+
+	// TokenStore, if set, shares requested tokens with other clients. Set with WithTokenStore.
+	TokenStore TokenStore
+
+	// TokenLifetime is how long requested tokens last. Zero leaves it to the server's default.
+	// Set with WithTokenLifetime.
+	TokenLifetime time.Duration
+
+	// TokenProvider, if set, supplies tokens in place of requesting them from TigerGraph.
+	TokenProvider TokenProvider
+
+	// Cloud makes the client authenticate as TigerGraph Cloud expects: tokens are requested
+	// from the GSQL server's CloudTokenURL, and without basic auth credentials GSQL server
+	// requests are authenticated with a token. Set by NewCloudClient.
+	Cloud bool
+
+	// DisableTokenRequests stops the client requesting tokens, so only StaticTokens are used.
+	DisableTokenRequests bool
+
+	// TokenMetrics receives token lifecycle events. Events are discarded if this is nil.
+	TokenMetrics TokenMetrics
+
+	// UpsertMetrics receives accepted and skipped entity counts for every upsert. Events are
+	// discarded if this is nil.
+	UpsertMetrics UpsertMetrics
+
+	// RequestMetrics receives the duration and attempt count of every request. Events are
+	// discarded if this is nil.
+	RequestMetrics RequestMetrics
+
+	// QueryResponseLimit caps the size in bytes of responses to installed query calls.
+	// Zero means no limit.
+	QueryResponseLimit int64
+
+	// DatetimeEncoding is how DATETIME values are sent by NewUpsertPayload, NewQueryParams,
+	// WrapAttributes, GetEdges and the soft delete helpers.
+	DatetimeEncoding DatetimeEncoding
+
+	// ReplicaRetryInterval is how long a read replica which has failed is skipped for.
+	// Zero means DefaultReplicaRetryInterval.
+	ReplicaRetryInterval time.Duration
+
+	// ConnectionPool tunes how connections to TigerGraph are pooled. NewClient uses
+	// DefaultConnectionPool, applied to its own copy of the HTTP client's transport.
+	ConnectionPool ConnectionPool
+
+	// Timeouts bounds connecting, requests, queries and migration steps. NewClient uses
+	// DefaultTimeouts. Zero durations mean calls are only bounded by their context.
+	Timeouts Timeouts
+
+	// Now returns the current time, used to decide when cached tokens have expired and to
+	// timestamp migrations and soft deletes. Defaults to time.Now.
+	Now func() time.Time
+
+	// AllowedQueries, if not nil, are the only installed queries Get, Post and PostRaw may
+	// run. Other queries are rejected with ErrQueryNotAllowed before a request is made.
+	AllowedQueries []string
+
+	// LogLevels sets the level requests, GSQL output and migration steps are logged at when
+	// Logger is a LevelLogger. NewClient uses DefaultLogLevels.
+	LogLevels LogLevels
+
+	// Headers are sent with every request unless the library or the call sets a header with
+	// the same name.
+	Headers http.Header
+
+	// AppName identifies the calling application in the User-Agent header sent with every
+	// request, so that RESTPP traffic can be attributed to it.
+	AppName string
+
+	// UserAgent replaces the User-Agent sent with every request. Defaults to
+	// go-tigergraph/<version>, followed by AppName if it is set.
+	UserAgent string
+
+	// MaxRequestSize is the maximum size in bytes of a POST body sent to RESTPP. Larger
+	// bodies are rejected with ErrPayloadTooLarge before being sent. Zero means no limit.
+	MaxRequestSize int
+
+	// RetryPolicy retries requests to RESTPP which fail with network errors or transient
+	// statuses. Requests are not retried if this is nil.
+	RetryPolicy *RetryPolicy
+
+	// CircuitBreaker short-circuits requests with ErrCircuitOpen after repeated failures.
+	// Requests are always sent if this is nil.
+	CircuitBreaker *CircuitBreaker
+
+	// GraphLimiter limits the number of concurrent Get, Post and PostRaw calls to each graph.
+	// Calls are not limited if this is nil.
+	GraphLimiter *GraphLimiter
+
+	// MaxBusyRetries is the number of times a request is retried when TigerGraph responds
+	// with 503 Service Unavailable. The Retry-After header is honoured between attempts, and
+	// no retry is made if its delay would end after the request's deadline. Busy responses
+	// are not retried by default, see WithBusyRetries. Ping never retries busy responses.
+	MaxBusyRetries int
+
+	// MaxBusyWait is the longest Retry-After delay the client will wait for. Busy responses
+	// asking for a longer delay fail immediately with ErrServerBusy. Defaults to DefaultMaxBusyWait.
+	MaxBusyWait time.Duration
+
+	// MaxGSQLBodySize is the largest request body, in bytes, sent to the GSQL server. Larger
+	// GSQL given to RunGSQL, e.g. a migration file with hundreds of queries, is split into
+	// parts on statement boundaries which are run one after another. Zero means GSQL is never split.
+	MaxGSQLBodySize int
+
+	// CompressRequestsAbove is the size in bytes at which POST bodies are gzipped before being
+	// sent. MaxRequestSize applies to the uncompressed size. Zero means bodies are never compressed.
+	CompressRequestsAbove int
+
+	// DisableCompression stops the client asking for gzip compressed responses. It is applied
+	// to the client's transport by NewClient, so it must be set by an option such as
+	// WithoutResponseCompression.
+	DisableCompression bool
+
+	// DecodeErrorBodyLimit is the number of bytes of a response which could not be decoded
+	// included in the DecodeError. Zero means DefaultDecodeErrorBodyLimit, and negative none.
+	DecodeErrorBodyLimit int
+
+	// UseJSONNumber decodes numbers in responses into json.Number instead of float64 when the
+	// target is an interface{}, avoiding loss of precision for INT64 and UINT attributes.
+	UseJSONNumber bool
+
+	// MetadataInitGSQL replaces InitFileString as the GSQL used to create the metadata graph.
+	// It must create the Migration vertex type and get_latest_migration query.
+	MetadataInitGSQL string
+
+	// MetadataMigrations are applied to the metadata graph after the built-in metadata migrations,
+	// e.g. to add extra attributes. They are numbered from 1, versioned apart from the built-in
+	// migrations.
+	MetadataMigrations []MetadataMigration
+
+	// ResumableMigrations makes Migrate persist its plan before running it, so that it can be
+	// resumed with ResumeMigration.
+	ResumableMigrations bool
+
+	// MigrationReportWriter and MigrationReportPath, if set, receive a JSON MigrationReport at
+	// the end of every call to Migrate.
+	MigrationReportWriter io.Writer
+	MigrationReportPath   string
+
+	// AllowGlobalSchemaChanges lets RunGSQL run GSQL which changes the global schema.
+	AllowGlobalSchemaChanges bool
+
+	// LatestMigrationQueryName is the name of the query installed on the metadata graph which is
+	// used to get the latest migration. Defaults to DefaultLatestMigrationQueryName. Migrate
+	// installs the query if it is missing.
+	LatestMigrationQueryName string
+
+	// MigrationComparator orders migration identifiers. Defaults to NumericMigrationComparator.
+	MigrationComparator MigrationComparator
+
+	// MigrationDiscovery controls how Migrate finds migration files, e.g. in subdirectories.
+	MigrationDiscovery MigrationDiscovery
+
+	// WAL buffers writes made with BufferedUpsert and BufferedRunLoadingJobJSONL while
+	// TigerGraph is unreachable.
+	WAL *WAL
+
+	// Logger receives warnings, e.g. about clock skew. Warnings are discarded if this is nil.
+	Logger Logger
+
+	// Debug receives every request sent and its response in full, with credentials redacted.
+	// Requests are not dumped if this is nil.
+	Debug func(DebugExchange)
+
+	// MaxClockSkew is the difference between the local clock and TigerGraph's above which a
+	// warning is logged. Defaults to DefaultMaxClockSkew.
+	MaxClockSkew time.Duration
+
+	callCredentials callCredentials
+
+	drain drainState
+
+	metadataSchema metadataSchemaState
+
+	// commandSession keeps the cookies of GSQLCommand calls made without a GSQLSession
+	commandSession GSQLSession
+
+	lastLatency atomic.Pointer[LatencyStats]
+	optionErr   error
+
+	// ownedTransport is the transport configured by options such as WithTLSConfig
+	ownedTransport *http.Transport
+
+	// replicas are the read replicas configured with WithReadReplicas
+	replicas *replicaPool
+
+	atomicSupport   int32
+	clockSkew       int64
+	clockSkewKnown  int32
+	clockSkewWarned int32
 }
 
-// NewClient creates a new TigerGraphClient
-func NewClient(
-	baseURL string,
-	baseFileURL string,
-	username string,
-	password string,
-) *TigerGraphClient {
-	return &TigerGraphClient{
-		BaseURL:           baseURL,
-		BaseFileURL:       baseFileURL,
-		Tokens:            make(map[string]*Token),
-		BasicAuthUsername: username,
-		BasicAuthPassword: password,
+// NewClient creates a new TigerGraphClient for the RESTPP server at baseURL, configured by
+// the given options. Options are applied in order.
+func NewClient(baseURL string, opts ...Option) *TigerGraphClient {
+	c := &TigerGraphClient{
+		BaseURL:        baseURL,
+		BaseFileURL:    baseURL,
+		Tokens:         NewTokenCache(),
+		Timeouts:       DefaultTimeouts,
+		LogLevels:      DefaultLogLevels,
+		ConnectionPool: DefaultConnectionPool,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.applyConnectTimeout()
+	c.applyConnectionPool()
+	c.applyDisableCompression()
+
+	return c
+}
+
+func (c *TigerGraphClient) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+
+	return c.HTTPClient
 }
 
 // Get makes a GET request to the TigerGraph endpoint. This handles auth automatically.
-func (c *TigerGraphClient) Get(ctx context.Context, queryURL string, graph string, result interface{}) error {
-	request, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+queryURL, nil)
+// Errors are returned as an *OperationError, wrapping a *DeadlineExceededError if the call
+// ran out of time. With WithDiagnostics, errors of installed query calls are wrapped in a
+// *DiagnosticError.
+func (c *TigerGraphClient) Get(
+	ctx context.Context,
+	queryURL string,
+	graph string,
+	result interface{},
+	opts ...RequestOption,
+) error {
+	op := operationName(opts, "get")
+	err := c.routeRequest(ctx, true, opts, func(baseURL string) error {
+		return c.get(ctx, baseURL, queryURL, graph, result, opts...)
+	})
+
+	return c.diagnose(ctx, queryURL, newOperationError(op, graph, queryURL, c.newDeadlineError(ctx, op, opts, err)))
+}
+
+func (c *TigerGraphClient) get(
+	ctx context.Context,
+	baseURL string,
+	queryURL string,
+	graph string,
+	result interface{},
+	opts ...RequestOption,
+) error {
+	if err := c.checkQueryAllowed(queryURL); err != nil {
+		return err
+	}
+
+	queryTimeout := c.queryTimeout(ctx, opts)
+	ctx, cancel := c.withTimeout(ctx, opts)
+	defer cancel()
+
+	release, err := c.acquireGraph(ctx, graph)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	request, err := http.NewRequestWithContext(ctx, "GET", baseURL+queryURL, nil)
 	if err != nil {
 		return err
 	}
+	options := collectRequestOptions(opts)
+	setRequestHeaders(request, options)
+	setQueryTimeout(request, queryTimeout)
+	c.setResponseLimit(request, queryURL, options)
 
-	if err = c.ApplyTokenAuth(request, graph); err != nil {
+	if err = c.applyCallTokenAuth(request, graph, options); err != nil {
 		return err
 	}
 
-	return c.RequestInto(request, result)
+	return c.requestInto(request, result, options.maxResultVertices)
 }
 
 // Post makes a POST request to the TigerGraph endpoint. This handles auth automatically.
-func (c *TigerGraphClient) Post(ctx context.Context, queryURL string, graph string, body interface{}, result interface{}) error {
+func (c *TigerGraphClient) Post(
+	ctx context.Context,
+	queryURL string,
+	graph string,
+	body interface{},
+	result interface{},
+	opts ...RequestOption,
+) error {
 	requestBody, err := json.Marshal(body)
 	if err != nil {
-		return err
+		return newOperationError(operationName(opts, "post"), graph, queryURL, err)
 	}
 
-	return c.PostRaw(ctx, queryURL, graph, requestBody, result)
+	return c.PostRaw(ctx, queryURL, graph, requestBody, result, opts...)
 }
 
 // PostRaw makes a POST request to the TigerGraph endpoint with some given bytes. This handles auth automatically.
-func (c *TigerGraphClient) PostRaw(ctx context.Context, queryURL string, graph string, body []byte, result interface{}) error {
-	request, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+queryURL, bytes.NewBuffer(body))
+// Errors are returned as an *OperationError, wrapping a *DeadlineExceededError if the call
+// ran out of time. With WithDiagnostics, errors of installed query and loading job calls are
+// wrapped in a *DiagnosticError.
+func (c *TigerGraphClient) PostRaw(
+	ctx context.Context,
+	queryURL string,
+	graph string,
+	body []byte,
+	result interface{},
+	opts ...RequestOption,
+) error {
+	op := operationName(opts, "post")
+	err := c.routeRequest(ctx, false, opts, func(baseURL string) error {
+		return c.postRaw(ctx, baseURL, queryURL, graph, body, result, opts...)
+	})
+
+	return c.diagnose(ctx, queryURL, newOperationError(op, graph, queryURL, c.newDeadlineError(ctx, op, opts, err)))
+}
+
+func (c *TigerGraphClient) postRaw(
+	ctx context.Context,
+	baseURL string,
+	queryURL string,
+	graph string,
+	body []byte,
+	result interface{},
+	opts ...RequestOption,
+) error {
+	if c.MaxRequestSize > 0 && len(body) > c.MaxRequestSize {
+		return fmt.Errorf(
+			"payload is %d bytes, maximum is %d bytes: %w",
+			len(body),
+			c.MaxRequestSize,
+			ErrPayloadTooLarge,
+		)
+	}
+
+	if err := c.checkQueryAllowed(queryURL); err != nil {
+		return err
+	}
+
+	queryTimeout := c.queryTimeout(ctx, opts)
+	ctx, cancel := c.withTimeout(ctx, opts)
+	defer cancel()
+
+	// The server version is checked before taking a request slot for the graph, because
+	// checking it makes a request of its own for the graph
+	options := collectRequestOptions(opts)
+	if options.atomic {
+		if err := c.requireAtomicPosts(ctx, graph); err != nil {
+			return err
+		}
+	}
+
+	release, err := c.acquireGraph(ctx, graph)
 	if err != nil {
 		return err
 	}
+	defer release()
 
-	err = c.ApplyTokenAuth(request, graph)
+	body, compressed, err := c.compressRequestBody(body)
 	if err != nil {
 		return err
 	}
 
-	return c.RequestInto(request, result)
+	request, err := http.NewRequestWithContext(ctx, "POST", baseURL+queryURL, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	if compressed {
+		request.Header.Set("Content-Encoding", "gzip")
+	}
+
+	if options.atomic {
+		request.Header.Set(AtomicLevelHeader, AtomicLevelAtomic)
+	}
+	setRequestHeaders(request, options)
+	setQueryTimeout(request, queryTimeout)
+	c.setResponseLimit(request, queryURL, options)
+
+	err = c.applyCallTokenAuth(request, graph, options)
+	if err != nil {
+		return err
+	}
+
+	return c.requestInto(request, result, options.maxResultVertices)
 }
 
 // RequestInto takes an HTTP request, performs it and unmarshals the response into the supplied
-// result argument.
+// result argument. A RESPONSE-LIMIT header on the request is also enforced on the response.
+// Responses which cannot be unmarshalled return a *DecodeError.
 func (c *TigerGraphClient) RequestInto(req *http.Request, result interface{}) error {
-	resp, err := http.DefaultClient.Do(req)
+	return c.requestInto(req, result, 0)
+}
+
+// requestInto is RequestInto, failing with ErrResultTooLarge once the response holds more than
+// maxVertices vertices. Zero means no limit.
+func (c *TigerGraphClient) requestInto(req *http.Request, result interface{}, maxVertices int) error {
+	c.requestCompressedResponse(req)
+
+	resp, err := c.doWithRetries(req)
 
 	if err != nil {
 		return err
@@ -140,27 +518,97 @@ func (c *TigerGraphClient) RequestInto(req *http.Request, result interface{}) er
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return ErrNonOK
+		return statusError(resp.StatusCode)
 	}
 
-	jsonBytes, err := io.ReadAll(resp.Body)
+	jsonBytes, err := readResponseBodyLimited(resp, responseLimit(req), maxVertices)
 
 	if err != nil {
 		return err
 	}
 
-	err = json.Unmarshal(jsonBytes, result)
+	err = c.unmarshal(jsonBytes, result)
 
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal response. response: %s, %w", string(jsonBytes), err)
+		return c.newDecodeError(jsonBytes, err)
 	}
 
 	return nil
 }
 
+// do performs an HTTP request, handling busy responses and rejected credentials.
+func (c *TigerGraphClient) do(req *http.Request) (*http.Response, error) {
+	if c.optionErr != nil {
+		return nil, c.optionErr
+	}
+
+	end, err := c.beginRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doInFlight(req)
+	if resp == nil {
+		end()
+		return resp, err
+	}
+	resp.Body = &inFlightBody{ReadCloser: resp.Body, end: end}
+
+	return resp, err
+}
+
+// doInFlight performs an HTTP request recorded as in flight by do.
+func (c *TigerGraphClient) doInFlight(req *http.Request) (*http.Response, error) {
+	c.setDefaultHeaders(req)
+	c.setUserAgent(req)
+
+	breaker := c.CircuitBreaker
+	if isPingRequest(req) {
+		breaker = nil
+	}
+
+	if breaker != nil {
+		if err := breaker.allow(); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+
+	resp, attempts, err := c.doWithBusyRetries(req)
+	if err == nil && c.isCredentialsRejected(req, resp) {
+		var retryAttempts int
+		resp, retryAttempts, err = c.retryWithRefreshedCredentials(req, resp)
+		attempts += retryAttempts
+	}
+
+	if resp != nil {
+		c.recordClockSkew(resp, start, time.Now())
+	}
+
+	if breaker != nil {
+		breaker.record(resp, err)
+	}
+
+	return resp, c.recordRequest(req, resp, attempts, time.Since(start), err)
+}
+
 // CreateGSQLServerRequest returns a Request instance that is authenticated and ready to
 // pass to RequestInto. This is useful if headers need to be changed by the caller (such as setting the Content-Type).
+// The request carries the cookies of the client's GSQLSession, if it has one.
 func (c *TigerGraphClient) CreateGSQLServerRequest(ctx context.Context, method string, url string, body string) (*http.Request, error) {
+	return c.createGSQLServerRequest(ctx, method, url, body, c.GSQLSession)
+}
+
+// createGSQLServerRequest is CreateGSQLServerRequest, carrying the cookies of the given
+// session if it is not nil
+func (c *TigerGraphClient) createGSQLServerRequest(
+	ctx context.Context,
+	method string,
+	url string,
+	body string,
+	session *GSQLSession,
+) (*http.Request, error) {
 	request, err := http.NewRequestWithContext(
 		ctx,
 		method,
@@ -171,7 +619,13 @@ func (c *TigerGraphClient) CreateGSQLServerRequest(ctx context.Context, method s
 		return nil, err
 	}
 
-	c.ApplyBasicAuth(request)
+	if err = c.applyGSQLAuth(request); err != nil {
+		return nil, err
+	}
+
+	if session != nil {
+		session.AddCookies(request)
+	}
 
 	return request, nil
 }
@@ -181,19 +635,26 @@ func (c *TigerGraphClient) CreateGSQLServerRequest(ctx context.Context, method s
 //
 // https://docs.tigergraph.com/tigergraph-server/current/api/built-in-endpoints#_request_a_token
 func (c *TigerGraphClient) ApplyTokenAuth(req *http.Request, graph string) error {
-	err := c.Auth(req.Context(), graph)
+	token, err := c.token(req.Context(), graph)
 	if err != nil {
 		return err
 	}
 
-	authToken := fmt.Sprintf("Bearer %s", c.Tokens[graph].Value)
+	authToken := fmt.Sprintf("Bearer %s", token.Value)
 	req.Header.Add("Authorization", authToken)
 	return nil
 }
 
-// ApplyBasicAuth takes a request and authenticates it generally as a TigerGraph user for GSQL server requests
+// ApplyBasicAuth takes a request and authenticates it generally as a TigerGraph user for GSQL server requests.
+// The credentials come from the client's CredentialsProvider if one is set.
 //
 // https://docs.tigergraph.com/tigergraph-server/current/api/authentication#_gsql_server_requests
-func (c *TigerGraphClient) ApplyBasicAuth(req *http.Request) {
-	req.SetBasicAuth(c.BasicAuthUsername, c.BasicAuthPassword)
+func (c *TigerGraphClient) ApplyBasicAuth(req *http.Request) error {
+	credentials, err := c.credentials(req.Context())
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(credentials.Username, credentials.Password)
+	return nil
 }
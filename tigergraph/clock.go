@@ -0,0 +1,33 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import "time"
+
+// WithClock replaces time.Now as the client's source of the current time, which decides when
+// cached tokens have expired and timestamps migrations and soft deletes. This lets tests
+// exercise token expiry and refresh without sleeping.
+func WithClock(now func() time.Time) Option {
+	return func(c *TigerGraphClient) {
+		c.Now = now
+	}
+}
+
+func (c *TigerGraphClient) now() time.Time {
+	if c.Now == nil {
+		return time.Now()
+	}
+
+	return c.Now()
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxClockSkew is the clock skew above which a warning is logged when MaxClockSkew is not set
+const DefaultMaxClockSkew = 30 * time.Second
+
+// ClockSkew returns the latest estimate of how far TigerGraph's clock is ahead of the local
+// clock, negative if it is behind, based on the Date header of its responses. The estimate is
+// accurate to about a second. The second return value is false until a response with a valid
+// Date header has been received.
+func (c *TigerGraphClient) ClockSkew() (time.Duration, bool) {
+	if atomic.LoadInt32(&c.clockSkewKnown) == 0 {
+		return 0, false
+	}
+
+	return time.Duration(atomic.LoadInt64(&c.clockSkew)), true
+}
+
+func (c *TigerGraphClient) maxClockSkew() time.Duration {
+	if c.MaxClockSkew == 0 {
+		return DefaultMaxClockSkew
+	}
+
+	return c.MaxClockSkew
+}
+
+// recordClockSkew estimates clock skew from the response's Date header, comparing it with the
+// midpoint of the request. A warning is logged when the skew first exceeds MaxClockSkew.
+func (c *TigerGraphClient) recordClockSkew(resp *http.Response, sent time.Time, received time.Time) {
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	localTime := sent.Add(received.Sub(sent) / 2)
+	skew := serverTime.Sub(localTime.Truncate(time.Second))
+
+	atomic.StoreInt64(&c.clockSkew, int64(skew))
+	atomic.StoreInt32(&c.clockSkewKnown, 1)
+
+	if skew.Abs() <= c.maxClockSkew() {
+		atomic.StoreInt32(&c.clockSkewWarned, 0)
+		return
+	}
+
+	if atomic.CompareAndSwapInt32(&c.clockSkewWarned, 0, 1) {
+		c.logger().Warn(
+			"TigerGraph clock skew exceeds threshold, token expiry and migration ordering may be affected",
+			"skew", skew,
+			"threshold", c.maxClockSkew(),
+		)
+	}
+}
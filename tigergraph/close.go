@@ -0,0 +1,47 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+)
+
+// Close revokes every unexpired token in the client's cache on the server, so that tokens
+// do not linger until they expire, and closes idle connections. Each token is removed from
+// the cache whether or not revoking it succeeded, and every token is attempted; the errors
+// of any that failed are joined. The client can still be used after Close, fetching new
+// tokens as needed. Close may be called after Drain.
+//
+// Tokens shared through a TokenStore are only removed from the client's cache, as other
+// clients may still be using them.
+func (c *TigerGraphClient) Close(ctx context.Context) error {
+	ctx = withDrainRequests(ctx)
+	var errs []error
+
+	for _, graph := range c.Tokens.Graphs() {
+		if c.TokenStore != nil {
+			c.Tokens.Delete(graph)
+			continue
+		}
+
+		if err := c.RevokeToken(ctx, graph); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	c.httpClient().CloseIdleConnections()
+
+	return errors.Join(errs...)
+}
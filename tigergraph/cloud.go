@@ -0,0 +1,63 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	// CloudTokenURL is the GSQL server URL from which TigerGraph Cloud issues tokens
+	CloudTokenURL = "/gsql/v1/tokens"
+
+	// CloudRESTPPPath is the path under which TigerGraph Cloud serves RESTPP
+	CloudRESTPPPath = "/restpp"
+)
+
+// CloudTokenResponse represents the response body from TigerGraph Cloud when requesting a token
+type CloudTokenResponse struct {
+	Error      bool   `json:"error"`
+	Message    string `json:"message"`
+	Token      string `json:"token"`
+	Expiration int64  `json:"expiration"`
+}
+
+// NewCloudClient creates a new TigerGraphClient for the TigerGraph Cloud instance at host, e.g.
+// https://my-instance.i.tgcloud.io, which serves both RESTPP, under CloudRESTPPPath, and the
+// GSQL server on the same port. Tokens are requested with a secret set by WithSecret, or with
+// basic auth credentials, which are also used to run GSQL if given. Without credentials, GSQL
+// is run with a token not bound to any graph, so a secret must be set for GlobalGraph.
+func NewCloudClient(host string, opts ...Option) *TigerGraphClient {
+	host = strings.TrimSuffix(host, "/")
+
+	cloudOpts := []Option{
+		WithFileURL(host),
+		func(c *TigerGraphClient) {
+			c.Cloud = true
+		},
+	}
+
+	return NewClient(host+CloudRESTPPPath, append(cloudOpts, opts...)...)
+}
+
+// applyGSQLAuth authenticates a GSQL server request with basic auth or, for TigerGraph Cloud
+// clients without basic auth credentials, a token not bound to any graph.
+func (c *TigerGraphClient) applyGSQLAuth(req *http.Request) error {
+	if c.Cloud && c.CredentialsProvider == nil && c.BasicAuthUsername == "" {
+		return c.ApplyGlobalTokenAuth(req)
+	}
+
+	return c.ApplyBasicAuth(req)
+}
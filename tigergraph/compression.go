@@ -0,0 +1,103 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// WithRequestCompression gzips POST bodies of at least the given number of bytes, e.g. large
+// upserts and loading job payloads. Zero disables request compression.
+func WithRequestCompression(threshold int) Option {
+	return func(c *TigerGraphClient) {
+		c.CompressRequestsAbove = threshold
+	}
+}
+
+// compressRequestBody gzips body if it reaches the client's compression threshold, returning
+// the body to send and whether it was compressed.
+func (c *TigerGraphClient) compressRequestBody(body []byte) ([]byte, bool, error) {
+	if c.CompressRequestsAbove <= 0 || len(body) < c.CompressRequestsAbove {
+		return body, false, nil
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(body); err != nil {
+		return nil, false, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, false, err
+	}
+
+	return compressed.Bytes(), true, nil
+}
+
+// WithoutResponseCompression stops the client asking for gzip compressed responses, e.g. when
+// a proxy in between mishandles them.
+func WithoutResponseCompression() Option {
+	return func(c *TigerGraphClient) {
+		c.DisableCompression = true
+	}
+}
+
+// applyDisableCompression stops the client's own transport asking for gzip compressed
+// responses, which it otherwise does for every request without an Accept-Encoding header.
+// Clients whose transport is not an *http.Transport are left as they are.
+func (c *TigerGraphClient) applyDisableCompression() {
+	if !c.DisableCompression {
+		return
+	}
+
+	transport, err := c.ownTransport()
+	if err != nil {
+		return
+	}
+
+	transport.DisableCompression = true
+}
+
+// requestCompressedResponse asks the server for a gzip encoded response, unless compression
+// is disabled on the client. Setting the header explicitly means the HTTP transport leaves
+// decompression to readResponseBodyLimited.
+func (c *TigerGraphClient) requestCompressedResponse(req *http.Request) {
+	if c.DisableCompression || req.Header.Get("Accept-Encoding") != "" {
+		return
+	}
+
+	req.Header.Set("Accept-Encoding", "gzip")
+}
+
+// readResponseBodyLimited reads the whole response body, decompressing it if the server chose
+// to gzip it. The HTTP transport only decompresses responses itself when it asked for
+// compression, so this is needed whenever Accept-Encoding was set on the request, e.g. by
+// requestCompressedResponse or a default header. It fails with ErrResponseLimitExceeded once the decompressed body is larger than
+// limit bytes, and with ErrResultTooLarge once it holds more than maxVertices vertices. Zeros
+// mean no limit.
+func readResponseBodyLimited(resp *http.Response, limit int64, maxVertices int) ([]byte, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return readLimited(limitResultVertices(resp.Body, maxVertices), limit)
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return readLimited(limitResultVertices(reader, maxVertices), limit)
+}
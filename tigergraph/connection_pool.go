@@ -0,0 +1,82 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import "time"
+
+// ConnectionPool tunes how the client's transport pools connections to TigerGraph. Zero fields
+// leave the transport's own setting in place.
+type ConnectionPool struct {
+	// MaxIdleConns limits idle connections across all hosts.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost limits idle connections to each host. Go's default of 2 causes
+	// connections to be opened and closed constantly under concurrent upserts.
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost limits connections to each host, including those in use.
+	MaxConnsPerHost int
+
+	// IdleConnTimeout is how long an idle connection is kept open.
+	IdleConnTimeout time.Duration
+
+	// TLSHandshakeTimeout bounds the TLS handshake of a new connection.
+	TLSHandshakeTimeout time.Duration
+}
+
+// DefaultConnectionPool is the ConnectionPool of a client created with NewClient.
+var DefaultConnectionPool = ConnectionPool{
+	MaxIdleConns:        100,
+	MaxIdleConnsPerHost: 32,
+	IdleConnTimeout:     90 * time.Second,
+	TLSHandshakeTimeout: 10 * time.Second,
+}
+
+// WithConnectionPool replaces the client's ConnectionPool. It must come after WithHTTPClient,
+// which keeps the given client's own pool settings.
+func WithConnectionPool(pool ConnectionPool) Option {
+	return func(c *TigerGraphClient) {
+		c.ConnectionPool = pool
+	}
+}
+
+// applyConnectionPool configures the client's own transport with its ConnectionPool. Clients
+// whose transport is not an *http.Transport are left as they are.
+func (c *TigerGraphClient) applyConnectionPool() {
+	if c.ConnectionPool == (ConnectionPool{}) {
+		return
+	}
+
+	transport, err := c.ownTransport()
+	if err != nil {
+		return
+	}
+
+	pool := c.ConnectionPool
+	if pool.MaxIdleConns > 0 {
+		transport.MaxIdleConns = pool.MaxIdleConns
+	}
+	if pool.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = pool.MaxIdleConnsPerHost
+	}
+	if pool.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = pool.MaxConnsPerHost
+	}
+	if pool.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = pool.IdleConnTimeout
+	}
+	if pool.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = pool.TLSHandshakeTimeout
+	}
+}
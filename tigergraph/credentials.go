@@ -0,0 +1,93 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Credentials are the username and password used for basic auth with TigerGraph
+type Credentials struct {
+	Username string
+	Password string
+
+	// Secrets are GSQL secrets by graph name, where the credential source provides them. They
+	// are used to request tokens in place of basic auth for graphs without a secret set with
+	// WithSecret. A secret rejected by TigerGraph is refreshed and the request retried once.
+	Secrets map[string]string
+}
+
+// CredentialsProvider supplies the credentials used for basic auth, allowing passwords to be
+// rotated without recreating the client. When TigerGraph rejects a basic auth request with
+// 401 or 403, Refresh is called and the request is retried once with the new credentials.
+//
+// Implementations must be safe to call from multiple goroutines.
+type CredentialsProvider interface {
+	// Credentials returns the current credentials, which may be cached.
+	Credentials(ctx context.Context) (Credentials, error)
+
+	// Refresh fetches new credentials after the current ones were rejected.
+	Refresh(ctx context.Context) (Credentials, error)
+}
+
+func (c *TigerGraphClient) credentials(ctx context.Context) (Credentials, error) {
+	if c.CredentialsProvider == nil {
+		return Credentials{Username: c.BasicAuthUsername, Password: c.BasicAuthPassword}, nil
+	}
+
+	return c.CredentialsProvider.Credentials(ctx)
+}
+
+// isCredentialsRejected reports whether the response rejects the provider's basic auth
+// credentials on the request. Credentials given to the call with WithCallCredentials, for
+// another user, are not refreshed.
+func (c *TigerGraphClient) isCredentialsRejected(req *http.Request, resp *http.Response) bool {
+	if c.CredentialsProvider == nil {
+		return false
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+
+	username, _, ok := req.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	credentials, err := c.CredentialsProvider.Credentials(req.Context())
+	return err == nil && credentials.Username == username
+}
+
+// retryWithRefreshedCredentials refreshes the credentials from the provider and sends the
+// request again with them.
+func (c *TigerGraphClient) retryWithRefreshedCredentials(req *http.Request, rejected *http.Response) (*http.Response, int, error) {
+	_, _ = io.Copy(io.Discard, rejected.Body)
+	rejected.Body.Close()
+
+	credentials, err := c.CredentialsProvider.Refresh(req.Context())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	retry, err := rewindRequest(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	retry.SetBasicAuth(credentials.Username, credentials.Password)
+
+	return c.doWithBusyRetries(retry)
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrCredentialsMissing represents a credential source that contains neither a username and
+// password nor any secrets
+var ErrCredentialsMissing = errors.New("credential source does not contain a username and password or any secrets")
+
+// CredentialKeys names the keys holding each credential in a secret. Empty keys fall back to
+// "username", "password" and "secrets". The secrets key holds an object of GSQL secrets by
+// graph name.
+type CredentialKeys struct {
+	Username string
+	Password string
+	Secrets  string
+}
+
+func (k CredentialKeys) withDefaults() CredentialKeys {
+	if k.Username == "" {
+		k.Username = "username"
+	}
+	if k.Password == "" {
+		k.Password = "password"
+	}
+	if k.Secrets == "" {
+		k.Secrets = "secrets"
+	}
+
+	return k
+}
+
+func (k CredentialKeys) extract(data map[string]any) (Credentials, error) {
+	k = k.withDefaults()
+
+	credentials := Credentials{}
+	for key, target := range map[string]*string{
+		k.Username: &credentials.Username,
+		k.Password: &credentials.Password,
+	} {
+		if value, ok := data[key].(string); ok {
+			*target = value
+		}
+	}
+
+	if secrets, ok := data[k.Secrets].(map[string]any); ok {
+		credentials.Secrets = make(map[string]string, len(secrets))
+		for graph, value := range secrets {
+			if secret, isString := value.(string); isString && secret != "" {
+				credentials.Secrets[graph] = secret
+			}
+		}
+	}
+
+	// A source may hold only secrets, for a client which only needs tokens
+	hasBasicAuth := credentials.Username != "" && credentials.Password != ""
+	if !hasBasicAuth && len(credentials.Secrets) == 0 {
+		return Credentials{}, ErrCredentialsMissing
+	}
+
+	return credentials, nil
+}
+
+// CachedCredentialsProvider is a CredentialsProvider which fetches credentials once and caches
+// them until TigerGraph rejects them.
+type CachedCredentialsProvider struct {
+	fetch func(ctx context.Context) (Credentials, error)
+
+	mu     sync.Mutex
+	cached *Credentials
+}
+
+// NewCachedCredentialsProvider creates a CredentialsProvider which caches the credentials returned by fetch.
+func NewCachedCredentialsProvider(fetch func(ctx context.Context) (Credentials, error)) *CachedCredentialsProvider {
+	return &CachedCredentialsProvider{fetch: fetch}
+}
+
+// Credentials implements CredentialsProvider
+func (p *CachedCredentialsProvider) Credentials(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cached != nil {
+		return *p.cached, nil
+	}
+
+	return p.refreshLocked(ctx)
+}
+
+// Refresh implements CredentialsProvider
+func (p *CachedCredentialsProvider) Refresh(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.refreshLocked(ctx)
+}
+
+func (p *CachedCredentialsProvider) refreshLocked(ctx context.Context) (Credentials, error) {
+	credentials, err := p.fetch(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	p.cached = &credentials
+	return credentials, nil
+}
+
+// VaultSecretReader reads the data of a secret from HashiCorp Vault. It can be implemented with
+// a few lines wrapping the official Vault client, e.g. client.KVv2(mount).Get(ctx, path).
+type VaultSecretReader interface {
+	ReadSecret(ctx context.Context, path string) (map[string]any, error)
+}
+
+// NewVaultCredentialsProvider creates a CredentialsProvider which reads credentials from the
+// Vault secret at path.
+func NewVaultCredentialsProvider(reader VaultSecretReader, path string, keys CredentialKeys) *CachedCredentialsProvider {
+	return NewCachedCredentialsProvider(func(ctx context.Context) (Credentials, error) {
+		data, err := reader.ReadSecret(ctx, path)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to read vault secret %s: %w", path, err)
+		}
+
+		return keys.extract(data)
+	})
+}
+
+// SecretsManagerClient fetches the string value of a secret from AWS Secrets Manager. It can be
+// implemented with a few lines wrapping the AWS SDK's GetSecretValue.
+type SecretsManagerClient interface {
+	GetSecretString(ctx context.Context, secretID string) (string, error)
+}
+
+// NewSecretsManagerCredentialsProvider creates a CredentialsProvider which reads credentials from
+// the AWS Secrets Manager secret with the given ID. The secret must be a JSON object.
+func NewSecretsManagerCredentialsProvider(
+	client SecretsManagerClient,
+	secretID string,
+	keys CredentialKeys,
+) *CachedCredentialsProvider {
+	return NewCachedCredentialsProvider(func(ctx context.Context) (Credentials, error) {
+		secret, err := client.GetSecretString(ctx, secretID)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to get secret %s: %w", secretID, err)
+		}
+
+		var data map[string]any
+		if err = json.Unmarshal([]byte(secret), &data); err != nil {
+			return Credentials{}, fmt.Errorf("secret %s is not a JSON object: %w", secretID, err)
+		}
+
+		return keys.extract(data)
+	})
+}
+
+// FileCredentialsProvider reads credentials from a JSON file, such as a mounted Kubernetes
+// secret. The file is read again whenever it changes, so rotated credentials are picked up
+// without restarting.
+type FileCredentialsProvider struct {
+	path string
+	keys CredentialKeys
+
+	mu      sync.Mutex
+	cached  *Credentials
+	modTime time.Time
+	size    int64
+}
+
+// NewFileCredentialsProvider creates a FileCredentialsProvider for the file at path.
+func NewFileCredentialsProvider(path string, keys CredentialKeys) *FileCredentialsProvider {
+	return &FileCredentialsProvider{path: path, keys: keys}
+}
+
+// Credentials implements CredentialsProvider
+func (p *FileCredentialsProvider) Credentials(_ context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	if p.cached != nil && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return *p.cached, nil
+	}
+
+	return p.readLocked(info)
+}
+
+// Refresh implements CredentialsProvider
+func (p *FileCredentialsProvider) Refresh(_ context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	return p.readLocked(info)
+}
+
+func (p *FileCredentialsProvider) readLocked(info os.FileInfo) (Credentials, error) {
+	contents, err := os.ReadFile(p.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	var data map[string]any
+	if err = json.Unmarshal(contents, &data); err != nil {
+		return Credentials{}, fmt.Errorf("credentials file %s is not a JSON object: %w", p.path, err)
+	}
+
+	credentials, err := p.keys.extract(data)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	p.cached = &credentials
+	p.modTime = info.ModTime()
+	p.size = info.Size()
+
+	return credentials, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeVault struct {
+	data  map[string]any
+	reads int
+}
+
+func (v *fakeVault) ReadSecret(_ context.Context, _ string) (map[string]any, error) {
+	v.reads++
+	return v.data, nil
+}
+
+type fakeSecretsManager struct {
+	secret string
+}
+
+func (s *fakeSecretsManager) GetSecretString(_ context.Context, _ string) (string, error) {
+	return s.secret, nil
+}
+
+func TestVaultCredentialsProvider(t *testing.T) {
+	ctx := context.Background()
+	vault := &fakeVault{data: map[string]any{"user": "tigergraph", "pass": "first"}}
+	provider := NewVaultCredentialsProvider(vault, "secret/tigergraph", CredentialKeys{Username: "user", Password: "pass"})
+
+	credentials, err := provider.Credentials(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{Username: "tigergraph", Password: "first"}, credentials)
+
+	// Cached until refreshed
+	vault.data["pass"] = "second"
+	credentials, err = provider.Credentials(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "first", credentials.Password)
+	assert.Equal(t, 1, vault.reads)
+
+	credentials, err = provider.Refresh(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "second", credentials.Password)
+
+	vault.data = map[string]any{"user": "tigergraph"}
+	_, err = provider.Refresh(ctx)
+	assert.ErrorIs(t, err, ErrCredentialsMissing)
+
+	// Secrets alone are enough to request tokens
+	vault.data = map[string]any{"secrets": map[string]any{"Social": "s"}}
+	credentials, err = provider.Refresh(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{Secrets: map[string]string{"Social": "s"}}, credentials)
+}
+
+func TestSecretsManagerCredentialsProvider(t *testing.T) {
+	client := &fakeSecretsManager{secret: `{"username": "tigergraph", "password": "pw", "secrets": {"Social": "s"}}`}
+	provider := NewSecretsManagerCredentialsProvider(client, "tigergraph", CredentialKeys{})
+
+	credentials, err := provider.Credentials(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{Username: "tigergraph", Password: "pw", Secrets: map[string]string{"Social": "s"}}, credentials)
+
+	client.secret = "not json"
+	_, err = provider.Refresh(context.Background())
+	assert.NotNil(t, err)
+}
+
+func TestFileCredentialsProvider(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"username": "tigergraph", "password": "first"}`), 0o600))
+
+	provider := NewFileCredentialsProvider(path, CredentialKeys{})
+	credentials, err := provider.Credentials(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "first", credentials.Password)
+
+	// Rotating the file is picked up without an explicit refresh
+	assert.Nil(t, os.WriteFile(path, []byte(`{"username": "tigergraph", "password": "rotated"}`), 0o600))
+	later := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(path, later, later))
+
+	credentials, err = provider.Credentials(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "rotated", credentials.Password)
+
+	assert.Nil(t, os.Remove(path))
+	_, err = provider.Credentials(ctx)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DatetimeEncoding is how DATETIME values are sent to TigerGraph. Depending on its
+// configuration, TigerGraph expects either formatted strings or seconds since the Unix epoch.
+type DatetimeEncoding int
+
+const (
+	// DatetimeString sends DATETIME values as strings in TigerGraphDateTimeFormat, in UTC.
+	DatetimeString DatetimeEncoding = iota
+
+	// DatetimeEpoch sends DATETIME values as whole seconds since the Unix epoch.
+	DatetimeEpoch
+)
+
+// String implements fmt.Stringer
+func (e DatetimeEncoding) String() string {
+	switch e {
+	case DatetimeString:
+		return "string"
+	case DatetimeEpoch:
+		return "epoch"
+	default:
+		return fmt.Sprintf("DatetimeEncoding(%d)", int(e))
+	}
+}
+
+// Encode converts t to the value sent for a DATETIME: a string for DatetimeString, or an
+// int64 for DatetimeEpoch.
+func (e DatetimeEncoding) Encode(t time.Time) any {
+	if e == DatetimeEpoch {
+		return t.Unix()
+	}
+
+	return t.UTC().Format(TigerGraphDateTimeFormat)
+}
+
+// Format converts t to the text sent for a DATETIME, e.g. in a query string.
+func (e DatetimeEncoding) Format(t time.Time) string {
+	if e == DatetimeEpoch {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+
+	return t.UTC().Format(TigerGraphDateTimeFormat)
+}
+
+// WithDatetimeEncoding sets how the client sends DATETIME values. The default is DatetimeString.
+func WithDatetimeEncoding(encoding DatetimeEncoding) Option {
+	return func(c *TigerGraphClient) {
+		c.DatetimeEncoding = encoding
+	}
+}
+
+// NewUpsertPayload creates an empty UpsertPayload which sends times using the client's
+// DatetimeEncoding.
+func (c *TigerGraphClient) NewUpsertPayload() *UpsertPayload {
+	payload := NewUpsertPayload()
+	payload.datetimeEncoding = c.DatetimeEncoding
+
+	return payload
+}
+
+// NewQueryParams creates an empty QueryParams which formats times using the client's
+// DatetimeEncoding.
+func (c *TigerGraphClient) NewQueryParams(opts ...ParamOption) *QueryParams {
+	return NewQueryParams(append([]ParamOption{ParamDatetimeEncoding(c.DatetimeEncoding)}, opts...)...)
+}
+
+// WrapAttributes is WrapAttributes using the client's DatetimeEncoding.
+func (c *TigerGraphClient) WrapAttributes(attributes map[string]any) map[string]ValueWrapper {
+	return wrapAttributes(attributes, c.DatetimeEncoding)
+}
+
+// DetectDatetimeEncoding probes the schema of graphName for the encoding its server uses, and
+// sets the client's DatetimeEncoding to match. The server's encoding is taken from the default
+// values of DATETIME attributes. If the graph has none, the client's DatetimeEncoding is
+// returned unchanged.
+//
+// It should be called before the client is shared between goroutines.
+func (c *TigerGraphClient) DetectDatetimeEncoding(ctx context.Context, graphName string) (DatetimeEncoding, error) {
+	metadata, err := c.GetGraphMetadata(ctx, graphName)
+	if err != nil {
+		return c.DatetimeEncoding, newOperationError("detect datetime encoding", graphName, GetGraphMetadataQueryURL, err)
+	}
+
+	if metadata.Error || metadata.Results == nil {
+		return c.DatetimeEncoding, newOperationError(
+			"detect datetime encoding",
+			graphName,
+			GetGraphMetadataQueryURL,
+			fmt.Errorf("TigerGraph returned an error when getting graph metadata. Message: %s: %w", metadata.Message, ErrTigerGraphError),
+		)
+	}
+
+	attributes := make([]GraphMetadataAttribute, 0)
+	for _, vertexType := range metadata.Results.VertexTypes {
+		attributes = append(attributes, vertexType.Attributes...)
+	}
+	for _, edgeType := range metadata.Results.EdgeTypes {
+		attributes = append(attributes, edgeType.Attributes...)
+	}
+
+	for _, attribute := range attributes {
+		if attribute.AttributeType.Name != "DATETIME" || attribute.DefaultValue == nil {
+			continue
+		}
+
+		if encoding, ok := datetimeEncodingOf(*attribute.DefaultValue); ok {
+			c.DatetimeEncoding = encoding
+			return encoding, nil
+		}
+	}
+
+	return c.DatetimeEncoding, nil
+}
+
+// datetimeEncodingOf reports which encoding a DATETIME value given by TigerGraph is in.
+func datetimeEncodingOf(value string) (DatetimeEncoding, bool) {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return DatetimeEpoch, true
+	}
+
+	if _, err := time.Parse(TigerGraphDateTimeFormat, value); err == nil {
+		return DatetimeString, true
+	}
+
+	return DatetimeString, false
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatetimeEncoding(t *testing.T) {
+	at := time.Date(2023, 6, 1, 9, 0, 0, 0, time.FixedZone("BST", 3600))
+
+	assert.Equal(t, "2023-06-01 08:00:00", DatetimeString.Encode(at))
+	assert.Equal(t, int64(1685606400), DatetimeEpoch.Encode(at))
+	assert.Equal(t, "2023-06-01 08:00:00", DatetimeString.Format(at))
+	assert.Equal(t, "1685606400", DatetimeEpoch.Format(at))
+	assert.Equal(t, DefaultDateTime, DatetimeString.Encode(time.Unix(0, 0)))
+	assert.Equal(t, "epoch", DatetimeEpoch.String())
+}
+
+func TestClientDatetimeEncoding(t *testing.T) {
+	at := time.Date(2023, 6, 1, 8, 0, 0, 0, time.UTC)
+	client := NewClient("http://tg:9000", WithDatetimeEncoding(DatetimeEpoch))
+
+	body, err := json.Marshal(client.NewUpsertPayload().
+		AddVertex("Person", "1", map[string]any{"born": at, "dates": []any{at}}).
+		AddEdge("Person", "1", "Called", "Person", "2", map[string]any{"at": &at}))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"vertices": {"Person": {"1": {"born": {"value": 1685606400}, "dates": {"value": [1685606400]}}}},
+		"edges": {"Person": {"1": {"Called": {"Person": {"2": {"at": {"value": 1685606400}}}}}}}
+	}`, string(body))
+
+	encoded, err := client.NewQueryParams(MaxParamLength(20)).Set("since", at).Encode()
+	assert.Nil(t, err)
+	assert.Equal(t, "since=1685606400", encoded)
+
+	assert.Equal(t, ValueWrapper{int64(1685606400)}, client.WrapAttributes(map[string]any{"at": at})["at"])
+	assert.Equal(t, "at==1685606400", edgeFilter(map[string]any{"at": at}, client.DatetimeEncoding))
+
+	// The package level builders are unaffected by the client
+	assert.Equal(t, ValueWrapper{"2023-06-01 08:00:00"}, WrapAttributes(map[string]any{"at": at})["at"])
+}
+
+func TestDatetimeEncodingOf(t *testing.T) {
+	tests := []struct {
+		value    string
+		encoding DatetimeEncoding
+		ok       bool
+	}{
+		{"0", DatetimeEpoch, true},
+		{"1685606400", DatetimeEpoch, true},
+		{"1970-01-01 00:00:00", DatetimeString, true},
+		{"", DatetimeString, false},
+		{"yesterday", DatetimeString, false},
+	}
+
+	for _, test := range tests {
+		encoding, ok := datetimeEncodingOf(test.value)
+		assert.Equal(t, test.encoding, encoding, test.value)
+		assert.Equal(t, test.ok, ok, test.value)
+	}
+}
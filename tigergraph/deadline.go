@@ -0,0 +1,82 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDeadlineExceeded is matched by a *DeadlineExceededError, returned when a call to TigerGraph
+// runs out of time on the client, as opposed to failing on the server.
+var ErrDeadlineExceeded = errors.New("deadline exceeded")
+
+// DeadlineExceededError records the operation which ran out of time and the timeout it was
+// given. It matches ErrDeadlineExceeded, and unwraps to the underlying error, which matches
+// context.DeadlineExceeded.
+type DeadlineExceededError struct {
+	Op string
+
+	// Timeout is the request timeout the call was given, by the client's Timeouts or
+	// WithRequestTimeout. Zero means the call had no timeout of its own.
+	Timeout time.Duration
+
+	// CallerDeadline is true when the deadline of the caller's context expired, rather than
+	// Timeout.
+	CallerDeadline bool
+
+	Err error
+}
+
+// Error implements error. The operation is left out, as the error is returned wrapped in an
+// *OperationError which names it.
+func (e *DeadlineExceededError) Error() string {
+	if e.CallerDeadline || e.Timeout <= 0 {
+		return fmt.Sprintf("caller's deadline exceeded: %s", e.Err)
+	}
+
+	return fmt.Sprintf("timeout of %s exceeded: %s", e.Timeout, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *DeadlineExceededError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrDeadlineExceeded
+func (e *DeadlineExceededError) Is(target error) bool {
+	return target == ErrDeadlineExceeded
+}
+
+// newDeadlineError wraps err in a *DeadlineExceededError if it is the result of a deadline
+// expiring. ctx must be the caller's context, before the request timeout is applied.
+func (c *TigerGraphClient) newDeadlineError(ctx context.Context, op string, opts []RequestOption, err error) error {
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrDeadlineExceeded) {
+		return err
+	}
+
+	timeout := c.Timeouts.Request
+	if options := collectRequestOptions(opts); options.timeout != nil {
+		timeout = *options.timeout
+	}
+
+	return &DeadlineExceededError{
+		Op:             op,
+		Timeout:        timeout,
+		CallerDeadline: errors.Is(ctx.Err(), context.DeadlineExceeded),
+		Err:            err,
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxDebugBodySize is the number of bytes of each request and response body included in a
+// DebugExchange. Longer bodies are truncated, and the rest of a response is still streamed to
+// the caller.
+const MaxDebugBodySize = 1 << 20
+
+// redacted replaces credentials in a DebugExchange
+const redacted = "[REDACTED]"
+
+// DebugExchange is a single request sent to TigerGraph and its response, in full, with
+// credentials redacted. Each attempt of a retried request is a separate exchange.
+type DebugExchange struct {
+	Method         string
+	URL            string
+	RequestHeader  http.Header
+	RequestBody    string
+	StatusCode     int
+	ResponseHeader http.Header
+	ResponseBody   string
+
+	// Truncated is true if either body was longer than MaxDebugBodySize
+	Truncated bool
+
+	// Duration is the time taken until the response headers were received
+	Duration time.Duration
+
+	// Err is the reason the request failed, or nil if a response was received
+	Err error
+}
+
+// WithDebug logs every request and response in full, with credentials redacted, at
+// LogLevelInfo. This is meant for diagnosing failures, e.g. of a migration, and is too verbose
+// and slow to leave on.
+func WithDebug() Option {
+	return func(c *TigerGraphClient) {
+		c.Debug = c.logDebugExchange
+	}
+}
+
+// WithDebugFunc sends every request and response in full, with credentials redacted, to fn.
+// fn must be safe to call from multiple goroutines.
+func WithDebugFunc(fn func(DebugExchange)) Option {
+	return func(c *TigerGraphClient) {
+		c.Debug = fn
+	}
+}
+
+func (c *TigerGraphClient) logDebugExchange(exchange DebugExchange) {
+	args := []any{
+		"method", exchange.Method,
+		"url", exchange.URL,
+		"request_headers", exchange.RequestHeader,
+		"request_body", exchange.RequestBody,
+		"status", exchange.StatusCode,
+		"response_headers", exchange.ResponseHeader,
+		"response_body", exchange.ResponseBody,
+		"truncated", exchange.Truncated,
+		"duration", exchange.Duration,
+	}
+	if exchange.Err != nil {
+		args = append(args, "error", exchange.Err)
+	}
+
+	c.log(LogLevelInfo, "TigerGraph request dump", args...)
+}
+
+// send sends a single request, passing it and its response to the client's Debug func if set.
+func (c *TigerGraphClient) send(req *http.Request) (*http.Response, error) {
+	if c.Debug == nil {
+		return c.httpClient().Do(req)
+	}
+
+	exchange := DebugExchange{
+		Method:        req.Method,
+		URL:           redactURL(req.URL),
+		RequestHeader: redactHeader(req.Header),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			exchange.RequestBody, exchange.Truncated = debugBody(body, req.Header)
+			body.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient().Do(req)
+	exchange.Duration = time.Since(start)
+	exchange.Err = err
+
+	if resp != nil {
+		exchange.StatusCode = resp.StatusCode
+		exchange.ResponseHeader = redactHeader(resp.Header)
+
+		// The start of the body is read for the exchange and put back in front of the rest, so
+		// that large responses are still streamed
+		prefix, readErr := io.ReadAll(io.LimitReader(resp.Body, MaxDebugBodySize+1))
+		resp.Body = &prefixedReadCloser{Reader: io.MultiReader(bytes.NewReader(prefix), resp.Body), Closer: resp.Body}
+		if readErr == nil {
+			var truncated bool
+			exchange.ResponseBody, truncated = debugBody(io.NopCloser(bytes.NewReader(prefix)), resp.Header)
+			exchange.Truncated = exchange.Truncated || truncated
+		}
+	}
+
+	c.Debug(exchange)
+
+	return resp, err
+}
+
+// prefixedReadCloser reads a body whose start has already been read, closing the original body
+type prefixedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// debugBody returns up to MaxDebugBodySize bytes of body as a string with credentials
+// redacted, decompressing it if it is gzipped, and whether it was truncated.
+func debugBody(body io.Reader, header http.Header) (string, bool) {
+	raw, _ := io.ReadAll(io.LimitReader(body, MaxDebugBodySize+1))
+	truncated := len(raw) > MaxDebugBodySize
+	if truncated {
+		raw = raw[:MaxDebugBodySize]
+	}
+
+	if strings.EqualFold(header.Get("Content-Encoding"), "gzip") {
+		if reader, err := gzip.NewReader(bytes.NewReader(raw)); err == nil {
+			// A truncated body decompresses as far as it goes
+			decompressed, _ := io.ReadAll(io.LimitReader(reader, MaxDebugBodySize+1))
+			truncated = truncated || len(decompressed) > MaxDebugBodySize
+			if len(decompressed) > MaxDebugBodySize {
+				decompressed = decompressed[:MaxDebugBodySize]
+			}
+			raw = decompressed
+		}
+	}
+
+	return redactBody(string(raw)), truncated
+}
+
+// sensitiveHeaders are the headers which carry credentials
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// sensitiveFields are the names of query parameters and JSON fields which carry credentials
+var sensitiveFields = []string{"token", "secret", "password"}
+
+// sensitiveJSONField matches a JSON string field carrying credentials, e.g. the token returned
+// by the request token endpoint
+var sensitiveJSONField = regexp.MustCompile(`("(?i:` + strings.Join(sensitiveFields, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+func redactHeader(header http.Header) http.Header {
+	result := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if _, ok := result[name]; ok {
+			result[name] = []string{redacted}
+		}
+	}
+
+	return result
+}
+
+func redactURL(u *url.URL) string {
+	redactedURL := *u
+	redactedURL.User = nil
+
+	query := redactedURL.Query()
+	changed := false
+	for name := range query {
+		for _, field := range sensitiveFields {
+			if strings.EqualFold(name, field) {
+				query.Set(name, redacted)
+				changed = true
+			}
+		}
+	}
+	if changed {
+		redactedURL.RawQuery = query.Encode()
+	}
+
+	return redactedURL.String()
+}
+
+func redactBody(body string) string {
+	body = createdSecretRegexp.ReplaceAllString(body, "The secret: "+redacted+" has been created")
+	return sensitiveJSONField.ReplaceAllString(body, `$1"`+redacted+`"`)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultDecodeErrorBodyLimit is the number of bytes of a response included in a DecodeError
+// when the client's DecodeErrorBodyLimit is zero
+const DefaultDecodeErrorBodyLimit = 1024
+
+// ErrDecodeFailed represents a response which could not be decoded into the result
+var ErrDecodeFailed = errors.New("failed to decode response")
+
+// DecodeError is returned when a response from TigerGraph cannot be decoded into the result
+// given to a call. It wraps ErrDecodeFailed and the error from encoding/json.
+type DecodeError struct {
+	// Path is the JSON path of the value which could not be decoded, e.g. results[0].age, or
+	// empty for the whole response
+	Path string
+
+	// Expected is the Go type the value could not be decoded into, if it had the wrong type
+	Expected string
+
+	// Value describes the JSON value found, e.g. "string", if it had the wrong type
+	Value string
+
+	// Offset is the number of bytes of the response read when decoding failed
+	Offset int64
+
+	// Body is the start of the response, with credentials redacted, up to the client's
+	// DecodeErrorBodyLimit
+	Body string
+
+	// Truncated is true if Body is only the start of the response
+	Truncated bool
+
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	var message strings.Builder
+	message.WriteString(ErrDecodeFailed.Error())
+
+	if e.Path != "" {
+		fmt.Fprintf(&message, " at %s", e.Path)
+	}
+	if e.Expected != "" {
+		fmt.Fprintf(&message, ": expected %s, got %s", e.Expected, e.Value)
+	} else {
+		fmt.Fprintf(&message, ": %s", e.Err)
+	}
+
+	if e.Body != "" {
+		fmt.Fprintf(&message, ". response: %s", e.Body)
+		if e.Truncated {
+			message.WriteString("... (truncated)")
+		}
+	}
+
+	return message.String()
+}
+
+// Unwrap returns ErrDecodeFailed and the error from encoding/json
+func (e *DecodeError) Unwrap() []error {
+	return []error{ErrDecodeFailed, e.Err}
+}
+
+// WithDecodeErrorBodyLimit sets how many bytes of a response which could not be decoded are
+// included in the DecodeError. Negative means none.
+func WithDecodeErrorBodyLimit(limit int) Option {
+	return func(c *TigerGraphClient) {
+		c.DecodeErrorBodyLimit = limit
+	}
+}
+
+// newDecodeError describes the failure to decode body, locating the value which failed.
+func (c *TigerGraphClient) newDecodeError(body []byte, err error) *DecodeError {
+	decodeErr := &DecodeError{Err: err}
+
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &typeErr):
+		decodeErr.Offset = typeErr.Offset
+		decodeErr.Expected = typeErr.Type.String()
+		decodeErr.Value = typeErr.Value
+	case errors.As(err, &syntaxErr):
+		decodeErr.Offset = syntaxErr.Offset
+	}
+
+	if decodeErr.Offset > 0 {
+		decodeErr.Path = jsonPathAt(body, decodeErr.Offset)
+	}
+
+	limit := c.DecodeErrorBodyLimit
+	if limit == 0 {
+		limit = DefaultDecodeErrorBodyLimit
+	}
+	if limit > 0 {
+		// Credentials are redacted first so that truncation cannot leave part of one behind
+		redactedBody := redactBody(string(body))
+		decodeErr.Truncated = len(redactedBody) > limit
+		if decodeErr.Truncated {
+			redactedBody = redactedBody[:limit]
+		}
+		decodeErr.Body = redactedBody
+	}
+
+	return decodeErr
+}
+
+// jsonPathFrame is an object or array being read by jsonPathAt
+type jsonPathFrame struct {
+	array     bool
+	index     int
+	key       string
+	expectKey bool
+}
+
+// jsonPathAt returns the path of the last value started within the first offset bytes of
+// data, e.g. results[0].age.
+func jsonPathAt(data []byte, offset int64) string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var frames []*jsonPathFrame
+	path := ""
+
+	// valueDone moves the innermost container past the value just read
+	valueDone := func() {
+		if len(frames) == 0 {
+			return
+		}
+		frame := frames[len(frames)-1]
+		if frame.array {
+			frame.index++
+		} else {
+			frame.expectKey = true
+		}
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return path
+		}
+
+		inObject := len(frames) > 0 && !frames[len(frames)-1].array
+		switch {
+		case inObject && frames[len(frames)-1].expectKey && token != json.Delim('}'):
+			frame := frames[len(frames)-1]
+			frame.key, _ = token.(string)
+			frame.expectKey = false
+		case token == json.Delim('{') || token == json.Delim('['):
+			path = renderJSONPath(frames)
+			frames = append(frames, &jsonPathFrame{array: token == json.Delim('['), expectKey: token == json.Delim('{')})
+		case token == json.Delim('}') || token == json.Delim(']'):
+			frames = frames[:len(frames)-1]
+			valueDone()
+		default:
+			path = renderJSONPath(frames)
+			valueDone()
+		}
+
+		if decoder.InputOffset() >= offset {
+			return path
+		}
+	}
+}
+
+// renderJSONPath returns the path of the value at the current position of each frame
+func renderJSONPath(frames []*jsonPathFrame) string {
+	var path strings.Builder
+	for _, frame := range frames {
+		if frame.array {
+			path.WriteString("[" + strconv.Itoa(frame.index) + "]")
+			continue
+		}
+		if path.Len() > 0 {
+			path.WriteString(".")
+		}
+		path.WriteString(frame.key)
+	}
+
+	return path.String()
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeError(t *testing.T) { //nolint:funlen
+	type person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	tests := []struct {
+		name              string
+		body              string
+		result            any
+		limit             int
+		expectedPath      string
+		expectedExpected  string
+		expectedValue     string
+		expectedBody      string
+		expectedTruncated bool
+	}{
+		{
+			name:             "wrong type nested in arrays",
+			body:             `{"error": false, "results": [{"name": "a", "age": 1}, {"name": "b", "age": "two"}]}`,
+			result:           &TigerGraphResponse[person]{},
+			expectedPath:     "results[1].age",
+			expectedExpected: "int",
+			expectedValue:    "string",
+			expectedBody:     `{"error": false, "results": [{"name": "a", "age": 1}, {"name": "b", "age": "two"}]}`,
+		},
+		{
+			name:             "wrong container type",
+			body:             `{"results": {"name": "a"}}`,
+			result:           &TigerGraphResponse[person]{},
+			expectedPath:     "results",
+			expectedExpected: "[]tigergraph.person",
+			expectedValue:    "object",
+			expectedBody:     `{"results": {"name": "a"}}`,
+		},
+		{
+			name:              "syntax errors are located and bodies truncated and redacted",
+			body:              `{"token": "abc123", "results": [{"name": "a",, "age": 1}]}`,
+			result:            &TigerGraphResponse[person]{},
+			limit:             20,
+			expectedPath:      "results[0].name",
+			expectedBody:      `{"token": "[REDACTED`,
+			expectedTruncated: true,
+		},
+		{
+			name:             "bodies can be left out",
+			body:             `{"results": "none"}`,
+			result:           &TigerGraphResponse[person]{},
+			limit:            -1,
+			expectedPath:     "results",
+			expectedExpected: "[]tigergraph.person",
+			expectedValue:    "string",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := NewClient("http://tg:9000", WithDecodeErrorBodyLimit(test.limit))
+			err := client.newDecodeError([]byte(test.body), json.Unmarshal([]byte(test.body), test.result))
+
+			assert.ErrorIs(t, err, ErrDecodeFailed)
+			assert.Equal(t, test.expectedPath, err.Path)
+			assert.Equal(t, test.expectedExpected, err.Expected)
+			assert.Equal(t, test.expectedValue, err.Value)
+			assert.Equal(t, test.expectedBody, err.Body)
+			assert.Equal(t, test.expectedTruncated, err.Truncated)
+			assert.NotContains(t, err.Error(), "abc123")
+			if test.expectedPath != "" {
+				assert.True(t, strings.Contains(err.Error(), " at "+test.expectedPath), err.Error())
+			}
+		})
+	}
+}
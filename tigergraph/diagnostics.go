@@ -0,0 +1,232 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// LogURL is the GSQL server URL returning the recent log lines of a TigerGraph component,
+	// where the deployment exposes it
+	LogURL = "/api/log"
+
+	// LogComponentRESTPP names the RESTPP logs, which record requests and loading jobs
+	LogComponentRESTPP = "restpp"
+
+	// LogComponentGPE names the GPE logs, which record query execution
+	LogComponentGPE = "gpe"
+
+	// DefaultDiagnosticLines is the number of matching log lines attached to an error per component
+	DefaultDiagnosticLines = 20
+
+	// DefaultDiagnosticScanLines is the number of recent log lines searched per component
+	DefaultDiagnosticScanLines = 1000
+
+	// DefaultDiagnosticTimeout bounds fetching logs for a failed call
+	DefaultDiagnosticTimeout = 5 * time.Second
+)
+
+// LogSource fetches the recent log lines of a TigerGraph component, oldest first. The client's
+// RecentLogs is used unless Diagnostics sets another, e.g. reading from a log aggregator.
+type LogSource interface {
+	RecentLogs(ctx context.Context, component string, limit int) ([]string, error)
+}
+
+// Diagnostics configures attaching log lines to the errors of failed installed queries and
+// loading jobs. Zero fields take their defaults.
+type Diagnostics struct {
+	// Source fetches the logs. Defaults to the client's RecentLogs.
+	Source LogSource
+
+	// Components are the components whose logs are searched. Defaults to RESTPP and GPE.
+	Components []string
+
+	// Lines is the most matching lines attached per component
+	Lines int
+
+	// ScanLines is the number of recent lines fetched and searched per component
+	ScanLines int
+
+	// Timeout bounds fetching the logs for a failed call
+	Timeout time.Duration
+}
+
+// DiagnosticError is a failed installed query or loading job with the log lines which mention
+// it. It unwraps to the original error, so it matches the same errors.
+type DiagnosticError struct {
+	Err error
+
+	// Logs maps each component to the recent lines mentioning the query or loading job
+	Logs map[string][]string
+
+	// LogErr records why logs could not be fetched, if they could not
+	LogErr error
+}
+
+// Error implements error, listing the log lines after the original error.
+func (e *DiagnosticError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Err.Error())
+
+	components := make([]string, 0, len(e.Logs))
+	for component := range e.Logs {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	for _, component := range components {
+		fmt.Fprintf(&b, "\nrecent %s log lines:", component)
+		for _, line := range e.Logs[component] {
+			fmt.Fprintf(&b, "\n  %s", line)
+		}
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the original error
+func (e *DiagnosticError) Unwrap() error {
+	return e.Err
+}
+
+// WithDiagnostics attaches log lines mentioning an installed query or loading job to the
+// errors of failed calls, as a *DiagnosticError. Fetching logs costs extra requests, and only
+// happens when a call fails.
+func WithDiagnostics(diagnostics Diagnostics) Option {
+	return func(c *TigerGraphClient) {
+		if len(diagnostics.Components) == 0 {
+			diagnostics.Components = []string{LogComponentRESTPP, LogComponentGPE}
+		}
+		if diagnostics.Lines <= 0 {
+			diagnostics.Lines = DefaultDiagnosticLines
+		}
+		if diagnostics.ScanLines <= 0 {
+			diagnostics.ScanLines = DefaultDiagnosticScanLines
+		}
+		if diagnostics.Timeout <= 0 {
+			diagnostics.Timeout = DefaultDiagnosticTimeout
+		}
+		c.Diagnostics = &diagnostics
+	}
+}
+
+// RecentLogs returns up to limit recent log lines of the component from LogURL, oldest first.
+// Not every deployment exposes logs, in which case an error wrapping ErrNonOK is returned.
+func (c *TigerGraphClient) RecentLogs(ctx context.Context, component string, limit int) ([]string, error) {
+	params := url.Values{}
+	params.Set("component", component)
+	params.Set("limit", strconv.Itoa(limit))
+
+	request, err := c.CreateGSQLServerRequest(ctx, http.MethodGet, LogURL+"?"+params.Encode(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	var response TigerGraphResponse[string]
+	if err = c.RequestInto(request, &response); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s logs: %w", component, err)
+	}
+
+	if response.Error {
+		return nil, fmt.Errorf("failed to fetch %s logs. message: %s: %w", component, response.Message, ErrTigerGraphError)
+	}
+
+	return response.Results, nil
+}
+
+// diagnose wraps the error of a failed call in a *DiagnosticError, if diagnostics are enabled
+// and the call ran an installed query or loading job
+func (c *TigerGraphClient) diagnose(ctx context.Context, queryURL string, err error) error {
+	if err == nil || c.Diagnostics == nil {
+		return err
+	}
+
+	var diagnosticErr *DiagnosticError
+	if errors.As(err, &diagnosticErr) {
+		return err
+	}
+
+	keyword := diagnosticKeyword(queryURL)
+	if keyword == "" {
+		return err
+	}
+
+	// The call may have failed because ctx expired, which should not stop the logs being fetched
+	if ctx.Err() != nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.Diagnostics.Timeout)
+	defer cancel()
+
+	var source LogSource = c
+	if c.Diagnostics.Source != nil {
+		source = c.Diagnostics.Source
+	}
+
+	result := &DiagnosticError{Err: err, Logs: make(map[string][]string)}
+	logErrs := make([]error, 0)
+	for _, component := range c.Diagnostics.Components {
+		lines, logErr := source.RecentLogs(ctx, component, c.Diagnostics.ScanLines)
+		if logErr != nil {
+			logErrs = append(logErrs, logErr)
+			continue
+		}
+
+		matching := make([]string, 0)
+		for _, line := range lines {
+			if strings.Contains(line, keyword) {
+				matching = append(matching, line)
+			}
+		}
+		if len(matching) > c.Diagnostics.Lines {
+			matching = matching[len(matching)-c.Diagnostics.Lines:]
+		}
+		if len(matching) > 0 {
+			result.Logs[component] = matching
+		}
+	}
+	result.LogErr = errors.Join(logErrs...)
+
+	return result
+}
+
+// diagnosticKeyword returns the installed query or loading job named by the URL, which log
+// lines about the call mention, or "" for other calls
+func diagnosticKeyword(queryURL string) string {
+	path, rawQuery, _ := strings.Cut(queryURL, "?")
+
+	if strings.HasPrefix(path, "/query/") {
+		parts := strings.Split(strings.TrimPrefix(path, "/query/"), "/")
+		return parts[len(parts)-1]
+	}
+
+	if strings.HasPrefix(path, "/ddl/") {
+		params, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return ""
+		}
+		return params.Get("tag")
+	}
+
+	return ""
+}
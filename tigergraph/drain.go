@@ -0,0 +1,138 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ErrClientClosed is returned for requests made once the client has started draining.
+var ErrClientClosed = errors.New("client is draining and accepts no new requests")
+
+// drainState tracks the client's requests in flight, so that Drain can wait for them
+type drainState struct {
+	mu       sync.Mutex
+	draining bool
+	inFlight int
+	idle     chan struct{}
+}
+
+// drainRequestKey marks the context of requests made by Drain and Close themselves, which
+// are sent after draining has started
+type drainRequestKey struct{}
+
+// Drain prepares the client for shutdown, e.g. in a Kubernetes preStop hook. New requests
+// fail with ErrClientClosed, and Drain waits for requests in flight to finish until ctx is
+// done. Writes buffered in the client's WAL are then replayed. Close may still be called to
+// revoke tokens once Drain returns.
+func (c *TigerGraphClient) Drain(ctx context.Context) error {
+	idle := c.drain.start()
+
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		return fmt.Errorf("%d request(s) still in flight: %w", c.drain.count(), ctx.Err())
+	}
+
+	if c.WAL == nil {
+		return nil
+	}
+
+	if _, err := c.ReplayWAL(withDrainRequests(ctx)); err != nil {
+		return fmt.Errorf("failed to flush write-ahead log: %w", err)
+	}
+
+	return nil
+}
+
+// inFlightBody is the body of a response to a request in flight, which finishes once the
+// body is closed
+type inFlightBody struct {
+	io.ReadCloser
+	end func()
+}
+
+func (b *inFlightBody) Close() error {
+	defer b.end()
+	return b.ReadCloser.Close()
+}
+
+// withDrainRequests marks ctx so that requests made with it are sent while draining
+func withDrainRequests(ctx context.Context) context.Context {
+	return context.WithValue(ctx, drainRequestKey{}, true)
+}
+
+// beginRequest records a request in flight, returning the function to call when it has
+// finished, or ErrClientClosed if the client is draining.
+func (c *TigerGraphClient) beginRequest(req *http.Request) (func(), error) {
+	_, allowed := req.Context().Value(drainRequestKey{}).(bool)
+	return c.drain.begin(allowed)
+}
+
+func (d *drainState) begin(allowed bool) (func(), error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.draining && !allowed {
+		return nil, ErrClientClosed
+	}
+	d.inFlight++
+
+	var once sync.Once
+	return func() {
+		once.Do(d.end)
+	}, nil
+}
+
+func (d *drainState) end() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.inFlight--
+	if d.inFlight == 0 && d.idle != nil {
+		close(d.idle)
+		d.idle = nil
+	}
+}
+
+// start stops new requests and returns a channel closed once none are in flight
+func (d *drainState) start() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.draining = true
+	idle := make(chan struct{})
+	if d.inFlight == 0 {
+		close(idle)
+		return idle
+	}
+
+	if d.idle == nil {
+		d.idle = make(chan struct{})
+	}
+
+	return d.idle
+}
+
+func (d *drainState) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.inFlight
+}
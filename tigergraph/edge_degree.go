@@ -0,0 +1,264 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+const (
+	// EdgesURLTemplate is the RESTPP URL for listing the edges of a vertex. It must be formatted
+	// with the graph name, source vertex type, source vertex ID and edge type.
+	EdgesURLTemplate = "/graph/%s/edges/%s/%s/%s"
+
+	// AnyEdgeType can be passed as the edge type to count edges of all types.
+	AnyEdgeType = "_"
+
+	// reverseEdgeConfig is the key of an edge type's config holding the name of its reverse edge
+	reverseEdgeConfig = "REVERSE_EDGE"
+
+	// maxConcurrentDegreeLookups bounds the requests made at once by GetVertexDegrees
+	maxConcurrentDegreeLookups = 8
+)
+
+var (
+	// ErrNoReverseEdge represents counting incoming edges of a directed edge type which was
+	// created without a reverse edge.
+	ErrNoReverseEdge = errors.New("edge type has no reverse edge")
+
+	// ErrUnknownEdgeType represents counting incoming edges of an edge type which is not in
+	// the graph's schema.
+	ErrUnknownEdgeType = errors.New("edge type is not in the graph schema")
+)
+
+// EdgeDirection selects which edges of a vertex are counted.
+type EdgeDirection int
+
+const (
+	// EdgeDirectionOutgoing counts edges leaving the vertex, including undirected edges.
+	EdgeDirectionOutgoing EdgeDirection = iota
+
+	// EdgeDirectionIncoming counts edges arriving at the vertex. A directed edge type must
+	// have a reverse edge, which is looked up in the graph's schema.
+	EdgeDirectionIncoming
+)
+
+// EdgeCountResult is a single result in a count_only response from the edges endpoint.
+type EdgeCountResult struct {
+	EdgeType string `json:"e_type"`
+	Count    int    `json:"count"`
+}
+
+// GetVertexDegree returns the number of edges of the given type connected to a vertex, without
+// fetching the edges themselves. Pass AnyEdgeType to count edges of every type.
+func (c *TigerGraphClient) GetVertexDegree(
+	ctx context.Context,
+	graphName string,
+	vertexType string,
+	id string,
+	edgeType string,
+	direction EdgeDirection,
+	opts ...RequestOption,
+) (int, error) {
+	edgeType, err := c.directedEdgeType(ctx, graphName, edgeType, direction)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.vertexDegree(ctx, graphName, vertexType, id, edgeType, opts)
+}
+
+// GetVertexDegrees returns the degree of each of the given vertices, keyed by vertex ID. The
+// degrees are counted concurrently, and the first failure cancels the remaining lookups.
+func (c *TigerGraphClient) GetVertexDegrees(
+	ctx context.Context,
+	graphName string,
+	vertexType string,
+	ids []string,
+	edgeType string,
+	direction EdgeDirection,
+	opts ...RequestOption,
+) (map[string]int, error) {
+	edgeType, err := c.directedEdgeType(ctx, graphName, edgeType, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+
+	result := make(map[string]int, len(ids))
+	slots := make(chan struct{}, maxConcurrentDegreeLookups)
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+
+		go func(id string) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			degree, err := c.vertexDegree(ctx, graphName, vertexType, id, edgeType, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to get degree of vertex %s: %w", id, err)
+					cancel()
+				}
+				return
+			}
+
+			result[id] = degree
+		}(id)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return result, nil
+}
+
+// directedEdgeType returns the edge type to count for the given direction. Incoming edges of a
+// directed edge type are counted with its reverse edge, as named in the graph's schema.
+func (c *TigerGraphClient) directedEdgeType(
+	ctx context.Context,
+	graphName string,
+	edgeType string,
+	direction EdgeDirection,
+) (string, error) {
+	if direction != EdgeDirectionIncoming || edgeType == AnyEdgeType {
+		return edgeType, nil
+	}
+
+	metadata, err := c.GetGraphMetadata(ctx, graphName)
+	if err != nil {
+		return "", newOperationError("count edges", graphName, GetGraphMetadataQueryURL, err)
+	}
+
+	if metadata.Error || metadata.Results == nil {
+		return "", newOperationError(
+			"count edges",
+			graphName,
+			GetGraphMetadataQueryURL,
+			fmt.Errorf("TigerGraph returned an error when getting graph metadata. Message: %s: %w", metadata.Message, ErrTigerGraphError),
+		)
+	}
+
+	for _, existing := range metadata.Results.EdgeTypes {
+		if existing.Name != edgeType {
+			continue
+		}
+
+		if !existing.IsDirected {
+			return edgeType, nil
+		}
+
+		if reverse := existing.Config[reverseEdgeConfig]; reverse != "" {
+			return reverse, nil
+		}
+
+		return "", fmt.Errorf("edge type %s: %w", edgeType, ErrNoReverseEdge)
+	}
+
+	return "", fmt.Errorf("edge type %s: %w", edgeType, ErrUnknownEdgeType)
+}
+
+func (c *TigerGraphClient) vertexDegree(
+	ctx context.Context,
+	graphName string,
+	vertexType string,
+	id string,
+	edgeType string,
+	opts []RequestOption,
+) (int, error) {
+	queryURL := fmt.Sprintf(
+		EdgesURLTemplate,
+		graphName,
+		url.PathEscape(vertexType),
+		url.PathEscape(id),
+		url.PathEscape(edgeType),
+	)
+
+	return c.countEdges(ctx, graphName, queryURL, opts)
+}
+
+// EdgeExists reports whether an edge of the given type exists between two vertices.
+func (c *TigerGraphClient) EdgeExists(
+	ctx context.Context,
+	graphName string,
+	sourceType string,
+	sourceID string,
+	edgeType string,
+	targetType string,
+	targetID string,
+	opts ...RequestOption,
+) (bool, error) {
+	queryURL := fmt.Sprintf(
+		EdgesURLTemplate+"/%s/%s",
+		graphName,
+		url.PathEscape(sourceType),
+		url.PathEscape(sourceID),
+		url.PathEscape(edgeType),
+		url.PathEscape(targetType),
+		url.PathEscape(targetID),
+	)
+
+	count, err := c.countEdges(ctx, graphName, queryURL, opts)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (c *TigerGraphClient) countEdges(ctx context.Context, graphName string, queryURL string, opts []RequestOption) (int, error) {
+	var response TigerGraphResponse[EdgeCountResult]
+	err := c.Get(ctx, queryURL+"?count_only=true", graphName, &response, operationOptions("count edges", opts)...)
+	if err != nil {
+		return 0, err
+	}
+
+	if response.Error {
+		return 0, newOperationError("count edges", graphName, queryURL, fmt.Errorf(
+			"failed to count edges. message: %s: %w", response.Message, ErrTigerGraphError,
+		))
+	}
+
+	total := 0
+	for _, result := range response.Results {
+		total += result.Count
+	}
+
+	return total, nil
+}
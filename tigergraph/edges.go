@@ -0,0 +1,159 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EdgeReadOption configures GetEdges.
+type EdgeReadOption func(*edgeReadOptions)
+
+type edgeReadOptions struct {
+	targetType    string
+	targetID      string
+	discriminator map[string]any
+	limit         int
+	requestOpts   []RequestOption
+}
+
+// ToVertexType only returns edges to vertices of the given type.
+func ToVertexType(targetType string) EdgeReadOption {
+	return func(o *edgeReadOptions) {
+		o.targetType = targetType
+	}
+}
+
+// ToVertex only returns edges to the given vertex. For edge types with a discriminator there
+// may be several.
+func ToVertex(targetType string, targetID string) EdgeReadOption {
+	return func(o *edgeReadOptions) {
+		o.targetType = targetType
+		o.targetID = targetID
+	}
+}
+
+// WithDiscriminator only returns edges whose attributes have the given values, e.g. to pick
+// a single edge out of several between the same vertices by its discriminator.
+func WithDiscriminator(attributes map[string]any) EdgeReadOption {
+	return func(o *edgeReadOptions) {
+		o.discriminator = attributes
+	}
+}
+
+// EdgeLimit returns at most the given number of edges.
+func EdgeLimit(limit int) EdgeReadOption {
+	return func(o *edgeReadOptions) {
+		o.limit = limit
+	}
+}
+
+// EdgeRequestOptions passes RequestOptions, e.g. WithRequestHeader, to the request GetEdges makes.
+func EdgeRequestOptions(opts ...RequestOption) EdgeReadOption {
+	return func(o *edgeReadOptions) {
+		o.requestOpts = append(o.requestOpts, opts...)
+	}
+}
+
+// GetEdges returns the edges of the given type from a vertex. Pass AnyEdgeType to return edges
+// of every type.
+func GetEdges[T any](
+	ctx context.Context,
+	c *TigerGraphClient,
+	graphName string,
+	sourceType string,
+	sourceID string,
+	edgeType string,
+	opts ...EdgeReadOption,
+) ([]ResponseEdge[T], error) {
+	options := &edgeReadOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	queryURL := fmt.Sprintf(
+		EdgesURLTemplate,
+		graphName,
+		url.PathEscape(sourceType),
+		url.PathEscape(sourceID),
+		url.PathEscape(edgeType),
+	)
+	if options.targetType != "" {
+		queryURL += "/" + url.PathEscape(options.targetType)
+		if options.targetID != "" {
+			queryURL += "/" + url.PathEscape(options.targetID)
+		}
+	}
+
+	query := url.Values{}
+	if len(options.discriminator) > 0 {
+		query.Set("filter", edgeFilter(options.discriminator, c.DatetimeEncoding))
+	}
+	if options.limit > 0 {
+		query.Set("limit", strconv.Itoa(options.limit))
+	}
+	if len(query) > 0 {
+		queryURL += "?" + query.Encode()
+	}
+
+	var response TigerGraphResponse[ResponseEdge[T]]
+	if err := c.Get(ctx, queryURL, graphName, &response, operationOptions("get edges", options.requestOpts)...); err != nil {
+		return nil, err
+	}
+
+	if response.Error {
+		return nil, newOperationError("get edges", graphName, queryURL, fmt.Errorf(
+			"failed to get edges. message: %s: %w", response.Message, ErrTigerGraphError,
+		))
+	}
+
+	return response.Results, nil
+}
+
+// edgeFilter formats attribute values as a RESTPP filter, e.g. `since="2023-06-01 00:00:00",weight=2`.
+// Conditions are sorted by attribute name so the filter is stable.
+func edgeFilter(attributes map[string]any, encoding DatetimeEncoding) string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	conditions := make([]string, 0, len(names))
+	for _, name := range names {
+		conditions = append(conditions, name+"=="+filterValue(attributes[name], encoding))
+	}
+
+	return strings.Join(conditions, ",")
+}
+
+func filterValue(value any, encoding DatetimeEncoding) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case time.Time:
+		if encoding == DatetimeEpoch {
+			return encoding.Format(v)
+		}
+		return strconv.Quote(encoding.Format(v))
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// EndpointServerRESTPP marks an endpoint served by RESTPP, at the client's BaseURL
+	EndpointServerRESTPP = "restpp"
+
+	// EndpointServerGSQL marks an endpoint served by the GSQL server, at the client's BaseFileURL
+	EndpointServerGSQL = "gsql"
+
+	// EndpointParamPath marks a parameter in the endpoint's path
+	EndpointParamPath = "path"
+
+	// EndpointParamQuery marks a parameter in the endpoint's query string
+	EndpointParamQuery = "query"
+)
+
+// Endpoint describes a TigerGraph endpoint which the client wraps, so that tooling such as API
+// gateways can configure routing and allow lists for the requests the client makes.
+type Endpoint struct {
+	// Method is the client method or function making the request, e.g. "Upsert"
+	Method string `json:"method"`
+
+	// HTTPMethod is the HTTP method of the request, e.g. "POST"
+	HTTPMethod string `json:"http_method"`
+
+	// Server is EndpointServerRESTPP or EndpointServerGSQL
+	Server string `json:"server"`
+
+	// Path is the path of the request, with parameters in braces, e.g. "/graph/{graph}"
+	Path string `json:"path"`
+
+	// Params are the parameters of the path and query string, in order
+	Params []EndpointParam `json:"params,omitempty"`
+
+	// ContentType is the content type of the request body, if it has one
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// EndpointParam is a parameter of an Endpoint
+type EndpointParam struct {
+	Name string `json:"name"`
+
+	// In is EndpointParamPath or EndpointParamQuery
+	In string `json:"in"`
+
+	// Value is the value the client always sends, for query parameters with a fixed value
+	Value string `json:"value,omitempty"`
+}
+
+// endpoints is the table of every endpoint the client wraps. Paths are built from the URL
+// constants the client uses to make its requests.
+var endpoints = []Endpoint{
+	newEndpoint("Get", http.MethodGet, EndpointServerRESTPP, QueryURLTemplate, "", "graph", "query"),
+	newEndpoint("Post", http.MethodPost, EndpointServerRESTPP, QueryURLTemplate, "application/json", "graph", "query"),
+	newEndpoint("PostRaw", http.MethodPost, EndpointServerRESTPP, QueryURLTemplate, "", "graph", "query"),
+	newEndpoint("Upsert", http.MethodPost, EndpointServerRESTPP, UpsertURL+"/%s", "application/json", "graph"),
+	newEndpoint(
+		"SoftDeleteVertices",
+		http.MethodPost,
+		EndpointServerRESTPP,
+		UpsertURL+"/%s?vertex_must_exist=true",
+		"application/json",
+		"graph",
+	),
+	newEndpoint(
+		"RestoreVertices",
+		http.MethodPost,
+		EndpointServerRESTPP,
+		UpsertURL+"/%s?vertex_must_exist=true",
+		"application/json",
+		"graph",
+	),
+	newEndpoint("GetVertices", http.MethodGet, EndpointServerRESTPP, VerticesURLTemplate, "", "graph", "vertex_type"),
+	newEndpoint(
+		"GetVerticesByIDs",
+		http.MethodPost,
+		EndpointServerRESTPP,
+		fmt.Sprintf(QueryURLTemplate, "%s", VerticesByIDsQueryName),
+		"application/json",
+		"graph",
+	),
+	newEndpoint(
+		"GetEdges",
+		http.MethodGet,
+		EndpointServerRESTPP,
+		EdgesURLTemplate,
+		"",
+		"graph",
+		"source_type",
+		"source_id",
+		"edge_type",
+	),
+	newEndpoint(
+		"GetVertexDegree",
+		http.MethodGet,
+		EndpointServerRESTPP,
+		EdgesURLTemplate+"?count_only=true",
+		"",
+		"graph",
+		"vertex_type",
+		"vertex_id",
+		"edge_type",
+	),
+	newEndpoint(
+		"EdgeExists",
+		http.MethodGet,
+		EndpointServerRESTPP,
+		EdgesURLTemplate+"/%s/%s?count_only=true",
+		"",
+		"graph",
+		"source_type",
+		"source_id",
+		"edge_type",
+		"target_type",
+		"target_id",
+	),
+	newEndpoint(
+		"RunLoadingJobJSONL",
+		http.MethodPost,
+		EndpointServerRESTPP,
+		LoadingJobURLTemplate,
+		"application/json",
+		"graph",
+		"loading_job",
+	),
+	newEndpoint("RunLoadingJobRaw", http.MethodPost, EndpointServerRESTPP, LoadingJobURLTemplate, "", "graph", "loading_job"),
+	newEndpoint("IsQueryInstalled", http.MethodGet, EndpointServerRESTPP, EndpointsURLTemplate, "", "graph"),
+	newEndpoint("ServerVersion", http.MethodGet, EndpointServerRESTPP, VersionURL, ""),
+	newEndpoint("MeasureLatency", http.MethodGet, EndpointServerRESTPP, EchoURL, ""),
+	newEndpoint("Auth", http.MethodPost, EndpointServerRESTPP, RequestTokenURL, "application/json"),
+	newEndpoint("RevokeToken", http.MethodDelete, EndpointServerRESTPP, RequestTokenURL, "application/json"),
+	newEndpoint("Ping", http.MethodGet, EndpointServerGSQL, PingURL, ""),
+	newEndpoint("Healthcheck", http.MethodGet, EndpointServerGSQL, GSQLVersionURL, ""),
+	newEndpoint("GetGraphMetadata", http.MethodGet, EndpointServerGSQL, GetGraphMetadataQueryURL+"?graph=%s", "", "graph"),
+	newEndpoint("RunGSQL", http.MethodPost, EndpointServerGSQL, FileURL, "text/plain"),
+	newEndpoint(
+		"RecentLogs",
+		http.MethodGet,
+		EndpointServerGSQL,
+		LogURL+"?component=%s&limit=%s",
+		"",
+		"component",
+		"limit",
+	),
+	newEndpoint("GSQLCommand", http.MethodPost, EndpointServerGSQL, "%s", "application/x-www-form-urlencoded", "path"),
+}
+
+// Endpoints returns every TigerGraph endpoint the client wraps. It is generated from the URLs
+// the client uses, so it can be marshalled to JSON for tooling without drifting from them.
+func Endpoints() []Endpoint {
+	result := make([]Endpoint, len(endpoints))
+	for i, endpoint := range endpoints {
+		result[i] = endpoint
+		result[i].Params = append([]EndpointParam(nil), endpoint.Params...)
+	}
+
+	return result
+}
+
+// newEndpoint builds an endpoint from a URL template whose verbs are filled, in order, by the named
+// parameters. Query parameters are named by their key, and those not filled by a named
+// parameter have a fixed value.
+func newEndpoint(method string, httpMethod string, server string, template string, contentType string, params ...string) Endpoint {
+	placeholders := make([]any, len(params))
+	for i, param := range params {
+		placeholders[i] = "{" + param + "}"
+	}
+	if len(params) > 0 {
+		template = fmt.Sprintf(template, placeholders...)
+	}
+
+	path, rawQuery, _ := strings.Cut(template, "?")
+	endpoint := Endpoint{Method: method, HTTPMethod: httpMethod, Server: server, Path: path, ContentType: contentType}
+
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			endpoint.Params = append(endpoint.Params, EndpointParam{Name: strings.Trim(segment, "{}"), In: EndpointParamPath})
+		}
+	}
+
+	if rawQuery != "" {
+		for _, pair := range strings.Split(rawQuery, "&") {
+			name, value, _ := strings.Cut(pair, "=")
+			param := EndpointParam{Name: name, In: EndpointParamQuery}
+			if !strings.HasPrefix(value, "{") {
+				param.Value = value
+			}
+			endpoint.Params = append(endpoint.Params, param)
+		}
+	}
+
+	return endpoint
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpoints(t *testing.T) {
+	byMethod := map[string]Endpoint{}
+	for _, endpoint := range Endpoints() {
+		_, duplicate := byMethod[endpoint.Method]
+		assert.False(t, duplicate, endpoint.Method)
+		assert.NotContains(t, endpoint.Path, "%", endpoint.Method)
+		assert.NotContains(t, endpoint.Path, "?", endpoint.Method)
+		byMethod[endpoint.Method] = endpoint
+	}
+
+	assert.Equal(t, Endpoint{
+		Method:      "RunLoadingJobJSONL",
+		HTTPMethod:  http.MethodPost,
+		Server:      EndpointServerRESTPP,
+		Path:        "/ddl/{graph}",
+		ContentType: "application/json",
+		Params: []EndpointParam{
+			{Name: "graph", In: EndpointParamPath},
+			{Name: "tag", In: EndpointParamQuery},
+			{Name: "filename", In: EndpointParamQuery, Value: "f"},
+		},
+	}, byMethod["RunLoadingJobJSONL"])
+
+	assert.Equal(t, "/query/{graph}/"+VerticesByIDsQueryName, byMethod["GetVerticesByIDs"].Path)
+	assert.Equal(t, EndpointServerGSQL, byMethod["RunGSQL"].Server)
+	assert.Equal(t, http.MethodDelete, byMethod["RevokeToken"].HTTPMethod)
+	assert.Empty(t, byMethod["Ping"].Params)
+
+	endpoints := Endpoints()
+	endpoints[0].Params[0].Name = "changed"
+	assert.Equal(t, "graph", Endpoints()[0].Params[0].Name)
+}
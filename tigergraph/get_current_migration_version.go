@@ -13,17 +13,40 @@ specific language governing permissions and limitations under the License.
 */
 package tigergraph
 
-import "context"
-
-// GetCurrentMigrationVersionURL is the URL to get the current migration version
-const GetCurrentMigrationVersionURL = "/query/get_latest_migration"
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultLatestMigrationQueryName is the name of the installed query used to get the latest migration
+	DefaultLatestMigrationQueryName = "get_latest_migration"
+
+	// GetCurrentMigrationVersionURL is the URL to get the current migration version
+	GetCurrentMigrationVersionURL = "/query/" + DefaultLatestMigrationQueryName
+
+	// EndpointsURLTemplate is the RESTPP URL listing the installed query endpoints of a graph.
+	// It must be formatted with the graph name.
+	EndpointsURLTemplate = "/endpoints/%s?dynamic=true"
+
+	latestMigrationQueryNamePlaceholder = "{{QUERY_NAME}}"
+)
+
+// LatestMigrationQueryTemplate is the GSQL which installs the latest migration query, with
+// a placeholder for the query name
+//
+//go:embed gsql/latest_migration_query.gsql
+var LatestMigrationQueryTemplate string
 
 // MigrationVertexAttributes is the attributes of a migration vertex
 type MigrationVertexAttributes struct {
-	CreatedAt       string `json:"created_at"`
-	MigrationNumber string `json:"migration_number"`
-	Mode            string `json:"mode"`
-	GraphName       string `json:"graph_name"`
+	CreatedAt       string        `json:"created_at"`
+	MigrationNumber string        `json:"migration_number"`
+	Mode            MigrationMode `json:"mode"`
+	GraphName       string        `json:"graph_name"`
 }
 
 // MigrationVertex is the shape of a returned migration vertex
@@ -53,38 +76,107 @@ type CurrentMigrationVersionPostBody struct {
 
 // GetCurrentMigrationNumber returns the current migration number set on the TG instance.
 // Returns "" if no migrations have been run
+//
+// If the latest migration was a down migration and the client's MigrationComparator does not
+// implement MigrationSequencer, ErrUnknownPreviousMigration is returned; Migrate resolves this
+// using the migration directory.
 func (c *TigerGraphClient) GetCurrentMigrationNumber(ctx context.Context, graph string) (string, error) {
+	return c.getCurrentMigrationNumber(ctx, graph, migrationSource{})
+}
+
+func (c *TigerGraphClient) getCurrentMigrationNumber(ctx context.Context, graph string, source migrationSource) (string, error) {
+	latestMigration, err := c.latestMigration(ctx, graph)
+	if err != nil || latestMigration == nil {
+		return "", err
+	}
+
+	if err = latestMigration.Attributes.Mode.Validate(); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidMigrationNumber, err)
+	}
+
+	if latestMigration.Attributes.Mode == MigrationDown {
+		result, err := previousMigration(c.migrationComparator(), latestMigration.Attributes.MigrationNumber, source)
+		return result, err
+	}
+
+	return latestMigration.Attributes.MigrationNumber, nil
+}
+
+// latestMigration returns the most recently committed migration vertex of the graph, or nil if
+// no migrations have been committed.
+func (c *TigerGraphClient) latestMigration(ctx context.Context, graph string) (*MigrationVertex, error) {
 	response := &CurrentMigrationVersionResponse{}
 
 	postBody := CurrentMigrationVersionPostBody{
 		GraphName: graph,
 	}
 
-	err := c.Post(ctx, GetCurrentMigrationVersionURL, MetadataGraphName, postBody, response)
+	queryURL := "/query/" + c.latestMigrationQueryName()
+	err := c.Post(ctx, queryURL, MetadataGraphName, postBody, response, WithOperation("get current migration"))
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if response.Error {
-		return "", ErrTigerGraphError
+		return nil, newOperationError("get current migration", MetadataGraphName, queryURL, ErrTigerGraphError)
+	}
+
+	if len(response.Results) != 1 {
+		return nil, newOperationError("get current migration", MetadataGraphName, queryURL, ErrNotOneResult)
 	}
 
 	if len(response.Results[0].LatestMigration) == 0 {
-		return "", nil
+		return nil, nil
 	}
 
-	latestMigration := response.Results[0].LatestMigration[0]
+	return &response.Results[0].LatestMigration[0], nil
+}
 
-	mode := latestMigration.Attributes.Mode
-	if mode != "up" && mode != "down" { //nolint:goconst
-		return "", ErrInvalidMigrationNumber
+func (c *TigerGraphClient) latestMigrationQueryName() string {
+	if c.LatestMigrationQueryName != "" {
+		return c.LatestMigrationQueryName
 	}
 
-	if latestMigration.Attributes.Mode == "down" {
-		result, err := decrementMigrationNumber(latestMigration.Attributes.MigrationNumber)
-		return result, err
+	return DefaultLatestMigrationQueryName
+}
+
+// IsQueryInstalled reports whether a query with the given name is installed on the graph.
+func (c *TigerGraphClient) IsQueryInstalled(ctx context.Context, graph string, queryName string) (bool, error) {
+	var endpoints map[string]json.RawMessage
+	err := c.Get(ctx, fmt.Sprintf(EndpointsURLTemplate, graph), graph, &endpoints, WithOperation("get endpoints"))
+	if err != nil {
+		return false, err
 	}
 
-	return latestMigration.Attributes.MigrationNumber, nil
+	// Endpoints are keyed by method and path, e.g. "POST /query/MyGraph/my_query"
+	for endpoint := range endpoints {
+		if strings.HasSuffix(endpoint, "/query/"+graph+"/"+queryName) || strings.HasSuffix(endpoint, "/query/"+queryName) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ensureLatestMigrationQuery installs the query used to get the latest migration if it is missing
+// from the metadata graph, e.g. because a custom name is configured.
+func (c *TigerGraphClient) ensureLatestMigrationQuery(ctx context.Context) error {
+	queryName := c.latestMigrationQueryName()
+
+	installed, err := c.IsQueryInstalled(ctx, MetadataGraphName, queryName)
+	if err != nil {
+		return fmt.Errorf("failed to check whether query %s is installed: %w", queryName, err)
+	}
+
+	if installed {
+		return nil
+	}
+
+	gsql := strings.ReplaceAll(LatestMigrationQueryTemplate, latestMigrationQueryNamePlaceholder, queryName)
+	if err = c.RunGSQL(ctx, gsql); err != nil {
+		return fmt.Errorf("failed to install query %s: %w", queryName, err)
+	}
+
+	return nil
 }
@@ -32,6 +32,7 @@ type GraphMetadataAttributeType struct {
 type GraphMetadataAttribute struct {
 	AttributeName string                     `json:"AttributeName"`
 	AttributeType GraphMetadataAttributeType `json:"AttributeType"`
+	DefaultValue  *string                    `json:"DefaultValue,omitempty"`
 }
 
 // GraphMetadataVertexTypePrimaryID is the primary ID attribute in a vertex type
@@ -79,6 +80,7 @@ type GraphMetadataResponseResult struct {
 type GraphMetadataResponse struct {
 	Message string                       `json:"message"`
 	Error   bool                         `json:"error"`
+	Code    string                       `json:"code,omitempty"`
 	Results *GraphMetadataResponseResult `json:"results"`
 }
 
@@ -87,6 +89,7 @@ type GraphMetadataResponse struct {
 type GraphMetadataPartialResponse struct {
 	Message string          `json:"message"`
 	Error   bool            `json:"error"`
+	Code    string          `json:"code"`
 	Results json.RawMessage `json:"results"`
 }
 
@@ -115,12 +118,14 @@ func (c *TigerGraphClient) GetGraphMetadata(ctx context.Context, graphName strin
 		return &GraphMetadataResponse{
 			Message: resp.Message,
 			Error:   resp.Error,
+			Code:    resp.Code,
 		}, nil
 	}
 
 	return &GraphMetadataResponse{
 		Message: resp.Message,
 		Error:   resp.Error,
+		Code:    resp.Code,
 		Results: &responseResult,
 	}, nil
 }
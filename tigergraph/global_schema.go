@@ -0,0 +1,181 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrGlobalSchemaChange is returned when GSQL which changes the global schema, and so can
+// affect every graph, is run without being allowed
+var ErrGlobalSchemaChange = errors.New("GSQL changes the global schema")
+
+// GlobalSchemaChangeError lists the statements which change the global schema in GSQL which
+// was not allowed to. It matches ErrGlobalSchemaChange with errors.Is.
+type GlobalSchemaChangeError struct {
+	Statements []GSQLStatement
+}
+
+// Error implements error
+func (e *GlobalSchemaChangeError) Error() string {
+	statements := make([]string, 0, len(e.Statements))
+	for _, statement := range e.Statements {
+		statements = append(statements, fmt.Sprintf(
+			"%s %s at line %d",
+			statement.Kind,
+			strings.Join(statement.Names, ", "),
+			statement.Position.Line,
+		))
+	}
+
+	return fmt.Sprintf(
+		"%s: %s; allow it with WithGlobalSchemaChanges or AllowGlobalSchemaChange",
+		ErrGlobalSchemaChange,
+		strings.Join(statements, "; "),
+	)
+}
+
+// Unwrap allows errors.Is to match ErrGlobalSchemaChange
+func (e *GlobalSchemaChangeError) Unwrap() error {
+	return ErrGlobalSchemaChange
+}
+
+// WithGlobalSchemaChanges allows RunGSQL, and so Migrate, to run GSQL which changes the global
+// schema. Without it, such GSQL returns a *GlobalSchemaChangeError before it is run.
+func WithGlobalSchemaChanges() Option {
+	return func(c *TigerGraphClient) {
+		c.AllowGlobalSchemaChanges = true
+	}
+}
+
+// AllowGlobalSchemaChange allows a single RunGSQL call to change the global schema.
+func AllowGlobalSchemaChange() RequestOption {
+	return func(o *requestOptions) {
+		o.allowGlobalSchemaChange = true
+	}
+}
+
+// GlobalSchemaStatements returns the statements in GSQL which change the global schema:
+// global schema change jobs, and vertex and edge types created or dropped outside of a
+// graph's schema change job, as well as DROP ALL.
+func GlobalSchemaStatements(src string) ([]GSQLStatement, error) {
+	tokens, err := TokenizeGSQL(src)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := ClassifyGSQL(src)
+	if err != nil {
+		return nil, err
+	}
+
+	// Statements inside braces belong to a job or query body, e.g. ADD VERTEX in a local
+	// schema change job, and only change the schema of that graph
+	depthAt := make(map[int]int, len(tokens))
+	depth := 0
+	for _, token := range tokens {
+		depthAt[token.Position.Offset] = depth
+		if token.Kind == GSQLTokenPunctuation {
+			switch token.Value {
+			case "{":
+				depth++
+			case "}":
+				depth--
+			}
+		}
+	}
+
+	global := make([]GSQLStatement, 0)
+	for _, statement := range statements {
+		switch statement.Kind {
+		case StatementCreateGlobalSchemaJob, StatementRunGlobalSchemaJob, StatementDropAll:
+			global = append(global, statement)
+		case StatementCreateVertex, StatementCreateEdge, StatementDropVertex, StatementDropEdge:
+			if depthAt[statement.Position.Offset] == 0 {
+				global = append(global, statement)
+			}
+		}
+	}
+
+	return global, nil
+}
+
+// checkGlobalSchemaChanges returns a *GlobalSchemaChangeError if the GSQL changes the global
+// schema and neither the client nor the call allows it.
+func (c *TigerGraphClient) checkGlobalSchemaChanges(gsql string, opts []RequestOption) error {
+	if c.AllowGlobalSchemaChanges || collectRequestOptions(opts).allowGlobalSchemaChange {
+		return nil
+	}
+
+	statements, err := GlobalSchemaStatements(gsql)
+	if err != nil {
+		return fmt.Errorf("failed to check GSQL for global schema changes: %w", err)
+	}
+
+	if len(statements) > 0 {
+		return &GlobalSchemaChangeError{Statements: statements}
+	}
+
+	return nil
+}
+
+// GlobalVertexTypes returns the names of the vertex types shared with other graphs.
+func (r *GraphMetadataResponseResult) GlobalVertexTypes() []string {
+	names := make([]string, 0)
+	for _, vertexType := range r.VertexTypes {
+		if !vertexType.IsLocal {
+			names = append(names, vertexType.Name)
+		}
+	}
+
+	return names
+}
+
+// LocalVertexTypes returns the names of the vertex types which belong only to this graph.
+func (r *GraphMetadataResponseResult) LocalVertexTypes() []string {
+	names := make([]string, 0)
+	for _, vertexType := range r.VertexTypes {
+		if vertexType.IsLocal {
+			names = append(names, vertexType.Name)
+		}
+	}
+
+	return names
+}
+
+// GlobalEdgeTypes returns the names of the edge types shared with other graphs.
+func (r *GraphMetadataResponseResult) GlobalEdgeTypes() []string {
+	names := make([]string, 0)
+	for _, edgeType := range r.EdgeTypes {
+		if !edgeType.IsLocal {
+			names = append(names, edgeType.Name)
+		}
+	}
+
+	return names
+}
+
+// LocalEdgeTypes returns the names of the edge types which belong only to this graph.
+func (r *GraphMetadataResponseResult) LocalEdgeTypes() []string {
+	names := make([]string, 0)
+	for _, edgeType := range r.EdgeTypes {
+		if edgeType.IsLocal {
+			names = append(names, edgeType.Name)
+		}
+	}
+
+	return names
+}
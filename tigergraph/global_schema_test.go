@@ -0,0 +1,92 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobalSchemaStatements(t *testing.T) {
+	tests := []struct {
+		name          string
+		gsql          string
+		expectedKinds []GSQLStatementKind
+	}{
+		{
+			name: "local schema change job",
+			gsql: `CREATE SCHEMA_CHANGE JOB j FOR GRAPH G {
+    ADD VERTEX Person (PRIMARY_ID id STRING);
+    DROP VERTEX Company;
+}
+RUN SCHEMA_CHANGE JOB j`,
+			expectedKinds: []GSQLStatementKind{},
+		},
+		{
+			name:          "top level vertex and edge types",
+			gsql:          "CREATE VERTEX Person (PRIMARY_ID id STRING)\nDROP EDGE Knows",
+			expectedKinds: []GSQLStatementKind{StatementCreateVertex, StatementDropEdge},
+		},
+		{
+			name: "global schema change job",
+			gsql: `CREATE GLOBAL SCHEMA_CHANGE JOB g {
+    ADD VERTEX Person (PRIMARY_ID id STRING);
+}
+RUN GLOBAL SCHEMA_CHANGE JOB g`,
+			expectedKinds: []GSQLStatementKind{StatementCreateGlobalSchemaJob, StatementRunGlobalSchemaJob},
+		},
+		{
+			name:          "drop all",
+			gsql:          "DROP ALL",
+			expectedKinds: []GSQLStatementKind{StatementDropAll},
+		},
+		{
+			name:          "commented out",
+			gsql:          "// CREATE VERTEX Person (PRIMARY_ID id STRING)\n/* DROP ALL */",
+			expectedKinds: []GSQLStatementKind{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			statements, err := GlobalSchemaStatements(test.gsql)
+			assert.Nil(t, err)
+
+			kinds := make([]GSQLStatementKind, 0, len(statements))
+			for _, statement := range statements {
+				kinds = append(kinds, statement.Kind)
+			}
+			assert.Equal(t, test.expectedKinds, kinds)
+		})
+	}
+}
+
+func TestGraphMetadataLocalAndGlobalTypes(t *testing.T) {
+	metadata := &GraphMetadataResponseResult{
+		VertexTypes: []GraphMetadataVertexType{
+			{Name: "Person", IsLocal: false},
+			{Name: "Draft", IsLocal: true},
+		},
+		EdgeTypes: []GraphMetadataEdgeType{
+			{Name: "Knows", IsLocal: false},
+			{Name: "Edits", IsLocal: true},
+		},
+	}
+
+	assert.Equal(t, []string{"Person"}, metadata.GlobalVertexTypes())
+	assert.Equal(t, []string{"Draft"}, metadata.LocalVertexTypes())
+	assert.Equal(t, []string{"Knows"}, metadata.GlobalEdgeTypes())
+	assert.Equal(t, []string{"Edits"}, metadata.LocalEdgeTypes())
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"net/http"
+)
+
+// GlobalGraph is the graph name under which a token not bound to any graph is requested and
+// cached. Such tokens are needed by admin endpoints, e.g. for users, secrets and backups,
+// which do not belong to a graph.
+const GlobalGraph = ""
+
+// AuthGlobal fetches a token not bound to any graph, as Auth does for a graph. It requires
+// a user with privileges on the global scope, e.g. a superuser.
+func (c *TigerGraphClient) AuthGlobal(ctx context.Context, opts ...RequestOption) error {
+	return c.Auth(ctx, GlobalGraph, opts...)
+}
+
+// ApplyGlobalTokenAuth authenticates a request with a token not bound to any graph.
+func (c *TigerGraphClient) ApplyGlobalTokenAuth(req *http.Request) error {
+	return c.ApplyTokenAuth(req, GlobalGraph)
+}
+
+// GetGlobal makes a GET request to an admin endpoint using a token not bound to any graph,
+// rather than borrowing the token of some graph.
+func (c *TigerGraphClient) GetGlobal(
+	ctx context.Context,
+	queryURL string,
+	result interface{},
+	opts ...RequestOption,
+) error {
+	return c.Get(ctx, queryURL, GlobalGraph, result, opts...)
+}
+
+// PostGlobal makes a POST request to an admin endpoint using a token not bound to any graph,
+// rather than borrowing the token of some graph.
+func (c *TigerGraphClient) PostGlobal(
+	ctx context.Context,
+	queryURL string,
+	body interface{},
+	result interface{},
+	opts ...RequestOption,
+) error {
+	return c.Post(ctx, queryURL, GlobalGraph, body, result, opts...)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"io"
+)
+
+// GraphClient is a TigerGraphClient bound to a single graph, so that calls do not take a graph
+// name. It shares its parent's tokens, transport and configuration, and any number can be
+// created cheaply. Generic helpers such as GetVertices take GraphClient.Client() and
+// GraphClient.Graph().
+type GraphClient struct {
+	client *TigerGraphClient
+	graph  string
+}
+
+// ForGraph returns a GraphClient bound to the given graph.
+func (c *TigerGraphClient) ForGraph(graph string) *GraphClient {
+	return &GraphClient{client: c, graph: graph}
+}
+
+// Graph returns the name of the graph the client is bound to.
+func (g *GraphClient) Graph() string {
+	return g.graph
+}
+
+// Client returns the TigerGraphClient the client was created from.
+func (g *GraphClient) Client() *TigerGraphClient {
+	return g.client
+}
+
+// Get is TigerGraphClient.Get for the bound graph.
+func (g *GraphClient) Get(ctx context.Context, queryURL string, result interface{}, opts ...RequestOption) error {
+	return g.client.Get(ctx, queryURL, g.graph, result, opts...)
+}
+
+// Post is TigerGraphClient.Post for the bound graph.
+func (g *GraphClient) Post(ctx context.Context, queryURL string, body interface{}, result interface{}, opts ...RequestOption) error {
+	return g.client.Post(ctx, queryURL, g.graph, body, result, opts...)
+}
+
+// PostRaw is TigerGraphClient.PostRaw for the bound graph.
+func (g *GraphClient) PostRaw(ctx context.Context, queryURL string, body []byte, result interface{}, opts ...RequestOption) error {
+	return g.client.PostRaw(ctx, queryURL, g.graph, body, result, opts...)
+}
+
+// Upsert is TigerGraphClient.Upsert for the bound graph.
+func (g *GraphClient) Upsert(ctx context.Context, data any, opts ...RequestOption) (*UpsertResponseResult, error) {
+	return g.client.Upsert(ctx, g.graph, data, opts...)
+}
+
+// RunLoadingJobJSONL is TigerGraphClient.RunLoadingJobJSONL for the bound graph.
+func (g *GraphClient) RunLoadingJobJSONL(ctx context.Context, loadingJobName string, lines []any, opts ...RequestOption) error {
+	return g.client.RunLoadingJobJSONL(ctx, g.graph, loadingJobName, lines)
+}
+
+// RunLoadingJobRaw is TigerGraphClient.RunLoadingJobRaw for the bound graph.
+func (g *GraphClient) RunLoadingJobRaw(
+	ctx context.Context,
+	loadingJobName string,
+	body io.Reader,
+	contentType string,
+	opts ...RequestOption,
+) (*LoadingJobStatistics, error) {
+	return g.client.RunLoadingJobRaw(ctx, g.graph, loadingJobName, body, contentType, opts...)
+}
+
+// SoftDeleteVertices is TigerGraphClient.SoftDeleteVertices for the bound graph.
+func (g *GraphClient) SoftDeleteVertices(
+	ctx context.Context,
+	vertexType string,
+	ids []string,
+	opts ...RequestOption,
+) (*UpsertResponseResult, error) {
+	return g.client.SoftDeleteVertices(ctx, g.graph, vertexType, ids, opts...)
+}
+
+// RestoreVertices is TigerGraphClient.RestoreVertices for the bound graph.
+func (g *GraphClient) RestoreVertices(
+	ctx context.Context,
+	vertexType string,
+	ids []string,
+	opts ...RequestOption,
+) (*UpsertResponseResult, error) {
+	return g.client.RestoreVertices(ctx, g.graph, vertexType, ids, opts...)
+}
+
+// IsQueryInstalled is TigerGraphClient.IsQueryInstalled for the bound graph.
+func (g *GraphClient) IsQueryInstalled(ctx context.Context, queryName string) (bool, error) {
+	return g.client.IsQueryInstalled(ctx, g.graph, queryName)
+}
+
+// GetGraphMetadata is TigerGraphClient.GetGraphMetadata for the bound graph.
+func (g *GraphClient) GetGraphMetadata(ctx context.Context) (*GraphMetadataResponse, error) {
+	return g.client.GetGraphMetadata(ctx, g.graph)
+}
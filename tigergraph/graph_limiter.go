@@ -0,0 +1,108 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// GraphLimiter limits the number of concurrent requests made to each graph, so that a burst
+// of heavy queries on one graph cannot use all of a client's connections and starve requests
+// to other graphs.
+//
+// A GraphLimiter is safe to use from multiple goroutines and may be shared between clients.
+type GraphLimiter struct {
+	// Limits maps graph names to the maximum number of concurrent requests to that graph
+	Limits map[string]int
+
+	// DefaultLimit applies to graphs which are not in Limits. Zero means unlimited.
+	DefaultLimit int
+
+	mu         sync.Mutex
+	semaphores map[string]chan struct{}
+}
+
+// NewGraphLimiter creates a GraphLimiter with the given limits.
+func NewGraphLimiter(defaultLimit int, limits map[string]int) *GraphLimiter {
+	return &GraphLimiter{
+		Limits:       limits,
+		DefaultLimit: defaultLimit,
+		semaphores:   make(map[string]chan struct{}),
+	}
+}
+
+func (l *GraphLimiter) semaphore(graph string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if semaphore, ok := l.semaphores[graph]; ok {
+		return semaphore
+	}
+
+	limit, ok := l.Limits[graph]
+	if !ok {
+		limit = l.DefaultLimit
+	}
+
+	var semaphore chan struct{}
+	if limit > 0 {
+		semaphore = make(chan struct{}, limit)
+	}
+
+	if l.semaphores == nil {
+		l.semaphores = make(map[string]chan struct{})
+	}
+	l.semaphores[graph] = semaphore
+
+	return semaphore
+}
+
+// Acquire waits for a free slot for a request to the graph. The returned function must be
+// called to release the slot once the request has completed.
+func (l *GraphLimiter) Acquire(ctx context.Context, graph string) (func(), error) {
+	semaphore := l.semaphore(graph)
+	if semaphore == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case semaphore <- struct{}{}:
+		return func() { <-semaphore }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for a request slot for graph %s: %w", graph, ctx.Err())
+	}
+}
+
+// InFlight returns the number of requests currently being made to the graph.
+func (l *GraphLimiter) InFlight(graph string) int {
+	return len(l.semaphore(graph))
+}
+
+// acquireGraph waits for a request slot from the client's GraphLimiter, if it has one.
+func (c *TigerGraphClient) acquireGraph(ctx context.Context, graph string) (func(), error) {
+	if c.GraphLimiter == nil {
+		return func() {}, nil
+	}
+
+	return c.GraphLimiter.Acquire(ctx, graph)
+}
+
+// WithGraphLimiter sets the GraphLimiter used to limit concurrent requests to each graph.
+func WithGraphLimiter(limiter *GraphLimiter) Option {
+	return func(c *TigerGraphClient) {
+		c.GraphLimiter = limiter
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GSQLCommand makes a request to a form-encoded GSQL server command endpoint, such as
+// those used for getting the server version or listing users, and unmarshals the JSON
+// response into result. The path is relative to the client's BaseFileURL.
+//
+// Cookies set by the GSQL server are kept by the client and sent with subsequent
+// commands, so that commands relying on a GSQL session behave as they would in the
+// GSQL shell. They are kept in the call's or client's GSQLSession if there is one.
+func (c *TigerGraphClient) GSQLCommand(
+	ctx context.Context,
+	path string,
+	form url.Values,
+	result interface{},
+	opts ...RequestOption,
+) error {
+	session := c.gsqlSession(collectRequestOptions(opts))
+	if session == nil {
+		session = &c.commandSession
+	}
+
+	request, err := c.createGSQLServerRequest(ctx, http.MethodPost, path, form.Encode(), session)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRequestFailed, err)
+	}
+
+	defer func() {
+		resp.Body.Close()
+	}()
+
+	session.StoreCookies(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf(
+			"GSQL command came back with non 200 status code. code: %d: %w",
+			resp.StatusCode,
+			statusError(resp.StatusCode),
+		)
+	}
+
+	respBytes, err := readResponseBodyLimited(resp, 0, 0)
+	if err != nil {
+		return ErrBodyReadFailed
+	}
+
+	if err = c.unmarshal(respBytes, result); err != nil {
+		return c.newDecodeError(respBytes, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,386 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrGSQLSyntax represents GSQL which could not be tokenized, such as an unterminated string
+var ErrGSQLSyntax = errors.New("failed to tokenize GSQL")
+
+// GSQLTokenKind is the kind of a GSQL token
+type GSQLTokenKind int
+
+const (
+	// GSQLTokenIdentifier is a keyword or a name
+	GSQLTokenIdentifier GSQLTokenKind = iota
+
+	// GSQLTokenNumber is a numeric literal
+	GSQLTokenNumber
+
+	// GSQLTokenString is a quoted string literal. Its value excludes the quotes.
+	GSQLTokenString
+
+	// GSQLTokenPunctuation is any other single character, such as a brace or semicolon
+	GSQLTokenPunctuation
+)
+
+// GSQLPosition is a location in GSQL source. Line and Column start at 1, Offset at 0.
+type GSQLPosition struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// GSQLToken is a single lexical token of GSQL. Comments and whitespace are not tokens.
+type GSQLToken struct {
+	Kind     GSQLTokenKind
+	Value    string
+	Position GSQLPosition
+}
+
+// GSQLStatementKind classifies a GSQL statement
+type GSQLStatementKind string
+
+// The statement kinds recognised by ClassifyGSQL
+const (
+	StatementCreateGraph           GSQLStatementKind = "CREATE GRAPH"
+	StatementDropGraph             GSQLStatementKind = "DROP GRAPH"
+	StatementUseGraph              GSQLStatementKind = "USE GRAPH"
+	StatementUseGlobal             GSQLStatementKind = "USE GLOBAL"
+	StatementDropAll               GSQLStatementKind = "DROP ALL"
+	StatementCreateVertex          GSQLStatementKind = "CREATE VERTEX"
+	StatementAddVertex             GSQLStatementKind = "ADD VERTEX"
+	StatementDropVertex            GSQLStatementKind = "DROP VERTEX"
+	StatementCreateEdge            GSQLStatementKind = "CREATE EDGE"
+	StatementAddEdge               GSQLStatementKind = "ADD EDGE"
+	StatementDropEdge              GSQLStatementKind = "DROP EDGE"
+	StatementCreateQuery           GSQLStatementKind = "CREATE QUERY"
+	StatementDropQuery             GSQLStatementKind = "DROP QUERY"
+	StatementInstallQuery          GSQLStatementKind = "INSTALL QUERY"
+	StatementRunQuery              GSQLStatementKind = "RUN QUERY"
+	StatementCreateLoadingJob      GSQLStatementKind = "CREATE LOADING JOB"
+	StatementRunLoadingJob         GSQLStatementKind = "RUN LOADING JOB"
+	StatementCreateSchemaChangeJob GSQLStatementKind = "CREATE SCHEMA_CHANGE JOB"
+	StatementRunSchemaChangeJob    GSQLStatementKind = "RUN SCHEMA_CHANGE JOB"
+	StatementCreateGlobalSchemaJob GSQLStatementKind = "CREATE GLOBAL SCHEMA_CHANGE JOB"
+	StatementRunGlobalSchemaJob    GSQLStatementKind = "RUN GLOBAL SCHEMA_CHANGE JOB"
+	StatementDropJob               GSQLStatementKind = "DROP JOB"
+)
+
+// IsDestructive reports whether statements of this kind remove graphs, types or queries.
+func (k GSQLStatementKind) IsDestructive() bool {
+	switch k {
+	case StatementDropGraph, StatementDropAll, StatementDropVertex, StatementDropEdge, StatementDropQuery:
+		return true
+	default:
+		return false
+	}
+}
+
+// GSQLStatement is a statement found by ClassifyGSQL, with the names it refers to.
+type GSQLStatement struct {
+	Kind     GSQLStatementKind
+	Names    []string
+	Position GSQLPosition
+}
+
+// TokenizeGSQL splits GSQL source into tokens, discarding whitespace and comments.
+func TokenizeGSQL(src string) ([]GSQLToken, error) {
+	l := &gsqlLexer{src: []rune(src), line: 1, column: 1}
+	return l.tokenize()
+}
+
+type gsqlLexer struct {
+	src    []rune
+	offset int
+	line   int
+	column int
+}
+
+func (l *gsqlLexer) peek(ahead int) rune {
+	if l.offset+ahead >= len(l.src) {
+		return 0
+	}
+
+	return l.src[l.offset+ahead]
+}
+
+func (l *gsqlLexer) advance() rune {
+	r := l.src[l.offset]
+	l.offset++
+	if r == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+
+	return r
+}
+
+func (l *gsqlLexer) position() GSQLPosition {
+	return GSQLPosition{Offset: l.offset, Line: l.line, Column: l.column}
+}
+
+func (l *gsqlLexer) tokenize() ([]GSQLToken, error) { //nolint:gocyclo
+	tokens := make([]GSQLToken, 0)
+
+	for l.offset < len(l.src) {
+		r := l.peek(0)
+		start := l.position()
+
+		switch {
+		case unicode.IsSpace(r):
+			l.advance()
+		case r == '#' || (r == '/' && l.peek(1) == '/'):
+			for l.offset < len(l.src) && l.peek(0) != '\n' {
+				l.advance()
+			}
+		case r == '/' && l.peek(1) == '*':
+			l.advance()
+			l.advance()
+			for !(l.peek(0) == '*' && l.peek(1) == '/') {
+				if l.offset >= len(l.src) {
+					return nil, fmt.Errorf("unterminated comment at line %d column %d: %w", start.Line, start.Column, ErrGSQLSyntax)
+				}
+				l.advance()
+			}
+			l.advance()
+			l.advance()
+		case r == '"' || r == '\'':
+			value, err := l.readString(r, start)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, GSQLToken{Kind: GSQLTokenString, Value: value, Position: start})
+		case r == '_' || unicode.IsLetter(r):
+			value := l.readWhile(func(r rune) bool { return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) })
+			tokens = append(tokens, GSQLToken{Kind: GSQLTokenIdentifier, Value: value, Position: start})
+		case unicode.IsDigit(r):
+			value := l.readWhile(func(r rune) bool { return r == '.' || unicode.IsDigit(r) })
+			tokens = append(tokens, GSQLToken{Kind: GSQLTokenNumber, Value: value, Position: start})
+		default:
+			tokens = append(tokens, GSQLToken{Kind: GSQLTokenPunctuation, Value: string(l.advance()), Position: start})
+		}
+	}
+
+	return tokens, nil
+}
+
+func (l *gsqlLexer) readWhile(accept func(rune) bool) string {
+	start := l.offset
+	for l.offset < len(l.src) && accept(l.peek(0)) {
+		l.advance()
+	}
+
+	return string(l.src[start:l.offset])
+}
+
+func (l *gsqlLexer) readString(quote rune, start GSQLPosition) (string, error) {
+	l.advance()
+
+	var value strings.Builder
+	for {
+		if l.offset >= len(l.src) {
+			return "", fmt.Errorf("unterminated string at line %d column %d: %w", start.Line, start.Column, ErrGSQLSyntax)
+		}
+
+		r := l.advance()
+		switch {
+		case r == quote:
+			return value.String(), nil
+		case r == '\\' && l.offset < len(l.src):
+			value.WriteRune(l.advance())
+		default:
+			value.WriteRune(r)
+		}
+	}
+}
+
+// ClassifyGSQL finds the statements in GSQL source which create, drop, install or run
+// graphs, types, queries and jobs. It does not validate the GSQL; statements it does not
+// recognise are ignored, and the bodies of queries are skipped.
+func ClassifyGSQL(src string) ([]GSQLStatement, error) {
+	tokens, err := TokenizeGSQL(src)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &gsqlClassifier{tokens: tokens}
+	return c.classify(), nil
+}
+
+type gsqlClassifier struct {
+	tokens []GSQLToken
+	index  int
+}
+
+// keywordsAt reports whether the identifiers starting at index i match the given keywords.
+func (c *gsqlClassifier) keywordsAt(i int, keywords ...string) bool {
+	for j, keyword := range keywords {
+		if i+j >= len(c.tokens) {
+			return false
+		}
+
+		token := c.tokens[i+j]
+		if token.Kind != GSQLTokenIdentifier || !strings.EqualFold(token.Value, keyword) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// skipKeywords moves past any of the given optional keywords, in order.
+func (c *gsqlClassifier) skipKeywords(i int, keywords ...string) int {
+	for _, keyword := range keywords {
+		if c.keywordsAt(i, keyword) {
+			i++
+		}
+	}
+
+	return i
+}
+
+// name returns the identifier at index i, or "" if there is none.
+func (c *gsqlClassifier) name(i int) string {
+	if i < len(c.tokens) && c.tokens[i].Kind == GSQLTokenIdentifier {
+		return c.tokens[i].Value
+	}
+
+	return ""
+}
+
+// nameList reads a comma separated list of names starting at index i, skipping options such as -force.
+func (c *gsqlClassifier) nameList(i int) []string {
+	names := make([]string, 0)
+	for i < len(c.tokens) {
+		token := c.tokens[i]
+		switch {
+		case token.Kind == GSQLTokenPunctuation && token.Value == "-":
+			i += 2
+			continue
+		case token.Kind == GSQLTokenIdentifier || (token.Kind == GSQLTokenPunctuation && token.Value == "*"):
+			names = append(names, token.Value)
+		default:
+			return names
+		}
+
+		if i+1 >= len(c.tokens) || c.tokens[i+1].Value != "," {
+			return names
+		}
+		i += 2
+	}
+
+	return names
+}
+
+// skipBlock moves past the next brace delimited block, starting the search at index i.
+func (c *gsqlClassifier) skipBlock(i int) int {
+	for i < len(c.tokens) && c.tokens[i].Value != "{" {
+		i++
+	}
+
+	depth := 0
+	for ; i < len(c.tokens); i++ {
+		if c.tokens[i].Kind != GSQLTokenPunctuation {
+			continue
+		}
+
+		switch c.tokens[i].Value {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return i
+}
+
+func (c *gsqlClassifier) classify() []GSQLStatement { //nolint:funlen,gocyclo
+	statements := make([]GSQLStatement, 0)
+	add := func(kind GSQLStatementKind, names []string) {
+		statements = append(statements, GSQLStatement{Kind: kind, Names: names, Position: c.tokens[c.index].Position})
+	}
+
+	for c.index < len(c.tokens) {
+		i := c.index
+		switch {
+		case c.keywordsAt(i, "CREATE") || c.keywordsAt(i, "ADD"):
+			verb := strings.ToUpper(c.tokens[i].Value)
+			next := c.skipKeywords(i+1, "OR", "REPLACE", "DISTRIBUTED", "DIRECTED", "UNDIRECTED")
+
+			switch {
+			case verb == "CREATE" && c.keywordsAt(next, "QUERY"):
+				add(StatementCreateQuery, []string{c.name(next + 1)})
+				c.index = c.skipBlock(next + 1)
+				continue
+			case c.keywordsAt(next, "VERTEX"):
+				add(GSQLStatementKind(verb+" VERTEX"), []string{c.name(next + 1)})
+			case c.keywordsAt(next, "EDGE"):
+				add(GSQLStatementKind(verb+" EDGE"), []string{c.name(next + 1)})
+			case verb == "CREATE" && c.keywordsAt(next, "GRAPH"):
+				add(StatementCreateGraph, []string{c.name(next + 1)})
+			case verb == "CREATE" && c.keywordsAt(next, "LOADING", "JOB"):
+				add(StatementCreateLoadingJob, []string{c.name(next + 2)})
+			case verb == "CREATE" && c.keywordsAt(next, "SCHEMA_CHANGE", "JOB"):
+				add(StatementCreateSchemaChangeJob, []string{c.name(next + 2)})
+			case verb == "CREATE" && c.keywordsAt(next, "GLOBAL", "SCHEMA_CHANGE", "JOB"):
+				add(StatementCreateGlobalSchemaJob, []string{c.name(next + 3)})
+			}
+		case c.keywordsAt(i, "DROP"):
+			switch {
+			case c.keywordsAt(i+1, "ALL"):
+				add(StatementDropAll, []string{})
+			case c.keywordsAt(i+1, "GRAPH"):
+				add(StatementDropGraph, c.nameList(i+2))
+			case c.keywordsAt(i+1, "VERTEX"):
+				add(StatementDropVertex, c.nameList(i+2))
+			case c.keywordsAt(i+1, "EDGE"):
+				add(StatementDropEdge, c.nameList(i+2))
+			case c.keywordsAt(i+1, "QUERY"):
+				add(StatementDropQuery, c.nameList(i+2))
+			case c.keywordsAt(i+1, "JOB"):
+				add(StatementDropJob, c.nameList(i+2))
+			}
+		case c.keywordsAt(i, "INSTALL", "QUERY"):
+			add(StatementInstallQuery, c.nameList(i+2))
+		case c.keywordsAt(i, "RUN", "QUERY"):
+			add(StatementRunQuery, []string{c.name(i + 2)})
+		case c.keywordsAt(i, "RUN", "LOADING", "JOB"):
+			add(StatementRunLoadingJob, c.nameList(i+3))
+		case c.keywordsAt(i, "RUN", "SCHEMA_CHANGE", "JOB"):
+			add(StatementRunSchemaChangeJob, []string{c.name(i + 3)})
+		case c.keywordsAt(i, "RUN", "GLOBAL", "SCHEMA_CHANGE", "JOB"):
+			add(StatementRunGlobalSchemaJob, []string{c.name(i + 4)})
+		case c.keywordsAt(i, "USE", "GRAPH"):
+			add(StatementUseGraph, []string{c.name(i + 2)})
+		case c.keywordsAt(i, "USE", "GLOBAL"):
+			add(StatementUseGlobal, []string{})
+		}
+
+		c.index++
+	}
+
+	return statements
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizeGSQL(t *testing.T) {
+	tokens, err := TokenizeGSQL("USE GRAPH G # comment\n/* block\ncomment */ PRINT \"a \\\" b\";")
+	assert.Nil(t, err)
+	assert.Equal(t, []GSQLToken{
+		{Kind: GSQLTokenIdentifier, Value: "USE", Position: GSQLPosition{Offset: 0, Line: 1, Column: 1}},
+		{Kind: GSQLTokenIdentifier, Value: "GRAPH", Position: GSQLPosition{Offset: 4, Line: 1, Column: 5}},
+		{Kind: GSQLTokenIdentifier, Value: "G", Position: GSQLPosition{Offset: 10, Line: 1, Column: 11}},
+		{Kind: GSQLTokenIdentifier, Value: "PRINT", Position: GSQLPosition{Offset: 42, Line: 3, Column: 12}},
+		{Kind: GSQLTokenString, Value: "a \" b", Position: GSQLPosition{Offset: 48, Line: 3, Column: 18}},
+		{Kind: GSQLTokenPunctuation, Value: ";", Position: GSQLPosition{Offset: 56, Line: 3, Column: 26}},
+	}, tokens)
+
+	_, err = TokenizeGSQL(`PRINT "unterminated`)
+	assert.ErrorIs(t, err, ErrGSQLSyntax)
+
+	_, err = TokenizeGSQL(`/* unterminated`)
+	assert.ErrorIs(t, err, ErrGSQLSyntax)
+}
+
+func TestClassifyGSQL(t *testing.T) { //nolint:funlen
+	gsql := `
+CREATE GRAPH Social()
+USE GRAPH Social
+
+CREATE SCHEMA_CHANGE JOB setup FOR GRAPH Social {
+    ADD VERTEX Person (PRIMARY_ID id STRING, name STRING);
+    ADD UNDIRECTED EDGE Knows (FROM Person, TO Person);
+    DROP VERTEX Company, Office;
+}
+RUN SCHEMA_CHANGE JOB setup
+
+CREATE OR REPLACE DISTRIBUTED QUERY friends(VERTEX<Person> p) FOR GRAPH Social {
+    // DROP QUERY inside a comment is ignored
+    result = SELECT t FROM Person:s -(Knows)- Person:t WHERE s == p;
+    PRINT "DROP VERTEX in a string is ignored";
+    IF true THEN PRINT result; END;
+}
+INSTALL QUERY -force friends, others
+RUN LOADING JOB load_people USING f="people.csv"
+DROP QUERY old_query
+DROP JOB setup
+`
+
+	statements, err := ClassifyGSQL(gsql)
+	assert.Nil(t, err)
+
+	type classified struct {
+		Kind  GSQLStatementKind
+		Names []string
+		Line  int
+	}
+
+	result := make([]classified, 0, len(statements))
+	for _, statement := range statements {
+		result = append(result, classified{statement.Kind, statement.Names, statement.Position.Line})
+	}
+
+	assert.Equal(t, []classified{
+		{StatementCreateGraph, []string{"Social"}, 2},
+		{StatementUseGraph, []string{"Social"}, 3},
+		{StatementCreateSchemaChangeJob, []string{"setup"}, 5},
+		{StatementAddVertex, []string{"Person"}, 6},
+		{StatementAddEdge, []string{"Knows"}, 7},
+		{StatementDropVertex, []string{"Company", "Office"}, 8},
+		{StatementRunSchemaChangeJob, []string{"setup"}, 10},
+		{StatementCreateQuery, []string{"friends"}, 12},
+		{StatementInstallQuery, []string{"friends", "others"}, 18},
+		{StatementRunLoadingJob, []string{"load_people"}, 19},
+		{StatementDropQuery, []string{"old_query"}, 20},
+		{StatementDropJob, []string{"setup"}, 21},
+	}, result)
+
+	assert.True(t, StatementDropVertex.IsDestructive())
+	assert.False(t, StatementDropJob.IsDestructive())
+	assert.False(t, StatementCreateQuery.IsDestructive())
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"regexp"
+	"sync"
+)
+
+// useGraphRegexp matches the USE GRAPH and USE GLOBAL statements which change the active graph
+var useGraphRegexp = regexp.MustCompile(`(?im)^\s*USE\s+(?:GRAPH\s+(\w+)|GLOBAL)\s*;?\s*$`)
+
+// GSQLSession keeps the state of a GSQL server session across requests, as the GSQL shell
+// does: the cookies set by the GSQL server, and the active graph. Each GSQL request otherwise
+// starts afresh, so multi-statement workflows split over several RunGSQL calls would have to
+// repeat USE GRAPH in each.
+//
+// A session is used by RunGSQL when set on the client with WithGSQLSession or given to a
+// call with InGSQLSession. RunGSQL runs its GSQL in the session's active graph, and a USE
+// GRAPH or USE GLOBAL statement in the GSQL changes the active graph for the calls which
+// follow. It is safe for concurrent use, though concurrent calls changing the active graph
+// race as they would in a shared GSQL shell.
+type GSQLSession struct {
+	mu    sync.Mutex
+	jar   http.CookieJar
+	graph string
+}
+
+// NewGSQLSession creates a session with no cookies, whose active graph is graph. An empty
+// graph leaves the GSQL server's default in place.
+func NewGSQLSession(graph string) *GSQLSession {
+	return &GSQLSession{graph: graph}
+}
+
+// WithGSQLSession makes RunGSQL and GSQLCommand use the session, and CreateGSQLServerRequest
+// send its cookies.
+func WithGSQLSession(session *GSQLSession) Option {
+	return func(c *TigerGraphClient) {
+		c.GSQLSession = session
+	}
+}
+
+// InGSQLSession runs a single RunGSQL or GSQLCommand call in the session, in place of the
+// client's GSQLSession.
+func InGSQLSession(session *GSQLSession) RequestOption {
+	return func(o *requestOptions) {
+		o.gsqlSession = session
+	}
+}
+
+// Graph returns the session's active graph.
+func (s *GSQLSession) Graph() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.graph
+}
+
+// SetGraph changes the session's active graph. An empty graph leaves the GSQL server's
+// default in place.
+func (s *GSQLSession) SetGraph(graph string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.graph = graph
+}
+
+// Reset clears the session's cookies and active graph, as if it were new.
+func (s *GSQLSession) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jar = nil
+	s.graph = ""
+}
+
+// AddCookies adds the session's cookies for the request's URL to the request, e.g. to one
+// from CreateGSQLServerRequest.
+func (s *GSQLSession) AddCookies(req *http.Request) {
+	jar := s.cookieJar()
+	for _, cookie := range jar.Cookies(req.URL) {
+		req.AddCookie(cookie)
+	}
+}
+
+// StoreCookies keeps the cookies set by a GSQL server response for the requests which follow.
+func (s *GSQLSession) StoreCookies(resp *http.Response) {
+	if resp.Request == nil {
+		return
+	}
+
+	cookies := resp.Cookies()
+	if len(cookies) == 0 {
+		return
+	}
+
+	s.cookieJar().SetCookies(resp.Request.URL, cookies)
+}
+
+// cookieJar returns the session's cookie jar, creating it if needed
+func (s *GSQLSession) cookieJar() http.CookieJar {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.jar == nil {
+		// cookiejar.New only fails for invalid options, and none are given
+		s.jar, _ = cookiejar.New(nil)
+	}
+
+	return s.jar
+}
+
+// inGraph prefixes the GSQL with a USE GRAPH statement for the session's active graph
+func (s *GSQLSession) inGraph(body string) string {
+	graph := s.Graph()
+	if graph == "" {
+		return body
+	}
+
+	return "USE GRAPH " + graph + "\n" + body
+}
+
+// track makes the graph chosen by the last USE GRAPH or USE GLOBAL statement in the GSQL the
+// session's active graph
+func (s *GSQLSession) track(body string) {
+	matches := useGraphRegexp.FindAllStringSubmatch(body, -1)
+	if len(matches) == 0 {
+		return
+	}
+
+	s.SetGraph(matches[len(matches)-1][1])
+}
+
+// gsqlSession returns the session a GSQL server call uses: the call's, or else the client's
+func (c *TigerGraphClient) gsqlSession(options requestOptions) *GSQLSession {
+	if options.gsqlSession != nil {
+		return options.gsqlSession
+	}
+
+	return c.GSQLSession
+}
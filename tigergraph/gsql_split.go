@@ -0,0 +1,194 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrGSQLStatementTooLarge is returned when GSQL cannot be split into parts small enough to
+// send, because a single statement is larger than the maximum body size
+var ErrGSQLStatementTooLarge = errors.New("GSQL statement exceeds the maximum GSQL body size")
+
+// WithMaxGSQLBodySize splits GSQL given to RunGSQL into parts whose request bodies are at
+// most the given number of bytes.
+func WithMaxGSQLBodySize(maxBytes int) Option {
+	return func(c *TigerGraphClient) {
+		c.MaxGSQLBodySize = maxBytes
+	}
+}
+
+// gsqlPart is a part of a larger GSQL body which can be run on its own.
+type gsqlPart struct {
+	body string
+
+	// line is where the part starts in the original GSQL
+	line int
+}
+
+// SplitGSQL splits GSQL into parts whose escaped request bodies are at most maxBytes, so that
+// they can be run one after another. Parts end on the boundaries of the statements found by
+// ClassifyGSQL, never inside a BEGIN ... END block or a braced job or query body. Each part
+// after the first starts with the latest USE GRAPH statement, unless a USE GLOBAL statement
+// came after it, as every request to the GSQL server starts a new session in the global scope.
+func SplitGSQL(src string, maxBytes int) ([]string, error) {
+	parts, err := splitGSQL(src, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	bodies := make([]string, len(parts))
+	for i, part := range parts {
+		bodies[i] = part.body
+	}
+
+	return bodies, nil
+}
+
+func splitGSQL(src string, maxBytes int) ([]gsqlPart, error) { //nolint:gocyclo
+	if maxBytes <= 0 || len(url.QueryEscape(src)) <= maxBytes {
+		return []gsqlPart{{body: src, line: 1}}, nil
+	}
+
+	tokens, err := TokenizeGSQL(src)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := ClassifyGSQL(src)
+	if err != nil {
+		return nil, err
+	}
+
+	statementStarts := make(map[int]GSQLStatementKind, len(statements))
+	for _, statement := range statements {
+		statementStarts[statement.Position.Offset] = statement.Kind
+	}
+
+	// Find the statements which can start a part, i.e. those outside of any block
+	runes := []rune(src)
+	type boundary struct {
+		offset    int
+		line      int
+		useEnd    int
+		useGlobal bool
+	}
+	boundaries := []boundary{{offset: 0, line: 1, useEnd: -1}}
+
+	depth := 0
+	inBlock := false
+	for i, token := range tokens {
+		if token.Kind == GSQLTokenPunctuation {
+			switch token.Value {
+			case "{":
+				depth++
+			case "}":
+				depth--
+			}
+			continue
+		}
+
+		if depth > 0 || token.Kind != GSQLTokenIdentifier {
+			continue
+		}
+
+		switch {
+		case strings.EqualFold(token.Value, "BEGIN") && !inBlock:
+			inBlock = true
+			boundaries = append(boundaries, boundary{offset: token.Position.Offset, line: token.Position.Line, useEnd: -1})
+		case strings.EqualFold(token.Value, "END") && inBlock:
+			inBlock = false
+		case !inBlock:
+			kind, isStatement := statementStarts[token.Position.Offset]
+			if !isStatement {
+				continue
+			}
+
+			b := boundary{offset: token.Position.Offset, line: token.Position.Line, useEnd: -1}
+			if kind == StatementUseGraph && i+2 < len(tokens) {
+				// USE GRAPH <name>
+				b.useEnd = tokens[i+2].Position.Offset + len([]rune(tokens[i+2].Value))
+			}
+			b.useGlobal = kind == StatementUseGlobal
+			boundaries = append(boundaries, b)
+		}
+	}
+
+	// Cut the source into segments between boundaries, remembering the USE GRAPH in effect
+	type segment struct {
+		text     string
+		line     int
+		useGraph string
+	}
+	segments := make([]segment, 0, len(boundaries))
+	useGraph := ""
+	for i, b := range boundaries {
+		end := len(runes)
+		if i+1 < len(boundaries) {
+			end = boundaries[i+1].offset
+		}
+		if end <= b.offset {
+			continue
+		}
+
+		segments = append(segments, segment{text: string(runes[b.offset:end]), line: b.line, useGraph: useGraph})
+		switch {
+		case b.useEnd >= 0:
+			useGraph = string(runes[b.offset:b.useEnd])
+		case b.useGlobal:
+			useGraph = ""
+		}
+	}
+
+	// Pack segments into parts no larger than maxBytes
+	parts := make([]gsqlPart, 0)
+	var current strings.Builder
+	currentLine := 0
+	for _, s := range segments {
+		if current.Len() > 0 && len(url.QueryEscape(current.String()+s.text)) <= maxBytes {
+			current.WriteString(s.text)
+			continue
+		}
+
+		if current.Len() > 0 {
+			parts = append(parts, gsqlPart{body: current.String(), line: currentLine})
+			current.Reset()
+		}
+
+		if s.useGraph != "" && len(parts) > 0 && !strings.HasPrefix(s.text, s.useGraph) {
+			current.WriteString(s.useGraph + "\n")
+		}
+		current.WriteString(s.text)
+		currentLine = s.line
+
+		if size := len(url.QueryEscape(current.String())); size > maxBytes {
+			return nil, fmt.Errorf(
+				"statement at line %d is %d bytes, maximum is %d bytes: %w",
+				s.line,
+				size,
+				maxBytes,
+				ErrGSQLStatementTooLarge,
+			)
+		}
+	}
+
+	if current.Len() > 0 {
+		parts = append(parts, gsqlPart{body: current.String(), line: currentLine})
+	}
+
+	return parts, nil
+}
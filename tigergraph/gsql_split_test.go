@@ -0,0 +1,94 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitGSQL(t *testing.T) { //nolint:funlen
+	gsql := `USE GRAPH Social
+CREATE SCHEMA_CHANGE JOB setup FOR GRAPH Social {
+    ADD VERTEX Person (PRIMARY_ID id STRING, name STRING);
+    ADD VERTEX Company (PRIMARY_ID id STRING, name STRING);
+}
+RUN SCHEMA_CHANGE JOB setup
+BEGIN
+CREATE QUERY first() FOR GRAPH Social {
+  PRINT "first";
+}
+END
+BEGIN
+CREATE QUERY second() FOR GRAPH Social {
+  PRINT "second";
+}
+END
+INSTALL QUERY first, second
+`
+
+	t.Run("small GSQL is not split", func(t *testing.T) {
+		parts, err := SplitGSQL(gsql, len(url.QueryEscape(gsql)))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{gsql}, parts)
+
+		parts, err = SplitGSQL(gsql, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{gsql}, parts)
+	})
+
+	t.Run("splits on statement boundaries", func(t *testing.T) {
+		maxBytes := 250
+		parts, err := SplitGSQL(gsql, maxBytes)
+		assert.Nil(t, err)
+		assert.Greater(t, len(parts), 1)
+
+		for i, part := range parts {
+			assert.LessOrEqual(t, len(url.QueryEscape(part)), maxBytes)
+			assert.True(t, strings.HasPrefix(part, "USE GRAPH Social"), "part %d: %s", i, part)
+			assert.Equal(t, strings.Count(part, "{"), strings.Count(part, "}"), "part %d: %s", i, part)
+			assert.Equal(t, strings.Count(part, "BEGIN"), strings.Count(part, "END"), "part %d: %s", i, part)
+		}
+
+		// Removing the repeated USE GRAPH statements gives back the original GSQL
+		joined := parts[0]
+		for _, part := range parts[1:] {
+			joined += strings.TrimPrefix(part, "USE GRAPH Social\n")
+		}
+		assert.Equal(t, gsql, joined)
+	})
+
+	t.Run("statements larger than the maximum", func(t *testing.T) {
+		_, err := SplitGSQL(gsql, 50)
+		assert.ErrorIs(t, err, ErrGSQLStatementTooLarge)
+	})
+
+	t.Run("USE GLOBAL ends the USE GRAPH scope", func(t *testing.T) {
+		parts, err := SplitGSQL("USE GRAPH g\nDROP QUERY q\nUSE GLOBAL\nCREATE VERTEX Person (PRIMARY_ID id STRING)", 80)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"USE GRAPH g\nDROP QUERY q\nUSE GLOBAL\n",
+			"CREATE VERTEX Person (PRIMARY_ID id STRING)",
+		}, parts)
+	})
+
+	t.Run("too large statements report the size of their part", func(t *testing.T) {
+		_, err := SplitGSQL("USE GRAPH Social\nDROP QUERY first\nDROP QUERY a_query_with_a_long_name", 40)
+		assert.ErrorIs(t, err, ErrGSQLStatementTooLarge)
+		assert.Contains(t, err.Error(), "is 54 bytes, maximum is 40 bytes")
+	})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ReplicaHeader asks RESTPP to run a query on the given replica of a replicated cluster.
+const ReplicaHeader = "GSQL-REPLICA"
+
+// WithHeader adds a header sent with every request, e.g. a tracing or gateway routing header.
+// It can be overridden per call with WithRequestHeader.
+func WithHeader(key string, value string) Option {
+	return func(c *TigerGraphClient) {
+		if c.Headers == nil {
+			c.Headers = http.Header{}
+		}
+		c.Headers.Add(key, value)
+	}
+}
+
+// WithRequestHeader sets a header for a single call, replacing any default header with the
+// same name, e.g. GSQL-TIMEOUT or RESPONSE-LIMIT.
+func WithRequestHeader(key string, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.headers == nil {
+			o.headers = http.Header{}
+		}
+		o.headers.Add(key, value)
+	}
+}
+
+// WithGSQLReplica runs a single call on the given replica of a replicated cluster, by sending
+// it in the GSQL-REPLICA header, e.g. to measure how placement affects query performance.
+func WithGSQLReplica(replica int) RequestOption {
+	return WithRequestHeader(ReplicaHeader, strconv.Itoa(replica))
+}
+
+// setRequestHeaders sets the headers given to a single call on req.
+func setRequestHeaders(req *http.Request, options requestOptions) {
+	for key, values := range options.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+}
+
+// setDefaultHeaders sets the client's default headers on req, without replacing headers
+// set by the library or for the call.
+func (c *TigerGraphClient) setDefaultHeaders(req *http.Request) {
+	for key, values := range c.Headers {
+		key = http.CanonicalHeaderKey(key)
+		if _, exists := req.Header[key]; exists {
+			continue
+		}
+		req.Header[key] = append([]string(nil), values...)
+	}
+}
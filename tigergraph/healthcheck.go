@@ -0,0 +1,164 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// GSQLVersionURL is the GSQL server URL used to check that the GSQL server is up
+const GSQLVersionURL = "/gsqlserver/gsql/version"
+
+// HealthStatus is the outcome of a single health check
+type HealthStatus string
+
+const (
+	// HealthOK is the status of a check that passed
+	HealthOK HealthStatus = "ok"
+	// HealthFailed is the status of a check that failed
+	HealthFailed HealthStatus = "failed"
+)
+
+// HealthCheck is the result of one check made by Healthcheck
+type HealthCheck struct {
+	Name    string        `json:"name"`
+	Status  HealthStatus  `json:"status"`
+	Latency time.Duration `json:"latency"`
+	Error   string        `json:"error,omitempty"`
+
+	// Err is the error that caused the check to fail, if any
+	Err error `json:"-"`
+}
+
+// HealthReport is the structured result of Healthcheck, suitable for exposing
+// from a service health endpoint.
+type HealthReport struct {
+	Healthy   bool          `json:"healthy"`
+	CheckedAt time.Time     `json:"checkedAt"`
+	Checks    []HealthCheck `json:"checks"`
+}
+
+// Failed returns the checks in the report that did not pass.
+func (r *HealthReport) Failed() []HealthCheck {
+	var result []HealthCheck
+	for _, check := range r.Checks {
+		if check.Status != HealthOK {
+			result = append(result, check)
+		}
+	}
+
+	return result
+}
+
+// Healthcheck checks that TigerGraph can be used by this client. It pings the server,
+// checks that the GSQL server responds to authenticated requests and, for each of the
+// given graphs, that a new token can be acquired. Every check is run even if an earlier one
+// fails, so the report describes the state of each component.
+func (c *TigerGraphClient) Healthcheck(ctx context.Context, graphs ...string) *HealthReport {
+	report := &HealthReport{
+		Healthy:   true,
+		CheckedAt: c.now(),
+	}
+
+	run := func(name string, check func() error) {
+		start := time.Now()
+		err := check()
+
+		result := HealthCheck{
+			Name:    name,
+			Status:  HealthOK,
+			Latency: time.Since(start),
+		}
+
+		if err != nil {
+			result.Status = HealthFailed
+			result.Error = err.Error()
+			result.Err = err
+			report.Healthy = false
+		}
+
+		report.Checks = append(report.Checks, result)
+	}
+
+	run("ping", func() error {
+		_, err := c.Ping(ctx)
+		return err
+	})
+
+	run("gsql", func() error {
+		return c.checkGSQLServer(ctx)
+	})
+
+	for _, graph := range graphs {
+		graph := graph
+		run("token "+graph, func() error {
+			return c.checkToken(ctx, graph)
+		})
+	}
+
+	return report
+}
+
+// checkToken requests a new token for the graph rather than reading one from the cache or the
+// TokenStore, so that the check shows whether tokens can be acquired now. The new token
+// replaces the graph's cached token.
+func (c *TigerGraphClient) checkToken(ctx context.Context, graph string) error {
+	if token, err := c.staticToken(graph); token != nil || err != nil {
+		return err
+	}
+
+	var token *Token
+	var err error
+	if c.TokenProvider != nil {
+		token, err = c.providedToken(ctx, graph)
+	} else {
+		token, err = c.requestToken(ctx, graph, c.TokenLifetime)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.Tokens.Set(graph, token)
+
+	return nil
+}
+
+func (c *TigerGraphClient) checkGSQLServer(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseFileURL+GSQLVersionURL, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = c.applyGSQLAuth(request); err != nil {
+		return err
+	}
+
+	resp, err := c.do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRequestFailed, err)
+	}
+
+	defer func() {
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GSQL server came back with non 200 status code. code: %d: %w", resp.StatusCode, statusError(resp.StatusCode))
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"io"
+)
+
+// Querier runs installed queries and reads from graphs. Code which only reads can accept a
+// Querier rather than a *TigerGraphClient, so that it can be given a fake in tests.
+type Querier interface {
+	Get(ctx context.Context, queryURL string, graph string, result interface{}, opts ...RequestOption) error
+	Post(ctx context.Context, queryURL string, graph string, body interface{}, result interface{}, opts ...RequestOption) error
+	PostRaw(ctx context.Context, queryURL string, graph string, body []byte, result interface{}, opts ...RequestOption) error
+	GetGraphMetadata(ctx context.Context, graphName string) (*GraphMetadataResponse, error)
+	GetVertexDegree(
+		ctx context.Context,
+		graphName string,
+		vertexType string,
+		id string,
+		edgeType string,
+		direction EdgeDirection,
+		opts ...RequestOption,
+	) (int, error)
+	GetVertexDegrees(
+		ctx context.Context,
+		graphName string,
+		vertexType string,
+		ids []string,
+		edgeType string,
+		direction EdgeDirection,
+		opts ...RequestOption,
+	) (map[string]int, error)
+	EdgeExists(
+		ctx context.Context,
+		graphName string,
+		sourceType string,
+		sourceID string,
+		edgeType string,
+		targetType string,
+		targetID string,
+		opts ...RequestOption,
+	) (bool, error)
+	ActiveQueryName(ctx context.Context, graph string, alias string) (string, error)
+}
+
+// Loader writes vertices and edges to graphs, with upserts and loading jobs.
+type Loader interface {
+	Upsert(ctx context.Context, graphName string, data any, opts ...RequestOption) (*UpsertResponseResult, error)
+	RunLoadingJobJSONL(ctx context.Context, graphName string, loadingJobName string, lines []any, opts ...RequestOption) error
+	RunLoadingJobRaw(
+		ctx context.Context,
+		graphName string,
+		loadingJobName string,
+		body io.Reader,
+		contentType string,
+		opts ...RequestOption,
+	) (*LoadingJobStatistics, error)
+	SoftDeleteVertices(
+		ctx context.Context,
+		graphName string,
+		vertexType string,
+		ids []string,
+		opts ...RequestOption,
+	) (*UpsertResponseResult, error)
+	RestoreVertices(
+		ctx context.Context,
+		graphName string,
+		vertexType string,
+		ids []string,
+		opts ...RequestOption,
+	) (*UpsertResponseResult, error)
+	BufferedUpsert(ctx context.Context, graphName string, key string, data any) (bool, error)
+	BufferedRunLoadingJobJSONL(ctx context.Context, graphName string, loadingJobName string, key string, lines []any) (bool, error)
+}
+
+// Migrator applies and tracks schema migrations.
+type Migrator interface {
+	Migrate(ctx context.Context, graph string, version string, initVersion string, migrationFileDir string, dryRun bool) error
+	ResumeMigration(ctx context.Context) error
+	AbandonMigration(ctx context.Context, graph string) error
+	CheckIsInitialised(ctx context.Context) (bool, error)
+	GetCurrentMigrationNumber(ctx context.Context, graph string) (string, error)
+	DiffPendingMigrations(migrationFileDir string, fromVersion string, toVersion string) (*MigrationDiff, error)
+	ApplyMigrationSeeds(ctx context.Context, graph string, migrationFileDir string) error
+}
+
+// Admin runs GSQL, manages installed queries and tokens, and checks the health of the server.
+type Admin interface {
+	RunGSQL(ctx context.Context, body string, opts ...RequestOption) error
+	IsQueryInstalled(ctx context.Context, graph string, queryName string) (bool, error)
+	ReleaseQuery(ctx context.Context, release QueryRelease) (*QueryReleaseResult, error)
+	Auth(ctx context.Context, graph string, opts ...RequestOption) error
+	AuthGlobal(ctx context.Context, opts ...RequestOption) error
+	RevokeToken(ctx context.Context, graph string) error
+	GetGlobal(ctx context.Context, queryURL string, result interface{}, opts ...RequestOption) error
+	PostGlobal(ctx context.Context, queryURL string, body interface{}, result interface{}, opts ...RequestOption) error
+	Ping(ctx context.Context, opts ...RequestOption) (*PingResult, error)
+	Healthcheck(ctx context.Context, graphs ...string) *HealthReport
+	Close(ctx context.Context) error
+}
+
+// Client is every capability of the TigerGraphClient.
+type Client interface {
+	Querier
+	Loader
+	Migrator
+	Admin
+}
+
+var _ Client = (*TigerGraphClient)(nil)
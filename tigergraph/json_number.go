@@ -0,0 +1,105 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNotANumber represents a value that cannot be converted to the requested number type
+var ErrNotANumber = errors.New("value is not a number of the requested type")
+
+// jsonNumberUser is implemented by results which decode parts of a response themselves, e.g.
+// MultiResult, so that the client can tell them whether UseJSONNumber is set before decoding
+type jsonNumberUser interface {
+	useJSONNumber(use bool)
+}
+
+// unmarshal decodes JSON from TigerGraph. If UseJSONNumber is set on the client, numbers
+// decoded into interface{} values are json.Number rather than float64, so that INT64
+// attributes keep their precision.
+func (c *TigerGraphClient) unmarshal(data []byte, result interface{}) error {
+	if user, ok := result.(jsonNumberUser); ok {
+		user.useJSONNumber(c.UseJSONNumber)
+	}
+
+	return decodeJSON(data, result, c.UseJSONNumber)
+}
+
+// decodeJSON decodes data into target. If useNumber is set, numbers decoded into interface{}
+// values are json.Number rather than float64.
+func decodeJSON(data []byte, target any, useNumber bool) error {
+	if !useNumber {
+		return json.Unmarshal(data, target)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decoder.Decode(target)
+}
+
+// ToInt64 converts a number decoded from a TigerGraph response into an int64. It accepts
+// json.Number, as produced when UseJSONNumber is set, as well as float64 values holding
+// whole numbers and Go integer types.
+func ToInt64(value any) (int64, error) {
+	switch v := value.(type) {
+	case json.Number:
+		result, err := v.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", err, ErrNotANumber)
+		}
+		return result, nil
+	case float64:
+		if v != math.Trunc(v) || v >= 1<<63 || v < math.MinInt64 {
+			return 0, fmt.Errorf("%v cannot be represented as an int64: %w", v, ErrNotANumber)
+		}
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int32:
+		return int64(v), nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T: %w", value, ErrNotANumber)
+	}
+}
+
+// ToFloat64 converts a number decoded from a TigerGraph response into a float64. It accepts
+// json.Number, float64 and Go integer types.
+func ToFloat64(value any) (float64, error) {
+	switch v := value.(type) {
+	case json.Number:
+		result, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", err, ErrNotANumber)
+		}
+		return result, nil
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T: %w", value, ErrNotANumber)
+	}
+}
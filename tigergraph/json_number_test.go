@@ -0,0 +1,117 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToInt64(t *testing.T) {
+	cases := []struct {
+		name          string
+		value         any
+		expected      int64
+		expectedError error
+	}{
+		{name: "json number", value: json.Number("9007199254740993"), expected: 9007199254740993},
+		{name: "whole float", value: float64(42), expected: 42},
+		{name: "int", value: 7, expected: 7},
+		{name: "fractional float", value: 1.5, expectedError: ErrNotANumber},
+		{name: "float of 2^63", value: float64(9223372036854775808), expectedError: ErrNotANumber},
+		{name: "float of -2^63", value: float64(-9223372036854775808), expected: math.MinInt64},
+		{name: "fractional json number", value: json.Number("1.5"), expectedError: ErrNotANumber},
+		{name: "string", value: "42", expectedError: ErrNotANumber},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := ToInt64(testCase.value)
+			assert.ErrorIs(t, err, testCase.expectedError)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+}
+
+func TestUnmarshalUseJSONNumber(t *testing.T) {
+	data := []byte(`{"id": 9007199254740993}`)
+
+	client := &TigerGraphClient{}
+	var lossy map[string]any
+	assert.Nil(t, client.unmarshal(data, &lossy))
+	lossyID, err := ToInt64(lossy["id"])
+	assert.Nil(t, err)
+	assert.NotEqual(t, int64(9007199254740993), lossyID)
+
+	client.UseJSONNumber = true
+	var precise map[string]any
+	assert.Nil(t, client.unmarshal(data, &precise))
+	preciseID, err := ToInt64(precise["id"])
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740993), preciseID)
+}
+
+func TestUnmarshalUseJSONNumberNested(t *testing.T) {
+	data := []byte(`{"error": false, "message": "", "results": [{"@@id": 9007199254740993}]}`)
+	client := &TigerGraphClient{UseJSONNumber: true}
+
+	t.Run("multi result", func(t *testing.T) {
+		var id any
+		assert.Nil(t, client.unmarshal(data, NewMultiResult().Register("@@id", &id)))
+		assert.Equal(t, json.Number("9007199254740993"), id)
+
+		var lossy any
+		assert.Nil(t, (&TigerGraphClient{}).unmarshal(data, NewMultiResult().Register("@@id", &lossy)))
+		assert.Equal(t, float64(9007199254740993), lossy)
+	})
+
+	t.Run("named results", func(t *testing.T) {
+		var response NamedResultsResponse
+		assert.Nil(t, client.unmarshal(data, &response))
+
+		id, err := DecodeNamedResult[any](response.Results, "@@id")
+		assert.Nil(t, err)
+		assert.Equal(t, json.Number("9007199254740993"), id)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		var id Null[any]
+		assert.Nil(t, json.Unmarshal([]byte("9007199254740993"), &id))
+		assert.Equal(t, NewNull[any](json.Number("9007199254740993")), id)
+	})
+
+	t.Run("attributes", func(t *testing.T) {
+		vertexType := &GraphMetadataVertexType{
+			Attributes: []GraphMetadataAttribute{
+				{AttributeName: "id", AttributeType: GraphMetadataAttributeType{Name: "INT"}},
+			},
+		}
+
+		var attributes map[string]any
+		assert.Nil(t, client.DecodeAttributes([]byte(`{"id": 9007199254740993}`), vertexType, &attributes))
+		assert.Equal(t, map[string]any{"id": json.Number("9007199254740993")}, attributes)
+
+		// An INT64 one away from the default is not mistaken for it
+		unset := "9007199254740992"
+		vertexType.Attributes[0].DefaultValue = &unset
+		var id struct {
+			ID Null[int64] `json:"id"`
+		}
+		assert.Nil(t, client.DecodeAttributes([]byte(`{"id": 9007199254740993}`), vertexType, &id))
+		assert.Equal(t, NewNull[int64](9007199254740993), id.ID)
+	})
+}
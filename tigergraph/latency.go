@@ -0,0 +1,161 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// EchoURL is the RESTPP URL which echoes a fixed response, used for measuring latency
+const EchoURL = "/echo"
+
+// ErrInvalidSampleCount represents a request to measure latency with fewer than one sample
+var ErrInvalidSampleCount = errors.New("at least one sample is required to measure latency")
+
+// EchoResponse is the response body from the echo endpoint
+type EchoResponse struct {
+	Error   bool   `json:"error"`
+	Message string `json:"message"`
+}
+
+// LatencyStats summarises a set of round trip times to TigerGraph.
+type LatencyStats struct {
+	Samples int
+	Min     time.Duration
+	Max     time.Duration
+	Mean    time.Duration
+
+	// Jitter is the mean absolute difference between consecutive round trip times.
+	Jitter time.Duration
+
+	MeasuredAt time.Time
+}
+
+// MeasureLatency estimates the round trip time to TigerGraph by making the given number
+// of sequential requests to the echo endpoint. Each request is sent once with the client's
+// HTTP client, after its token has been applied, so that only the round trip is timed and
+// not token requests, retries or backoff. The result is also kept on the client and can be
+// read again with LastLatency.
+func (c *TigerGraphClient) MeasureLatency(ctx context.Context, samples int) (*LatencyStats, error) {
+	if samples < 1 {
+		return nil, ErrInvalidSampleCount
+	}
+
+	if c.optionErr != nil {
+		return nil, c.optionErr
+	}
+
+	rtts := make([]time.Duration, 0, samples)
+	for i := 0; i < samples; i++ {
+		rtt, err := c.measureEcho(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		rtts = append(rtts, rtt)
+	}
+
+	stats := summariseLatency(rtts)
+	c.lastLatency.Store(stats)
+
+	return stats, nil
+}
+
+// measureEcho returns the round trip time of a single request to the echo endpoint
+func (c *TigerGraphClient) measureEcho(ctx context.Context) (time.Duration, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+EchoURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = c.ApplyGlobalTokenAuth(request); err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient().Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrRequestFailed, err)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPingResponseSize))
+	rtt := time.Since(start)
+	resp.Body.Close()
+
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrBodyReadFailed, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("echo came back with non 200 status code. code: %d: %w", resp.StatusCode, statusError(resp.StatusCode))
+	}
+
+	var response EchoResponse
+	if err = json.Unmarshal(body, &response); err != nil {
+		return 0, c.newDecodeError(body, err)
+	}
+
+	if response.Error {
+		return 0, fmt.Errorf("echo request failed. message: %s: %w", response.Message, ErrTigerGraphError)
+	}
+
+	return rtt, nil
+}
+
+// LastLatency returns the result of the most recent successful call to MeasureLatency,
+// or nil if latency has not been measured yet.
+func (c *TigerGraphClient) LastLatency() *LatencyStats {
+	return c.lastLatency.Load()
+}
+
+func summariseLatency(rtts []time.Duration) *LatencyStats {
+	stats := &LatencyStats{
+		Samples:    len(rtts),
+		Min:        rtts[0],
+		Max:        rtts[0],
+		MeasuredAt: time.Now(),
+	}
+
+	var total, totalDiff time.Duration
+	for i, rtt := range rtts {
+		total += rtt
+		if rtt < stats.Min {
+			stats.Min = rtt
+		}
+		if rtt > stats.Max {
+			stats.Max = rtt
+		}
+
+		if i > 0 {
+			diff := rtt - rtts[i-1]
+			if diff < 0 {
+				diff = -diff
+			}
+			totalDiff += diff
+		}
+	}
+
+	stats.Mean = total / time.Duration(len(rtts))
+	if len(rtts) > 1 {
+		stats.Jitter = totalDiff / time.Duration(len(rtts)-1)
+	}
+
+	return stats
+}
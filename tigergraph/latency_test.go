@@ -0,0 +1,60 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummariseLatency(t *testing.T) {
+	cases := []struct {
+		name     string
+		rtts     []time.Duration
+		expected LatencyStats
+	}{
+		{
+			name: "single sample has no jitter",
+			rtts: []time.Duration{10 * time.Millisecond},
+			expected: LatencyStats{
+				Samples: 1,
+				Min:     10 * time.Millisecond,
+				Max:     10 * time.Millisecond,
+				Mean:    10 * time.Millisecond,
+				Jitter:  0,
+			},
+		},
+		{
+			name: "multiple samples",
+			rtts: []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 12 * time.Millisecond},
+			expected: LatencyStats{
+				Samples: 3,
+				Min:     10 * time.Millisecond,
+				Max:     20 * time.Millisecond,
+				Mean:    14 * time.Millisecond,
+				Jitter:  9 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			stats := summariseLatency(testCase.rtts)
+			stats.MeasuredAt = time.Time{}
+			assert.Equal(t, testCase.expected, *stats)
+		})
+	}
+}
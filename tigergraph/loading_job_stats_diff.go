@@ -0,0 +1,215 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CounterDelta is the change in one loading job statistics counter between two runs.
+type CounterDelta struct {
+	Name     string
+	Previous int
+	Current  int
+
+	// Failure is true for counters of rejected lines or objects, and false for valid ones
+	Failure bool
+}
+
+// Change returns the difference between the current and previous counts.
+func (d CounterDelta) Change() int {
+	return d.Current - d.Previous
+}
+
+// PercentChange returns the change as a percentage of the previous count. It is false if the
+// previous count was zero, as the change has no percentage.
+func (d CounterDelta) PercentChange() (float64, bool) {
+	if d.Previous == 0 {
+		return 0, false
+	}
+
+	return float64(d.Change()) / float64(d.Previous) * 100, true
+}
+
+// String describes the change, e.g. "rejectLine up 400% (10 -> 50)".
+func (d CounterDelta) String() string {
+	if d.Change() == 0 {
+		return fmt.Sprintf("%s unchanged (%d)", d.Name, d.Current)
+	}
+
+	percent, ok := d.PercentChange()
+	if !ok {
+		return fmt.Sprintf("%s up from 0 (0 -> %d)", d.Name, d.Current)
+	}
+
+	direction := "up"
+	if percent < 0 {
+		direction, percent = "down", -percent
+	}
+
+	return fmt.Sprintf("%s %s %.0f%% (%d -> %d)", d.Name, direction, percent, d.Previous, d.Current)
+}
+
+// ObjectStatisticsDiff is the change in the statistics of one vertex or edge type.
+type ObjectStatisticsDiff struct {
+	// Kind is "vertex" or "edge"
+	Kind     string
+	TypeName string
+	Counters []CounterDelta
+}
+
+// LoadingJobStatisticsDiff is the change in loading job statistics between two runs, e.g.
+// yesterday's and today's scheduled load. Counters which are zero in both runs are left out.
+type LoadingJobStatisticsDiff struct {
+	Lines   []CounterDelta
+	Objects []ObjectStatisticsDiff
+}
+
+// DiffLoadingJobStatistics compares the statistics of two runs of a loading job. Types loaded
+// in only one of the runs count as zero in the other.
+func DiffLoadingJobStatistics(previous *LoadingJobStatistics, current *LoadingJobStatistics) *LoadingJobStatisticsDiff {
+	diff := &LoadingJobStatisticsDiff{
+		Lines: diffCounters(
+			append([]namedCounter{{"validLine", previous.ValidLine}}, previous.lineFailureCounters()...),
+			append([]namedCounter{{"validLine", current.ValidLine}}, current.lineFailureCounters()...),
+		),
+		Objects: make([]ObjectStatisticsDiff, 0),
+	}
+
+	for _, group := range []struct {
+		kind     string
+		previous []LoadingJobObjectResult
+		current  []LoadingJobObjectResult
+	}{
+		{"vertex", previous.Vertex, current.Vertex},
+		{"edge", previous.Edge, current.Edge},
+	} {
+		for _, typeName := range objectTypeNames(group.previous, group.current) {
+			previousResult, _ := findObjectResult(group.previous, typeName)
+			currentResult, _ := findObjectResult(group.current, typeName)
+
+			counters := diffCounters(objectCounters(previousResult), objectCounters(currentResult))
+			if len(counters) > 0 {
+				diff.Objects = append(diff.Objects, ObjectStatisticsDiff{
+					Kind:     group.kind,
+					TypeName: typeName,
+					Counters: counters,
+				})
+			}
+		}
+	}
+
+	return diff
+}
+
+// FailuresIncreased reports whether any failure counter is higher than in the previous run.
+func (d *LoadingJobStatisticsDiff) FailuresIncreased() bool {
+	for _, counter := range d.allCounters() {
+		if counter.Failure && counter.Change() > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Summary describes every counter which changed, grouped by vertex and edge type, e.g.
+// "rejectLine up 400% (10 -> 50); vertex Person: invalidAttribute up from 0 (0 -> 3)". It is
+// "no change" if no counter changed.
+func (d *LoadingJobStatisticsDiff) Summary() string {
+	parts := make([]string, 0)
+
+	if lines := describeChanges(d.Lines); lines != "" {
+		parts = append(parts, lines)
+	}
+	for _, object := range d.Objects {
+		if changes := describeChanges(object.Counters); changes != "" {
+			parts = append(parts, fmt.Sprintf("%s %s: %s", object.Kind, object.TypeName, changes))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "no change"
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func (d *LoadingJobStatisticsDiff) allCounters() []CounterDelta {
+	counters := append([]CounterDelta{}, d.Lines...)
+	for _, object := range d.Objects {
+		counters = append(counters, object.Counters...)
+	}
+
+	return counters
+}
+
+func describeChanges(counters []CounterDelta) string {
+	changes := make([]string, 0)
+	for _, counter := range counters {
+		if counter.Change() != 0 {
+			changes = append(changes, counter.String())
+		}
+	}
+
+	return strings.Join(changes, ", ")
+}
+
+// diffCounters pairs counters of the same name, which must be in the same order in both runs.
+// The first counter is the valid count and the rest are failures.
+func diffCounters(previous []namedCounter, current []namedCounter) []CounterDelta {
+	deltas := make([]CounterDelta, 0)
+	for i := range current {
+		if previous[i].count == 0 && current[i].count == 0 {
+			continue
+		}
+
+		deltas = append(deltas, CounterDelta{
+			Name:     current[i].name,
+			Previous: previous[i].count,
+			Current:  current[i].count,
+			Failure:  i > 0,
+		})
+	}
+
+	return deltas
+}
+
+// objectCounters returns the valid count then the failure counters of a type, all zero if it
+// was not loaded.
+func objectCounters(result *LoadingJobObjectResult) []namedCounter {
+	if result == nil {
+		result = &LoadingJobObjectResult{}
+	}
+
+	return append([]namedCounter{{"validObject", result.ValidObject}}, result.objectFailureCounters()...)
+}
+
+// objectTypeNames returns the type names in either run, in the order of the current run and
+// then those only in the previous run.
+func objectTypeNames(previous []LoadingJobObjectResult, current []LoadingJobObjectResult) []string {
+	names := make([]string, 0, len(current))
+	seen := make(map[string]bool, len(current))
+	for _, results := range [][]LoadingJobObjectResult{current, previous} {
+		for _, result := range results {
+			if !seen[result.TypeName] {
+				seen[result.TypeName] = true
+				names = append(names, result.TypeName)
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLoadingJobStatistics(t *testing.T) { //nolint:funlen
+	yesterday := &LoadingJobStatistics{
+		ValidLine:  1000,
+		RejectLine: 10,
+		Vertex: []LoadingJobObjectResult{
+			{TypeName: "Person", ValidObject: 990, InvalidAttribute: 4},
+			{TypeName: "Retired", ValidObject: 5},
+		},
+	}
+	today := &LoadingJobStatistics{
+		ValidLine:  1000,
+		RejectLine: 50,
+		Vertex: []LoadingJobObjectResult{
+			{TypeName: "Person", ValidObject: 950, InvalidAttribute: 2},
+			{TypeName: "Company", ValidObject: 10, InvalidPrimaryID: 3},
+		},
+	}
+
+	t.Run("diffs every counter which is non-zero in either run", func(t *testing.T) {
+		diff := DiffLoadingJobStatistics(yesterday, today)
+
+		assert.Equal(t, []CounterDelta{
+			{Name: "validLine", Previous: 1000, Current: 1000},
+			{Name: "rejectLine", Previous: 10, Current: 50, Failure: true},
+		}, diff.Lines)
+
+		assert.Equal(t, []ObjectStatisticsDiff{
+			{Kind: "vertex", TypeName: "Person", Counters: []CounterDelta{
+				{Name: "validObject", Previous: 990, Current: 950},
+				{Name: "invalidAttribute", Previous: 4, Current: 2, Failure: true},
+			}},
+			{Kind: "vertex", TypeName: "Company", Counters: []CounterDelta{
+				{Name: "validObject", Current: 10},
+				{Name: "invalidPrimaryId", Current: 3, Failure: true},
+			}},
+			{Kind: "vertex", TypeName: "Retired", Counters: []CounterDelta{
+				{Name: "validObject", Previous: 5},
+			}},
+		}, diff.Objects)
+
+		assert.True(t, diff.FailuresIncreased())
+	})
+
+	t.Run("summarises the changed counters", func(t *testing.T) {
+		assert.Equal(
+			t,
+			"rejectLine up 400% (10 -> 50); "+
+				"vertex Person: validObject down 4% (990 -> 950), invalidAttribute down 50% (4 -> 2); "+
+				"vertex Company: validObject up from 0 (0 -> 10), invalidPrimaryId up from 0 (0 -> 3); "+
+				"vertex Retired: validObject down 100% (5 -> 0)",
+			DiffLoadingJobStatistics(yesterday, today).Summary(),
+		)
+	})
+
+	t.Run("identical runs have no change", func(t *testing.T) {
+		diff := DiffLoadingJobStatistics(today, today)
+		assert.Equal(t, "no change", diff.Summary())
+		assert.False(t, diff.FailuresIncreased())
+	})
+
+	t.Run("fewer failures are not an increase", func(t *testing.T) {
+		diff := DiffLoadingJobStatistics(
+			&LoadingJobStatistics{RejectLine: 10},
+			&LoadingJobStatistics{RejectLine: 5},
+		)
+		assert.False(t, diff.FailuresIncreased())
+		assert.Equal(t, "rejectLine down 50% (10 -> 5)", diff.Summary())
+	})
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+// Logger receives warnings about conditions which do not fail a request but may need attention.
+// Arguments are alternating keys and values, so a *slog.Logger can be used directly.
+//
+// Loggers which also implement LevelLogger, as *slog.Logger does, additionally receive requests,
+// GSQL output and migration steps at the levels set in the client's LogLevels.
+//
+// Implementations must be safe to call from multiple goroutines.
+type Logger interface {
+	Warn(msg string, args ...any)
+}
+
+// LevelLogger is a Logger which accepts messages at every level.
+type LevelLogger interface {
+	Logger
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// LogLevel is the level a kind of message is logged at.
+type LogLevel int
+
+const (
+	// LogLevelOff stops a kind of message being logged
+	LogLevelOff LogLevel = iota
+
+	// LogLevelDebug logs with Debug
+	LogLevelDebug
+
+	// LogLevelInfo logs with Info
+	LogLevelInfo
+
+	// LogLevelWarn logs with Warn
+	LogLevelWarn
+
+	// LogLevelError logs with Error
+	LogLevelError
+)
+
+// LogLevels sets the level each kind of message is logged at.
+type LogLevels struct {
+	// Request is the level of a message for every request, with its method, path, status
+	// code, attempts and duration.
+	Request LogLevel
+
+	// GSQL is the level of a message with the output of every GSQL command.
+	GSQL LogLevel
+
+	// MigrationStep is the level of the messages before and after running each migration file.
+	// Failed steps are logged at LogLevelError unless logging them is turned off.
+	MigrationStep LogLevel
+}
+
+// DefaultLogLevels are the LogLevels of a client created with NewClient.
+var DefaultLogLevels = LogLevels{
+	Request:       LogLevelDebug,
+	GSQL:          LogLevelDebug,
+	MigrationStep: LogLevelInfo,
+}
+
+// NoopLogger is a Logger implementation that discards all messages.
+// It is used when no Logger is set on the client.
+type NoopLogger struct{}
+
+// Warn implements Logger
+func (NoopLogger) Warn(string, ...any) {}
+
+func (c *TigerGraphClient) logger() Logger {
+	if c.Logger == nil {
+		return NoopLogger{}
+	}
+
+	return c.Logger
+}
+
+// WithLogger sets the Logger warnings are sent to.
+func WithLogger(logger Logger) Option {
+	return func(c *TigerGraphClient) {
+		c.Logger = logger
+	}
+}
+
+// WithLogLevels sets the levels requests, GSQL output and migration steps are logged at.
+func WithLogLevels(levels LogLevels) Option {
+	return func(c *TigerGraphClient) {
+		c.LogLevels = levels
+	}
+}
+
+// log sends a message to the client's Logger at the given level. Messages below LogLevelWarn
+// are dropped if the Logger is not a LevelLogger.
+func (c *TigerGraphClient) log(level LogLevel, msg string, args ...any) {
+	if level == LogLevelOff || c.Logger == nil {
+		return
+	}
+
+	levelLogger, ok := c.Logger.(LevelLogger)
+	if !ok {
+		if level >= LogLevelWarn {
+			c.Logger.Warn(msg, args...)
+		}
+		return
+	}
+
+	switch level {
+	case LogLevelDebug:
+		levelLogger.Debug(msg, args...)
+	case LogLevelInfo:
+		levelLogger.Info(msg, args...)
+	case LogLevelWarn:
+		levelLogger.Warn(msg, args...)
+	default:
+		levelLogger.Error(msg, args...)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// ErrInvalidMetadataMigrations represents metadata migrations which are not numbered 1, 2, 3...
+var ErrInvalidMetadataMigrations = errors.New("metadata migrations must be numbered consecutively from 1")
+
+// BuiltinMetadataSchemaName is the graph name under which the versions of the built-in
+// metadata migrations are recorded, apart from the client's MetadataMigrations, which are
+// recorded under MetadataGraphName. It cannot clash with a graph, as graph names cannot
+// contain a dot.
+const BuiltinMetadataSchemaName = MetadataGraphName + ".builtin"
+
+// MetadataMigration upgrades the schema of the metadata graph from the previous version to
+// Version. The metadata graph created by the init GSQL is version 0.
+type MetadataMigration struct {
+	Version int
+	GSQL    string
+
+	// vertexType is the vertex type a built-in migration adds. Earlier clients added these
+	// types when first needed, without recording a version, so where the type already exists
+	// the migration is recorded without being run.
+	vertexType string
+}
+
+// metadataSchemaState remembers that the client has brought the metadata schema up to date
+type metadataSchemaState struct {
+	mu      sync.Mutex
+	current bool
+}
+
+// builtinMetadataMigrations evolve the metadata schema created by InitFileString. New entries
+// must be appended, never edited, as they may already have been applied to existing installations.
+// They are versioned separately from the client's MetadataMigrations, so adding one never
+// renumbers those.
+var builtinMetadataMigrations = []MetadataMigration{
+	{Version: 1, GSQL: MigrationPlanSchemaGSQL, vertexType: MigrationPlanVertexType},
+	{Version: 2, GSQL: MigrationSeedSchemaGSQL, vertexType: MigrationSeedVertexType},
+	{Version: 3, GSQL: BatchSizeSchemaGSQL, vertexType: BatchSizeVertexType},
+	{Version: 4, GSQL: QueryAliasSchemaGSQL, vertexType: QueryAliasVertexType},
+}
+
+// BuiltinMetadataSchemaVersion returns the version of the metadata schema which the client's
+// built-in metadata migrations bring the metadata graph to.
+func BuiltinMetadataSchemaVersion() int {
+	return len(builtinMetadataMigrations)
+}
+
+func (c *TigerGraphClient) metadataInitGSQL() string {
+	if c.MetadataInitGSQL != "" {
+		return c.MetadataInitGSQL
+	}
+
+	return InitFileString
+}
+
+// validateMetadataMigrations checks that the migrations are numbered 1, 2, 3...
+func validateMetadataMigrations(kind string, migrations []MetadataMigration) error {
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return fmt.Errorf(
+				"%s metadata migration %d has version %d, expected %d: %w",
+				kind,
+				i,
+				migration.Version,
+				i+1,
+				ErrInvalidMetadataMigrations,
+			)
+		}
+	}
+
+	return nil
+}
+
+// GetMetadataSchemaVersion returns the version of the metadata graph schema given by the
+// client's MetadataMigrations. Metadata graphs to which none have been applied are version 0.
+func (c *TigerGraphClient) GetMetadataSchemaVersion(ctx context.Context) (int, error) {
+	return c.metadataSchemaVersion(ctx, MetadataGraphName)
+}
+
+// metadataSchemaVersion returns the version of the metadata migrations recorded under name
+func (c *TigerGraphClient) metadataSchemaVersion(ctx context.Context, name string) (int, error) {
+	number, err := c.GetCurrentMigrationNumber(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	if number == "" {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(number)
+	if err != nil {
+		return 0, ErrInvalidMigrationNumber
+	}
+
+	return version, nil
+}
+
+// ensureMetadataSchema brings the metadata schema up to date, with the latest migration query
+// installed, once per client, so that the metadata vertex types of the client's features can
+// be relied upon.
+func (c *TigerGraphClient) ensureMetadataSchema(ctx context.Context) error {
+	c.metadataSchema.mu.Lock()
+	defer c.metadataSchema.mu.Unlock()
+
+	if c.metadataSchema.current {
+		return nil
+	}
+
+	if err := c.ensureLatestMigrationQuery(ctx); err != nil {
+		return err
+	}
+
+	if err := c.migrateMetadataSchema(ctx); err != nil {
+		return err
+	}
+
+	c.metadataSchema.current = true
+
+	return nil
+}
+
+// migrateMetadataSchema runs any metadata migrations which have not yet been applied to the
+// metadata graph, the built-in ones first. Versions are tracked as migrations of the metadata
+// graph itself.
+func (c *TigerGraphClient) migrateMetadataSchema(ctx context.Context) error {
+	if err := validateMetadataMigrations("built-in", builtinMetadataMigrations); err != nil {
+		return err
+	}
+	if err := validateMetadataMigrations("client", c.MetadataMigrations); err != nil {
+		return err
+	}
+
+	if err := c.applyMetadataMigrations(ctx, BuiltinMetadataSchemaName, builtinMetadataMigrations); err != nil {
+		return err
+	}
+
+	return c.applyMetadataMigrations(ctx, MetadataGraphName, c.MetadataMigrations)
+}
+
+// applyMetadataMigrations runs the migrations newer than the version recorded under name
+func (c *TigerGraphClient) applyMetadataMigrations(ctx context.Context, name string, migrations []MetadataMigration) error {
+	// Avoid a round trip for the common case of the metadata schema never having changed
+	if len(migrations) == 0 {
+		return nil
+	}
+
+	current, err := c.metadataSchemaVersion(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to get metadata schema version: %w", err)
+	}
+
+	for _, migration := range migrations {
+		if migration.Version <= current {
+			continue
+		}
+
+		applied := false
+		if migration.vertexType != "" {
+			if applied, err = c.hasMetadataVertexType(ctx, migration.vertexType); err != nil {
+				return fmt.Errorf("failed to check for the %s vertex type: %w", migration.vertexType, err)
+			}
+		}
+
+		if !applied {
+			if err = c.RunGSQL(ctx, migration.GSQL); err != nil {
+				return fmt.Errorf("failed to migrate metadata schema to version %d: %w", migration.Version, err)
+			}
+		}
+
+		version := fmt.Sprintf("%03d", migration.Version)
+		if err = c.commitMigrationVersion(ctx, name, version, MigrationUp); err != nil {
+			return fmt.Errorf("failed to record metadata schema version %d: %w", migration.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// hasMetadataVertexType reports whether the metadata graph has the given vertex type.
+func (c *TigerGraphClient) hasMetadataVertexType(ctx context.Context, vertexType string) (bool, error) {
+	meta, err := c.GetGraphMetadata(ctx, MetadataGraphName)
+	if err != nil {
+		return false, err
+	}
+
+	if meta.Error || meta.Results == nil {
+		return false, nil
+	}
+
+	for _, existing := range meta.Results.VertexTypes {
+		if existing.Name == vertexType {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMetadataMigrations(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []MetadataMigration
+		expected   error
+	}{
+		{name: "none"},
+		{name: "numbered from 1", migrations: []MetadataMigration{{Version: 1}, {Version: 2}}},
+		{name: "not numbered from 1", migrations: []MetadataMigration{{Version: 2}}, expected: ErrInvalidMetadataMigrations},
+		{name: "gap", migrations: []MetadataMigration{{Version: 1}, {Version: 3}}, expected: ErrInvalidMetadataMigrations},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateMetadataMigrations("client", test.migrations)
+			assert.ErrorIs(t, err, test.expected)
+		})
+	}
+
+	t.Run("built-in migrations", func(t *testing.T) {
+		assert.Nil(t, validateMetadataMigrations("built-in", builtinMetadataMigrations))
+	})
+}
@@ -19,7 +19,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -51,6 +50,24 @@ var (
 	ErrInvalidMigrationNumber = errors.New("migration number was invalid")
 )
 
+// InitialisationCheckError carries the response from TigerGraph when the initialisation check
+// fails for an unknown reason, e.g. authentication or licensing problems. It matches
+// ErrUnknownInitialisationCheckFailure with errors.Is.
+type InitialisationCheckError struct {
+	Message string
+	Code    string
+}
+
+// Error implements error
+func (e *InitialisationCheckError) Error() string {
+	return fmt.Sprintf("%s. message: %q, code: %q", ErrUnknownInitialisationCheckFailure, e.Message, e.Code)
+}
+
+// Unwrap allows errors.Is to match ErrUnknownInitialisationCheckFailure
+func (e *InitialisationCheckError) Unwrap() error {
+	return ErrUnknownInitialisationCheckFailure
+}
+
 // CheckIsInitialised determines if the metadata graph has been initialised
 // and ready for use.
 func (c *TigerGraphClient) CheckIsInitialised(ctx context.Context) (bool, error) {
@@ -67,7 +84,7 @@ func (c *TigerGraphClient) CheckIsInitialised(ctx context.Context) (bool, error)
 		return false, nil
 	}
 
-	return false, ErrUnknownInitialisationCheckFailure
+	return false, &InitialisationCheckError{Message: meta.Message, Code: meta.Code}
 }
 
 // InitFileString is the content of the initialisation GSQL as a string
@@ -79,7 +96,22 @@ var InitFileString string
 // information along with the specified version to determine which migrations to
 // run.
 //
-// If the metadata graph does not yet exist, it is created and initialised.
+// If the metadata graph does not yet exist, it is created and initialised. Any metadata
+// migrations which have not been applied to the metadata graph are then run.
+//
+// Before any migrations are run, the migration directory is checked to contain exactly one
+// file for each of them. If it does not, a *MigrationPlanError is returned.
+//
+// Unless the client allows global schema changes with WithGlobalSchemaChanges, every migration
+// is checked for them before any are run, and a *GlobalSchemaChangeError is returned naming
+// the file which makes them.
+//
+// With WithResumableMigrations, the plan is persisted in the metadata graph before it is run,
+// and a plan for the graph left running by an earlier call is finished first.
+//
+// Migrations with a seed manifest (see SeedManifest) have their seeds loaded once they are
+// run up and committed. Seeds which were not loaded, e.g. because loading them failed, are
+// loaded before any new migrations are run.
 func (c *TigerGraphClient) Migrate(
 	ctx context.Context,
 	graph string,
@@ -87,6 +119,21 @@ func (c *TigerGraphClient) Migrate(
 	initVersion string,
 	migrationFileDir string,
 	dryRun bool,
+) error {
+	report := c.newMigrationReport(graph, version, dryRun)
+	err := c.migrate(ctx, graph, version, initVersion, migrationFileDir, dryRun, report)
+
+	return errors.Join(err, c.writeMigrationReport(report, err))
+}
+
+func (c *TigerGraphClient) migrate(
+	ctx context.Context,
+	graph string,
+	version string,
+	initVersion string,
+	migrationFileDir string,
+	dryRun bool,
+	report *MigrationReport,
 ) error {
 	isInitialised, err := c.CheckIsInitialised(ctx)
 	if err != nil {
@@ -94,13 +141,18 @@ func (c *TigerGraphClient) Migrate(
 	}
 
 	if !isInitialised {
-		if err = c.RunGSQL(ctx, InitFileString); err != nil {
+		if err = c.RunGSQL(ctx, c.metadataInitGSQL()); err != nil {
 			return err
 		}
 
 		initialVersion := initVersion
 		if initialVersion != "" {
-			migrationNumbers, migrationMode, err := getMigrationsBetweenVersions("", initialVersion)
+			migrationNumbers, migrationMode, err := getMigrationsBetweenVersions(
+				c.migrationComparator(),
+				"",
+				initialVersion,
+				c.migrationSource(migrationFileDir),
+			)
 
 			if err != nil {
 				return fmt.Errorf(
@@ -118,27 +170,89 @@ func (c *TigerGraphClient) Migrate(
 		}
 	}
 
-	currentMigrationNumber, err := c.GetCurrentMigrationNumber(ctx, graph)
+	if err = c.ensureMetadataSchema(ctx); err != nil {
+		return err
+	}
+
+	if c.ResumableMigrations && !dryRun {
+		if err = c.resumeRunningMigrationPlan(ctx, graph, report); err != nil {
+			return err
+		}
+	}
+
+	currentMigrationNumber, err := c.getCurrentMigrationNumber(ctx, graph, c.migrationSource(migrationFileDir))
 	if err != nil {
 		return fmt.Errorf("failed to get current migration number from TigerGraph: %w", err)
 	}
 
+	if report != nil {
+		report.StartVersion = currentMigrationNumber
+		report.ResultVersion = currentMigrationNumber
+	}
+
+	if !dryRun {
+		if err = c.applyPendingSeeds(ctx, graph, c.migrationSource(migrationFileDir), currentMigrationNumber); err != nil {
+			return err
+		}
+	}
+
 	desiredMigrationNumber := version
-	migrationNumbers, migrationMode, err := getMigrationsBetweenVersions(currentMigrationNumber, desiredMigrationNumber)
+	migrationNumbers, migrationMode, err := getMigrationsBetweenVersions(
+		c.migrationComparator(),
+		currentMigrationNumber,
+		desiredMigrationNumber,
+		c.migrationSource(migrationFileDir),
+	)
+	if err != nil {
+		return err
+	}
+
+	fileNames, err := planMigrationFiles(c.migrationSource(migrationFileDir), migrationNumbers, migrationMode)
 	if err != nil {
 		return err
 	}
 
-	for _, migrationNumber := range migrationNumbers {
+	if report != nil {
+		report.Mode = migrationMode
+	}
+
+	if !dryRun {
+		if err = c.checkMigrationGlobalSchemaChanges(fileNames); err != nil {
+			return err
+		}
+	}
+
+	if c.ResumableMigrations && !dryRun && len(migrationNumbers) > 0 {
+		plan := &MigrationPlan{
+			Graph:         graph,
+			TargetVersion: version,
+			Mode:          migrationMode,
+			Steps:         migrationNumbers,
+			Files:         fileNames,
+			Status:        MigrationPlanRunning,
+		}
+		if err = c.saveMigrationPlan(ctx, plan); err != nil {
+			return err
+		}
+
+		return c.runMigrationPlan(ctx, plan, report)
+	}
+
+	for i, migrationNumber := range migrationNumbers {
+		step := report.addStep(migrationNumber, fileNames[i], migrationMode)
 		if dryRun {
 			continue
 		}
-		if err = c.tryMigrateStep(ctx, migrationNumber, migrationMode, migrationFileDir); err != nil {
+		if err = c.tryMigrateStep(ctx, fileNames[i], step); err != nil {
 			return err
 		}
 		if err = c.commitMigrationVersion(ctx, graph, migrationNumber, migrationMode); err != nil {
 			return fmt.Errorf(trackMigrationFailureTemplate, migrationNumber, err)
 		}
+		report.recordVersion(versionAfterStep(migrationNumbers, i, migrationMode, desiredMigrationNumber))
+		if err = c.seedMigration(ctx, graph, migrationNumber, fileNames[i], migrationMode); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -152,87 +266,104 @@ func decrementMigrationNumber(n string) (string, error) {
 	return fmt.Sprintf("%03d", asInt-1), nil
 }
 
-func getMigrationsBetweenVersions(from string, to string) ([]string, string, error) {
+// getMigrationsBetweenVersions returns the migrations to run to move from one version to another,
+// and whether they are up or down migrations. Identifiers are ordered by the comparator.
+func getMigrationsBetweenVersions(
+	comparator MigrationComparator,
+	from string,
+	to string,
+	source migrationSource,
+) ([]string, MigrationMode, error) {
 	result := make([]string, 0)
 
 	if to == "" {
 		return result, "", ErrInvalidMigrationNumber
 	}
 
-	if from == "" {
-		from = "-001"
+	if _, err := comparator.Compare(to, to); err != nil {
+		return result, "", err
 	}
 
-	fromInt, err := strconv.ParseInt(from, 10, 32)
-	if err != nil {
-		return result, "", ErrInvalidMigrationNumber
+	lower, upper, mode := from, to, MigrationUp
+	if from != "" {
+		cmp, err := comparator.Compare(from, to)
+		if err != nil {
+			return result, "", err
+		}
+
+		if cmp > 0 {
+			lower, upper, mode = to, from, MigrationDown
+		}
 	}
 
-	toInt, err := strconv.ParseInt(to, 10, 32)
+	migrations, err := sequenceMigrations(comparator, lower, upper, source)
 	if err != nil {
-		return result, "", ErrInvalidMigrationNumber
+		return result, "", err
 	}
+	result = append(result, migrations...)
 
-	minInt := fromInt
-	if toInt < minInt {
-		minInt = toInt
+	if mode == MigrationDown {
+		for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+			result[i], result[j] = result[j], result[i]
+		}
 	}
 
-	maxInt := toInt
-	if fromInt > maxInt {
-		maxInt = fromInt
-	}
+	return result, mode, nil
+}
 
-	for i := minInt + 1; i <= maxInt; i++ {
-		versionString := fmt.Sprintf("%03d", i)
-		result = append(result, versionString)
-	}
+func (c *TigerGraphClient) tryMigrateStep(ctx context.Context, fileName string, step *MigrationReportStep) error {
+	c.log(c.LogLevels.MigrationStep, "running migration", "file", fileName)
+	start := time.Now()
+	step.start(c.now())
 
-	mode := "up"
-	if fromInt > toInt {
-		mode = "down"
-		sort.Slice(result, func(i, j int) bool {
-			return result[i] > result[j]
-		})
+	err := c.migrateFile(ctx, fileName, step)
+	step.finish(time.Since(start), err)
+	if err != nil {
+		if c.LogLevels.MigrationStep != LogLevelOff {
+			c.log(LogLevelError, "migration failed", "file", fileName, "duration", time.Since(start), "error", err)
+		}
+		return fmt.Errorf("failed to set up TG schema: %s, %w", err, ErrTigerGraphSchemaSetUpFailed)
 	}
 
-	return result, mode, nil
+	c.log(c.LogLevels.MigrationStep, "migration completed", "file", fileName, "duration", time.Since(start))
+
+	return nil
 }
 
-func (c *TigerGraphClient) tryMigrateStep(ctx context.Context, number string, mode string, migrationFileDir string) error {
-	files, err := os.ReadDir(migrationFileDir)
-	if err != nil {
-		return err
+// checkMigrationGlobalSchemaChanges checks every migration file for global schema changes, so
+// that none are run if any would be refused.
+func (c *TigerGraphClient) checkMigrationGlobalSchemaChanges(fileNames []string) error {
+	if c.AllowGlobalSchemaChanges {
+		return nil
 	}
 
-	expectedSuffix := fmt.Sprintf("%s.gsql", mode)
-
-	for _, file := range files {
-		if strings.HasPrefix(file.Name(), number+"_") && strings.HasSuffix(file.Name(), expectedSuffix) {
-			fileName := migrationFileDir + "/" + file.Name()
-			err = c.migrateFile(ctx, fileName)
-			if err != nil {
-				return fmt.Errorf("failed to set up TG schema: %s, %w", err, ErrTigerGraphSchemaSetUpFailed)
-			}
+	for _, fileName := range fileNames {
+		bytes, err := os.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
 
-			return nil
+		if err = c.checkGlobalSchemaChanges(string(bytes), nil); err != nil {
+			return fmt.Errorf("migration %s: %w", fileName, err)
 		}
 	}
 
-	return fmt.Errorf(
-		"failed to run migration, no file with migration number found. number: %s, mode: %s",
-		number,
-		mode,
-	)
+	return nil
 }
 
-func (c *TigerGraphClient) migrateFile(ctx context.Context, fileName string) error {
+func (c *TigerGraphClient) migrateFile(ctx context.Context, fileName string, step *MigrationReportStep) error {
 	bytes, err := os.ReadFile(fileName)
 	if err != nil {
 		return err
 	}
 
-	err = c.RunGSQL(ctx, string(bytes))
+	opts := []RequestOption{WithRequestTimeout(c.Timeouts.MigrationStep)}
+	if step != nil {
+		step.recordGSQL(bytes)
+		opts = append(opts, withGSQLOutput(step.recordOutput))
+	}
+
+	err = c.RunGSQL(ctx, string(bytes), opts...)
 	if err != nil {
 		return err
 	}
@@ -247,10 +378,10 @@ type MigrationVertexPayloadValue[T any] struct {
 
 // MigrationVertexPayload is the shape of a migration vertex being stored in the vertex upsert payload
 type MigrationVertexPayload struct {
-	GraphName       MigrationVertexPayloadValue[string]    `json:"graph_name"`
-	MigrationNumber MigrationVertexPayloadValue[string]    `json:"migration_number"`
-	Mode            MigrationVertexPayloadValue[string]    `json:"mode"`
-	CreatedAt       MigrationVertexPayloadValue[time.Time] `json:"created_at"`
+	GraphName       MigrationVertexPayloadValue[string]        `json:"graph_name"`
+	MigrationNumber MigrationVertexPayloadValue[string]        `json:"migration_number"`
+	Mode            MigrationVertexPayloadValue[MigrationMode] `json:"mode"`
+	CreatedAt       MigrationVertexPayloadValue[time.Time]     `json:"created_at"`
 }
 
 // MigrationVerticesPayload is the map to all vertices in the payload
@@ -263,8 +394,13 @@ type MigrationUpsertPayload struct {
 	Vertices MigrationVerticesPayload `json:"vertices"`
 }
 
-func (c *TigerGraphClient) commitMigrationVersion(ctx context.Context, graph string, version string, mode string) error {
-	createdAt := time.Now()
+func (c *TigerGraphClient) commitMigrationVersion(
+	ctx context.Context,
+	graph string,
+	version string,
+	mode MigrationMode,
+) error {
+	createdAt := c.now()
 	id := fmt.Sprintf("%s_%s_%s", version, mode, createdAt.Format(time.RFC3339))
 	payload := MigrationUpsertPayload{
 		MigrationVerticesPayload{
@@ -272,7 +408,7 @@ func (c *TigerGraphClient) commitMigrationVersion(ctx context.Context, graph str
 				id: {
 					GraphName:       MigrationVertexPayloadValue[string]{graph},
 					MigrationNumber: MigrationVertexPayloadValue[string]{version},
-					Mode:            MigrationVertexPayloadValue[string]{mode},
+					Mode:            MigrationVertexPayloadValue[MigrationMode]{mode},
 					CreatedAt:       MigrationVertexPayloadValue[time.Time]{createdAt},
 				},
 			},
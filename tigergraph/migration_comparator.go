@@ -0,0 +1,201 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownPreviousMigration occurs when the migration before a down migration cannot be
+// determined because the comparator cannot sequence identifiers and no migration directory was given.
+var ErrUnknownPreviousMigration = errors.New("unable to determine the previous migration")
+
+// MigrationComparator orders migration identifiers, e.g. "001", "2023-06-01" or "1.2.0".
+// Compare returns a negative number when a is before b, zero when they are equal and a positive
+// number when a is after b. It returns an error wrapping ErrInvalidMigrationNumber when either
+// identifier is invalid.
+//
+// Unless the comparator also implements MigrationSequencer, the identifiers between two
+// versions are found by listing the migration directory.
+type MigrationComparator interface {
+	Compare(a string, b string) (int, error)
+}
+
+// MigrationSequencer is implemented by comparators whose identifiers can be enumerated without
+// listing the migration directory.
+type MigrationSequencer interface {
+	// Sequence returns the identifiers after from, up to and including to, in ascending order.
+	// An empty from means the sequence starts at the first identifier.
+	Sequence(from string, to string) ([]string, error)
+
+	// Previous returns the identifier immediately before id.
+	Previous(id string) (string, error)
+}
+
+// NumericMigrationComparator orders three digit, numerical migration identifiers. It is the
+// default MigrationComparator.
+type NumericMigrationComparator struct{}
+
+// Compare implements MigrationComparator
+func (NumericMigrationComparator) Compare(a string, b string) (int, error) {
+	aInt, err := strconv.ParseInt(a, 10, 32)
+	if err != nil {
+		return 0, ErrInvalidMigrationNumber
+	}
+
+	bInt, err := strconv.ParseInt(b, 10, 32)
+	if err != nil {
+		return 0, ErrInvalidMigrationNumber
+	}
+
+	switch {
+	case aInt < bInt:
+		return -1, nil
+	case aInt > bInt:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Sequence implements MigrationSequencer
+func (NumericMigrationComparator) Sequence(from string, to string) ([]string, error) {
+	if from == "" {
+		from = "-001"
+	}
+
+	fromInt, err := strconv.ParseInt(from, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidMigrationNumber
+	}
+
+	toInt, err := strconv.ParseInt(to, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidMigrationNumber
+	}
+
+	result := make([]string, 0)
+	for i := fromInt + 1; i <= toInt; i++ {
+		result = append(result, fmt.Sprintf("%03d", i))
+	}
+
+	return result, nil
+}
+
+// Previous implements MigrationSequencer
+func (NumericMigrationComparator) Previous(id string) (string, error) {
+	return decrementMigrationNumber(id)
+}
+
+func (c *TigerGraphClient) migrationComparator() MigrationComparator {
+	if c.MigrationComparator == nil {
+		return NumericMigrationComparator{}
+	}
+
+	return c.MigrationComparator
+}
+
+// listMigrationIdentifiers returns the identifiers of the migrations in the source, in
+// ascending order. The identifier is the part of the file name before the first underscore.
+func listMigrationIdentifiers(comparator MigrationComparator, source migrationSource) ([]string, error) {
+	files, err := source.files()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	result := make([]string, 0)
+
+	for _, file := range files {
+		id, _, found := strings.Cut(file.name, "_")
+		if !found || seen[id] {
+			continue
+		}
+
+		if _, err = comparator.Compare(id, id); err != nil {
+			return nil, fmt.Errorf("invalid migration identifier in file %s: %w", file.relativePath, err)
+		}
+
+		seen[id] = true
+		result = append(result, id)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		cmp, _ := comparator.Compare(result[i], result[j])
+		return cmp < 0
+	})
+
+	return result, nil
+}
+
+// sequenceMigrations returns the identifiers after from, up to and including to, in ascending
+// order, using the comparator's MigrationSequencer if it has one.
+func sequenceMigrations(comparator MigrationComparator, from string, to string, source migrationSource) ([]string, error) {
+	if sequencer, ok := comparator.(MigrationSequencer); ok {
+		return sequencer.Sequence(from, to)
+	}
+
+	ids, err := listMigrationIdentifiers(comparator, source)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0)
+	for _, id := range ids {
+		if from != "" {
+			if cmp, _ := comparator.Compare(id, from); cmp <= 0 {
+				continue
+			}
+		}
+
+		if cmp, _ := comparator.Compare(id, to); cmp > 0 {
+			break
+		}
+
+		result = append(result, id)
+	}
+
+	return result, nil
+}
+
+// previousMigration returns the identifier before id. When the comparator cannot sequence
+// identifiers this is the closest earlier migration in the source, or "" if there is none.
+func previousMigration(comparator MigrationComparator, id string, source migrationSource) (string, error) {
+	if sequencer, ok := comparator.(MigrationSequencer); ok {
+		return sequencer.Previous(id)
+	}
+
+	if source.dir == "" {
+		return "", fmt.Errorf("%w: migration %s", ErrUnknownPreviousMigration, id)
+	}
+
+	ids, err := listMigrationIdentifiers(comparator, source)
+	if err != nil {
+		return "", err
+	}
+
+	previous := ""
+	for _, candidate := range ids {
+		if cmp, _ := comparator.Compare(candidate, id); cmp >= 0 {
+			break
+		}
+		previous = candidate
+	}
+
+	return previous, nil
+}
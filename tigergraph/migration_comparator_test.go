@@ -0,0 +1,130 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dateMigrationComparator struct{}
+
+func (dateMigrationComparator) Compare(a string, b string) (int, error) {
+	aTime, err := time.Parse("20060102", a)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidMigrationNumber, a)
+	}
+
+	bTime, err := time.Parse("20060102", b)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidMigrationNumber, b)
+	}
+
+	return aTime.Compare(bTime), nil
+}
+
+func TestGetMigrationsBetweenVersionsWithComparator(t *testing.T) { //nolint:funlen
+	dir := t.TempDir()
+	for _, name := range []string{
+		"20230601_first.up.gsql",
+		"20230601_first.down.gsql",
+		"20230115_zeroth.up.gsql",
+		"20230115_zeroth.down.gsql",
+		"20231201_second.up.gsql",
+		"20231201_second.down.gsql",
+	} {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(""), 0o600))
+	}
+
+	cases := []struct {
+		name               string
+		from               string
+		to                 string
+		expectedMigrations []string
+		expectedMode       MigrationMode
+		expectedError      error
+	}{
+		{
+			name:               "empty from version",
+			from:               "",
+			to:                 "20230601",
+			expectedMigrations: []string{"20230115", "20230601"},
+			expectedMode:       "up",
+		},
+		{
+			name:               "up migrations",
+			from:               "20230115",
+			to:                 "20231201",
+			expectedMigrations: []string{"20230601", "20231201"},
+			expectedMode:       "up",
+		},
+		{
+			name:               "down migrations",
+			from:               "20231201",
+			to:                 "20230115",
+			expectedMigrations: []string{"20231201", "20230601"},
+			expectedMode:       "down",
+		},
+		{
+			name:               "same version",
+			from:               "20230601",
+			to:                 "20230601",
+			expectedMigrations: []string{},
+			expectedMode:       "up",
+		},
+		{
+			name:               "invalid to version",
+			from:               "20230601",
+			to:                 "001",
+			expectedMigrations: []string{},
+			expectedError:      ErrInvalidMigrationNumber,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			migrations, mode, err := getMigrationsBetweenVersions(dateMigrationComparator{}, testCase.from, testCase.to, migrationSource{dir: dir})
+			assert.Equal(t, testCase.expectedMigrations, migrations)
+			assert.Equal(t, testCase.expectedMode, mode)
+			assert.ErrorIs(t, err, testCase.expectedError)
+		})
+	}
+}
+
+func TestPreviousMigration(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"20230115_zeroth.up.gsql", "20230601_first.up.gsql"} {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(""), 0o600))
+	}
+
+	previous, err := previousMigration(NumericMigrationComparator{}, "002", migrationSource{})
+	assert.Nil(t, err)
+	assert.Equal(t, "001", previous)
+
+	previous, err = previousMigration(dateMigrationComparator{}, "20230601", migrationSource{dir: dir})
+	assert.Nil(t, err)
+	assert.Equal(t, "20230115", previous)
+
+	previous, err = previousMigration(dateMigrationComparator{}, "20230115", migrationSource{dir: dir})
+	assert.Nil(t, err)
+	assert.Equal(t, "", previous)
+
+	_, err = previousMigration(dateMigrationComparator{}, "20230601", migrationSource{})
+	assert.ErrorIs(t, err, ErrUnknownPreviousMigration)
+}
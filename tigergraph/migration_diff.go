@@ -0,0 +1,205 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MigrationSummary lists the schema and query changes made by a single migration file.
+type MigrationSummary struct {
+	Number   string
+	Mode     MigrationMode
+	FileName string
+
+	QueriesCreated     []string
+	QueriesDropped     []string
+	VertexTypesAdded   []string
+	VertexTypesRemoved []string
+	EdgeTypesAdded     []string
+	EdgeTypesRemoved   []string
+
+	// Statements holds every statement recognised in the migration file
+	Statements []GSQLStatement
+
+	// GlobalSchemaChanges holds the statements which change the global schema, which Migrate
+	// refuses to run unless the client allows them with WithGlobalSchemaChanges
+	GlobalSchemaChanges []GSQLStatement
+}
+
+// IsDestructive reports whether the migration drops any graphs, queries or types.
+func (s MigrationSummary) IsDestructive() bool {
+	for _, statement := range s.Statements {
+		if statement.Kind.IsDestructive() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsGlobal reports whether the migration changes the global schema, affecting every graph.
+func (s MigrationSummary) IsGlobal() bool {
+	return len(s.GlobalSchemaChanges) > 0
+}
+
+// MigrationDiff summarises the migrations that would be run to move between two versions.
+type MigrationDiff struct {
+	From       string
+	To         string
+	Mode       MigrationMode
+	Migrations []MigrationSummary
+}
+
+// IsDestructive reports whether any of the pending migrations is destructive. CI can use
+// this to require extra approval before a deployment.
+func (d *MigrationDiff) IsDestructive() bool {
+	for _, migration := range d.Migrations {
+		if migration.IsDestructive() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsGlobal reports whether any of the pending migrations changes the global schema.
+func (d *MigrationDiff) IsGlobal() bool {
+	for _, migration := range d.Migrations {
+		if migration.IsGlobal() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DiffPendingMigrations summarises the migrations in migrationFileDir which would be run to
+// migrate from fromVersion to toVersion. An empty fromVersion means no migrations have been run.
+//
+// The summaries are found by classifying the statements in each file with ClassifyGSQL.
+func DiffPendingMigrations(migrationFileDir string, fromVersion string, toVersion string) (*MigrationDiff, error) {
+	return DiffPendingMigrationsWithComparator(NumericMigrationComparator{}, migrationFileDir, fromVersion, toVersion)
+}
+
+// DiffPendingMigrationsWithComparator is DiffPendingMigrations for migration identifiers
+// ordered by the given comparator.
+func DiffPendingMigrationsWithComparator(
+	comparator MigrationComparator,
+	migrationFileDir string,
+	fromVersion string,
+	toVersion string,
+) (*MigrationDiff, error) {
+	return diffPendingMigrations(comparator, migrationSource{dir: migrationFileDir}, fromVersion, toVersion)
+}
+
+// DiffPendingMigrations is the package level DiffPendingMigrations using the client's
+// MigrationComparator and MigrationDiscovery, i.e. the migrations Migrate would run.
+func (c *TigerGraphClient) DiffPendingMigrations(migrationFileDir string, fromVersion string, toVersion string) (*MigrationDiff, error) {
+	return diffPendingMigrations(c.migrationComparator(), c.migrationSource(migrationFileDir), fromVersion, toVersion)
+}
+
+func diffPendingMigrations(
+	comparator MigrationComparator,
+	source migrationSource,
+	fromVersion string,
+	toVersion string,
+) (*MigrationDiff, error) {
+	migrationNumbers, mode, err := getMigrationsBetweenVersions(comparator, fromVersion, toVersion, source)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &MigrationDiff{
+		From:       fromVersion,
+		To:         toVersion,
+		Mode:       mode,
+		Migrations: make([]MigrationSummary, 0, len(migrationNumbers)),
+	}
+
+	fileNames, err := planMigrationFiles(source, migrationNumbers, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, number := range migrationNumbers {
+		fileName := fileNames[i]
+
+		contents, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		summary, err := summariseGSQL(string(contents))
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarise %s: %w", fileName, err)
+		}
+		summary.Number = number
+		summary.Mode = mode
+
+		relativePath, err := filepath.Rel(source.dir, fileName)
+		if err != nil {
+			return nil, err
+		}
+		summary.FileName = filepath.ToSlash(relativePath)
+
+		diff.Migrations = append(diff.Migrations, summary)
+	}
+
+	return diff, nil
+}
+
+func summariseGSQL(gsql string) (MigrationSummary, error) {
+	statements, err := ClassifyGSQL(gsql)
+	if err != nil {
+		return MigrationSummary{}, err
+	}
+
+	globalStatements, err := GlobalSchemaStatements(gsql)
+	if err != nil {
+		return MigrationSummary{}, err
+	}
+
+	summary := MigrationSummary{
+		QueriesCreated:      make([]string, 0),
+		QueriesDropped:      make([]string, 0),
+		VertexTypesAdded:    make([]string, 0),
+		VertexTypesRemoved:  make([]string, 0),
+		EdgeTypesAdded:      make([]string, 0),
+		EdgeTypesRemoved:    make([]string, 0),
+		Statements:          statements,
+		GlobalSchemaChanges: globalStatements,
+	}
+
+	for _, statement := range statements {
+		switch statement.Kind {
+		case StatementCreateQuery:
+			summary.QueriesCreated = append(summary.QueriesCreated, statement.Names...)
+		case StatementDropQuery:
+			summary.QueriesDropped = append(summary.QueriesDropped, statement.Names...)
+		case StatementCreateVertex, StatementAddVertex:
+			summary.VertexTypesAdded = append(summary.VertexTypesAdded, statement.Names...)
+		case StatementDropVertex:
+			summary.VertexTypesRemoved = append(summary.VertexTypesRemoved, statement.Names...)
+		case StatementCreateEdge, StatementAddEdge:
+			summary.EdgeTypesAdded = append(summary.EdgeTypesAdded, statement.Names...)
+		case StatementDropEdge:
+			summary.EdgeTypesRemoved = append(summary.EdgeTypesRemoved, statement.Names...)
+		}
+	}
+
+	return summary, nil
+}
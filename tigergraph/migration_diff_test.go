@@ -0,0 +1,111 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffPendingMigrations(t *testing.T) { //nolint:funlen
+	dir := t.TempDir()
+	files := map[string]string{
+		"000_init.up.gsql": `
+CREATE SCHEMA_CHANGE JOB init FOR GRAPH G {
+    ADD VERTEX Person (PRIMARY_ID id STRING);
+    ADD DIRECTED EDGE Knows (FROM Person, TO Person);
+}
+// CREATE QUERY commented_out() FOR GRAPH G {}
+CREATE OR REPLACE QUERY people() FOR GRAPH G { PRINT "ok"; }
+`,
+		"000_init.down.gsql": `
+DROP QUERY people
+CREATE SCHEMA_CHANGE JOB teardown FOR GRAPH G {
+    DROP EDGE Knows;
+    DROP VERTEX Person;
+}
+`,
+		"001_company.up.gsql": `
+/* DROP VERTEX Person; */
+CREATE VERTEX Company (PRIMARY_ID id STRING)
+`,
+		"001_company.down.gsql": `DROP VERTEX Company`,
+	}
+	for name, contents := range files {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600))
+	}
+
+	t.Run("up migrations", func(t *testing.T) {
+		diff, err := DiffPendingMigrations(dir, "", "001")
+		assert.Nil(t, err)
+		assert.Equal(t, MigrationUp, diff.Mode)
+		assert.False(t, diff.IsDestructive())
+
+		assert.True(t, diff.IsGlobal())
+		assert.False(t, diff.Migrations[0].IsGlobal())
+		assert.True(t, diff.Migrations[1].IsGlobal())
+
+		for i := range diff.Migrations {
+			assert.NotEmpty(t, diff.Migrations[i].Statements)
+			diff.Migrations[i].Statements = nil
+			diff.Migrations[i].GlobalSchemaChanges = nil
+		}
+		assert.Equal(t, []MigrationSummary{
+			{
+				Number:             "000",
+				Mode:               "up",
+				FileName:           "000_init.up.gsql",
+				QueriesCreated:     []string{"people"},
+				QueriesDropped:     []string{},
+				VertexTypesAdded:   []string{"Person"},
+				VertexTypesRemoved: []string{},
+				EdgeTypesAdded:     []string{"Knows"},
+				EdgeTypesRemoved:   []string{},
+			},
+			{
+				Number:             "001",
+				Mode:               "up",
+				FileName:           "001_company.up.gsql",
+				QueriesCreated:     []string{},
+				QueriesDropped:     []string{},
+				VertexTypesAdded:   []string{"Company"},
+				VertexTypesRemoved: []string{},
+				EdgeTypesAdded:     []string{},
+				EdgeTypesRemoved:   []string{},
+			},
+		}, diff.Migrations)
+	})
+
+	t.Run("down migrations are destructive", func(t *testing.T) {
+		diff, err := DiffPendingMigrations(dir, "001", "")
+		assert.ErrorIs(t, err, ErrInvalidMigrationNumber)
+		assert.Nil(t, diff)
+
+		diff, err = DiffPendingMigrations(dir, "001", "-001")
+		assert.Nil(t, err)
+		assert.True(t, diff.IsDestructive())
+		assert.Len(t, diff.Migrations, 2)
+		assert.Equal(t, []string{"Company"}, diff.Migrations[0].VertexTypesRemoved)
+		assert.Equal(t, []string{"people"}, diff.Migrations[1].QueriesDropped)
+		assert.Equal(t, []string{"Knows"}, diff.Migrations[1].EdgeTypesRemoved)
+	})
+
+	t.Run("missing migration file", func(t *testing.T) {
+		_, err := DiffPendingMigrations(dir, "001", "002")
+		assert.ErrorIs(t, err, ErrInvalidMigrationPlan)
+	})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MigrationDiscovery controls how migration files are found in the migration directory.
+//
+// Migration numbers are global: subdirectories only organise the files, e.g. into schema/,
+// queries/ and loading_jobs/, and migrations are always run in migration number order. Each
+// number must therefore be used by only one up and one down file across all included directories.
+type MigrationDiscovery struct {
+	// Recursive finds migrations in the subdirectories of the migration directory too.
+	Recursive bool
+
+	// Include restricts discovery to the files which, or whose parent directories, match one of
+	// these path.Match patterns. Paths are slash separated and relative to the migration
+	// directory, e.g. "schema" or "queries/*.gsql". All files are included if this is empty.
+	Include []string
+}
+
+// migrationSource is a migration directory along with how to find the migrations in it.
+type migrationSource struct {
+	dir       string
+	discovery MigrationDiscovery
+}
+
+func (c *TigerGraphClient) migrationSource(migrationFileDir string) migrationSource {
+	return migrationSource{dir: migrationFileDir, discovery: c.MigrationDiscovery}
+}
+
+// migrationFile is a .gsql file found in a migration directory.
+type migrationFile struct {
+	// name is the file name without its directory
+	name string
+
+	// relativePath is the slash separated path relative to the migration directory
+	relativePath string
+
+	// path is the path of the file including the migration directory
+	path string
+}
+
+// files returns the .gsql files in the source, ordered by their relative path.
+func (s migrationSource) files() ([]migrationFile, error) {
+	result := make([]migrationFile, 0)
+
+	err := filepath.WalkDir(s.dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if filePath != s.dir && !s.discovery.Recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(entry.Name(), ".gsql") {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(s.dir, filePath)
+		if err != nil {
+			return err
+		}
+		relativePath = filepath.ToSlash(relativePath)
+
+		included, err := s.discovery.includes(relativePath)
+		if err != nil {
+			return err
+		}
+
+		if included {
+			result = append(result, migrationFile{name: entry.Name(), relativePath: relativePath, path: filePath})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// includes reports whether the file at relativePath, or one of its parent directories, matches
+// an Include pattern.
+func (d MigrationDiscovery) includes(relativePath string) (bool, error) {
+	if len(d.Include) == 0 {
+		return true, nil
+	}
+
+	for candidate := relativePath; candidate != "."; candidate = path.Dir(candidate) {
+		for _, pattern := range d.Include {
+			matched, err := path.Match(pattern, candidate)
+			if err != nil {
+				return false, fmt.Errorf("invalid migration include pattern %q: %w", pattern, err)
+			}
+
+			if matched {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationDiscovery(t *testing.T) { //nolint:funlen
+	dir := t.TempDir()
+	files := map[string]string{
+		"000_init.up.gsql":                     "CREATE GRAPH G()",
+		"schema/001_person.up.gsql":            "CREATE VERTEX Person (PRIMARY_ID id STRING)",
+		"schema/003_company.up.gsql":           "CREATE VERTEX Company (PRIMARY_ID id STRING)",
+		"queries/002_people.up.gsql":           "CREATE QUERY people() FOR GRAPH G { PRINT 1; }",
+		"loading_jobs/004_load.up.gsql":        "CREATE LOADING JOB load FOR GRAPH G {}",
+		"loading_jobs/archive/005_old.up.gsql": "CREATE LOADING JOB old FOR GRAPH G {}",
+		"schema/README.md":                     "not a migration",
+	}
+	for name, contents := range files {
+		assert.Nil(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o700))
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600))
+	}
+
+	relativePaths := func(t *testing.T, source migrationSource) []string {
+		t.Helper()
+		found, err := source.files()
+		assert.Nil(t, err)
+
+		result := make([]string, 0, len(found))
+		for _, file := range found {
+			result = append(result, file.relativePath)
+		}
+		return result
+	}
+
+	t.Run("only the top level directory by default", func(t *testing.T) {
+		assert.Equal(t, []string{"000_init.up.gsql"}, relativePaths(t, migrationSource{dir: dir}))
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		source := migrationSource{dir: dir, discovery: MigrationDiscovery{Recursive: true}}
+		assert.Equal(t, []string{
+			"000_init.up.gsql",
+			"loading_jobs/004_load.up.gsql",
+			"loading_jobs/archive/005_old.up.gsql",
+			"queries/002_people.up.gsql",
+			"schema/001_person.up.gsql",
+			"schema/003_company.up.gsql",
+		}, relativePaths(t, source))
+
+		ids, err := listMigrationIdentifiers(NumericMigrationComparator{}, source)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"000", "001", "002", "003", "004", "005"}, ids)
+
+		fileNames, err := planMigrationFiles(source, []string{"001", "002", "003"}, "up")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(dir, "schema", "001_person.up.gsql"),
+			filepath.Join(dir, "queries", "002_people.up.gsql"),
+			filepath.Join(dir, "schema", "003_company.up.gsql"),
+		}, fileNames)
+	})
+
+	t.Run("include filters", func(t *testing.T) {
+		source := migrationSource{dir: dir, discovery: MigrationDiscovery{
+			Recursive: true,
+			Include:   []string{"schema", "loading_jobs/*.gsql"},
+		}}
+		assert.Equal(t, []string{
+			"loading_jobs/004_load.up.gsql",
+			"schema/001_person.up.gsql",
+			"schema/003_company.up.gsql",
+		}, relativePaths(t, source))
+	})
+
+	t.Run("invalid include pattern", func(t *testing.T) {
+		source := migrationSource{dir: dir, discovery: MigrationDiscovery{Include: []string{"["}}}
+		_, err := source.files()
+		assert.ErrorIs(t, err, path.ErrBadPattern)
+	})
+
+	t.Run("duplicate numbers across directories", func(t *testing.T) {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, "queries", "001_people.up.gsql"), []byte(""), 0o600))
+		defer os.Remove(filepath.Join(dir, "queries", "001_people.up.gsql"))
+
+		source := migrationSource{dir: dir, discovery: MigrationDiscovery{Recursive: true}}
+		_, err := planMigrationFiles(source, []string{"001"}, "up")
+
+		var planErr *MigrationPlanError
+		assert.ErrorAs(t, err, &planErr)
+		assert.Equal(t, []string{"queries/001_people.up.gsql", "schema/001_person.up.gsql"}, planErr.Duplicates["001"])
+	})
+}
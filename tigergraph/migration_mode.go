@@ -0,0 +1,73 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidMigrationMode means that a migration mode was neither "up" nor "down"
+var ErrInvalidMigrationMode = errors.New("migration mode must be up or down")
+
+// MigrationMode is the direction of a migration: MigrationUp applies .up.gsql files and
+// MigrationDown applies .down.gsql files. It is stored on migration vertices and plans as the
+// string "up" or "down".
+type MigrationMode string
+
+const (
+	// MigrationUp moves a graph to a later migration
+	MigrationUp MigrationMode = "up"
+
+	// MigrationDown moves a graph to an earlier migration
+	MigrationDown MigrationMode = "down"
+)
+
+// ParseMigrationMode returns the migration mode named by s, or ErrInvalidMigrationMode.
+func ParseMigrationMode(s string) (MigrationMode, error) {
+	mode := MigrationMode(s)
+	if err := mode.Validate(); err != nil {
+		return "", err
+	}
+
+	return mode, nil
+}
+
+// Validate returns ErrInvalidMigrationMode unless the mode is MigrationUp or MigrationDown.
+func (m MigrationMode) Validate() error {
+	switch m {
+	case MigrationUp, MigrationDown:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidMigrationMode, string(m))
+	}
+}
+
+// String implements fmt.Stringer
+func (m MigrationMode) String() string {
+	return string(m)
+}
+
+// MarshalJSON implements json.Marshaler, refusing to write an invalid mode. The zero value,
+// used where there are no migrations to run, is written as "".
+func (m MigrationMode) MarshalJSON() ([]byte, error) {
+	if m != "" {
+		if err := m.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(string(m))
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectedMode MigrationMode
+		expectedErr  error
+	}{
+		{name: "up", input: "up", expectedMode: MigrationUp},
+		{name: "down", input: "down", expectedMode: MigrationDown},
+		{name: "misspelt", input: "don", expectedErr: ErrInvalidMigrationMode},
+		{name: "upper case", input: "UP", expectedErr: ErrInvalidMigrationMode},
+		{name: "empty", input: "", expectedErr: ErrInvalidMigrationMode},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mode, err := ParseMigrationMode(test.input)
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expectedMode, mode)
+		})
+	}
+}
+
+func TestMigrationModeJSON(t *testing.T) {
+	b, err := json.Marshal(MigrationVertexPayloadValue[MigrationMode]{MigrationDown})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"value": "down"}`, string(b))
+
+	b, err = json.Marshal(MigrationDiff{})
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"Mode":""`)
+
+	_, err = json.Marshal(MigrationVertexPayloadValue[MigrationMode]{"sideways"})
+	assert.ErrorIs(t, err, ErrInvalidMigrationMode)
+
+	var attributes MigrationVertexAttributes
+	assert.Nil(t, json.Unmarshal([]byte(`{"mode": "up"}`), &attributes))
+	assert.Equal(t, MigrationUp, attributes.Mode)
+}
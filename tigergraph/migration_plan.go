@@ -0,0 +1,114 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidMigrationPlan means the migration directory does not contain exactly one file for
+// each migration which would be run
+var ErrInvalidMigrationPlan = errors.New("migration directory does not match the migration plan")
+
+// MigrationPlanError lists the migrations in a plan which have no file, or more than one file,
+// in the migration directory. It is returned before any GSQL is run, and matches
+// ErrInvalidMigrationPlan with errors.Is.
+type MigrationPlanError struct {
+	Mode MigrationMode
+
+	// Missing are the migration numbers with no file, in plan order
+	Missing []string
+
+	// Duplicates maps migration numbers to the paths, relative to the migration directory, of the
+	// files which match them
+	Duplicates map[string][]string
+}
+
+// Error implements error
+func (e *MigrationPlanError) Error() string {
+	problems := make([]string, 0, len(e.Missing)+len(e.Duplicates))
+
+	if len(e.Missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing %s migrations: %s", e.Mode, strings.Join(e.Missing, ", ")))
+	}
+
+	numbers := make([]string, 0, len(e.Duplicates))
+	for number := range e.Duplicates {
+		numbers = append(numbers, number)
+	}
+	sort.Strings(numbers)
+
+	for _, number := range numbers {
+		problems = append(problems, fmt.Sprintf(
+			"duplicate %s migrations for %s: %s",
+			e.Mode,
+			number,
+			strings.Join(e.Duplicates[number], ", "),
+		))
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInvalidMigrationPlan, strings.Join(problems, "; "))
+}
+
+// Unwrap allows errors.Is to match ErrInvalidMigrationPlan
+func (e *MigrationPlanError) Unwrap() error {
+	return ErrInvalidMigrationPlan
+}
+
+// planMigrationFiles returns the file for each of the migration numbers, in order. A
+// *MigrationPlanError is returned if any migration has no file or more than one file.
+func planMigrationFiles(source migrationSource, numbers []string, mode MigrationMode) ([]string, error) {
+	if len(numbers) == 0 {
+		return []string{}, nil
+	}
+
+	files, err := source.files()
+	if err != nil {
+		return nil, err
+	}
+
+	expectedSuffix := fmt.Sprintf("%s.gsql", mode)
+	planErr := &MigrationPlanError{Mode: mode, Missing: []string{}, Duplicates: map[string][]string{}}
+	result := make([]string, 0, len(numbers))
+
+	for _, number := range numbers {
+		matches := make([]migrationFile, 0, 1)
+		for _, file := range files {
+			if strings.HasPrefix(file.name, number+"_") && strings.HasSuffix(file.name, expectedSuffix) {
+				matches = append(matches, file)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			planErr.Missing = append(planErr.Missing, number)
+		case 1:
+			result = append(result, matches[0].path)
+		default:
+			planErr.Duplicates[number] = make([]string, 0, len(matches))
+			for _, match := range matches {
+				planErr.Duplicates[number] = append(planErr.Duplicates[number], match.relativePath)
+			}
+		}
+	}
+
+	if len(planErr.Missing) > 0 || len(planErr.Duplicates) > 0 {
+		return nil, planErr
+	}
+
+	return result, nil
+}
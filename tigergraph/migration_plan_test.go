@@ -0,0 +1,67 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanMigrationFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"000_init.up.gsql",
+		"000_init.down.gsql",
+		"001_people.up.gsql",
+		"001_companies.up.gsql",
+		"001_people.down.gsql",
+		"003_places.up.gsql",
+	} {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(""), 0o600))
+	}
+
+	t.Run("returns one file per migration", func(t *testing.T) {
+		files, err := planMigrationFiles(migrationSource{dir: dir}, []string{"001", "000"}, "down")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(dir, "001_people.down.gsql"),
+			filepath.Join(dir, "000_init.down.gsql"),
+		}, files)
+	})
+
+	t.Run("reports gaps and duplicates", func(t *testing.T) {
+		_, err := planMigrationFiles(migrationSource{dir: dir}, []string{"000", "001", "002", "003"}, "up")
+		assert.ErrorIs(t, err, ErrInvalidMigrationPlan)
+
+		var planErr *MigrationPlanError
+		assert.ErrorAs(t, err, &planErr)
+		assert.Equal(t, []string{"002"}, planErr.Missing)
+		assert.Equal(t, map[string][]string{"001": {"001_companies.up.gsql", "001_people.up.gsql"}}, planErr.Duplicates)
+		assert.Equal(
+			t,
+			ErrInvalidMigrationPlan.Error()+": missing up migrations: 002; "+
+				"duplicate up migrations for 001: 001_companies.up.gsql, 001_people.up.gsql",
+			err.Error(),
+		)
+	})
+
+	t.Run("does not read the directory when there is nothing to run", func(t *testing.T) {
+		files, err := planMigrationFiles(migrationSource{dir: filepath.Join(dir, "missing")}, []string{}, "up")
+		assert.Nil(t, err)
+		assert.Empty(t, files)
+	})
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// MigrationReportSucceeded is the status of a migration run or step which succeeded
+	MigrationReportSucceeded = "succeeded"
+
+	// MigrationReportFailed is the status of a migration run or step which failed
+	MigrationReportFailed = "failed"
+
+	// MigrationReportPlanned is the status of a step of a dry run, which was not run
+	MigrationReportPlanned = "planned"
+
+	migrationReportFilePermissions = 0o600
+)
+
+// MigrationReport is a machine-readable record of a call to Migrate, written as JSON when the
+// client is configured with WithMigrationReport or WithMigrationReportFile, e.g. to be archived
+// as a build artifact for audits.
+type MigrationReport struct {
+	Graph         string        `json:"graph"`
+	TargetVersion string        `json:"target_version"`
+	StartVersion  string        `json:"start_version"`
+	ResultVersion string        `json:"result_version"`
+	Mode          MigrationMode `json:"mode,omitempty"`
+	DryRun        bool          `json:"dry_run"`
+	Status        string        `json:"status"`
+	Error         string        `json:"error,omitempty"`
+	StartedAt     time.Time     `json:"started_at"`
+	FinishedAt    time.Time     `json:"finished_at"`
+	Duration      string        `json:"duration"`
+
+	Steps []*MigrationReportStep `json:"steps"`
+}
+
+// MigrationReportStep records a single migration file run by Migrate. Digests are SHA-256,
+// of the file's GSQL and of the GSQL server's output while running it.
+type MigrationReportStep struct {
+	MigrationNumber  string        `json:"migration_number"`
+	File             string        `json:"file"`
+	Mode             MigrationMode `json:"mode"`
+	Status           string        `json:"status"`
+	Error            string        `json:"error,omitempty"`
+	StartedAt        time.Time     `json:"started_at,omitempty"`
+	Duration         string        `json:"duration,omitempty"`
+	GSQLDigest       string        `json:"gsql_digest,omitempty"`
+	TranscriptDigest string        `json:"transcript_digest,omitempty"`
+
+	transcript hash.Hash
+}
+
+// WithMigrationReport writes a JSON MigrationReport to w at the end of every call to Migrate,
+// whether it succeeds or fails.
+func WithMigrationReport(w io.Writer) Option {
+	return func(c *TigerGraphClient) {
+		c.MigrationReportWriter = w
+	}
+}
+
+// WithMigrationReportFile writes a JSON MigrationReport to the file at path at the end of every
+// call to Migrate, whether it succeeds or fails, replacing the report of any earlier call.
+func WithMigrationReportFile(path string) Option {
+	return func(c *TigerGraphClient) {
+		c.MigrationReportPath = path
+	}
+}
+
+// newMigrationReport starts a report of a call to Migrate, or returns nil if none is configured
+func (c *TigerGraphClient) newMigrationReport(graph string, version string, dryRun bool) *MigrationReport {
+	if c.MigrationReportWriter == nil && c.MigrationReportPath == "" {
+		return nil
+	}
+
+	return &MigrationReport{
+		Graph:         graph,
+		TargetVersion: version,
+		DryRun:        dryRun,
+		StartedAt:     c.now(),
+		Steps:         make([]*MigrationReportStep, 0),
+	}
+}
+
+// addStep records a step of the migration, returning nil if there is no report
+func (r *MigrationReport) addStep(number string, fileName string, mode MigrationMode) *MigrationReportStep {
+	if r == nil {
+		return nil
+	}
+
+	step := &MigrationReportStep{MigrationNumber: number, File: fileName, Mode: mode, Status: MigrationReportPlanned}
+	r.Steps = append(r.Steps, step)
+
+	return step
+}
+
+func (s *MigrationReportStep) start(now time.Time) {
+	if s == nil {
+		return
+	}
+
+	s.StartedAt = now
+	s.transcript = sha256.New()
+}
+
+func (s *MigrationReportStep) recordGSQL(gsql []byte) {
+	if s == nil {
+		return
+	}
+
+	sum := sha256.Sum256(gsql)
+	s.GSQLDigest = "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func (s *MigrationReportStep) recordOutput(output string) {
+	_, _ = io.WriteString(s.transcript, output)
+}
+
+func (s *MigrationReportStep) finish(duration time.Duration, err error) {
+	if s == nil {
+		return
+	}
+
+	s.Duration = duration.String()
+	s.TranscriptDigest = "sha256:" + hex.EncodeToString(s.transcript.Sum(nil))
+	s.Status = MigrationReportSucceeded
+	if err != nil {
+		s.Status = MigrationReportFailed
+		s.Error = err.Error()
+	}
+}
+
+// recordVersion records the version the graph is at after a step has been committed
+func (r *MigrationReport) recordVersion(version string) {
+	if r == nil {
+		return
+	}
+
+	r.ResultVersion = version
+}
+
+// versionAfterStep returns the version a graph is at once the i'th of the steps has been
+// committed: the step itself when migrating up, or else the step after it, or the target
+// version after the last step
+func versionAfterStep(steps []string, i int, mode MigrationMode, target string) string {
+	if mode == MigrationUp {
+		return steps[i]
+	}
+
+	if i+1 < len(steps) {
+		return steps[i+1]
+	}
+
+	return target
+}
+
+// withGSQLOutput passes the output of each GSQL request made by RunGSQL to fn
+func withGSQLOutput(fn func(string)) RequestOption {
+	return func(o *requestOptions) {
+		o.gsqlOutput = fn
+	}
+}
+
+// writeMigrationReport finishes the report with the outcome of Migrate and writes it out
+func (c *TigerGraphClient) writeMigrationReport(report *MigrationReport, migrateErr error) error {
+	if report == nil {
+		return nil
+	}
+
+	report.FinishedAt = c.now()
+	report.Duration = report.FinishedAt.Sub(report.StartedAt).String()
+	report.Status = MigrationReportSucceeded
+	if migrateErr != nil {
+		report.Status = MigrationReportFailed
+		report.Error = migrateErr.Error()
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal migration report: %w", err)
+	}
+
+	if c.MigrationReportWriter != nil {
+		if _, err = c.MigrationReportWriter.Write(data); err != nil {
+			return fmt.Errorf("failed to write migration report: %w", err)
+		}
+	}
+
+	if c.MigrationReportPath != "" {
+		if err = os.WriteFile(c.MigrationReportPath, data, migrationReportFilePermissions); err != nil {
+			return fmt.Errorf("failed to write migration report: %w", err)
+		}
+	}
+
+	return nil
+}
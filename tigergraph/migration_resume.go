@@ -0,0 +1,291 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+)
+
+const (
+	// MigrationPlanVertexType is the metadata vertex type which records the plan of each graph's
+	// latest migration, keyed by graph name
+	MigrationPlanVertexType = "MigrationPlan"
+
+	// MigrationPlanRunning is the status of a plan which has steps left to run
+	MigrationPlanRunning = "running"
+
+	// MigrationPlanCompleted is the status of a plan whose steps have all been committed
+	MigrationPlanCompleted = "completed"
+
+	// MigrationPlanAbandoned is the status of a plan given up with AbandonMigration
+	MigrationPlanAbandoned = "abandoned"
+)
+
+// MigrationPlanSchemaGSQL adds the MigrationPlan vertex type to the metadata graph
+//
+//go:embed gsql/migration_plan_schema.gsql
+var MigrationPlanSchemaGSQL string
+
+// ErrMigrationStepInterrupted means a migration step was started, but neither committed nor
+// seen to fail, e.g. because the process crashed while it ran
+var ErrMigrationStepInterrupted = errors.New("migration step was interrupted")
+
+// MigrationStepInterruptedError is returned when resuming a plan whose step was interrupted.
+// Its GSQL may or may not have been applied, so it is not run again. Once the graph has been
+// checked and repaired by hand, the plan can be given up with AbandonMigration. It matches
+// ErrMigrationStepInterrupted with errors.Is.
+type MigrationStepInterruptedError struct {
+	Graph           string
+	MigrationNumber string
+	Mode            MigrationMode
+	File            string
+}
+
+// Error implements error
+func (e *MigrationStepInterruptedError) Error() string {
+	return fmt.Sprintf(
+		"%s: %s migration %s of graph %s (%s) was started but not committed, and needs manual intervention",
+		ErrMigrationStepInterrupted,
+		e.Mode,
+		e.MigrationNumber,
+		e.Graph,
+		e.File,
+	)
+}
+
+// Unwrap allows errors.Is to match ErrMigrationStepInterrupted
+func (e *MigrationStepInterruptedError) Unwrap() error {
+	return ErrMigrationStepInterrupted
+}
+
+// MigrationPlan is the list of migration steps computed by Migrate, persisted in the metadata
+// graph before any of them run so that an interrupted migration can be resumed.
+type MigrationPlan struct {
+	Graph         string        `json:"graph_name"`
+	TargetVersion string        `json:"target_version"`
+	Mode          MigrationMode `json:"mode"`
+	Steps         []string      `json:"steps"`
+	Files         []string      `json:"files"`
+
+	// Completed is the number of steps which have been committed
+	Completed int `json:"completed"`
+
+	// InProgress is the migration number of the step being run, if any
+	InProgress string `json:"in_progress"`
+
+	Status string `json:"status"`
+}
+
+// WithResumableMigrations makes Migrate persist its plan in the metadata graph before running
+// it, so that ResumeMigration can pick up from the last committed step after a crash. The
+// MigrationPlan vertex type is added to the metadata graph by a built-in metadata migration.
+func WithResumableMigrations() Option {
+	return func(c *TigerGraphClient) {
+		c.ResumableMigrations = true
+	}
+}
+
+// ResumeMigration finishes every migration plan left running by Migrate, e.g. because the
+// process crashed, starting from the last committed step of each. Steps are not recomputed
+// from the migration directory; the files recorded in the plan are run. A step which was
+// started but not committed is not run again, and a *MigrationStepInterruptedError is returned.
+func (c *TigerGraphClient) ResumeMigration(ctx context.Context) error {
+	if err := c.ensureMetadataSchema(ctx); err != nil {
+		return err
+	}
+
+	plans, err := c.GetMigrationPlans(ctx)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, 0)
+	for i := range plans {
+		if plans[i].Status != MigrationPlanRunning {
+			continue
+		}
+
+		if err = c.runMigrationPlan(ctx, &plans[i], nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// AbandonMigration gives up the running migration plan of the graph, e.g. once an interrupted
+// step has been repaired by hand, so that Migrate computes a new plan.
+func (c *TigerGraphClient) AbandonMigration(ctx context.Context, graph string) error {
+	plan, err := c.runningMigrationPlan(ctx, graph)
+	if err != nil || plan == nil {
+		return err
+	}
+
+	plan.Status = MigrationPlanAbandoned
+	plan.InProgress = ""
+
+	return c.saveMigrationPlan(ctx, plan)
+}
+
+// GetMigrationPlans returns the latest migration plan of every graph.
+func (c *TigerGraphClient) GetMigrationPlans(ctx context.Context) ([]MigrationPlan, error) {
+	queryURL := fmt.Sprintf(VerticesURLTemplate, MetadataGraphName, MigrationPlanVertexType)
+	response := &TigerGraphResponse[ResponseVertex[MigrationPlan]]{}
+
+	err := c.Get(ctx, queryURL, MetadataGraphName, response, WithOperation("get migration plans"))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error {
+		return nil, newOperationError("get migration plans", MetadataGraphName, queryURL, fmt.Errorf(
+			"TigerGraph returned an error when getting migration plans. Message: %s: %w",
+			response.Message,
+			ErrTigerGraphError,
+		))
+	}
+
+	plans := make([]MigrationPlan, 0, len(response.Results))
+	for _, vertex := range response.Results {
+		plans = append(plans, vertex.Attributes)
+	}
+
+	return plans, nil
+}
+
+// runningMigrationPlan returns the graph's running migration plan, or nil if it has none.
+func (c *TigerGraphClient) runningMigrationPlan(ctx context.Context, graph string) (*MigrationPlan, error) {
+	plans, err := c.GetMigrationPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range plans {
+		if plans[i].Graph == graph && plans[i].Status == MigrationPlanRunning {
+			return &plans[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// saveMigrationPlan upserts the plan, replacing the graph's previous plan.
+func (c *TigerGraphClient) saveMigrationPlan(ctx context.Context, plan *MigrationPlan) error {
+	payload := c.NewUpsertPayload().AddVertex(MigrationPlanVertexType, plan.Graph, map[string]any{
+		"target_version": plan.TargetVersion,
+		"mode":           plan.Mode,
+		"steps":          plan.Steps,
+		"files":          plan.Files,
+		"completed":      plan.Completed,
+		"in_progress":    plan.InProgress,
+		"status":         plan.Status,
+		"updated_at":     c.now(),
+	})
+
+	res, err := c.Upsert(ctx, MetadataGraphName, payload, WithOperation("save migration plan"))
+	if err != nil {
+		return fmt.Errorf("failed to save the migration plan of graph %s: %w", plan.Graph, err)
+	}
+
+	if res.AcceptedVertices != 1 {
+		return fmt.Errorf(
+			"upsert of migration plan returned an unexpected number of accepted vertices. accepted: %d but expected only 1. error type: %w",
+			res.AcceptedVertices,
+			ErrTigerGraphSchemaSetUpFailed,
+		)
+	}
+
+	return nil
+}
+
+// runMigrationPlan runs the plan's remaining steps, recording each step in the plan before it
+// runs and after it is committed.
+func (c *TigerGraphClient) runMigrationPlan(ctx context.Context, plan *MigrationPlan, report *MigrationReport) error {
+	for plan.Completed < len(plan.Steps) {
+		number, file := plan.Steps[plan.Completed], plan.Files[plan.Completed]
+		step := report.addStep(number, file, plan.Mode)
+
+		if plan.InProgress == number {
+			// The step was interrupted, and is only done if its commit raced the interruption
+			committed, err := c.isMigrationCommitted(ctx, plan.Graph, number, plan.Mode)
+			if err != nil {
+				return err
+			}
+
+			if !committed {
+				return &MigrationStepInterruptedError{Graph: plan.Graph, MigrationNumber: number, Mode: plan.Mode, File: file}
+			}
+		} else {
+			plan.InProgress = number
+			if err := c.saveMigrationPlan(ctx, plan); err != nil {
+				return err
+			}
+
+			if err := c.tryMigrateStep(ctx, file, step); err != nil {
+				// The step failed rather than being interrupted, so it can safely be run again
+				plan.InProgress = ""
+				return errors.Join(err, c.saveMigrationPlan(ctx, plan))
+			}
+
+			if err := c.commitMigrationVersion(ctx, plan.Graph, number, plan.Mode); err != nil {
+				return fmt.Errorf(trackMigrationFailureTemplate, number, err)
+			}
+		}
+		report.recordVersion(versionAfterStep(plan.Steps, plan.Completed, plan.Mode, plan.TargetVersion))
+
+		plan.Completed++
+		plan.InProgress = ""
+		if plan.Completed == len(plan.Steps) {
+			plan.Status = MigrationPlanCompleted
+		}
+
+		if err := c.saveMigrationPlan(ctx, plan); err != nil {
+			return err
+		}
+
+		if err := c.seedMigration(ctx, plan.Graph, number, file, plan.Mode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isMigrationCommitted reports whether the graph's latest committed migration is the given step.
+func (c *TigerGraphClient) isMigrationCommitted(
+	ctx context.Context,
+	graph string,
+	number string,
+	mode MigrationMode,
+) (bool, error) {
+	latest, err := c.latestMigration(ctx, graph)
+	if err != nil {
+		return false, err
+	}
+
+	return latest != nil && latest.Attributes.MigrationNumber == number && latest.Attributes.Mode == mode, nil
+}
+
+// resumeRunningMigrationPlan finishes the graph's running migration plan, if it has one.
+func (c *TigerGraphClient) resumeRunningMigrationPlan(ctx context.Context, graph string, report *MigrationReport) error {
+	plan, err := c.runningMigrationPlan(ctx, graph)
+	if err != nil || plan == nil {
+		return err
+	}
+
+	return c.runMigrationPlan(ctx, plan, report)
+}
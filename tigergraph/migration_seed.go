@@ -0,0 +1,319 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// MigrationSeedVertexType is the metadata vertex type which records the seeds loaded for
+	// each migration of each graph
+	MigrationSeedVertexType = "MigrationSeed"
+
+	// SeedManifestSuffix names the seed manifest of a migration, which sits beside its
+	// .up.gsql file, e.g. 001_countries.seeds.json for 001_countries.up.gsql
+	SeedManifestSuffix = ".seeds.json"
+
+	// MigrationSeedApplied is the status of a seed which has been loaded
+	MigrationSeedApplied = "applied"
+
+	// MigrationSeedReverted is the status of a seed whose migration has been migrated down, so
+	// that it is loaded again when the migration is next run up
+	MigrationSeedReverted = "reverted"
+)
+
+// MigrationSeedSchemaGSQL adds the MigrationSeed vertex type to the metadata graph
+//
+//go:embed gsql/migration_seed_schema.gsql
+var MigrationSeedSchemaGSQL string
+
+var (
+	// ErrInvalidSeedManifest represents a seed manifest or seed file which could not be read
+	ErrInvalidSeedManifest = errors.New("invalid migration seed manifest")
+
+	// ErrMigrationSeedFailed represents a seed which failed to load. The migration it belongs to
+	// has been committed, and the seed is loaded again by the next Migrate or ApplyMigrationSeeds.
+	ErrMigrationSeedFailed = errors.New("failed to load migration seed")
+)
+
+// SeedManifest lists the data seeds loaded after a migration is run up, e.g.
+//
+//	{"seeds": [{"loading_job": "load_countries", "file": "seeds/countries.jsonl"}]}
+type SeedManifest struct {
+	Seeds []Seed `json:"seeds"`
+}
+
+// Seed is a JSONL file loaded into the migrated graph with one of its loading jobs. The file
+// is relative to the manifest.
+type Seed struct {
+	LoadingJob string `json:"loading_job"`
+	File       string `json:"file"`
+}
+
+// MigrationSeedRecord records a seed loaded into a graph, stored in the metadata graph.
+type MigrationSeedRecord struct {
+	ID              string `json:"id"`
+	Graph           string `json:"graph_name"`
+	MigrationNumber string `json:"migration_number"`
+	LoadingJob      string `json:"loading_job"`
+	File            string `json:"file"`
+	Lines           int    `json:"lines"`
+	Status          string `json:"status"`
+}
+
+// GetMigrationSeeds returns the seed records of the graph.
+func (c *TigerGraphClient) GetMigrationSeeds(ctx context.Context, graph string) ([]MigrationSeedRecord, error) {
+	queryURL := fmt.Sprintf(VerticesURLTemplate, MetadataGraphName, MigrationSeedVertexType)
+	response := &TigerGraphResponse[ResponseVertex[MigrationSeedRecord]]{}
+
+	err := c.Get(ctx, queryURL, MetadataGraphName, response, WithOperation("get migration seeds"))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error {
+		return nil, newOperationError("get migration seeds", MetadataGraphName, queryURL, fmt.Errorf(
+			"TigerGraph returned an error when getting migration seeds. Message: %s: %w",
+			response.Message,
+			ErrTigerGraphError,
+		))
+	}
+
+	records := make([]MigrationSeedRecord, 0)
+	for _, vertex := range response.Results {
+		if vertex.Attributes.Graph == graph {
+			records = append(records, vertex.Attributes)
+		}
+	}
+
+	return records, nil
+}
+
+// ApplyMigrationSeeds loads the seeds of every migration the graph has been migrated up to
+// which have not been loaded, e.g. because loading them failed. Migrate does this before
+// running new migrations.
+func (c *TigerGraphClient) ApplyMigrationSeeds(ctx context.Context, graph string, migrationFileDir string) error {
+	if err := c.ensureMetadataSchema(ctx); err != nil {
+		return err
+	}
+
+	source := c.migrationSource(migrationFileDir)
+
+	currentMigrationNumber, err := c.getCurrentMigrationNumber(ctx, graph, source)
+	if err != nil {
+		return err
+	}
+
+	return c.applyPendingSeeds(ctx, graph, source, currentMigrationNumber)
+}
+
+// applyPendingSeeds loads the seeds of the migrations up to and including current which have
+// not been loaded. Nothing is requested from TigerGraph if no migration has a seed manifest.
+func (c *TigerGraphClient) applyPendingSeeds(
+	ctx context.Context,
+	graph string,
+	source migrationSource,
+	current string,
+) error {
+	if current == "" {
+		return nil
+	}
+
+	files, err := source.files()
+	if err != nil {
+		return err
+	}
+
+	comparator := c.migrationComparator()
+	for _, file := range files {
+		if !strings.HasSuffix(file.name, ".up.gsql") {
+			continue
+		}
+		if _, err = os.Stat(seedManifestPath(file.path)); err != nil {
+			continue
+		}
+
+		number, _, _ := strings.Cut(file.name, "_")
+		cmp, err := comparator.Compare(number, current)
+		if err != nil {
+			return err
+		}
+
+		if cmp <= 0 {
+			if err = c.seedMigration(ctx, graph, number, file.path, MigrationUp); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// seedMigration loads the seeds of a migration which has been run up, or marks them reverted
+// once it has been run down. Seeds already loaded are skipped.
+func (c *TigerGraphClient) seedMigration(
+	ctx context.Context,
+	graph string,
+	number string,
+	migrationFile string,
+	mode MigrationMode,
+) error {
+	manifestPath := seedManifestPath(migrationFile)
+	manifest, err := readSeedManifest(manifestPath)
+	if err != nil || manifest == nil {
+		return err
+	}
+
+	records, err := c.GetMigrationSeeds(ctx, graph)
+	if err != nil {
+		return err
+	}
+
+	applied := make(map[string]bool, len(records))
+	for _, record := range records {
+		applied[record.ID] = record.Status == MigrationSeedApplied
+	}
+
+	for _, seed := range manifest.Seeds {
+		record := MigrationSeedRecord{
+			ID:              fmt.Sprintf("%s_%s_%s", graph, number, seed.File),
+			Graph:           graph,
+			MigrationNumber: number,
+			LoadingJob:      seed.LoadingJob,
+			File:            seed.File,
+		}
+
+		if mode == MigrationDown {
+			if applied[record.ID] {
+				record.Status = MigrationSeedReverted
+				if err = c.saveMigrationSeedRecord(ctx, record); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if applied[record.ID] {
+			continue
+		}
+
+		lines, err := readSeedFile(filepath.Join(filepath.Dir(manifestPath), seed.File))
+		if err != nil {
+			return err
+		}
+
+		c.log(c.LogLevels.MigrationStep, "loading migration seed", "file", seed.File, "lines", len(lines))
+		if err = c.RunLoadingJobJSONL(ctx, graph, seed.LoadingJob, lines); err != nil {
+			return fmt.Errorf("seed %s of migration %s: %w: %w", seed.File, number, ErrMigrationSeedFailed, err)
+		}
+
+		record.Lines = len(lines)
+		record.Status = MigrationSeedApplied
+		if err = c.saveMigrationSeedRecord(ctx, record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// seedManifestPath returns the path of the seed manifest of a migration file.
+func seedManifestPath(migrationFile string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(migrationFile, ".up.gsql"), ".down.gsql")
+	return base + SeedManifestSuffix
+}
+
+// readSeedManifest reads a seed manifest, returning nil if it does not exist.
+func readSeedManifest(manifestPath string) (*SeedManifest, error) {
+	data, err := os.ReadFile(manifestPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &SeedManifest{}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidSeedManifest, manifestPath, err)
+	}
+
+	for i, seed := range manifest.Seeds {
+		if seed.LoadingJob == "" || seed.File == "" {
+			return nil, fmt.Errorf("%w: %s: seed %d needs a loading_job and a file", ErrInvalidSeedManifest, manifestPath, i)
+		}
+	}
+
+	return manifest, nil
+}
+
+// readSeedFile reads the lines of a JSONL seed file, skipping blank lines.
+func readSeedFile(seedPath string) ([]any, error) {
+	data, err := os.ReadFile(seedPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSeedManifest, err)
+	}
+
+	lines := make([]any, 0)
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		if !json.Valid(line) {
+			return nil, fmt.Errorf("%w: %s line %d is not valid JSON", ErrInvalidSeedManifest, seedPath, i+1)
+		}
+		lines = append(lines, json.RawMessage(line))
+	}
+
+	return lines, nil
+}
+
+// saveMigrationSeedRecord upserts the record of a seed.
+func (c *TigerGraphClient) saveMigrationSeedRecord(ctx context.Context, record MigrationSeedRecord) error {
+	payload := c.NewUpsertPayload().AddVertex(MigrationSeedVertexType, record.ID, map[string]any{
+		"graph_name":       record.Graph,
+		"migration_number": record.MigrationNumber,
+		"loading_job":      record.LoadingJob,
+		"file":             record.File,
+		"lines":            record.Lines,
+		"status":           record.Status,
+		"updated_at":       c.now(),
+	})
+
+	res, err := c.Upsert(ctx, MetadataGraphName, payload, WithOperation("save migration seed"))
+	if err != nil {
+		return fmt.Errorf("failed to record seed %s of graph %s: %w", record.File, record.Graph, err)
+	}
+
+	if res.AcceptedVertices != 1 {
+		return fmt.Errorf(
+			"upsert of migration seed returned an unexpected number of accepted vertices. accepted: %d but expected only 1. error type: %w",
+			res.AcceptedVertices,
+			ErrTigerGraphSchemaSetUpFailed,
+		)
+	}
+
+	return nil
+}
@@ -25,7 +25,7 @@ func TestGetMigrationsBetweenVersions(t *testing.T) { //nolint:funlen
 		from               string
 		to                 string
 		expectedMigrations []string
-		expectedMode       string
+		expectedMode       MigrationMode
 		expectedError      error
 	}{
 		{
@@ -88,7 +88,7 @@ func TestGetMigrationsBetweenVersions(t *testing.T) { //nolint:funlen
 
 	for _, testCase := range cases {
 		t.Run(testCase.name, func(t *testing.T) {
-			migrations, mode, err := getMigrationsBetweenVersions(testCase.from, testCase.to)
+			migrations, mode, err := getMigrationsBetweenVersions(NumericMigrationComparator{}, testCase.from, testCase.to, migrationSource{})
 			assert.Equal(t, testCase.expectedMigrations, migrations)
 			assert.Equal(t, testCase.expectedMode, mode)
 			assert.ErrorIs(t, err, testCase.expectedError)
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrResultKeyMismatch represents a results entry which could not be decoded into the type
+	// registered for its key
+	ErrResultKeyMismatch = errors.New("results entry does not match the registered type")
+
+	// ErrResultKeyMissing represents a registered key which was not printed by the query
+	ErrResultKeyMissing = errors.New("registered key was not found in the results")
+)
+
+// MultiResult decodes a response from a query which PRINTs several differently shaped
+// values. Each entry in the "results" array is decoded into the target registered for
+// its print key, e.g.
+//
+//	var count int
+//	var people []ResponseVertex[Person]
+//	result := NewMultiResult().Register("@@count", &count).Register("people", &people)
+//	err := client.Get(ctx, "/query/MyGraph/my_query", "MyGraph", result)
+//
+// Keys which have no registered target are ignored. Every entry is also kept in Results, so
+// that values can be decoded by name afterwards with DecodeNamedResult. Numbers are decoded
+// into interface{} values as json.Number if the client decoding the response has
+// UseJSONNumber set.
+type MultiResult struct {
+	Version Version
+	Message string
+	Error   bool
+	Results []NamedResults
+
+	targets   map[string]any
+	useNumber bool
+}
+
+// NewMultiResult creates an empty MultiResult. Targets are added with Register.
+func NewMultiResult() *MultiResult {
+	return &MultiResult{
+		targets: make(map[string]any),
+	}
+}
+
+// Register sets the target which the results entry printed under key is decoded into.
+// The target must be a pointer.
+func (m *MultiResult) Register(key string, target any) *MultiResult {
+	if m.targets == nil {
+		m.targets = make(map[string]any)
+	}
+	m.targets[key] = target
+	return m
+}
+
+func (m *MultiResult) useJSONNumber(use bool) {
+	m.useNumber = use
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (m *MultiResult) UnmarshalJSON(data []byte) error {
+	var response TigerGraphResponse[NamedResults]
+	if err := json.Unmarshal(data, &response); err != nil {
+		return err
+	}
+
+	m.Version = response.Version
+	m.Message = response.Message
+	m.Error = response.Error
+	m.Results = response.Results
+	for i := range m.Results {
+		m.Results[i].useNumber = m.useNumber
+	}
+
+	// TigerGraph returns no results alongside an error, so there is nothing to match
+	if response.Error {
+		return nil
+	}
+
+	found := make(map[string]bool, len(m.targets))
+	for i, entry := range m.Results {
+		for key, target := range m.targets {
+			if !entry.Has(key) {
+				continue
+			}
+
+			if err := entry.Decode(key, target); err != nil {
+				return fmt.Errorf("results[%d]: %w", i, err)
+			}
+			found[key] = true
+		}
+	}
+
+	missing := make([]string, 0)
+	for key := range m.targets {
+		if !found[key] {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("keys %v were not printed by the query: %w", missing, ErrResultKeyMissing)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiResult(t *testing.T) { //nolint:funlen
+	type person struct {
+		Name string `json:"name"`
+	}
+
+	body := `{
+		"error": false,
+		"message": "",
+		"results": [
+			{"@@count": 2},
+			{"people": [{"v_id": "1", "v_type": "Person", "attributes": {"name": "Alice"}}]},
+			{"ignored": "value"}
+		]
+	}`
+
+	t.Run("decodes each key into its registered target", func(t *testing.T) {
+		var count int
+		var people []ResponseVertex[person]
+		result := NewMultiResult().Register("@@count", &count).Register("people", &people)
+
+		assert.Nil(t, json.Unmarshal([]byte(body), result))
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []ResponseVertex[person]{
+			{VID: "1", VType: "Person", Attributes: person{Name: "Alice"}},
+		}, people)
+	})
+
+	t.Run("keeps every entry by name", func(t *testing.T) {
+		var count int
+		result := NewMultiResult().Register("@@count", &count)
+
+		assert.Nil(t, json.Unmarshal([]byte(body), result))
+		assert.Len(t, result.Results, 3)
+
+		ignored, err := DecodeNamedResult[string](result.Results, "ignored")
+		assert.Nil(t, err)
+		assert.Equal(t, "value", ignored)
+	})
+
+	t.Run("reports mismatched types with the key", func(t *testing.T) {
+		var count string
+		result := NewMultiResult().Register("@@count", &count)
+
+		err := json.Unmarshal([]byte(body), result)
+		assert.ErrorIs(t, err, ErrResultKeyMismatch)
+		assert.Contains(t, err.Error(), "@@count")
+	})
+
+	t.Run("reports missing keys", func(t *testing.T) {
+		var other int
+		result := NewMultiResult().Register("@@other", &other)
+
+		err := json.Unmarshal([]byte(body), result)
+		assert.ErrorIs(t, err, ErrResultKeyMissing)
+		assert.Contains(t, err.Error(), "@@other")
+	})
+
+	t.Run("error responses are not matched", func(t *testing.T) {
+		var count int
+		result := NewMultiResult().Register("@@count", &count)
+
+		err := json.Unmarshal([]byte(`{"error": true, "message": "query failed", "results": null}`), result)
+		assert.Nil(t, err)
+		assert.True(t, result.Error)
+		assert.Equal(t, "query failed", result.Message)
+	})
+}
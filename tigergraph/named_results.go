@@ -0,0 +1,99 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// NamedResults is a results entry left undecoded under each name the query PRINTs, for
+// queries which PRINT several differently shaped values without a struct for the whole
+// response, e.g.
+//
+//	var response NamedResultsResponse
+//	err := client.Get(ctx, "/query/MyGraph/my_query", "MyGraph", &response)
+//	count, err := DecodeNamedResult[int](response.Results, "@@count")
+//	people, err := DecodeNamedResult[[]ResponseVertex[Person]](response.Results, "people")
+//
+// Numbers are decoded into interface{} values as json.Number if the client which decoded the
+// response has UseJSONNumber set. Each entry of a MultiResult is kept as NamedResults.
+type NamedResults struct {
+	values    map[string]json.RawMessage
+	useNumber bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *NamedResults) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &n.values)
+}
+
+// NamedResultsResponse is a response whose results entries are decoded as NamedResults. It is
+// a MultiResult, which need not have any targets registered.
+type NamedResultsResponse = MultiResult
+
+// Has reports whether the entry holds a value printed under key.
+func (n NamedResults) Has(key string) bool {
+	_, ok := n.values[key]
+	return ok
+}
+
+// Keys returns the names printed in the entry, in name order.
+func (n NamedResults) Keys() []string {
+	keys := make([]string, 0, len(n.values))
+	for key := range n.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Decode decodes the value printed under key into target, which must be a pointer. It fails
+// with ErrResultKeyMissing if the entry has no such key, and ErrResultKeyMismatch if the value
+// does not fit target.
+func (n NamedResults) Decode(key string, target any) error {
+	raw, ok := n.values[key]
+	if !ok {
+		return fmt.Errorf("key %q was not printed by the query: %w", key, ErrResultKeyMissing)
+	}
+
+	if err := decodeJSON(raw, target, n.useNumber); err != nil {
+		return fmt.Errorf("failed to decode key %q into %T: %s: %w", key, target, err, ErrResultKeyMismatch)
+	}
+
+	return nil
+}
+
+// DecodeNamedResult decodes the value printed under key in the first results entry which has
+// it. It fails with ErrResultKeyMissing if no entry has the key, and ErrResultKeyMismatch if
+// the value is not a T.
+func DecodeNamedResult[T any](results []NamedResults, key string) (T, error) {
+	var value T
+
+	for i, entry := range results {
+		if !entry.Has(key) {
+			continue
+		}
+
+		if err := entry.Decode(key, &value); err != nil {
+			return value, fmt.Errorf("results[%d]: %w", i, err)
+		}
+
+		return value, nil
+	}
+
+	return value, fmt.Errorf("key %q was not printed by the query: %w", key, ErrResultKeyMissing)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamedResults(t *testing.T) { //nolint:funlen
+	type person struct {
+		Name string `json:"name"`
+	}
+
+	body := `{
+		"error": false,
+		"message": "",
+		"results": [
+			{"@@count": 2, "@@total": 5},
+			{"people": [{"v_id": "1", "v_type": "Person", "attributes": {"name": "Alice"}}]}
+		]
+	}`
+
+	var response NamedResultsResponse
+	assert.Nil(t, json.Unmarshal([]byte(body), &response))
+
+	t.Run("lists the keys of each entry", func(t *testing.T) {
+		assert.Equal(t, []string{"@@count", "@@total"}, response.Results[0].Keys())
+		assert.True(t, response.Results[1].Has("people"))
+		assert.False(t, response.Results[1].Has("@@count"))
+	})
+
+	t.Run("decodes keys from any entry", func(t *testing.T) {
+		count, err := DecodeNamedResult[int](response.Results, "@@count")
+		assert.Nil(t, err)
+		assert.Equal(t, 2, count)
+
+		people, err := DecodeNamedResult[[]ResponseVertex[person]](response.Results, "people")
+		assert.Nil(t, err)
+		assert.Equal(t, []ResponseVertex[person]{
+			{VID: "1", VType: "Person", Attributes: person{Name: "Alice"}},
+		}, people)
+	})
+
+	t.Run("decodes keys of a single entry", func(t *testing.T) {
+		var total int
+		assert.Nil(t, response.Results[0].Decode("@@total", &total))
+		assert.Equal(t, 5, total)
+	})
+
+	t.Run("reports mismatched types with the key and entry", func(t *testing.T) {
+		_, err := DecodeNamedResult[string](response.Results, "@@count")
+		assert.ErrorIs(t, err, ErrResultKeyMismatch)
+		assert.Contains(t, err.Error(), "results[0]")
+		assert.Contains(t, err.Error(), "@@count")
+	})
+
+	t.Run("reports missing keys", func(t *testing.T) {
+		_, err := DecodeNamedResult[int](response.Results, "@@other")
+		assert.ErrorIs(t, err, ErrResultKeyMissing)
+		assert.Contains(t, err.Error(), "@@other")
+
+		var other int
+		assert.ErrorIs(t, response.Results[1].Decode("@@other", &other), ErrResultKeyMissing)
+	})
+}
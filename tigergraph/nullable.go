@@ -0,0 +1,168 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strconv"
+)
+
+// Null wraps an attribute value which may be unset. TigerGraph has no null values, so
+// unset attributes are returned with their default value; use DecodeAttributes to decode
+// those as Null values with Valid set to false.
+type Null[T any] struct {
+	Value T
+	Valid bool
+}
+
+// NewNull returns a valid Null holding the given value.
+func NewNull[T any](value T) Null[T] {
+	return Null[T]{Value: value, Valid: true}
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Numbers held in interface{} values, e.g. in a
+// Null[any], are decoded as json.Number whether or not UseJSONNumber is set, because a Null
+// cannot tell how the response around it is decoded, and so that INT64 values keep their
+// precision.
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		var zero T
+		n.Value = zero
+		n.Valid = false
+		return nil
+	}
+
+	if err := decodeJSON(data, &n.Value, true); err != nil {
+		return err
+	}
+	n.Valid = true
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.Value)
+}
+
+// GetVertexType returns the vertex type with the given name from the graph metadata.
+func (r *GraphMetadataResponseResult) GetVertexType(name string) (*GraphMetadataVertexType, bool) {
+	for i := range r.VertexTypes {
+		if r.VertexTypes[i].Name == name {
+			return &r.VertexTypes[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// DecodeAttributes decodes the attributes of a vertex into target. Attributes which hold the
+// default value for their type in the vertex schema are treated as unset and decoded as null,
+// so Null fields in target are left invalid.
+func DecodeAttributes(data []byte, vertexType *GraphMetadataVertexType, target any) error {
+	return decodeAttributes(data, vertexType, target, false)
+}
+
+// DecodeAttributes is DecodeAttributes, decoding numbers into interface{} values as
+// json.Number if UseJSONNumber is set on the client.
+func (c *TigerGraphClient) DecodeAttributes(data []byte, vertexType *GraphMetadataVertexType, target any) error {
+	return decodeAttributes(data, vertexType, target, c.UseJSONNumber)
+}
+
+func decodeAttributes(data []byte, vertexType *GraphMetadataVertexType, target any, useNumber bool) error {
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return err
+	}
+
+	for _, attribute := range vertexType.Attributes {
+		raw, exists := attributes[attribute.AttributeName]
+		if !exists {
+			continue
+		}
+
+		var value any
+		if err := decodeJSON(raw, &value, true); err != nil {
+			return err
+		}
+
+		if isAttributeDefault(value, attribute) {
+			attributes[attribute.AttributeName] = json.RawMessage("null")
+		}
+	}
+
+	marked, err := json.Marshal(attributes)
+	if err != nil {
+		return err
+	}
+
+	return decodeJSON(marked, target, useNumber)
+}
+
+// isAttributeDefault reports whether value, decoded with numbers as json.Number, is the value
+// TigerGraph uses for the attribute when it is unset. Numbers are compared exactly, so that
+// INT64 values next to the default are not mistaken for it.
+func isAttributeDefault(value any, attribute GraphMetadataAttribute) bool {
+	unset := attributeDefault(attribute)
+
+	if number, ok := value.(json.Number); ok {
+		unsetNumber, numeric := unset.(*big.Rat)
+		parsed, valid := new(big.Rat).SetString(number.String())
+		return numeric && valid && parsed.Cmp(unsetNumber) == 0
+	}
+
+	return value == unset
+}
+
+// attributeDefault returns the value TigerGraph uses for the attribute when it is unset,
+// as it would be decoded into an interface{}, or as a *big.Rat for numbers.
+func attributeDefault(attribute GraphMetadataAttribute) any {
+	typeName := attribute.AttributeType.Name
+
+	switch typeName {
+	case "INT", "UINT", "FLOAT", "DOUBLE":
+		if attribute.DefaultValue != nil {
+			if parsed, ok := new(big.Rat).SetString(*attribute.DefaultValue); ok {
+				return parsed
+			}
+		}
+		return new(big.Rat)
+	case "BOOL":
+		if attribute.DefaultValue != nil {
+			if parsed, err := strconv.ParseBool(*attribute.DefaultValue); err == nil {
+				return parsed
+			}
+		}
+		return false
+	case "DATETIME":
+		if attribute.DefaultValue != nil {
+			return *attribute.DefaultValue
+		}
+		return DefaultDateTime
+	case "STRING", "STRING COMPRESS":
+		if attribute.DefaultValue != nil {
+			return *attribute.DefaultValue
+		}
+		return ""
+	default:
+		// Collections and user defined types have no meaningful unset value
+		return nil
+	}
+}
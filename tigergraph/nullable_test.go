@@ -0,0 +1,77 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullJSON(t *testing.T) {
+	var value Null[int]
+	assert.Nil(t, json.Unmarshal([]byte("5"), &value))
+	assert.Equal(t, NewNull(5), value)
+
+	assert.Nil(t, json.Unmarshal([]byte("null"), &value))
+	assert.Equal(t, Null[int]{}, value)
+
+	marshalled, err := json.Marshal(struct {
+		Set   Null[string] `json:"set"`
+		Unset Null[string] `json:"unset"`
+	}{Set: NewNull("a")})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"set":"a","unset":null}`, string(marshalled))
+}
+
+func TestDecodeAttributes(t *testing.T) {
+	type person struct {
+		Name    Null[string] `json:"name"`
+		Age     Null[int]    `json:"age"`
+		Score   Null[int]    `json:"score"`
+		Born    Null[string] `json:"born"`
+		Country string       `json:"country"`
+	}
+
+	minusOne := "-1"
+	vertexType := &GraphMetadataVertexType{
+		Name: "Person",
+		Attributes: []GraphMetadataAttribute{
+			{AttributeName: "name", AttributeType: GraphMetadataAttributeType{Name: "STRING"}},
+			{AttributeName: "age", AttributeType: GraphMetadataAttributeType{Name: "INT"}},
+			{AttributeName: "score", AttributeType: GraphMetadataAttributeType{Name: "INT"}, DefaultValue: &minusOne},
+			{AttributeName: "born", AttributeType: GraphMetadataAttributeType{Name: "DATETIME"}},
+			{AttributeName: "country", AttributeType: GraphMetadataAttributeType{Name: "STRING"}},
+		},
+	}
+
+	data := `{"name": "", "age": 0, "score": -1, "born": "1970-01-01 00:00:00", "country": ""}`
+
+	var unset person
+	assert.Nil(t, DecodeAttributes([]byte(data), vertexType, &unset))
+	assert.Equal(t, person{}, unset)
+
+	data = `{"name": "Alice", "age": 30, "score": 0, "born": "1990-01-01 00:00:00", "country": "UK"}`
+
+	var set person
+	assert.Nil(t, DecodeAttributes([]byte(data), vertexType, &set))
+	assert.Equal(t, person{
+		Name:    NewNull("Alice"),
+		Age:     NewNull(30),
+		Score:   NewNull(0),
+		Born:    NewNull("1990-01-01 00:00:00"),
+		Country: "UK",
+	}, set)
+}
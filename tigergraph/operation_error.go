@@ -0,0 +1,91 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OperationError is returned by Get, Post, PostRaw and RunGSQL, and the methods built on them,
+// recording the operation, graph and endpoint of a failed call so that failures can be told
+// apart when a client works with several graphs. It unwraps to the underlying error.
+type OperationError struct {
+	Op       string
+	Graph    string
+	Endpoint string
+	Err      error
+}
+
+// Error implements error, e.g. "upsert graph=MyGraph endpoint=/graph/MyGraph: ..."
+func (e *OperationError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Op)
+	if e.Graph != "" {
+		fmt.Fprintf(&b, " graph=%s", e.Graph)
+	}
+	if e.Endpoint != "" {
+		fmt.Fprintf(&b, " endpoint=%s", e.Endpoint)
+	}
+	fmt.Fprintf(&b, ": %s", e.Err)
+
+	return b.String()
+}
+
+// Unwrap returns the underlying error
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// WithOperation names the operation a call is part of in the OperationError it returns.
+// Defaults to the HTTP method, or "gsql" for RunGSQL.
+func WithOperation(op string) RequestOption {
+	return func(o *requestOptions) {
+		o.operation = op
+	}
+}
+
+// operationName returns the operation named with WithOperation, or defaultOp.
+func operationName(opts []RequestOption, defaultOp string) string {
+	if options := collectRequestOptions(opts); options.operation != "" {
+		return options.operation
+	}
+
+	return defaultOp
+}
+
+// operationOptions names the operation of a call made by the library, unless the caller has
+// already named it.
+func operationOptions(op string, opts []RequestOption) []RequestOption {
+	return append([]RequestOption{WithOperation(op)}, opts...)
+}
+
+// newOperationError wraps err in an *OperationError, unless it is nil or already wrapped by
+// an inner call.
+func newOperationError(op string, graph string, queryURL string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var opErr *OperationError
+	if errors.As(err, &opErr) {
+		return err
+	}
+
+	// Query strings are left out as they may contain user data
+	endpoint, _, _ := strings.Cut(queryURL, "?")
+
+	return &OperationError{Op: op, Graph: graph, Endpoint: endpoint, Err: err}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrInvalidOption is returned from every request made by a client if one of the options it
+// was created with could not be applied
+var ErrInvalidOption = errors.New("invalid client option")
+
+// Option configures a TigerGraphClient created with NewClient.
+type Option func(c *TigerGraphClient)
+
+// WithFileURL sets the URL of the GSQL server, used to run GSQL. Defaults to the RESTPP base URL.
+func WithFileURL(baseFileURL string) Option {
+	return func(c *TigerGraphClient) {
+		c.BaseFileURL = baseFileURL
+	}
+}
+
+// WithCredentials sets the basic auth username and password used to run GSQL and request tokens.
+func WithCredentials(username string, password string) Option {
+	return func(c *TigerGraphClient) {
+		c.BasicAuthUsername = username
+		c.BasicAuthPassword = password
+	}
+}
+
+// WithCredentialsProvider sets the CredentialsProvider used in place of static credentials.
+func WithCredentialsProvider(provider CredentialsProvider) Option {
+	return func(c *TigerGraphClient) {
+		c.CredentialsProvider = provider
+	}
+}
+
+// WithSecret sets the GSQL secret used to request tokens for the graph, in place of the basic
+// auth credentials, as TigerGraph recommends. The basic auth credentials are still used to
+// run GSQL. Use GlobalGraph for a secret whose tokens are not bound to a graph.
+func WithSecret(graph string, secret string) Option {
+	return func(c *TigerGraphClient) {
+		if c.Secrets == nil {
+			c.Secrets = make(map[string]string)
+		}
+		c.Secrets[graph] = secret
+	}
+}
+
+// WithHTTPClient sets the *http.Client used to make every request. Its transport's connection
+// pool settings are kept. Options which configure the transport, e.g. WithTLSConfig or
+// WithConnectionPool, must come after it.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *TigerGraphClient) {
+		c.HTTPClient = httpClient
+		c.ownedTransport = nil
+		c.ConnectionPool = ConnectionPool{}
+	}
+}
+
+// WithTimeout sets the client's request timeout for Get, Post, PostRaw and RunGSQL.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *TigerGraphClient) {
+		c.Timeouts.Request = timeout
+	}
+}
+
+// WithRetryPolicy sets the RetryPolicy used for requests to RESTPP.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *TigerGraphClient) {
+		c.RetryPolicy = policy
+	}
+}
+
+// setOptionErr records the first option which could not be applied.
+func (c *TigerGraphClient) setOptionErr(err error) {
+	if c.optionErr == nil {
+		c.optionErr = fmt.Errorf("%w: %w", ErrInvalidOption, err)
+	}
+}
+
+// ownTransport returns an *http.Transport which belongs to this client and can be configured.
+// On first use the client's HTTP client and its transport are copied, so that neither
+// http.DefaultClient, http.DefaultTransport nor a client given to WithHTTPClient is modified.
+func (c *TigerGraphClient) ownTransport() (*http.Transport, error) {
+	if c.ownedTransport != nil {
+		return c.ownedTransport, nil
+	}
+
+	base := c.httpClient()
+
+	var transport *http.Transport
+	switch baseTransport := base.Transport.(type) {
+	case nil:
+		defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("http.DefaultTransport is a %T, not an *http.Transport", http.DefaultTransport)
+		}
+		transport = defaultTransport.Clone()
+	case *http.Transport:
+		transport = baseTransport.Clone()
+	default:
+		return nil, fmt.Errorf("the HTTP client's transport is a %T, not an *http.Transport", baseTransport)
+	}
+
+	httpClient := *base
+	httpClient.Transport = transport
+	c.HTTPClient = &httpClient
+	c.ownedTransport = transport
+
+	return transport, nil
+}
+
+// withTransport returns an Option which configures the client's own transport.
+func withTransport(configure func(transport *http.Transport) error) Option {
+	return func(c *TigerGraphClient) {
+		transport, err := c.ownTransport()
+		if err == nil {
+			err = configure(transport)
+		}
+
+		if err != nil {
+			c.setOptionErr(err)
+		}
+	}
+}
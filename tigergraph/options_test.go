@@ -0,0 +1,87 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		client := NewClient("http://tg:9000")
+		assert.Equal(t, "http://tg:9000", client.BaseURL)
+		assert.Equal(t, "http://tg:9000", client.BaseFileURL)
+		assert.Zero(t, client.MaxBusyRetries)
+		assert.Equal(t, DefaultTimeouts, client.Timeouts)
+
+		// The connect timeout is applied to a copy of the default transport
+		assert.NotSame(t, http.DefaultClient, client.httpClient())
+		assert.NotSame(t, http.DefaultTransport, client.httpClient().Transport)
+		assert.NotNil(t, client.ownedTransport.DialContext)
+		assert.Equal(t, DefaultConnectionPool, client.ConnectionPool)
+		assert.Equal(t, DefaultConnectionPool.MaxIdleConnsPerHost, client.ownedTransport.MaxIdleConnsPerHost)
+		assert.Equal(t, DefaultConnectionPool.IdleConnTimeout, client.ownedTransport.IdleConnTimeout)
+	})
+
+	t.Run("no connect timeout or connection pool", func(t *testing.T) {
+		client := NewClient("http://tg:9000", WithTimeouts(Timeouts{}), WithConnectionPool(ConnectionPool{}))
+		assert.Same(t, http.DefaultClient, client.httpClient())
+	})
+
+	t.Run("connection pool", func(t *testing.T) {
+		client := NewClient("http://tg:9000", WithConnectionPool(ConnectionPool{
+			MaxIdleConnsPerHost: 64,
+			MaxConnsPerHost:     128,
+			TLSHandshakeTimeout: time.Second,
+		}))
+
+		transport := client.ownedTransport
+		assert.Equal(t, 64, transport.MaxIdleConnsPerHost)
+		assert.Equal(t, 128, transport.MaxConnsPerHost)
+		assert.Equal(t, time.Second, transport.TLSHandshakeTimeout)
+
+		// Unset fields keep the transport's own settings
+		defaultTransport := http.DefaultTransport.(*http.Transport)
+		assert.Equal(t, defaultTransport.MaxIdleConns, transport.MaxIdleConns)
+		assert.Equal(t, defaultTransport.IdleConnTimeout, transport.IdleConnTimeout)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		transport := &http.Transport{}
+		httpClient := &http.Client{Transport: transport}
+		client := NewClient(
+			"http://tg:9000",
+			WithFileURL("http://tg:14240"),
+			WithCredentials("user", "pass"),
+			WithHTTPClient(httpClient),
+			WithTimeout(time.Second),
+			WithBusyRetries(2),
+		)
+
+		assert.Equal(t, "http://tg:14240", client.BaseFileURL)
+		assert.Equal(t, "user", client.BasicAuthUsername)
+		assert.Equal(t, "pass", client.BasicAuthPassword)
+		assert.Equal(t, time.Second, client.Timeouts.Request)
+		assert.Equal(t, 2, client.MaxBusyRetries)
+
+		// The caller's HTTP client is used as it is, rather than a copy with the connect timeout
+		assert.Same(t, httpClient, client.HTTPClient)
+		assert.Nil(t, transport.DialContext)
+		assert.Nil(t, client.ownedTransport)
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxPingResponseSize bounds how much of a ping response is read
+const maxPingResponseSize = 4096
+
+// PingResponse is the response body from TigerGraph for a ping request
+type PingResponse struct {
+	Error   bool   `json:"error"`
+	Message string `json:"message"`
+}
+
+// PingResult is the outcome of a successful Ping
+type PingResult struct {
+	// Message is the server's reply, e.g. "pong". It is empty if the server sent no body.
+	Message string
+
+	// Latency is how long the ping took, including connecting
+	Latency time.Duration
+}
+
+// pingRequestKey marks the context of requests made by Ping, which reports whether TigerGraph
+// can answer right now, so they are neither retried when TigerGraph is busy nor blocked or
+// counted by the CircuitBreaker
+type pingRequestKey struct{}
+
+// Ping checks that TigerGraph is up by calling PingURL on the BaseFileURL, which does not
+// need a graph or a token. It is meant for liveness probes, so it is sent once, without busy
+// retries, and regardless of the CircuitBreaker, whose state it does not change. The call is
+// bounded by the client's Ping timeout, which can be overridden with WithRequestTimeout, and
+// errors are returned as an *OperationError.
+func (c *TigerGraphClient) Ping(ctx context.Context, opts ...RequestOption) (*PingResult, error) {
+	timeout := c.Timeouts.Ping
+	if options := collectRequestOptions(opts); options.timeout != nil {
+		timeout = *options.timeout
+	}
+
+	pingCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		pingCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result, err := c.ping(pingCtx)
+	if err != nil && errors.Is(err, context.DeadlineExceeded) {
+		err = &DeadlineExceededError{
+			Op:             "ping",
+			Timeout:        timeout,
+			CallerDeadline: errors.Is(ctx.Err(), context.DeadlineExceeded),
+			Err:            err,
+		}
+	}
+
+	return result, newOperationError("ping", "", PingURL, err)
+}
+
+func (c *TigerGraphClient) ping(ctx context.Context) (*PingResult, error) {
+	request, err := http.NewRequestWithContext(context.WithValue(ctx, pingRequestKey{}, true), http.MethodGet, c.BaseFileURL+PingURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := c.do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRequestFailed, err)
+	}
+
+	defer func() {
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ping came back with non 200 status code. code: %d: %w", resp.StatusCode, statusError(resp.StatusCode))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPingResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRequestFailed, err)
+	}
+
+	result := &PingResult{Latency: time.Since(start)}
+
+	if len(body) > 0 {
+		var response PingResponse
+		if err = json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal ping response. response: %s, %w", string(body), err)
+		}
+
+		if response.Error {
+			return nil, fmt.Errorf("ping failed. message: %s: %w", response.Message, ErrTigerGraphError)
+		}
+
+		result.Message = response.Message
+	}
+
+	return result, nil
+}
+
+// isPingRequest reports whether req was made by Ping
+func isPingRequest(req *http.Request) bool {
+	_, ping := req.Context().Value(pingRequestKey{}).(bool)
+	return ping
+}
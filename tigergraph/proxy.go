@@ -0,0 +1,43 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// WithProxyURL sends every request, including those to the GSQL server, through the given
+// HTTP or HTTPS proxy instead of the proxy configured in the environment.
+func WithProxyURL(proxyURL string) Option {
+	return withTransport(func(transport *http.Transport) error {
+		parsed, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+
+		transport.Proxy = http.ProxyURL(parsed)
+		return nil
+	})
+}
+
+// WithProxy chooses the proxy for each request with the given function, which has the same
+// semantics as http.Transport.Proxy. A nil function disables proxying.
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) Option {
+	return withTransport(func(transport *http.Transport) error {
+		transport.Proxy = proxy
+		return nil
+	})
+}
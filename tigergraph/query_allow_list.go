@@ -0,0 +1,73 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// queryURLPrefix begins the RESTPP path of every installed query
+const queryURLPrefix = "/query/"
+
+// ErrQueryNotAllowed is returned when a call names an installed query which is not in the
+// client's AllowedQueries
+var ErrQueryNotAllowed = errors.New("query is not in the allow-list")
+
+// WithQueryAllowList only allows Get, Post and PostRaw to run the named installed queries.
+// Calls naming any other query fail with ErrQueryNotAllowed without reaching TigerGraph.
+func WithQueryAllowList(queryNames ...string) Option {
+	return func(c *TigerGraphClient) {
+		c.AllowedQueries = append([]string{}, queryNames...)
+	}
+}
+
+// checkQueryAllowed rejects calls to installed queries which are not allowed. Calls to other
+// endpoints, and the queries the library installs itself, are always allowed.
+func (c *TigerGraphClient) checkQueryAllowed(queryURL string) error {
+	if c.AllowedQueries == nil {
+		return nil
+	}
+
+	queryName, isQuery := installedQueryName(queryURL)
+	if !isQuery || queryName == c.latestMigrationQueryName() || queryName == VerticesByIDsQueryName {
+		return nil
+	}
+
+	for _, allowed := range c.AllowedQueries {
+		if queryName == allowed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("query %q: %w", queryName, ErrQueryNotAllowed)
+}
+
+// installedQueryName returns the name of the query run by a URL of the form /query/<name> or
+// /query/<graph>/<name>. Anything after the name is treated as part of it, so that it cannot
+// smuggle in a different query.
+func installedQueryName(queryURL string) (string, bool) {
+	path, _, _ := strings.Cut(queryURL, "?")
+	if !strings.HasPrefix(path, queryURLPrefix) {
+		return "", false
+	}
+
+	segments := strings.SplitN(strings.TrimPrefix(path, queryURLPrefix), "/", 2) //nolint:gomnd
+	if len(segments) == 1 {
+		return segments[0], true
+	}
+
+	return segments[1], true
+}
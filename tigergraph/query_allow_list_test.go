@@ -0,0 +1,40 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstalledQueryName(t *testing.T) {
+	tests := []struct {
+		url     string
+		name    string
+		isQuery bool
+	}{
+		{url: "/query/MyGraph/my_query", name: "my_query", isQuery: true},
+		{url: "/query/my_query?limit=1", name: "my_query", isQuery: true},
+		{url: "/query/MyGraph/my_query/../other_query", name: "my_query/../other_query", isQuery: true},
+		{url: "/graph/MyGraph/vertices/Person", isQuery: false},
+		{url: "/endpoints/MyGraph?dynamic=true", isQuery: false},
+	}
+
+	for _, test := range tests {
+		name, isQuery := installedQueryName(test.url)
+		assert.Equal(t, test.name, name, test.url)
+		assert.Equal(t, test.isQuery, isQuery, test.url)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidParam is returned when a query parameter value fails the validation configured on
+// its QueryParams
+var ErrInvalidParam = errors.New("invalid query parameter")
+
+// ParamOption configures the validation applied to every value added to QueryParams.
+type ParamOption func(*QueryParams)
+
+// MaxParamLength rejects values longer than the given number of characters.
+func MaxParamLength(length int) ParamOption {
+	return func(p *QueryParams) {
+		p.maxLength = length
+	}
+}
+
+// ParamCharset rejects values containing any character for which allowed returns false,
+// e.g. unicode.IsLetter.
+func ParamCharset(allowed func(r rune) bool) ParamOption {
+	return func(p *QueryParams) {
+		p.allowed = allowed
+	}
+}
+
+// RejectControlCharacters rejects values containing control characters such as newlines or NUL.
+func RejectControlCharacters() ParamOption {
+	return func(p *QueryParams) {
+		p.rejectControl = true
+	}
+}
+
+// ParamDatetimeEncoding formats times with the given encoding, in place of DatetimeString.
+func ParamDatetimeEncoding(encoding DatetimeEncoding) ParamOption {
+	return func(p *QueryParams) {
+		p.datetimeEncoding = encoding
+	}
+}
+
+// QueryParams builds the query string for an installed query, formatting values the way RESTPP
+// expects and validating them, so that services can reject bad user input before it reaches
+// TigerGraph. The first invalid value is reported by Encode and URL.
+type QueryParams struct {
+	values        url.Values
+	maxLength     int
+	allowed       func(r rune) bool
+	rejectControl bool
+	err           error
+
+	datetimeEncoding DatetimeEncoding
+}
+
+// NewQueryParams creates an empty QueryParams which validates values with the given options.
+func NewQueryParams(opts ...ParamOption) *QueryParams {
+	p := &QueryParams{values: url.Values{}}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Set sets a parameter, replacing any values it already has. Times are formatted as DATETIME
+// values in UTC, using the DatetimeEncoding given with ParamDatetimeEncoding; other values are
+// formatted with fmt. A nil *time.Time or invalid Null[time.Time] leaves the parameter unset,
+// so that the query's default is used, and a []time.Time adds each time.
+func (p *QueryParams) Set(name string, value any) *QueryParams {
+	p.values.Del(name)
+
+	return p.Add(name, value)
+}
+
+// Add adds a value to a parameter, e.g. for SET and BAG parameters, which take repeated values.
+// Values are formatted as they are by Set.
+func (p *QueryParams) Add(name string, value any) *QueryParams {
+	switch v := value.(type) {
+	case *time.Time:
+		if v == nil {
+			return p
+		}
+		value = *v
+	case Null[time.Time]:
+		if !v.Valid {
+			return p
+		}
+		value = v.Value
+	case []time.Time:
+		for _, t := range v {
+			p.Add(name, t)
+		}
+
+		return p
+	}
+
+	formatted := formatParamValue(value, p.datetimeEncoding)
+	if err := p.validate(formatted); err != nil {
+		if p.err == nil {
+			p.err = fmt.Errorf("parameter %q: %w: %w", name, ErrInvalidParam, err)
+		}
+
+		return p
+	}
+
+	p.values.Add(name, formatted)
+
+	return p
+}
+
+// Encode returns the encoded query string, or the first validation failure.
+func (p *QueryParams) Encode() (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+
+	return p.values.Encode(), nil
+}
+
+// URL appends the encoded query string to queryURL, e.g. "/query/MyGraph/my_query".
+func (p *QueryParams) URL(queryURL string) (string, error) {
+	encoded, err := p.Encode()
+	if err != nil {
+		return "", err
+	}
+
+	if encoded == "" {
+		return queryURL, nil
+	}
+
+	return queryURL + "?" + encoded, nil
+}
+
+func (p *QueryParams) validate(value string) error {
+	if p.maxLength > 0 && utf8.RuneCountInString(value) > p.maxLength {
+		return fmt.Errorf("value is longer than %d characters", p.maxLength)
+	}
+
+	for _, r := range value {
+		if p.rejectControl && unicode.IsControl(r) {
+			return fmt.Errorf("value contains control character %U", r)
+		}
+
+		if p.allowed != nil && !p.allowed(r) {
+			return fmt.Errorf("value contains disallowed character %q", r)
+		}
+	}
+
+	return nil
+}
+
+func formatParamValue(value any, encoding DatetimeEncoding) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return encoding.Format(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryParams(t *testing.T) {
+	t.Run("formats values", func(t *testing.T) {
+		queryURL, err := NewQueryParams().
+			Set("name", "Ada Lovelace").
+			Set("limit", 10).
+			Set("since", time.Date(2023, 6, 1, 9, 0, 0, 0, time.UTC)).
+			Add("ids", "1").
+			Add("ids", "2").
+			URL("/query/MyGraph/my_query")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "/query/MyGraph/my_query?ids=1&ids=2&limit=10&name=Ada+Lovelace&since=2023-06-01+09%3A00%3A00", queryURL)
+	})
+
+	t.Run("formats times in UTC", func(t *testing.T) {
+		since := time.Date(2023, 6, 1, 10, 0, 0, 0, time.FixedZone("BST", 60*60))
+
+		encoded, err := NewQueryParams().
+			Set("since", &since).
+			Set("until", NewNull(since.Add(time.Hour))).
+			Add("days", []time.Time{since, since.AddDate(0, 0, 1)}).
+			Encode()
+		assert.Nil(t, err)
+		assert.Equal(
+			t,
+			"days=2023-06-01+09%3A00%3A00&days=2023-06-02+09%3A00%3A00&since=2023-06-01+09%3A00%3A00&until=2023-06-01+10%3A00%3A00",
+			encoded,
+		)
+
+		encoded, err = NewQueryParams(ParamDatetimeEncoding(DatetimeEpoch)).Set("since", since).Encode()
+		assert.Nil(t, err)
+		assert.Equal(t, "since=1685610000", encoded)
+	})
+
+	t.Run("leaves unset times out", func(t *testing.T) {
+		var since *time.Time
+		encoded, err := NewQueryParams().
+			Set("since", since).
+			Set("until", Null[time.Time]{}).
+			Add("days", []time.Time{}).
+			Encode()
+		assert.Nil(t, err)
+		assert.Equal(t, "", encoded)
+	})
+
+	t.Run("no parameters", func(t *testing.T) {
+		queryURL, err := NewQueryParams().URL("/query/MyGraph/my_query")
+		assert.Nil(t, err)
+		assert.Equal(t, "/query/MyGraph/my_query", queryURL)
+	})
+
+	t.Run("set replaces values", func(t *testing.T) {
+		encoded, err := NewQueryParams().Add("id", "1").Set("id", "2").Encode()
+		assert.Nil(t, err)
+		assert.Equal(t, "id=2", encoded)
+	})
+
+	tests := []struct {
+		name  string
+		opts  []ParamOption
+		value string
+		valid bool
+	}{
+		{name: "within max length", opts: []ParamOption{MaxParamLength(3)}, value: "abc", valid: true},
+		{name: "over max length", opts: []ParamOption{MaxParamLength(3)}, value: "abcd", valid: false},
+		{name: "max length counts characters", opts: []ParamOption{MaxParamLength(3)}, value: "été", valid: true},
+		{name: "control character", opts: []ParamOption{RejectControlCharacters()}, value: "a\nb", valid: false},
+		{name: "no control character", opts: []ParamOption{RejectControlCharacters()}, value: "a b", valid: true},
+		{name: "allowed charset", opts: []ParamOption{ParamCharset(unicode.IsLetter)}, value: "abc", valid: true},
+		{name: "disallowed charset", opts: []ParamOption{ParamCharset(unicode.IsLetter)}, value: "abc/../x", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := NewQueryParams(test.opts...).Set("other", "ok").Set("value", test.value)
+			_, err := params.Encode()
+			if test.valid {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidParam)
+				assert.Contains(t, err.Error(), `"value"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// QueryAliasVertexType is the metadata vertex type which records the active version of each
+// query alias of each graph
+const QueryAliasVertexType = "QueryAlias"
+
+// QueryAliasSchemaGSQL adds the QueryAlias vertex type to the metadata graph
+//
+//go:embed gsql/query_alias_schema.gsql
+var QueryAliasSchemaGSQL string
+
+var (
+	// ErrQueryAliasNotFound represents a query alias which has no active version
+	ErrQueryAliasNotFound = errors.New("query alias not found")
+
+	// ErrQueryValidationFailed represents a new query version which failed its validation, so
+	// was dropped rather than made active
+	ErrQueryValidationFailed = errors.New("query version failed validation")
+)
+
+// QueryRelease describes a new version of a query to install and make active under its alias.
+type QueryRelease struct {
+	Graph string
+
+	// Alias is the name callers use for the query, e.g. my_query. Each version is installed
+	// as VersionedQueryName(Alias, Version), e.g. my_query_v2.
+	Alias   string
+	Version int
+
+	// GSQL creates and installs the query, with {{GRAPH_NAME}} and {{QUERY_NAME}} placeholders
+	// for the graph and the versioned query name.
+	GSQL string
+
+	// Validate, if set, is run against the installed version before it is made active, e.g. by
+	// calling it with known inputs. If it fails, the version is dropped and the alias is left
+	// pointing at the previous version.
+	Validate func(ctx context.Context, queryName string) error
+
+	// KeepPrevious leaves the previous version installed once the alias points at the new one,
+	// e.g. so that a release can be rolled back without reinstalling.
+	KeepPrevious bool
+}
+
+// QueryAliasRecord records the active version of a query alias, stored in the metadata graph.
+type QueryAliasRecord struct {
+	ID        string `json:"id"`
+	Graph     string `json:"graph_name"`
+	Alias     string `json:"alias"`
+	QueryName string `json:"query_name"`
+	Version   int    `json:"version"`
+}
+
+// QueryReleaseResult describes a completed ReleaseQuery.
+type QueryReleaseResult struct {
+	// QueryName is the versioned query the alias now points at
+	QueryName string
+
+	// PreviousQueryName is the versioned query the alias pointed at before, if any
+	PreviousQueryName string
+
+	// PreviousDropped is true if the previous version was dropped
+	PreviousDropped bool
+}
+
+// VersionedQueryName returns the name a version of a query alias is installed under.
+func VersionedQueryName(alias string, version int) string {
+	return fmt.Sprintf("%s_v%d", alias, version)
+}
+
+// ReleaseQuery upgrades a query without downtime. It installs the release's version alongside
+// the active one, unless it is already installed, validates it, points the alias at it and
+// then drops the previous version. Callers which resolve the alias with ActiveQueryName before
+// each call switch to the new version as soon as the alias is repointed, which is a single
+// upsert to the metadata graph.
+//
+// If validation fails the new version is dropped and an error wrapping ErrQueryValidationFailed
+// is returned. If only dropping the previous version fails, the result is returned along with
+// the error, as the release itself succeeded.
+func (c *TigerGraphClient) ReleaseQuery(ctx context.Context, release QueryRelease) (*QueryReleaseResult, error) {
+	queryName := VersionedQueryName(release.Alias, release.Version)
+	result := &QueryReleaseResult{QueryName: queryName}
+
+	current, err := c.queryAlias(ctx, release.Graph, release.Alias)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		result.PreviousQueryName = current.QueryName
+	}
+
+	installed, err := c.IsQueryInstalled(ctx, release.Graph, queryName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check whether query %s is installed: %w", queryName, err)
+	}
+
+	if !installed {
+		gsql := strings.NewReplacer(
+			graphNamePlaceholder, release.Graph,
+			latestMigrationQueryNamePlaceholder, queryName,
+		).Replace(release.GSQL)
+
+		if err = c.RunGSQL(ctx, gsql); err != nil {
+			return nil, fmt.Errorf("failed to install query %s: %w", queryName, err)
+		}
+	}
+
+	if release.Validate != nil {
+		if err = release.Validate(ctx, queryName); err != nil {
+			validationErr := fmt.Errorf("query %s: %w: %w", queryName, ErrQueryValidationFailed, err)
+			if queryName != result.PreviousQueryName {
+				if dropErr := c.dropQuery(ctx, release.Graph, queryName); dropErr != nil {
+					return nil, errors.Join(validationErr, dropErr)
+				}
+			}
+
+			return nil, validationErr
+		}
+	}
+
+	if err = c.saveQueryAlias(ctx, QueryAliasRecord{
+		ID:        queryAliasID(release.Graph, release.Alias),
+		Graph:     release.Graph,
+		Alias:     release.Alias,
+		QueryName: queryName,
+		Version:   release.Version,
+	}); err != nil {
+		return nil, err
+	}
+
+	if release.KeepPrevious || result.PreviousQueryName == "" || result.PreviousQueryName == queryName {
+		return result, nil
+	}
+
+	if err = c.dropQuery(ctx, release.Graph, result.PreviousQueryName); err != nil {
+		return result, err
+	}
+	result.PreviousDropped = true
+
+	return result, nil
+}
+
+// ActiveQueryName returns the versioned query name the alias points at, e.g. to build the URL
+// of an installed query call. It returns an error wrapping ErrQueryAliasNotFound if the alias
+// has never been released.
+func (c *TigerGraphClient) ActiveQueryName(ctx context.Context, graph string, alias string) (string, error) {
+	record, err := c.queryAlias(ctx, graph, alias)
+	if err != nil {
+		return "", err
+	}
+
+	if record == nil {
+		return "", fmt.Errorf("alias %s of graph %s: %w", alias, graph, ErrQueryAliasNotFound)
+	}
+
+	return record.QueryName, nil
+}
+
+// GetQueryAliases returns the query alias records of the graph.
+func (c *TigerGraphClient) GetQueryAliases(ctx context.Context, graph string) ([]QueryAliasRecord, error) {
+	queryURL := fmt.Sprintf(VerticesURLTemplate, MetadataGraphName, QueryAliasVertexType)
+	response := &TigerGraphResponse[ResponseVertex[QueryAliasRecord]]{}
+
+	err := c.Get(ctx, queryURL, MetadataGraphName, response, WithOperation("get query aliases"))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error {
+		return nil, newOperationError("get query aliases", MetadataGraphName, queryURL, fmt.Errorf(
+			"TigerGraph returned an error when getting query aliases. Message: %s: %w",
+			response.Message,
+			ErrTigerGraphError,
+		))
+	}
+
+	records := make([]QueryAliasRecord, 0)
+	for _, vertex := range response.Results {
+		if vertex.Attributes.Graph == graph {
+			records = append(records, vertex.Attributes)
+		}
+	}
+
+	return records, nil
+}
+
+func queryAliasID(graph string, alias string) string {
+	return fmt.Sprintf("%s_%s", graph, alias)
+}
+
+// queryAlias returns the record of the alias, or nil if it has not been released
+func (c *TigerGraphClient) queryAlias(ctx context.Context, graph string, alias string) (*QueryAliasRecord, error) {
+	if err := c.ensureMetadataSchema(ctx); err != nil {
+		return nil, err
+	}
+
+	records, err := c.GetQueryAliases(ctx, graph)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range records {
+		if records[i].Alias == alias {
+			return &records[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// saveQueryAlias upserts the alias record
+func (c *TigerGraphClient) saveQueryAlias(ctx context.Context, record QueryAliasRecord) error {
+	payload := c.NewUpsertPayload().AddVertex(QueryAliasVertexType, record.ID, map[string]any{
+		"graph_name": record.Graph,
+		"alias":      record.Alias,
+		"query_name": record.QueryName,
+		"version":    record.Version,
+		"updated_at": c.now(),
+	})
+
+	res, err := c.Upsert(ctx, MetadataGraphName, payload, WithOperation("save query alias"))
+	if err != nil {
+		return fmt.Errorf("failed to point alias %s of graph %s at %s: %w", record.Alias, record.Graph, record.QueryName, err)
+	}
+
+	if res.AcceptedVertices != 1 {
+		return fmt.Errorf(
+			"upsert of query alias returned an unexpected number of accepted vertices. accepted: %d but expected only 1. error type: %w",
+			res.AcceptedVertices,
+			ErrTigerGraphSchemaSetUpFailed,
+		)
+	}
+
+	return nil
+}
+
+func (c *TigerGraphClient) dropQuery(ctx context.Context, graph string, queryName string) error {
+	if err := c.RunGSQL(ctx, fmt.Sprintf("USE GRAPH %s\nDROP QUERY %s", graph, queryName)); err != nil {
+		return fmt.Errorf("failed to drop query %s: %w", queryName, err)
+	}
+
+	return nil
+}
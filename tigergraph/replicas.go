@@ -0,0 +1,226 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultReplicaRetryInterval is how long a read replica which has failed is skipped for
+const DefaultReplicaRetryInterval = 30 * time.Second
+
+// Route chooses whether a call is sent to the primary RESTPP endpoint or a read replica.
+type Route int
+
+const (
+	// RouteDefault sends GET requests to a read replica and POST requests to the primary,
+	// as installed queries called with POST may write.
+	RouteDefault Route = iota
+
+	// RoutePrimary sends the call to the primary endpoint, e.g. for reads which must see the
+	// latest writes.
+	RoutePrimary
+
+	// RouteReplica sends the call to a read replica, e.g. for read-only installed queries
+	// called with POST.
+	RouteReplica
+)
+
+// WithRoute overrides where a single call is sent when the client has read replicas.
+func WithRoute(route Route) RequestOption {
+	return func(o *requestOptions) {
+		o.route = route
+	}
+}
+
+// WithReadReplicas sends reads to the given RESTPP base URLs, e.g. standby endpoints of the
+// same cluster, in turn, keeping heavy analytical reads away from the primary which serves
+// writes. Tokens requested from the primary are used with the replicas.
+//
+// A replica which cannot be reached is skipped for the client's ReplicaRetryInterval, and the
+// call is sent to the primary instead. Calls are sent to the primary while no replica is
+// healthy. CheckReplicas probes every replica, e.g. on a timer.
+func WithReadReplicas(baseURLs ...string) Option {
+	return func(c *TigerGraphClient) {
+		replicas := make([]*replica, 0, len(baseURLs))
+		for _, baseURL := range baseURLs {
+			replicas = append(replicas, &replica{baseURL: baseURL})
+		}
+		c.replicas = &replicaPool{replicas: replicas}
+	}
+}
+
+// WithReplicaRetryInterval sets how long a failed read replica is skipped for. Defaults to
+// DefaultReplicaRetryInterval.
+func WithReplicaRetryInterval(interval time.Duration) Option {
+	return func(c *TigerGraphClient) {
+		c.ReplicaRetryInterval = interval
+	}
+}
+
+type replica struct {
+	baseURL        string
+	unhealthyUntil time.Time
+}
+
+type replicaPool struct {
+	mu       sync.Mutex
+	replicas []*replica
+	next     int
+}
+
+// pick returns the next healthy replica in turn, or nil if none are healthy.
+func (p *replicaPool) pick(now time.Time) *replica {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := 0; i < len(p.replicas); i++ {
+		candidate := p.replicas[(p.next+i)%len(p.replicas)]
+		if now.Before(candidate.unhealthyUntil) {
+			continue
+		}
+
+		p.next = (p.next + i + 1) % len(p.replicas)
+		return candidate
+	}
+
+	return nil
+}
+
+func (p *replicaPool) setHealthy(r *replica, healthy bool, until time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if healthy {
+		r.unhealthyUntil = time.Time{}
+	} else {
+		r.unhealthyUntil = until
+	}
+}
+
+// ReplicaHealth reports, for each read replica's base URL, whether it is currently used.
+func (c *TigerGraphClient) ReplicaHealth() map[string]bool {
+	health := map[string]bool{}
+	if c.replicas == nil {
+		return health
+	}
+
+	now := c.now()
+	c.replicas.mu.Lock()
+	defer c.replicas.mu.Unlock()
+
+	for _, r := range c.replicas.replicas {
+		health[r.baseURL] = !now.Before(r.unhealthyUntil)
+	}
+
+	return health
+}
+
+// CheckReplicas calls the echo endpoint of every read replica, marking those which respond
+// healthy and those which do not unhealthy. The errors of unhealthy replicas are returned joined.
+func (c *TigerGraphClient) CheckReplicas(ctx context.Context) error {
+	if c.replicas == nil {
+		return nil
+	}
+
+	errs := make([]error, 0)
+	for _, r := range c.replicas.replicas {
+		err := c.echo(ctx, r.baseURL)
+		c.replicas.setHealthy(r, err == nil, c.now().Add(c.replicaRetryInterval()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("replica %s: %w", r.baseURL, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *TigerGraphClient) echo(ctx context.Context, baseURL string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+EchoURL, nil)
+	if err != nil {
+		return err
+	}
+
+	var response EchoResponse
+	if err = c.RequestInto(request, &response); err != nil {
+		return err
+	}
+
+	if response.Error {
+		return fmt.Errorf("echo request failed. message: %s: %w", response.Message, ErrTigerGraphError)
+	}
+
+	return nil
+}
+
+func (c *TigerGraphClient) replicaRetryInterval() time.Duration {
+	if c.ReplicaRetryInterval > 0 {
+		return c.ReplicaRetryInterval
+	}
+
+	return DefaultReplicaRetryInterval
+}
+
+// routeRequest calls send with the base URL of a read replica, if the call is a read and a
+// replica is healthy, or else the primary. If the replica cannot be reached it is marked
+// unhealthy and the call is sent to the primary.
+func (c *TigerGraphClient) routeRequest(ctx context.Context, read bool, opts []RequestOption, send func(baseURL string) error) error {
+	if c.replicas == nil {
+		return send(c.BaseURL)
+	}
+
+	switch collectRequestOptions(opts).route {
+	case RoutePrimary:
+		read = false
+	case RouteReplica:
+		read = true
+	case RouteDefault:
+	}
+
+	if !read {
+		return send(c.BaseURL)
+	}
+
+	r := c.replicas.pick(c.now())
+	if r == nil {
+		return send(c.BaseURL)
+	}
+
+	err := send(r.baseURL)
+	if !isConnectionError(ctx, err) {
+		return err
+	}
+
+	c.replicas.setHealthy(r, false, c.now().Add(c.replicaRetryInterval()))
+	c.log(LogLevelWarn, "read replica failed, sending to primary", "replica", r.baseURL, "error", err)
+
+	return send(c.BaseURL)
+}
+
+// isConnectionError reports whether err means the server could not be reached, rather than
+// the caller's context ending or the server rejecting the request.
+func isConnectionError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
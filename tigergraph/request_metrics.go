@@ -0,0 +1,132 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RequestEvent describes a single call to TigerGraph, including any retries.
+type RequestEvent struct {
+	Method string
+	Path   string
+
+	// StatusCode is the status of the final response, or zero if no response was received
+	StatusCode int
+
+	// Attempts is the number of times the request was sent
+	Attempts int
+
+	// Duration is the time taken across all attempts until the final response headers were received
+	Duration time.Duration
+
+	// Err is the reason the request failed, or nil if a response was received
+	Err error
+}
+
+// RequestMetrics receives an event for every request made to TigerGraph, which can be used to
+// tune timeouts and retries.
+//
+// Implementations must be safe to call from multiple goroutines.
+type RequestMetrics interface {
+	RequestCompleted(event RequestEvent)
+}
+
+// NoopRequestMetrics is a RequestMetrics implementation that discards all events.
+// It is used when no RequestMetrics is set on the client.
+type NoopRequestMetrics struct{}
+
+// RequestCompleted implements RequestMetrics
+func (NoopRequestMetrics) RequestCompleted(RequestEvent) {}
+
+func (c *TigerGraphClient) requestMetrics() RequestMetrics {
+	if c.RequestMetrics == nil {
+		return NoopRequestMetrics{}
+	}
+
+	return c.RequestMetrics
+}
+
+// RequestError is returned when no response could be received from TigerGraph, e.g. because
+// the server was unreachable or stayed busy. It records how long was spent and how many
+// attempts were made, and unwraps to the underlying error.
+type RequestError struct {
+	Method   string
+	Path     string
+	Attempts int
+	Duration time.Duration
+	Err      error
+}
+
+// Error implements error
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s %s failed after %d attempt(s) in %s: %s", e.Method, e.Path, e.Attempts, e.Duration, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// recordRequest sends a RequestEvent for the request and returns err wrapped in a *RequestError.
+func (c *TigerGraphClient) recordRequest(
+	req *http.Request,
+	resp *http.Response,
+	attempts int,
+	duration time.Duration,
+	err error,
+) error {
+	event := RequestEvent{
+		Method:   req.Method,
+		Path:     req.URL.Path,
+		Attempts: attempts,
+		Duration: duration,
+		Err:      err,
+	}
+	if resp != nil {
+		event.StatusCode = resp.StatusCode
+	}
+
+	c.requestMetrics().RequestCompleted(event)
+	c.logRequest(event)
+
+	if err == nil {
+		return nil
+	}
+
+	return &RequestError{
+		Method:   req.Method,
+		Path:     req.URL.Path,
+		Attempts: attempts,
+		Duration: duration,
+		Err:      err,
+	}
+}
+
+func (c *TigerGraphClient) logRequest(event RequestEvent) {
+	args := []any{
+		"method", event.Method,
+		"path", event.Path,
+		"status", event.StatusCode,
+		"attempts", event.Attempts,
+		"duration", event.Duration,
+	}
+	if event.Err != nil {
+		args = append(args, "error", event.Err)
+	}
+
+	c.log(c.LogLevels.Request, "TigerGraph request completed", args...)
+}
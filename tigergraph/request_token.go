@@ -17,6 +17,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 )
@@ -24,9 +26,15 @@ import (
 // RequestTokenURL is the URL part for getting a token from TigerGraph
 const RequestTokenURL = "/requesttoken"
 
-// RequestTokenRequest is the shape of the request to the TigerGraph endpoint for fetching a token
+// RequestTokenRequest is the shape of the request to the TigerGraph endpoint for fetching a token.
+// The graph is left out for a token not bound to any graph, and when requesting a token with a
+// secret, which determines the graph itself.
 type RequestTokenRequest struct {
-	Graph string `json:"graph"`
+	Graph  string `json:"graph,omitempty"`
+	Secret string `json:"secret,omitempty"`
+
+	// Lifetime is how long the token lasts, in seconds. Zero leaves it to the server's default.
+	Lifetime int64 `json:"lifetime,omitempty,string"`
 }
 
 // RequestTokenResponseResults represents the token results shape
@@ -45,36 +53,208 @@ type RequestTokenResponse struct {
 
 // Auth authenticates with TigerGraph by hitting the auth endpoint using Basic Auth.
 // Will do nothing if a non-expired token for the requested graph already exists in
-// the client cache.
-func (c *TigerGraphClient) Auth(ctx context.Context, graph string) error {
-	existingToken, exists := c.Tokens[graph]
-	if exists && existingToken.Expires.After(time.Now()) {
-		return nil
+// the client cache, unless a TokenLifetime is given. It is safe to call concurrently: while
+// a token for a graph is being requested, other callers for that graph wait for it rather
+// than requesting their own.
+func (c *TigerGraphClient) Auth(ctx context.Context, graph string, opts ...RequestOption) error {
+	if options := collectRequestOptions(opts); options.tokenLifetime != nil {
+		return c.authWithLifetime(ctx, graph, *options.tokenLifetime)
 	}
 
-	body := &RequestTokenRequest{Graph: graph}
-	tokenResponse := &RequestTokenResponse{}
+	_, err := c.token(ctx, graph)
+	return err
+}
+
+// token returns the graph's static token if it has one, or else a non-expired token for the
+// graph, getting one from the TokenProvider or TigerGraph if needed
+func (c *TigerGraphClient) token(ctx context.Context, graph string) (*Token, error) {
+	if token, err := c.staticToken(graph); token != nil || err != nil {
+		return token, err
+	}
+
+	return c.cachedToken(ctx, c.Tokens, graph, func(ctx context.Context) (*Token, error) {
+		if c.TokenProvider != nil {
+			return c.providedToken(ctx, graph)
+		}
+
+		return c.storedToken(ctx, graph, func() (*Token, error) {
+			return c.requestToken(ctx, graph, c.TokenLifetime)
+		})
+	})
+}
+
+// cachedToken returns the non-expired token for the graph in the cache, or else the token
+// from request, which is cached
+func (c *TigerGraphClient) cachedToken(
+	ctx context.Context,
+	cache *TokenCache,
+	graph string,
+	request func(ctx context.Context) (*Token, error),
+) (*Token, error) {
+	existingToken, exists := cache.Get(graph)
+	if exists {
+		if existingToken.Expires.After(c.now()) {
+			return existingToken, nil
+		}
+
+		c.tokenMetrics().TokenExpired(graph, existingToken.Expires)
+	}
+
+	return c.fetchToken(ctx, cache, graph, request)
+}
+
+// fetchToken fetches the token for the graph into the cache with request, which is shared by
+// every caller waiting for the graph's token. It runs on a context detached from ctx, bounded
+// by the client's request timeout instead.
+func (c *TigerGraphClient) fetchToken(
+	ctx context.Context,
+	cache *TokenCache,
+	graph string,
+	request func(ctx context.Context) (*Token, error),
+) (*Token, error) {
+	return cache.fetch(ctx, graph, func(detached context.Context) (*Token, error) {
+		requestCtx, cancel := c.withTimeout(detached, nil)
+		defer cancel()
+
+		return request(requestCtx)
+	})
+}
+
+// requestToken requests a new token for the graph from TigerGraph which lasts for lifetime,
+// using the graph's secret if it has one or else basic auth. A secret from the
+// CredentialsProvider which TigerGraph rejects is refreshed, and the token requested again.
+func (c *TigerGraphClient) requestToken(ctx context.Context, graph string, lifetime time.Duration) (*Token, error) {
+	secret, fromProvider, err := c.secret(ctx, graph)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := c.requestTokenWithSecret(ctx, graph, secret, lifetime)
+	if err == nil || !fromProvider || !isSecretRejected(err) {
+		return token, err
+	}
+
+	credentials, err := c.CredentialsProvider.Refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.requestTokenWithSecret(ctx, graph, credentials.Secrets[graph], lifetime)
+}
+
+// requestTokenWithSecret requests a token for the graph with the secret, or with basic auth if
+// the secret is empty
+func (c *TigerGraphClient) requestTokenWithSecret(
+	ctx context.Context,
+	graph string,
+	secret string,
+	lifetime time.Duration,
+) (*Token, error) {
+	if secret != "" {
+		return c.requestTokenWith(ctx, graph, &RequestTokenRequest{Secret: secret, Lifetime: lifetimeSeconds(lifetime)}, nil)
+	}
+
+	return c.requestTokenWith(ctx, graph, &RequestTokenRequest{Graph: graph, Lifetime: lifetimeSeconds(lifetime)}, c.ApplyBasicAuth)
+}
+
+// secret returns the GSQL secret set for the graph with WithSecret, or else the graph's secret
+// in the CredentialsProvider's credentials, if there is one. fromProvider reports whether the
+// secret came from the CredentialsProvider.
+func (c *TigerGraphClient) secret(ctx context.Context, graph string) (secret string, fromProvider bool, err error) {
+	if secret, ok := c.Secrets[graph]; ok {
+		return secret, false, nil
+	}
+
+	if c.CredentialsProvider == nil {
+		return "", false, nil
+	}
+
+	credentials, err := c.CredentialsProvider.Credentials(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	secret = credentials.Secrets[graph]
+	return secret, secret != "", nil
+}
+
+// isSecretRejected reports whether a token request failed because TigerGraph did not accept
+// its secret
+func isSecretRejected(err error) bool {
+	return errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrForbidden) || errors.Is(err, ErrTigerGraphError)
+}
+
+// requestTokenWith requests a new token for the graph from TigerGraph with the body, applying
+// authenticate to the request if it is not nil
+func (c *TigerGraphClient) requestTokenWith(
+	ctx context.Context,
+	graph string,
+	body *RequestTokenRequest,
+	authenticate func(*http.Request) error,
+) (*Token, error) {
+	metrics := c.tokenMetrics()
 
 	data, err := json.Marshal(body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	request, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+RequestTokenURL, bytes.NewReader(data))
+	tokenURL := c.BaseURL + RequestTokenURL
+	if c.Cloud {
+		tokenURL = c.BaseFileURL + CloudTokenURL
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", tokenURL, bytes.NewReader(data))
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if authenticate != nil {
+		if err = authenticate(request); err != nil {
+			return nil, err
+		}
 	}
-	request.SetBasicAuth(c.BasicAuthUsername, c.BasicAuthPassword)
 
-	err = c.RequestInto(request, tokenResponse)
+	metrics.TokenRequested(graph)
+	start := time.Now()
+
+	token, err := c.doTokenRequest(request)
 	if err != nil {
-		return err
+		metrics.AuthFailed(graph, err)
+		return nil, err
 	}
 
-	c.Tokens[graph] = &Token{
-		Value:   tokenResponse.Results.Token,
-		Expires: time.Unix(tokenResponse.ExpirationSecondsSinceEpoch, 0),
+	metrics.TokenRefreshed(graph, time.Since(start), token.Expires)
+
+	return token, nil
+}
+
+// doTokenRequest makes the token request, reading the token from the response of RESTPP, or of
+// the GSQL server for TigerGraph Cloud
+func (c *TigerGraphClient) doTokenRequest(request *http.Request) (*Token, error) {
+	if c.Cloud {
+		tokenResponse := &CloudTokenResponse{}
+		if err := c.RequestInto(request, tokenResponse); err != nil {
+			return nil, err
+		}
+
+		if tokenResponse.Error {
+			return nil, fmt.Errorf("token request failed. message: %s: %w", tokenResponse.Message, ErrTigerGraphError)
+		}
+
+		return &Token{Value: tokenResponse.Token, Expires: time.Unix(tokenResponse.Expiration, 0)}, nil
 	}
 
-	return nil
+	tokenResponse := &RequestTokenResponse{}
+	if err := c.RequestInto(request, tokenResponse); err != nil {
+		return nil, err
+	}
+
+	if tokenResponse.Error {
+		return nil, fmt.Errorf("token request failed. message: %s: %w", tokenResponse.Message, ErrTigerGraphError)
+	}
+
+	return &Token{
+		Value:   tokenResponse.Results.Token,
+		Expires: time.Unix(tokenResponse.ExpirationSecondsSinceEpoch, 0),
+	}, nil
 }
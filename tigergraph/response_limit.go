@@ -0,0 +1,95 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// ResponseLimitHeader tells RESTPP the largest response, in bytes, a query may return.
+const ResponseLimitHeader = "RESPONSE-LIMIT"
+
+// ErrResponseLimitExceeded is returned when a response is larger than its RESPONSE-LIMIT.
+var ErrResponseLimitExceeded = errors.New("response size limit exceeded")
+
+// WithQueryResponseLimit caps the response size of every installed query call at the given
+// number of bytes, so that a runaway query cannot exhaust the caller's memory. The limit is
+// sent to RESTPP in the RESPONSE-LIMIT header and enforced while reading the response. Zero
+// means no limit.
+func WithQueryResponseLimit(bytes int64) Option {
+	return func(c *TigerGraphClient) {
+		c.QueryResponseLimit = bytes
+	}
+}
+
+// WithResponseLimit caps the response size of a single call at the given number of bytes, in
+// place of the client's QueryResponseLimit. It applies to any call, not only installed
+// queries. Zero means no limit.
+func WithResponseLimit(bytes int64) RequestOption {
+	return func(o *requestOptions) {
+		o.responseLimit = &bytes
+	}
+}
+
+// setResponseLimit sets the RESPONSE-LIMIT header for the call's WithResponseLimit, or the
+// client's QueryResponseLimit for installed queries, unless the call has set its own header.
+func (c *TigerGraphClient) setResponseLimit(req *http.Request, queryURL string, options requestOptions) {
+	if req.Header.Get(ResponseLimitHeader) != "" {
+		return
+	}
+
+	limit := int64(0)
+	if options.responseLimit != nil {
+		limit = *options.responseLimit
+	} else if _, isQuery := installedQueryName(queryURL); isQuery {
+		limit = c.QueryResponseLimit
+	}
+
+	if limit > 0 {
+		req.Header.Set(ResponseLimitHeader, strconv.FormatInt(limit, 10))
+	}
+}
+
+// responseLimit returns the RESPONSE-LIMIT set on req, or zero if it has none.
+func responseLimit(req *http.Request) int64 {
+	limit, err := strconv.ParseInt(req.Header.Get(ResponseLimitHeader), 10, 64)
+	if err != nil || limit < 0 {
+		return 0
+	}
+
+	return limit
+}
+
+// readLimited reads r, failing with ErrResponseLimitExceeded once more than limit bytes have
+// been read. Zero means no limit.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%w: response is larger than %d bytes", ErrResponseLimitExceeded, limit)
+	}
+
+	return body, nil
+}
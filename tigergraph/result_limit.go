@@ -0,0 +1,125 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// vertexIDKey is the key TigerGraph gives the ID of every vertex in query and vertex responses
+const vertexIDKey = "v_id"
+
+// ErrResultTooLarge is returned when a response holds more vertices than MaxResultVertices allows.
+var ErrResultTooLarge = errors.New("result has too many vertices")
+
+// MaxResultVertices fails the call with ErrResultTooLarge once its response holds more than n
+// vertices, protecting callers from queries which unexpectedly match millions of vertices.
+// Vertices are counted as the response is read, so the rest of a response which is too large
+// is never read. Zero means no limit.
+func MaxResultVertices(n int) RequestOption {
+	return func(o *requestOptions) {
+		o.maxResultVertices = n
+	}
+}
+
+// vertexLimitReader counts the vertices in the JSON read through it, i.e. objects with a v_id
+// key, failing with ErrResultTooLarge once there are more than limit.
+type vertexLimitReader struct {
+	r     io.Reader
+	limit int
+	count int
+
+	// containers are the open objects and arrays, innermost last
+	containers []byte
+
+	inString  bool
+	escaped   bool
+	expectKey bool
+	isKey     bool
+	key       []byte
+}
+
+// limitResultVertices returns r, counting its vertices if limit is above zero.
+func limitResultVertices(r io.Reader, limit int) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+
+	return &vertexLimitReader{r: r, limit: limit}
+}
+
+func (v *vertexLimitReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	for _, b := range p[:n] {
+		if v.scan(b) {
+			return n, fmt.Errorf("%w: more than %d vertices", ErrResultTooLarge, v.limit)
+		}
+	}
+
+	return n, err
+}
+
+// scan advances the scanner by one byte, reporting whether the limit has been exceeded.
+func (v *vertexLimitReader) scan(b byte) bool {
+	if v.inString {
+		return v.scanString(b)
+	}
+
+	switch b {
+	case '{', '[':
+		v.containers = append(v.containers, b)
+		v.expectKey = b == '{'
+	case '}', ']':
+		if len(v.containers) > 0 {
+			v.containers = v.containers[:len(v.containers)-1]
+		}
+		v.expectKey = false
+	case ',':
+		v.expectKey = len(v.containers) > 0 && v.containers[len(v.containers)-1] == '{'
+	case ':':
+		v.expectKey = false
+	case '"':
+		v.inString = true
+		v.isKey = v.expectKey
+		v.key = v.key[:0]
+	}
+
+	return false
+}
+
+// scanString advances the scanner by one byte within a string, counting a vertex when a
+// v_id key ends.
+func (v *vertexLimitReader) scanString(b byte) bool {
+	switch {
+	case v.escaped:
+		v.escaped = false
+	case b == '\\':
+		v.escaped = true
+	case b == '"':
+		v.inString = false
+		if v.isKey && string(v.key) == vertexIDKey {
+			v.count++
+			return v.count > v.limit
+		}
+		return false
+	}
+
+	if v.isKey && len(v.key) <= len(vertexIDKey) {
+		v.key = append(v.key, b)
+	}
+
+	return false
+}
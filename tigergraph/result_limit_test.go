@@ -0,0 +1,72 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitResultVertices(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		limit       int
+		expectedErr error
+	}{
+		{
+			name:  "within the limit",
+			body:  `{"results": [{"vs": [{"v_id": "1", "v_type": "A"}, {"v_id": "2", "v_type": "A"}]}]}`,
+			limit: 2,
+		},
+		{
+			name:        "over the limit",
+			body:        `{"results": [{"vs": [{"v_id": "1", "v_type": "A"}, {"v_id": "2", "v_type": "A"}]}]}`,
+			limit:       1,
+			expectedErr: ErrResultTooLarge,
+		},
+		{
+			name:  "v_id values and strings are not vertices",
+			body:  `{"results": [{"v_id": "v_id", "names": ["v_id", "{\"v_id\": 1}"], "attributes": {"note": "\"v_id\""}}]}`,
+			limit: 1,
+		},
+		{
+			name:        "nested vertices are counted",
+			body:        `[{"a": {"b": [{"v_id": "1"}]}}, {"v_id": "2"}]`,
+			limit:       1,
+			expectedErr: ErrResultTooLarge,
+		},
+		{
+			name:  "no limit",
+			body:  `[{"v_id": "1"}, {"v_id": "2"}]`,
+			limit: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader := limitResultVertices(iotest.OneByteReader(strings.NewReader(test.body)), test.limit)
+			body, err := io.ReadAll(reader)
+
+			assert.ErrorIs(t, err, test.expectedErr)
+			if test.expectedErr == nil {
+				assert.Equal(t, test.body, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultInitialBackoff is the wait before the first retry when RetryPolicy.InitialBackoff is not set
+	DefaultInitialBackoff = 100 * time.Millisecond
+
+	// DefaultMaxBackoff is the longest wait between retries when RetryPolicy.MaxBackoff is not set
+	DefaultMaxBackoff = 10 * time.Second
+
+	// DefaultBackoffMultiplier is the growth of the wait between retries when RetryPolicy.Multiplier is not set
+	DefaultBackoffMultiplier = 2.0
+)
+
+// DefaultRetryableStatusCodes are the statuses retried when RetryPolicy.RetryableStatusCodes is
+// not set. 503 is not included because busy responses are retried separately, see MaxBusyRetries.
+var DefaultRetryableStatusCodes = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy controls how requests to RESTPP, e.g. Get, Post, Upsert and loading jobs, are
+// retried after transient failures. Network errors and the RetryableStatusCodes are retried with
+// exponential backoff. GSQL is never retried as it may not be safe to run twice.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times a request is sent. Values below 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry. Defaults to DefaultInitialBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between retries. Defaults to DefaultMaxBackoff.
+	MaxBackoff time.Duration
+
+	// Multiplier is applied to the wait after each retry. Defaults to DefaultBackoffMultiplier.
+	Multiplier float64
+
+	// Jitter randomly reduces each wait by up to this fraction, between 0 and 1, so that clients
+	// retrying at the same time spread out.
+	Jitter float64
+
+	// RetryableStatusCodes are the response statuses which are retried. Defaults to DefaultRetryableStatusCodes.
+	RetryableStatusCodes []int
+}
+
+// Backoff returns the wait before the given retry, starting from 1, before jitter is applied.
+func (p *RetryPolicy) Backoff(retry int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = DefaultInitialBackoff
+	}
+
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = DefaultBackoffMultiplier
+	}
+
+	wait := float64(initial) * math.Pow(multiplier, float64(retry-1))
+	if wait > float64(maxBackoff) {
+		return maxBackoff
+	}
+
+	return time.Duration(wait)
+}
+
+func (p *RetryPolicy) jitter(wait time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return wait
+	}
+
+	//nolint:gosec // jitter does not need to be cryptographically secure
+	return wait - time.Duration(float64(wait)*math.Min(p.Jitter, 1)*rand.Float64())
+}
+
+func (p *RetryPolicy) isRetryableStatus(statusCode int) bool {
+	codes := p.RetryableStatusCodes
+	if codes == nil {
+		codes = DefaultRetryableStatusCodes
+	}
+
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRetryableError reports whether a failed request could succeed if sent again, i.e. it failed
+// with a network error rather than because its context ended or the server stayed busy.
+func isRetryableError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// doWithRetries performs a request with do, retrying it according to the client's RetryPolicy.
+func (c *TigerGraphClient) doWithRetries(req *http.Request) (*http.Response, error) {
+	policy := c.RetryPolicy
+	if policy == nil || policy.MaxAttempts < 2 {
+		return c.do(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.do(req)
+
+		retryable := false
+		if err != nil {
+			retryable = isRetryableError(err)
+		} else {
+			retryable = policy.isRetryableStatus(resp.StatusCode)
+		}
+
+		// A request whose body cannot be read again is not retried, rather than resent empty
+		if !retryable || attempt >= policy.MaxAttempts || !canRewind(req) {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(policy.jitter(policy.Backoff(attempt)))
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req, err = rewindRequest(req); err != nil {
+			return nil, err
+		}
+	}
+}
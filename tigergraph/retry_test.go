@@ -0,0 +1,58 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 800*time.Millisecond, policy.Backoff(4))
+	assert.Equal(t, time.Second, policy.Backoff(5))
+
+	policy = &RetryPolicy{Multiplier: 3}
+	assert.Equal(t, DefaultInitialBackoff*9, policy.Backoff(3))
+
+	policy = &RetryPolicy{Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		wait := policy.jitter(time.Second)
+		assert.GreaterOrEqual(t, wait, 500*time.Millisecond)
+		assert.LessOrEqual(t, wait, time.Second)
+	}
+}
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	policy := &RetryPolicy{}
+	assert.True(t, policy.isRetryableStatus(http.StatusBadGateway))
+	assert.False(t, policy.isRetryableStatus(http.StatusBadRequest))
+
+	policy.RetryableStatusCodes = []int{http.StatusTooManyRequests}
+	assert.True(t, policy.isRetryableStatus(http.StatusTooManyRequests))
+	assert.False(t, policy.isRetryableStatus(http.StatusBadGateway))
+
+	networkErr := &url.Error{Op: "Post", URL: "http://tg:9000", Err: fmt.Errorf("connection refused")}
+	assert.True(t, isRetryableError(&RequestError{Err: networkErr}))
+	assert.False(t, isRetryableError(&RequestError{Err: &url.Error{Op: "Post", Err: context.DeadlineExceeded}}))
+	assert.False(t, isRetryableError(ErrServerBusy))
+}
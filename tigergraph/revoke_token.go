@@ -0,0 +1,104 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// RevokeTokenRequest is the shape of the request to the TigerGraph endpoint for deleting a token.
+// The secret is sent for tokens requested with one, in place of basic auth.
+type RevokeTokenRequest struct {
+	Token  string `json:"token"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// RevokeToken removes the cached token for the graph and, if it has not expired, deletes it on
+// the server so that it can no longer be used, e.g. by credential rotation jobs. The next call
+// for the graph requests a new token. It does nothing if no token is cached for the graph.
+// Tokens from a TokenProvider, which TigerGraph did not issue, and TigerGraph Cloud tokens are
+// only removed from the cache. The token is also removed from the client's TokenStore, and
+// revoked if only the store holds it.
+func (c *TigerGraphClient) RevokeToken(ctx context.Context, graph string) error {
+	token, ok := c.Tokens.Get(graph)
+	c.Tokens.Delete(graph)
+
+	if c.TokenStore != nil {
+		key, err := c.tokenStoreKey(ctx, graph)
+		if err != nil {
+			return fmt.Errorf("failed to get token store key for graph %q: %w", graph, err)
+		}
+
+		if !ok {
+			stored, err := c.TokenStore.GetToken(ctx, key)
+			if err != nil {
+				return fmt.Errorf("failed to get token for graph %q from token store: %w", graph, err)
+			}
+			token, ok = stored, stored != nil
+		}
+
+		if err := c.TokenStore.DeleteToken(ctx, key); err != nil {
+			return fmt.Errorf("failed to delete token for graph %q from token store: %w", graph, err)
+		}
+	}
+
+	if !ok || !token.Expires.After(c.now()) || c.TokenProvider != nil || c.Cloud {
+		return nil
+	}
+
+	if err := c.revokeToken(ctx, graph, token); err != nil {
+		return fmt.Errorf("failed to revoke token for graph %q: %w", graph, err)
+	}
+
+	return nil
+}
+
+// revokeToken deletes the token on the server, using the graph's secret if it has one or else
+// basic auth
+func (c *TigerGraphClient) revokeToken(ctx context.Context, graph string, token *Token) error {
+	secret, _, err := c.secret(ctx, graph)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&RevokeTokenRequest{Token: token.Value, Secret: secret})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.BaseURL+RequestTokenURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if secret == "" {
+		if err = c.ApplyBasicAuth(request); err != nil {
+			return err
+		}
+	}
+
+	response := &RequestTokenResponse{}
+	if err = c.RequestInto(request, response); err != nil {
+		return err
+	}
+
+	if response.Error {
+		return fmt.Errorf("token revocation failed. message: %s: %w", response.Message, ErrTigerGraphError)
+	}
+
+	return nil
+}
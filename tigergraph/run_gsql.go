@@ -17,7 +17,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -44,39 +43,110 @@ var (
 // If any failure is detected, an error is returned.  Note however that this
 // does not mean that none of the GSQL was executed. You may need to inspect the
 // logged response to identify what succeeded in the request.
-func (c *TigerGraphClient) RunGSQL(ctx context.Context, body string) error {
+//
+// GSQL larger than the client's MaxGSQLBodySize is split with SplitGSQL and its parts run in
+// order, stopping at the first which fails.
+//
+// GSQL which changes the global schema, and so can affect every graph, is only run if allowed
+// with WithGlobalSchemaChanges or AllowGlobalSchemaChange. Otherwise a *GlobalSchemaChangeError
+// is returned and nothing is run.
+//
+// Errors are returned as an *OperationError, wrapping a *DeadlineExceededError if the call
+// ran out of time.
+func (c *TigerGraphClient) RunGSQL(ctx context.Context, body string, opts ...RequestOption) error {
+	op := operationName(opts, "gsql")
+	err := c.runGSQL(ctx, body, opts...)
+
+	return newOperationError(op, "", FileURL, c.newDeadlineError(ctx, op, opts, err))
+}
+
+func (c *TigerGraphClient) runGSQL(ctx context.Context, body string, opts ...RequestOption) error {
+	if err := c.checkGlobalSchemaChanges(body, opts); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.withTimeout(ctx, opts)
+	defer cancel()
+
+	parts, err := splitGSQL(body, c.MaxGSQLBodySize)
+	if err != nil {
+		return err
+	}
+
+	for i, part := range parts {
+		if err = c.runGSQLPart(ctx, part.body, opts); err != nil {
+			if len(parts) == 1 {
+				return err
+			}
+
+			return fmt.Errorf(
+				"GSQL part %d of %d, starting at line %d, failed after %d part(s) succeeded: %w",
+				i+1,
+				len(parts),
+				part.line,
+				i,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// runGSQLPart sends GSQL to the GSQL server in a single request.
+func (c *TigerGraphClient) runGSQLPart(ctx context.Context, body string, opts []RequestOption) error {
+	options := collectRequestOptions(opts)
+	session := c.gsqlSession(options)
+
 	escapedBody := url.QueryEscape(body)
+	if session != nil {
+		escapedBody = url.QueryEscape(session.inGraph(body))
+	}
 
-	request, err := c.CreateGSQLServerRequest(ctx, http.MethodPost, FileURL, escapedBody)
+	request, err := c.createGSQLServerRequest(ctx, http.MethodPost, FileURL, escapedBody, session)
 	if err != nil {
 		return err
 	}
 	request.Header.Set("Content-Type", "application/octet-stream")
+	setRequestHeaders(request, options)
+	applyCallGSQLAuth(request, options)
 
-	resp, err := http.DefaultClient.Do(request)
+	resp, err := c.do(request)
 
 	if err != nil {
-		return ErrRequestFailed
+		return fmt.Errorf("%w: %w", ErrRequestFailed, err)
 	}
 
 	defer func() {
 		resp.Body.Close()
 	}()
 
+	if session != nil {
+		session.StoreCookies(resp)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf(
 			"http request came back with non 200 status code. code: %d: %w",
 			resp.StatusCode,
-			ErrNonOK,
+			statusError(resp.StatusCode),
 		)
 	}
 
-	respBytes, err := io.ReadAll(resp.Body)
+	respBytes, err := readResponseBodyLimited(resp, 0, 0)
 	if err != nil {
 		return err
 	}
 
 	respString := string(respBytes)
+	if options.sensitiveGSQLOutput {
+		c.log(c.LogLevels.GSQL, "GSQL output", "output", redacted)
+	} else {
+		c.log(c.LogLevels.GSQL, "GSQL output", "output", respString)
+	}
+	if options.gsqlOutput != nil {
+		options.gsqlOutput(respString)
+	}
 	respLines := strings.Split(respString, "\n")
 	if len(respLines) < 2 { //nolint:gomnd
 		return fmt.Errorf(
@@ -104,5 +174,9 @@ func (c *TigerGraphClient) RunGSQL(ctx context.Context, body string) error {
 		)
 	}
 
+	if session != nil {
+		session.track(body)
+	}
+
 	return nil
 }
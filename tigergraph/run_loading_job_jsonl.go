@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
@@ -31,6 +32,10 @@ var (
 	ErrLoadingJobPartialFailure = errors.New("not all lines of the JSONL were saved successfully")
 )
 
+// LoadingJobURLTemplate is the RESTPP URL for posting data to a loading job. It must be
+// formatted with the graph name and loading job name.
+const LoadingJobURLTemplate = "/ddl/%s?tag=%s&filename=f"
+
 // LoadingJobObjectResult is the shape of an edge or vertex entry in the
 // statistics shape
 type LoadingJobObjectResult struct {
@@ -57,6 +62,103 @@ type LoadingJobStatistics struct {
 	Edge                []LoadingJobObjectResult `json:"edge"`
 }
 
+// VertexStats returns the statistics for the vertex type with the given name.
+func (s *LoadingJobStatistics) VertexStats(typeName string) (*LoadingJobObjectResult, bool) {
+	return findObjectResult(s.Vertex, typeName)
+}
+
+// EdgeStats returns the statistics for the edge type with the given name.
+func (s *LoadingJobStatistics) EdgeStats(typeName string) (*LoadingJobObjectResult, bool) {
+	return findObjectResult(s.Edge, typeName)
+}
+
+// HasFailures reports whether any failure counter in the statistics is non-zero.
+func (s *LoadingJobStatistics) HasFailures() bool {
+	return s.FailureSummary() != ""
+}
+
+// FailureSummary describes every non-zero failure counter, grouped by vertex and edge
+// type, e.g. "rejectLine=1; vertex Person: invalidAttribute=2". It is empty if nothing failed.
+func (s *LoadingJobStatistics) FailureSummary() string {
+	parts := make([]string, 0)
+
+	if lineFailures := formatCounters(s.lineFailureCounters()); lineFailures != "" {
+		parts = append(parts, lineFailures)
+	}
+
+	for _, group := range []struct {
+		kind    string
+		results []LoadingJobObjectResult
+	}{
+		{"vertex", s.Vertex},
+		{"edge", s.Edge},
+	} {
+		for _, result := range group.results {
+			if failures := result.failureCounters(); failures != "" {
+				parts = append(parts, fmt.Sprintf("%s %s: %s", group.kind, result.TypeName, failures))
+			}
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// HasFailures reports whether any failure counter for this type is non-zero.
+func (r *LoadingJobObjectResult) HasFailures() bool {
+	return r.failureCounters() != ""
+}
+
+func (r *LoadingJobObjectResult) failureCounters() string {
+	return formatCounters(r.objectFailureCounters())
+}
+
+func (s *LoadingJobStatistics) lineFailureCounters() []namedCounter {
+	return []namedCounter{
+		{"rejectLine", s.RejectLine},
+		{"failedConditionLine", s.FailedConditionLine},
+		{"notEnoughToken", s.NotEnoughToken},
+		{"invalidJson", s.InvalidJSON},
+		{"oversizeToken", s.OversizeToken},
+	}
+}
+
+func (r *LoadingJobObjectResult) objectFailureCounters() []namedCounter {
+	return []namedCounter{
+		{"noIdFound", r.NoIDFound},
+		{"invalidAttribute", r.InvalidAttribute},
+		{"invalidVertexType", r.InvalidVertexType},
+		{"invalidPrimaryId", r.InvalidPrimaryID},
+		{"invalidSecondaryId", r.InvalidSecondaryID},
+		{"incorrectFixedBinaryLength", r.IncorrectFixedBinaryLength},
+	}
+}
+
+type namedCounter struct {
+	name  string
+	count int
+}
+
+func formatCounters(counters []namedCounter) string {
+	nonZero := make([]string, 0)
+	for _, counter := range counters {
+		if counter.count != 0 {
+			nonZero = append(nonZero, fmt.Sprintf("%s=%d", counter.name, counter.count))
+		}
+	}
+
+	return strings.Join(nonZero, ", ")
+}
+
+func findObjectResult(results []LoadingJobObjectResult, typeName string) (*LoadingJobObjectResult, bool) {
+	for i := range results {
+		if results[i].TypeName == typeName {
+			return &results[i], true
+		}
+	}
+
+	return nil, false
+}
+
 // LoadingJobResponseResult is the shape of the results value in the response body when saving
 // a loading job, edge or vertex
 type LoadingJobResponseResult struct {
@@ -100,38 +202,59 @@ func (c *TigerGraphClient) RunLoadingJobJSONL(ctx context.Context,
 	graphName string,
 	loadingJobName string,
 	lines []any,
+	opts ...RequestOption,
 ) error {
 	bodyBytes, err := marshalJSONL(lines)
 	if err != nil {
 		return ErrMarshallingJSONL
 	}
 
-	queryURL := fmt.Sprintf("/ddl/%s?tag=%s&filename=f", graphName, loadingJobName)
+	queryURL := loadingJobURL(graphName, loadingJobName)
+
+	statistics, err := c.runLoadingJob(ctx, graphName, queryURL, bodyBytes, operationOptions("run loading job", opts)...)
+	if err != nil {
+		return err
+	}
+
+	if statistics.ValidLine != len(lines) {
+		return c.diagnose(ctx, queryURL, newOperationError(operationName(opts, "run loading job"), graphName, queryURL, fmt.Errorf(
+			"tigergraph reported fewer valid JSON lines than were provided. got: %d, expected %d, failures: %s: %w",
+			statistics.ValidLine,
+			len(lines),
+			statistics.FailureSummary(),
+			ErrLoadingJobPartialFailure,
+		)))
+	}
+
+	return nil
+}
+
+func loadingJobURL(graphName string, loadingJobName string) string {
+	return fmt.Sprintf(LoadingJobURLTemplate, graphName, loadingJobName)
+}
 
+// runLoadingJob posts the body to the loading job and returns the statistics of its single result
+func (c *TigerGraphClient) runLoadingJob(
+	ctx context.Context,
+	graphName string,
+	queryURL string,
+	body []byte,
+	opts ...RequestOption,
+) (*LoadingJobStatistics, error) {
 	var response LoadingJobResponse
-	err = c.PostRaw(ctx, queryURL, graphName, bodyBytes, &response)
+	err := c.PostRaw(ctx, queryURL, graphName, body, &response, opts...)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(response.Results) != 1 {
-		return fmt.Errorf(
+		return nil, c.diagnose(ctx, queryURL, newOperationError(operationName(opts, "post"), graphName, queryURL, fmt.Errorf(
 			"response does not contain exactly one result. got %d results: %w",
 			len(response.Results),
 			ErrLoadingJobRequestFailed,
-		)
+		)))
 	}
 
-	result := response.Results[0]
-	if result.Statistics.ValidLine != len(lines) {
-		return fmt.Errorf(
-			"tigergraph reported fewer valid JSON lines than were provided. got: %d, expected %d: %w",
-			result.Statistics.ValidLine,
-			len(lines),
-			ErrLoadingJobPartialFailure,
-		)
-	}
-
-	return nil
+	return &response.Results[0].Statistics, nil
 }
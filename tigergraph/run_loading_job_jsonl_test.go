@@ -0,0 +1,61 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadingJobStatistics(t *testing.T) {
+	stats := LoadingJobStatistics{
+		ValidLine:  3,
+		RejectLine: 1,
+		Vertex: []LoadingJobObjectResult{
+			{TypeName: "Person", ValidObject: 2, InvalidAttribute: 1},
+			{TypeName: "Company", ValidObject: 1},
+		},
+		Edge: []LoadingJobObjectResult{
+			{TypeName: "WorksAt", ValidObject: 1, InvalidPrimaryID: 2, NoIDFound: 1},
+		},
+	}
+
+	person, found := stats.VertexStats("Person")
+	assert.True(t, found)
+	assert.Equal(t, 2, person.ValidObject)
+	assert.True(t, person.HasFailures())
+
+	company, found := stats.VertexStats("Company")
+	assert.True(t, found)
+	assert.False(t, company.HasFailures())
+
+	_, found = stats.VertexStats("WorksAt")
+	assert.False(t, found)
+
+	worksAt, found := stats.EdgeStats("WorksAt")
+	assert.True(t, found)
+	assert.Equal(t, 2, worksAt.InvalidPrimaryID)
+
+	assert.True(t, stats.HasFailures())
+	assert.Equal(
+		t,
+		"rejectLine=1; vertex Person: invalidAttribute=1; edge WorksAt: noIdFound=1, invalidPrimaryId=2",
+		stats.FailureSummary(),
+	)
+
+	clean := LoadingJobStatistics{ValidLine: 1, Vertex: []LoadingJobObjectResult{{TypeName: "Person", ValidObject: 1}}}
+	assert.False(t, clean.HasFailures())
+	assert.Equal(t, "", clean.FailureSummary())
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// RunLoadingJobRaw runs a loading job with a body in any format the job accepts, e.g. CSV,
+// or bytes which originate as Avro or Protobuf, sent as is with the given Content-Type. Auth,
+// options and compression are applied as for RunLoadingJobJSONL. As the number of lines in
+// the body is not known, the job is checked for failure counters rather than for one valid
+// line per input; the statistics are returned in either case so that callers can make their
+// own checks.
+func (c *TigerGraphClient) RunLoadingJobRaw(
+	ctx context.Context,
+	graphName string,
+	loadingJobName string,
+	body io.Reader,
+	contentType string,
+	opts ...RequestOption,
+) (*LoadingJobStatistics, error) {
+	queryURL := loadingJobURL(graphName, loadingJobName)
+
+	// The body is buffered so that it can be resent on retries
+	bodyBytes, err := io.ReadAll(body)
+	if err != nil {
+		return nil, newOperationError("run loading job", graphName, queryURL, fmt.Errorf(
+			"failed to read loading job body: %w",
+			err,
+		))
+	}
+
+	opts = append([]RequestOption{WithOperation("run loading job")}, opts...)
+	if contentType != "" {
+		opts = append(opts, WithRequestHeader("Content-Type", contentType))
+	}
+
+	statistics, err := c.runLoadingJob(ctx, graphName, queryURL, bodyBytes, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if statistics.HasFailures() {
+		return statistics, c.diagnose(ctx, queryURL, newOperationError(operationName(opts, "run loading job"), graphName, queryURL, fmt.Errorf(
+			"tigergraph reported failures loading the body. failures: %s: %w",
+			statistics.FailureSummary(),
+			ErrLoadingJobPartialFailure,
+		)))
+	}
+
+	return statistics, nil
+}
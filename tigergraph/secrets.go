@@ -0,0 +1,113 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrSecretNotCreated represents CREATE SECRET output which did not contain the new secret
+var ErrSecretNotCreated = errors.New("GSQL output did not contain the created secret")
+
+// createdSecretRegexp matches the line GSQL prints for a new secret
+var createdSecretRegexp = regexp.MustCompile(`The secret: (\S+) has been created`)
+
+// GSQLSecret is a secret listed by ShowSecrets
+type GSQLSecret struct {
+	// Secret is the secret as shown by GSQL, with all but its first and last characters masked
+	Secret string
+	Alias  string
+	Graph  string
+}
+
+// CreateSecret creates a GSQL secret for the graph, with the given alias or a generated one if
+// it is empty, and returns it. The secret is never logged.
+func (c *TigerGraphClient) CreateSecret(ctx context.Context, graph string, alias string) (string, error) {
+	var output string
+	gsql := strings.TrimSpace(fmt.Sprintf("USE GRAPH %s\nCREATE SECRET %s", graph, alias))
+	err := c.RunGSQL(ctx, gsql, WithOperation("create secret"), withSensitiveGSQLOutput(), withGSQLOutput(func(part string) {
+		output += part
+	}))
+	if err != nil {
+		return "", err
+	}
+
+	match := createdSecretRegexp.FindStringSubmatch(output)
+	if match == nil {
+		return "", fmt.Errorf("graph %q: %w", graph, ErrSecretNotCreated)
+	}
+
+	return match[1], nil
+}
+
+// ShowSecrets lists the GSQL secrets of the graph, masked as GSQL shows them.
+func (c *TigerGraphClient) ShowSecrets(ctx context.Context, graph string) ([]GSQLSecret, error) {
+	var output string
+	gsql := fmt.Sprintf("USE GRAPH %s\nSHOW SECRET", graph)
+	err := c.RunGSQL(ctx, gsql, WithOperation("show secrets"), withGSQLOutput(func(part string) {
+		output += part
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSecrets(output, graph), nil
+}
+
+// DropSecret drops a GSQL secret of the graph, given either the secret itself or its alias.
+func (c *TigerGraphClient) DropSecret(ctx context.Context, graph string, secretOrAlias string) error {
+	gsql := fmt.Sprintf("USE GRAPH %s\nDROP SECRET %s", graph, secretOrAlias)
+	return c.RunGSQL(ctx, gsql, WithOperation("drop secret"), withSensitiveGSQLOutput())
+}
+
+// withSensitiveGSQLOutput stops the output of RunGSQL being logged
+func withSensitiveGSQLOutput() RequestOption {
+	return func(o *requestOptions) {
+		o.sensitiveGSQLOutput = true
+	}
+}
+
+// parseSecrets reads the secrets from the output of SHOW SECRET, which lists
+// each secret as a "- Secret:" line followed by its "- Alias:" and
+// "- GraphName:" lines.
+// Secrets without a GraphName are given the graph they were listed for.
+func parseSecrets(output string, graph string) []GSQLSecret {
+	var secrets []GSQLSecret
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(strings.TrimLeft(line, " -"), ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.TrimSpace(key) {
+		case "Secret":
+			secrets = append(secrets, GSQLSecret{Secret: value, Graph: graph})
+		case "Alias":
+			if len(secrets) > 0 {
+				secrets[len(secrets)-1].Alias = value
+			}
+		case "GraphName":
+			if len(secrets) > 0 {
+				secrets[len(secrets)-1].Graph = value
+			}
+		}
+	}
+
+	return secrets
+}
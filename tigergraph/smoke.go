@@ -0,0 +1,201 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SmokeTestGraphPrefix begins the name of the scratch graph created by SmokeTest
+	SmokeTestGraphPrefix = "GoTigerGraphSmokeTest_"
+
+	// SmokeTestVertexType is the vertex type created in the scratch graph by SmokeTest
+	SmokeTestVertexType = "SmokeTestItem"
+
+	// SmokeTestLoadingJob is the loading job SmokeTest loads the scratch graph with
+	SmokeTestLoadingJob = "smoke_test_load"
+
+	// SmokeTestQuery is the query SmokeTest installs and runs to read the loaded data
+	SmokeTestQuery = "smoke_test_totals"
+
+	// SmokeTestVersion is the version the scratch graph is migrated to by SmokeTest
+	SmokeTestVersion = "001"
+
+	smokeTestMigrationDir    = "gsql/smoke_test"
+	smokeTestFilePermissions = 0o600
+	smokeTestItems           = 3
+)
+
+// ErrSmokeTestFailed is returned when SmokeTest reads back different data to what it loaded
+var ErrSmokeTestFailed = errors.New("smoke test failed")
+
+// SmokeTestMigrations are the migrations run on the scratch graph by SmokeTest, in the
+// gsql/smoke_test directory. They create the SmokeTestItem vertex type, the smoke_test_load
+// loading job and the smoke_test_totals query, with placeholders for the graph name.
+//
+//go:embed gsql/smoke_test
+var SmokeTestMigrations embed.FS
+
+// SmokeTestStep is a step of SmokeTest which completed, and how long it took.
+type SmokeTestStep struct {
+	Name     string
+	Duration time.Duration
+}
+
+// SmokeTestResult records the scratch graph used by SmokeTest and the steps which completed.
+type SmokeTestResult struct {
+	Graph string
+	Steps []SmokeTestStep
+}
+
+type smokeTestItem struct {
+	ID    string `json:"id"`
+	Value int    `json:"value"`
+}
+
+// SmokeTestTotals is the output of the smoke_test_totals query.
+type SmokeTestTotals struct {
+	Count int `json:"count"`
+	Total int `json:"total"`
+}
+
+// SmokeTest checks that TigerGraph works end to end, e.g. after an upgrade. It creates a
+// scratch graph, migrates it to SmokeTestVersion with SmokeTestMigrations, loads vertices with
+// the migrated loading job, checks them with the migrated query and finally drops the graph,
+// even if an earlier step failed.
+//
+// The user must be allowed to create and drop graphs. The metadata graph is created if needed,
+// and keeps the record of the scratch graph's migrations. Steps which completed are returned
+// alongside any error.
+func (c *TigerGraphClient) SmokeTest(ctx context.Context) (result *SmokeTestResult, err error) {
+	result = &SmokeTestResult{
+		Graph: SmokeTestGraphPrefix + strconv.FormatInt(c.now().UnixNano(), 10),
+	}
+
+	step := func(name string, run func() error) error {
+		start := time.Now()
+		if err := run(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		result.Steps = append(result.Steps, SmokeTestStep{Name: name, Duration: time.Since(start)})
+
+		return nil
+	}
+
+	err = step("create graph", func() error {
+		return c.RunGSQL(ctx, fmt.Sprintf("CREATE GRAPH %s()", result.Graph))
+	})
+	if err != nil {
+		return result, err
+	}
+
+	defer func() {
+		dropErr := step("drop graph", func() error {
+			return c.RunGSQL(ctx, fmt.Sprintf("DROP GRAPH %s CASCADE", result.Graph))
+		})
+		err = errors.Join(err, dropErr)
+	}()
+
+	err = step("migrate", func() error {
+		dir, err := writeSmokeTestMigrations(result.Graph)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+
+		return c.Migrate(ctx, result.Graph, SmokeTestVersion, "", dir, false)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	expected := SmokeTestTotals{Count: smokeTestItems}
+	err = step("load", func() error {
+		lines := make([]any, 0, smokeTestItems)
+		for i := 1; i <= smokeTestItems; i++ {
+			lines = append(lines, smokeTestItem{ID: strconv.Itoa(i), Value: i})
+			expected.Total += i
+		}
+
+		return c.RunLoadingJobJSONL(ctx, result.Graph, SmokeTestLoadingJob, lines)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = step("query", func() error {
+		var response TigerGraphResponse[SmokeTestTotals]
+		queryURL := fmt.Sprintf(QueryURLTemplate, result.Graph, SmokeTestQuery)
+		if err := c.Get(ctx, queryURL, result.Graph, &response); err != nil {
+			return err
+		}
+
+		if len(response.Results) != 1 {
+			return fmt.Errorf("%s returned %d results: %w", SmokeTestQuery, len(response.Results), ErrSmokeTestFailed)
+		}
+		if response.Results[0] != expected {
+			return fmt.Errorf(
+				"read %d vertices totalling %d, loaded %d totalling %d: %w",
+				response.Results[0].Count,
+				response.Results[0].Total,
+				expected.Count,
+				expected.Total,
+				ErrSmokeTestFailed,
+			)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// writeSmokeTestMigrations writes SmokeTestMigrations for the graph to a new temporary
+// directory, which the caller must remove
+func writeSmokeTestMigrations(graph string) (string, error) {
+	files, err := fs.ReadDir(SmokeTestMigrations, smokeTestMigrationDir)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "go-tigergraph-smoke-test-")
+	if err != nil {
+		return "", err
+	}
+
+	for _, file := range files {
+		contents, err := fs.ReadFile(SmokeTestMigrations, smokeTestMigrationDir+"/"+file.Name())
+		if err == nil {
+			gsql := strings.ReplaceAll(string(contents), graphNamePlaceholder, graph)
+			err = os.WriteFile(filepath.Join(dir, file.Name()), []byte(gsql), smokeTestFilePermissions)
+		}
+
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// DeletedAtAttribute is the DATETIME attribute used to mark vertices as soft deleted.
+	// Vertex types using the soft delete helpers must declare this attribute.
+	DeletedAtAttribute = "deleted_at"
+
+	// NotDeletedDateTime is the value of DeletedAtAttribute on vertices that are not deleted.
+	NotDeletedDateTime = DefaultDateTime
+
+	// VerticesURLTemplate is the RESTPP URL for listing vertices of a type. It must be
+	// formatted with the graph name and vertex type.
+	VerticesURLTemplate = "/graph/%s/vertices/%s"
+)
+
+// SoftDeletable is implemented by vertex attribute types that carry the soft delete attribute.
+type SoftDeletable interface {
+	IsDeleted() bool
+}
+
+// SoftDeleteFields can be embedded in vertex attribute types to make them SoftDeletable.
+type SoftDeleteFields struct {
+	DeletedAt string `json:"deleted_at"`
+}
+
+// IsDeleted reports whether the vertex has been soft deleted.
+func (f SoftDeleteFields) IsDeleted() bool {
+	return f.DeletedAt != "" && f.DeletedAt != NotDeletedDateTime
+}
+
+type readOptions struct {
+	includeDeleted bool
+	requestOpts    []RequestOption
+}
+
+// ReadOption configures the behaviour of read helpers such as GetVertices.
+type ReadOption func(*readOptions)
+
+// IncludeDeleted makes read helpers return soft deleted vertices, which are filtered out by default.
+func IncludeDeleted() ReadOption {
+	return func(o *readOptions) {
+		o.includeDeleted = true
+	}
+}
+
+// ReadRequestOptions passes RequestOptions, e.g. WithRequestHeader, to the request a read helper
+// makes. It is ignored by FilterDeleted, which makes no request.
+func ReadRequestOptions(opts ...RequestOption) ReadOption {
+	return func(o *readOptions) {
+		o.requestOpts = append(o.requestOpts, opts...)
+	}
+}
+
+// SoftDeleteVertices marks the given vertices as deleted by setting their deleted_at attribute
+// to the current time. Vertices that do not exist are not created. Pass the Atomic option to
+// delete all of the vertices or none of them.
+func (c *TigerGraphClient) SoftDeleteVertices(
+	ctx context.Context,
+	graphName string,
+	vertexType string,
+	ids []string,
+	opts ...RequestOption,
+) (*UpsertResponseResult, error) {
+	return c.setDeletedAt(ctx, graphName, vertexType, ids, c.DatetimeEncoding.Encode(c.now()), operationOptions("soft delete", opts)...)
+}
+
+// RestoreVertices reverses SoftDeleteVertices by resetting the deleted_at attribute.
+func (c *TigerGraphClient) RestoreVertices(
+	ctx context.Context,
+	graphName string,
+	vertexType string,
+	ids []string,
+	opts ...RequestOption,
+) (*UpsertResponseResult, error) {
+	return c.setDeletedAt(ctx, graphName, vertexType, ids, c.DatetimeEncoding.Encode(time.Unix(0, 0)), operationOptions("restore", opts)...)
+}
+
+func (c *TigerGraphClient) setDeletedAt(
+	ctx context.Context,
+	graphName string,
+	vertexType string,
+	ids []string,
+	deletedAt any,
+	opts ...RequestOption,
+) (*UpsertResponseResult, error) {
+	vertices := make(map[string]map[string]MigrationVertexPayloadValue[any], len(ids))
+	for _, id := range ids {
+		vertices[id] = map[string]MigrationVertexPayloadValue[any]{
+			DeletedAtAttribute: {deletedAt},
+		}
+	}
+
+	payload := map[string]any{
+		"vertices": map[string]any{
+			vertexType: vertices,
+		},
+	}
+
+	responseResult := &UpsertResponse{}
+	queryURL := UpsertURL + "/" + graphName + "?vertex_must_exist=true"
+	err := c.Post(ctx, queryURL, graphName, payload, responseResult, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if responseResult.Error {
+		return nil, newOperationError(operationName(opts, "upsert"), graphName, queryURL, fmt.Errorf(
+			"TigerGraph returned an error when trying to set %s. Message: %s: %w",
+			DeletedAtAttribute,
+			responseResult.Message,
+			ErrTigerGraphError,
+		))
+	}
+
+	if len(responseResult.Results) != 1 {
+		return nil, newOperationError(operationName(opts, "upsert"), graphName, queryURL, ErrNotOneResult)
+	}
+
+	result := &responseResult.Results[0]
+	c.recordUpsert(graphName, result)
+
+	return result, nil
+}
+
+// FilterDeleted removes soft deleted vertices from the given slice, unless the IncludeDeleted
+// option is supplied.
+func FilterDeleted[T SoftDeletable](vertices []ResponseVertex[T], opts ...ReadOption) []ResponseVertex[T] {
+	options := &readOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.includeDeleted {
+		return vertices
+	}
+
+	result := make([]ResponseVertex[T], 0, len(vertices))
+	for _, vertex := range vertices {
+		if !vertex.Attributes.IsDeleted() {
+			result = append(result, vertex)
+		}
+	}
+
+	return result
+}
+
+// GetVertices returns all vertices of the given type. Soft deleted vertices are
+// filtered out unless the IncludeDeleted option is supplied.
+func GetVertices[T SoftDeletable](
+	ctx context.Context,
+	c *TigerGraphClient,
+	graphName string,
+	vertexType string,
+	opts ...ReadOption,
+) ([]ResponseVertex[T], error) {
+	options := &readOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var response TigerGraphResponse[ResponseVertex[T]]
+	queryURL := fmt.Sprintf(VerticesURLTemplate, graphName, vertexType)
+	err := c.Get(ctx, queryURL, graphName, &response, operationOptions("get vertices", options.requestOpts)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error {
+		return nil, newOperationError("get vertices", graphName, queryURL, fmt.Errorf(
+			"failed to get vertices. message: %s: %w", response.Message, ErrTigerGraphError,
+		))
+	}
+
+	return FilterDeleted(response.Results, opts...), nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoToken represents a graph with no static token when token requests are disabled
+var ErrNoToken = errors.New("no token for graph and token requests are disabled")
+
+// WithStaticToken sets a pre-provisioned RESTPP token to use for the graph, e.g. one issued
+// out-of-band by a platform. The token is used as given: it is never requested, refreshed or
+// revoked by the client. Use GlobalGraph for a token not bound to a graph.
+func WithStaticToken(graph string, token string) Option {
+	return func(c *TigerGraphClient) {
+		if c.StaticTokens == nil {
+			c.StaticTokens = make(map[string]string)
+		}
+		c.StaticTokens[graph] = token
+	}
+}
+
+// WithoutTokenRequests stops the client requesting tokens from /requesttoken. Unless a
+// TokenProvider is set, requests to graphs without a static token fail with ErrNoToken.
+func WithoutTokenRequests() Option {
+	return func(c *TigerGraphClient) {
+		c.DisableTokenRequests = true
+	}
+}
+
+// staticToken returns the static token for the graph. It returns an error if the graph has no
+// static token and token requests are disabled with no TokenProvider, and no token otherwise.
+func (c *TigerGraphClient) staticToken(graph string) (*Token, error) {
+	if value, ok := c.StaticTokens[graph]; ok {
+		return &Token{Value: value}, nil
+	}
+
+	if c.DisableTokenRequests && c.TokenProvider == nil {
+		return nil, fmt.Errorf("graph %q: %w", graph, ErrNoToken)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrUnauthorized represents TigerGraph responding with 401 Unauthorized, e.g. to wrong
+	// credentials or an expired or revoked token, which refreshing credentials may fix. It
+	// also matches ErrNonOK.
+	ErrUnauthorized = errors.New("TigerGraph rejected the request's credentials")
+
+	// ErrForbidden represents TigerGraph responding with 403 Forbidden: the user was
+	// authenticated but lacks the privileges the request needs. It also matches ErrNonOK.
+	ErrForbidden = errors.New("TigerGraph denied the request's user permission")
+)
+
+// statusError is the error for a non-OK response status code: ErrNonOK, which is also wrapped in
+// ErrUnauthorized or ErrForbidden for authentication failures
+func statusError(statusCode int) error {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: %w", ErrUnauthorized, ErrNonOK)
+	case http.StatusForbidden:
+		return fmt.Errorf("%w: %w", ErrForbidden, ErrNonOK)
+	default:
+		return ErrNonOK
+	}
+}
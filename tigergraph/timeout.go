@@ -0,0 +1,221 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// QueryTimeoutHeader tells RESTPP how long, in milliseconds, a query may run for before it
+	// is aborted by the server.
+	QueryTimeoutHeader = "GSQL-TIMEOUT"
+
+	// DefaultConnectTimeout bounds how long opening a connection to TigerGraph may take.
+	DefaultConnectTimeout = 10 * time.Second
+
+	// DefaultRequestTimeout bounds how long Get, Post, PostRaw and RunGSQL may take.
+	DefaultRequestTimeout = 5 * time.Minute
+
+	// DefaultMigrationStepTimeout bounds how long running a single migration file may take.
+	DefaultMigrationStepTimeout = 30 * time.Minute
+
+	// DefaultPingTimeout bounds how long Ping may take, so that health probes fail fast.
+	DefaultPingTimeout = 2 * time.Second
+
+	dialKeepAlive = 30 * time.Second
+)
+
+// Timeouts bounds each layer of a request to TigerGraph. A zero duration leaves that layer
+// unbounded.
+type Timeouts struct {
+	// Connect bounds dialing a connection to TigerGraph.
+	Connect time.Duration
+
+	// Request bounds Get, Post, PostRaw and RunGSQL, including requesting a token and reading
+	// the response. It can be overridden per call with WithRequestTimeout.
+	Request time.Duration
+
+	// Query is sent to RESTPP in the GSQL-TIMEOUT header, so that the server aborts queries
+	// which run for longer. A shorter deadline on the caller's context is sent in its place.
+	// Zero, with no deadline, leaves the server's own default in place.
+	Query time.Duration
+
+	// MigrationStep bounds running each migration file, in place of Request.
+	MigrationStep time.Duration
+
+	// Ping bounds Ping, in place of Request. It can be overridden per call with
+	// WithRequestTimeout.
+	Ping time.Duration
+}
+
+// DefaultTimeouts are the Timeouts of a client created with NewClient.
+var DefaultTimeouts = Timeouts{
+	Connect:       DefaultConnectTimeout,
+	Request:       DefaultRequestTimeout,
+	MigrationStep: DefaultMigrationStepTimeout,
+	Ping:          DefaultPingTimeout,
+}
+
+// WithTimeouts replaces the client's Timeouts.
+func WithTimeouts(timeouts Timeouts) Option {
+	return func(c *TigerGraphClient) {
+		c.Timeouts = timeouts
+	}
+}
+
+// RequestOption configures a single call to TigerGraph, e.g. Get, Post, Upsert or RunGSQL.
+// Options which do not apply to a call are ignored.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	timeout       *time.Duration
+	queryTimeout  *time.Duration
+	responseLimit *int64
+	route         Route
+
+	maxResultVertices int
+
+	// tokenLifetime is the lifetime of the token Auth requests
+	tokenLifetime *time.Duration
+
+	// credentials and token replace the client's authentication for the call
+	credentials *Credentials
+	token       string
+
+	allowGlobalSchemaChange bool
+	atomic                  bool
+	headers                 http.Header
+	operation               string
+
+	// gsqlOutput receives the output of each GSQL request made by RunGSQL
+	gsqlOutput func(string)
+
+	// gsqlSession replaces the client's GSQLSession for the call
+	gsqlSession *GSQLSession
+
+	// sensitiveGSQLOutput stops the output of RunGSQL being logged, e.g. as it holds a secret
+	sensitiveGSQLOutput bool
+}
+
+// WithRequestTimeout overrides the client's request timeout for a single call. Zero means no timeout.
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = &timeout
+	}
+}
+
+// WithQueryTimeout overrides the GSQL-TIMEOUT sent to RESTPP for a single call, in place of
+// the client's query timeout and the caller's context deadline. Zero sends no GSQL-TIMEOUT,
+// leaving the server's own default in place.
+func WithQueryTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.queryTimeout = &timeout
+	}
+}
+
+func collectRequestOptions(opts []RequestOption) requestOptions {
+	options := requestOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// withTimeout bounds ctx by the call's timeout, or the client's request timeout if the call
+// does not override it. The returned CancelFunc must be called once the response has been read.
+func (c *TigerGraphClient) withTimeout(ctx context.Context, opts []RequestOption) (context.Context, context.CancelFunc) {
+	timeout := c.Timeouts.Request
+	if options := collectRequestOptions(opts); options.timeout != nil {
+		timeout = *options.timeout
+	}
+
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// queryTimeout is how long RESTPP may run the call's query for: the call's WithQueryTimeout,
+// or else the shorter of the client's query timeout and the time left before ctx's deadline.
+// It must be given the caller's ctx, before the client's request timeout is applied, so that
+// the server's own default is only replaced when the caller asks for it.
+func (c *TigerGraphClient) queryTimeout(ctx context.Context, opts []RequestOption) time.Duration {
+	if options := collectRequestOptions(opts); options.queryTimeout != nil {
+		return *options.queryTimeout
+	}
+
+	timeout := c.Timeouts.Query
+	if deadline, ok := ctx.Deadline(); ok {
+		// Never send a zero or negative timeout, which RESTPP treats as unbounded
+		remaining := time.Until(deadline)
+		if remaining < time.Millisecond {
+			remaining = time.Millisecond
+		}
+		if timeout <= 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	return timeout
+}
+
+// setQueryTimeout asks RESTPP to abort the query after timeout, unless the call has set its
+// own GSQL-TIMEOUT header.
+func setQueryTimeout(req *http.Request, timeout time.Duration) {
+	if timeout <= 0 || req.Header.Get(QueryTimeoutHeader) != "" {
+		return
+	}
+
+	req.Header.Set(QueryTimeoutHeader, strconv.FormatInt(timeout.Milliseconds(), 10))
+}
+
+// applyConnectTimeout bounds dialing by the client's connect timeout. A client given with
+// WithHTTPClient is left as it is, unless other options have already configured a copy of its
+// transport, in which case its own DialContext is kept and only bounded. Clients whose
+// transport is not an *http.Transport are left to dial as their transport sees fit.
+func (c *TigerGraphClient) applyConnectTimeout() {
+	if c.Timeouts.Connect <= 0 {
+		return
+	}
+	if c.HTTPClient != nil && c.ownedTransport == nil {
+		return
+	}
+
+	transport, err := c.ownTransport()
+	if err != nil {
+		return
+	}
+
+	if transport.DialContext == nil {
+		dialer := &net.Dialer{Timeout: c.Timeouts.Connect, KeepAlive: dialKeepAlive}
+		transport.DialContext = dialer.DialContext
+		return
+	}
+
+	dial := transport.DialContext
+	timeout := c.Timeouts.Connect
+	transport.DialContext = func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return dial(ctx, network, addr)
+	}
+}
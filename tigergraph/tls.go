@@ -0,0 +1,117 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrInvalidCACert means no certificates could be parsed from the PEM given to WithCACert
+	ErrInvalidCACert = errors.New("no valid certificates found in CA PEM")
+
+	// ErrInvalidClientCertificate means a client certificate and key pair could not be loaded
+	ErrInvalidClientCertificate = errors.New("invalid client certificate")
+)
+
+// WithTLSConfig sets the TLS configuration used to connect to TigerGraph.
+func WithTLSConfig(config *tls.Config) Option {
+	return withTransport(func(transport *http.Transport) error {
+		transport.TLSClientConfig = config.Clone()
+		return nil
+	})
+}
+
+// WithCACert trusts the PEM encoded CA certificates, e.g. for a TigerGraph server using a
+// self-signed certificate, in addition to the system's certificate pool.
+func WithCACert(pemCerts []byte) Option {
+	return withTransport(func(transport *http.Transport) error {
+		config := tlsConfig(transport)
+		if config.RootCAs == nil {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			config.RootCAs = pool
+		} else {
+			// The pool may be shared, e.g. by the caller's config given to WithTLSConfig
+			config.RootCAs = config.RootCAs.Clone()
+		}
+
+		if !config.RootCAs.AppendCertsFromPEM(pemCerts) {
+			return ErrInvalidCACert
+		}
+
+		return nil
+	})
+}
+
+// WithClientCertificate presents the certificate to TigerGraph, for servers requiring mutual TLS.
+// It is used for every request, to both RESTPP and the GSQL server.
+func WithClientCertificate(certificate tls.Certificate) Option {
+	return withTransport(func(transport *http.Transport) error {
+		config := tlsConfig(transport)
+		config.Certificates = append(config.Certificates, certificate)
+		return nil
+	})
+}
+
+// WithClientCertificateFiles presents the PEM encoded certificate and key in the given files to
+// TigerGraph, for servers requiring mutual TLS on the RESTPP and GSQL ports. The files are
+// read when the client is created.
+func WithClientCertificateFiles(certFile string, keyFile string) Option {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return withTransport(func(*http.Transport) error {
+			return fmt.Errorf("%w: %w", ErrInvalidClientCertificate, err)
+		})
+	}
+
+	return WithClientCertificate(certificate)
+}
+
+// WithClientCertificatePEM presents the PEM encoded certificate and key to TigerGraph, for
+// servers requiring mutual TLS, e.g. with a pair read from a secret store.
+func WithClientCertificatePEM(certPEM []byte, keyPEM []byte) Option {
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return withTransport(func(*http.Transport) error {
+			return fmt.Errorf("%w: %w", ErrInvalidClientCertificate, err)
+		})
+	}
+
+	return WithClientCertificate(certificate)
+}
+
+// WithInsecureSkipVerify stops the client verifying TigerGraph's certificate. This must only
+// be used in development.
+func WithInsecureSkipVerify() Option {
+	return withTransport(func(transport *http.Transport) error {
+		tlsConfig(transport).InsecureSkipVerify = true //nolint:gosec // explicitly requested by the caller
+		return nil
+	})
+}
+
+// tlsConfig returns the transport's TLS configuration, creating it if needed.
+func tlsConfig(transport *http.Transport) *tls.Config {
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	return transport.TLSClientConfig
+}
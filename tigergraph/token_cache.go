@@ -0,0 +1,164 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TokenCache holds the client's RESTPP tokens, one per graph. It is safe for concurrent use,
+// and only one token request per graph is made at a time: callers needing a token for a graph
+// whose token is already being requested wait for that request and share its result.
+type TokenCache struct {
+	mu       sync.RWMutex
+	tokens   map[string]*Token
+	inFlight map[string]*tokenRequest
+}
+
+// tokenRequest is a token request in flight, whose result is shared by every caller waiting
+// on done
+type tokenRequest struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+// NewTokenCache returns an empty TokenCache
+func NewTokenCache() *TokenCache {
+	return &TokenCache{
+		tokens:   make(map[string]*Token),
+		inFlight: make(map[string]*tokenRequest),
+	}
+}
+
+// Get returns the cached token for the graph, whether or not it has expired.
+func (tc *TokenCache) Get(graph string) (*Token, bool) {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+
+	token, ok := tc.tokens[graph]
+	return token, ok
+}
+
+// Set caches the token for the graph, replacing any existing token.
+func (tc *TokenCache) Set(graph string, token *Token) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.tokens == nil {
+		tc.tokens = make(map[string]*Token)
+	}
+	tc.tokens[graph] = token
+}
+
+// Delete removes the cached token for the graph, so that the next request fetches a new one.
+func (tc *TokenCache) Delete(graph string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	delete(tc.tokens, graph)
+}
+
+// Graphs returns the graphs which have a cached token, in name order.
+func (tc *TokenCache) Graphs() []string {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+
+	graphs := make([]string, 0, len(tc.tokens))
+	for graph := range tc.tokens {
+		graphs = append(graphs, graph)
+	}
+	sort.Strings(graphs)
+
+	return graphs
+}
+
+// fetch returns the token for the graph from request, unless a request for the graph is
+// already in flight, in which case it waits for that request's result instead. The token
+// returned by request is cached.
+//
+// The request is shared, so it runs on a context detached from the caller which started it,
+// keeping its values but not its deadline or cancellation, and every caller, including the
+// first, gives up waiting only when its own ctx is done.
+func (tc *TokenCache) fetch(
+	ctx context.Context,
+	graph string,
+	request func(ctx context.Context) (*Token, error),
+) (*Token, error) {
+	tc.mu.Lock()
+	if tc.inFlight == nil {
+		tc.inFlight = make(map[string]*tokenRequest)
+	}
+	inFlight, ok := tc.inFlight[graph]
+	if !ok {
+		inFlight = &tokenRequest{done: make(chan struct{})}
+		tc.inFlight[graph] = inFlight
+		go tc.request(detachedContext{parent: ctx}, graph, inFlight, request)
+	}
+	tc.mu.Unlock()
+
+	select {
+	case <-inFlight.done:
+		return inFlight.token, inFlight.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// request makes the token request in flight for the graph, caching its token and sharing its
+// result with the callers waiting on it
+func (tc *TokenCache) request(
+	ctx context.Context,
+	graph string,
+	inFlight *tokenRequest,
+	request func(ctx context.Context) (*Token, error),
+) {
+	inFlight.token, inFlight.err = request(ctx)
+
+	tc.mu.Lock()
+	if inFlight.err == nil {
+		if tc.tokens == nil {
+			tc.tokens = make(map[string]*Token)
+		}
+		tc.tokens[graph] = inFlight.token
+	}
+	delete(tc.inFlight, graph)
+	tc.mu.Unlock()
+	close(inFlight.done)
+}
+
+// detachedContext carries the values of its parent without its deadline or cancellation, as
+// context.WithoutCancel does from Go 1.21
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key any) any {
+	return d.parent.Value(key)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenCacheFetch(t *testing.T) { //nolint:funlen
+	t.Run("shares a request in flight", func(t *testing.T) {
+		cache := NewTokenCache()
+		release := make(chan struct{})
+		requests := 0
+
+		var wg sync.WaitGroup
+		tokens := make([]*Token, 5)
+		for i := range tokens {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				token, err := cache.fetch(context.Background(), "G", func(context.Context) (*Token, error) {
+					requests++
+					<-release
+					return &Token{Value: "token"}, nil
+				})
+				assert.Nil(t, err)
+				tokens[i] = token
+			}(i)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, 1, requests)
+		for _, token := range tokens {
+			assert.Equal(t, "token", token.Value)
+		}
+		cached, ok := cache.Get("G")
+		assert.True(t, ok)
+		assert.Equal(t, "token", cached.Value)
+	})
+
+	t.Run("does not cache failures", func(t *testing.T) {
+		cache := NewTokenCache()
+		failure := errors.New("failed")
+
+		_, err := cache.fetch(context.Background(), "G", func(context.Context) (*Token, error) {
+			return nil, failure
+		})
+		assert.ErrorIs(t, err, failure)
+
+		_, ok := cache.Get("G")
+		assert.False(t, ok)
+
+		token, err := cache.fetch(context.Background(), "G", func(context.Context) (*Token, error) {
+			return &Token{Value: "token"}, nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "token", token.Value)
+	})
+
+	t.Run("waiting callers give up with their context", func(t *testing.T) {
+		cache := NewTokenCache()
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+
+		go func() {
+			_, _ = cache.fetch(context.Background(), "G", func(context.Context) (*Token, error) {
+				close(started)
+				<-release
+				return &Token{Value: "token"}, nil
+			})
+		}()
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := cache.fetch(ctx, "G", func(context.Context) (*Token, error) {
+			t.Error("a second request should not be made")
+			return nil, nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("the request outlives the caller which started it", func(t *testing.T) {
+		type key struct{}
+
+		cache := NewTokenCache()
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+		first := make(chan error)
+		go func() {
+			_, err := cache.fetch(ctx, "G", func(ctx context.Context) (*Token, error) {
+				close(started)
+				<-release
+				assert.Nil(t, ctx.Err())
+				assert.Equal(t, "value", ctx.Value(key{}))
+				return &Token{Value: "token"}, nil
+			})
+			first <- err
+		}()
+		<-started
+
+		cancel()
+		assert.ErrorIs(t, <-first, context.Canceled)
+
+		// The request carries on and caches its token
+		close(release)
+		assert.Eventually(t, func() bool {
+			token, ok := cache.Get("G")
+			return ok && token.Value == "token"
+		}, time.Second, time.Millisecond)
+	})
+}
+
+func TestTokenCacheSetDelete(t *testing.T) {
+	cache := &TokenCache{}
+
+	cache.Set("B", &Token{Value: "b"})
+	cache.Set("A", &Token{Value: "a"})
+	assert.Equal(t, []string{"A", "B"}, cache.Graphs())
+
+	cache.Delete("B")
+	_, ok := cache.Get("B")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"A"}, cache.Graphs())
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"time"
+)
+
+// WithTokenLifetime sets how long the tokens the client requests from TigerGraph last, e.g.
+// minutes in high-security environments or days for long batch jobs. Zero leaves the lifetime
+// to the server's default.
+func WithTokenLifetime(lifetime time.Duration) Option {
+	return func(c *TigerGraphClient) {
+		c.TokenLifetime = lifetime
+	}
+}
+
+// TokenLifetime makes Auth request a new token for the graph which lasts for lifetime, in place
+// of the client's TokenLifetime, even if a non-expired token is already cached. The new token
+// is cached for the calls which follow. It is ignored for static tokens and tokens from a
+// TokenProvider, whose lifetimes are not the client's to choose.
+func TokenLifetime(lifetime time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.tokenLifetime = &lifetime
+	}
+}
+
+// authWithLifetime requests a new token for the graph which lasts for lifetime, and caches it
+func (c *TigerGraphClient) authWithLifetime(ctx context.Context, graph string, lifetime time.Duration) error {
+	if token, err := c.staticToken(graph); token != nil || err != nil {
+		return err
+	}
+
+	if c.TokenProvider != nil {
+		_, err := c.token(ctx, graph)
+		return err
+	}
+
+	_, err := c.fetchToken(ctx, c.Tokens, graph, func(ctx context.Context) (*Token, error) {
+		token, err := c.requestToken(ctx, graph, lifetime)
+		if err != nil {
+			return nil, err
+		}
+
+		c.storeToken(ctx, graph, token)
+
+		return token, nil
+	})
+
+	return err
+}
+
+// lifetimeSeconds is the lifetime sent in token requests, in whole seconds rounded up so that
+// a short lifetime is never sent as zero, which would leave the server's default in place
+func lifetimeSeconds(lifetime time.Duration) int64 {
+	if lifetime <= 0 {
+		return 0
+	}
+
+	return int64((lifetime + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import "time"
+
+// TokenMetrics receives events about the lifecycle of the tokens held by the client.
+// Implementations can forward these to a metrics backend in order to alert when, for
+// example, credentials are rotated and token requests start failing for a graph.
+//
+// Implementations must be safe to call from multiple goroutines.
+type TokenMetrics interface {
+	// TokenRequested is called every time a new token is requested from TigerGraph.
+	TokenRequested(graph string)
+
+	// TokenRefreshed is called when a token request succeeds, with the time the request took
+	// and the time at which the new token expires.
+	TokenRefreshed(graph string, latency time.Duration, expires time.Time)
+
+	// AuthFailed is called when a token request fails.
+	AuthFailed(graph string, err error)
+
+	// TokenExpired is called when a cached token is found to have expired and must be replaced.
+	TokenExpired(graph string, expiredAt time.Time)
+}
+
+// NoopTokenMetrics is a TokenMetrics implementation that discards all events.
+// It is used when no TokenMetrics is set on the client.
+type NoopTokenMetrics struct{}
+
+// TokenRequested implements TokenMetrics
+func (NoopTokenMetrics) TokenRequested(string) {}
+
+// TokenRefreshed implements TokenMetrics
+func (NoopTokenMetrics) TokenRefreshed(string, time.Duration, time.Time) {}
+
+// AuthFailed implements TokenMetrics
+func (NoopTokenMetrics) AuthFailed(string, error) {}
+
+// TokenExpired implements TokenMetrics
+func (NoopTokenMetrics) TokenExpired(string, time.Time) {}
+
+func (c *TigerGraphClient) tokenMetrics() TokenMetrics {
+	if c.TokenMetrics == nil {
+		return NoopTokenMetrics{}
+	}
+
+	return c.TokenMetrics
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"time"
+)
+
+// TokenProvider supplies the bearer tokens sent to RESTPP in place of requesting them from
+// /requesttoken with basic auth, e.g. JWTs from an OIDC client for a proxy in front of
+// TigerGraph, or tokens held in a vault. Tokens are cached per graph until their expiry, so a
+// zero expiry means the provider is asked again for every request.
+//
+// Implementations must be safe to call from multiple goroutines.
+type TokenProvider interface {
+	// Token returns a token for the graph and the time at which it expires. The graph is
+	// GlobalGraph for a token not bound to any graph.
+	Token(ctx context.Context, graph string) (string, time.Time, error)
+}
+
+// TokenProviderFunc is a function implementing TokenProvider
+type TokenProviderFunc func(ctx context.Context, graph string) (string, time.Time, error)
+
+// Token implements TokenProvider
+func (f TokenProviderFunc) Token(ctx context.Context, graph string) (string, time.Time, error) {
+	return f(ctx, graph)
+}
+
+// WithTokenProvider sets the TokenProvider supplying tokens, in place of requesting them from
+// TigerGraph. Static tokens set with WithStaticToken still take precedence.
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(c *TigerGraphClient) {
+		c.TokenProvider = provider
+	}
+}
+
+// providedToken gets a token for the graph from the client's TokenProvider
+func (c *TigerGraphClient) providedToken(ctx context.Context, graph string) (*Token, error) {
+	metrics := c.tokenMetrics()
+	metrics.TokenRequested(graph)
+	start := time.Now()
+
+	value, expires, err := c.TokenProvider.Token(ctx, graph)
+	if err != nil {
+		metrics.AuthFailed(graph, err)
+		return nil, err
+	}
+
+	metrics.TokenRefreshed(graph, time.Since(start), expires)
+
+	return &Token{Value: value, Expires: expires}, nil
+}
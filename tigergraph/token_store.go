@@ -0,0 +1,131 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"strings"
+)
+
+// TokenStore holds tokens outside the client, e.g. in Redis or memcached, so that a fleet of
+// processes can share the tokens one of them requests rather than each requesting its own.
+// The client's TokenCache remains in front of the store: the store is only read when the
+// cache has no unexpired token for a graph, and is given each token the client requests.
+// *TokenCache is itself a TokenStore, holding tokens in process.
+//
+// Tokens are stored under a key made by TokenStoreKey from the client's BaseURL, its
+// username and the graph, so that clients of different servers or users can share a store
+// without using each other's tokens.
+//
+// Implementations must be safe to call from multiple goroutines.
+type TokenStore interface {
+	// GetToken returns the token stored under key, or nil if there is none. Expired tokens
+	// may be returned, and are ignored by the client.
+	GetToken(ctx context.Context, key string) (*Token, error)
+
+	// SetToken stores the token under key, replacing any existing token. The token need not
+	// be kept after its Expires time.
+	SetToken(ctx context.Context, key string, token *Token) error
+
+	// DeleteToken removes the token stored under key, e.g. once it has been revoked.
+	DeleteToken(ctx context.Context, key string) error
+}
+
+// TokenStoreKey returns the key under which a client for the RESTPP server at baseURL, with
+// the given username, keeps its token for the graph in a TokenStore.
+func TokenStoreKey(baseURL string, username string, graph string) string {
+	return strings.Join([]string{baseURL, username, graph}, "|")
+}
+
+// WithTokenStore sets a TokenStore shared with other clients. Tokens in a shared store are
+// not revoked by Close, as other clients may still be using them.
+func WithTokenStore(store TokenStore) Option {
+	return func(c *TigerGraphClient) {
+		c.TokenStore = store
+	}
+}
+
+// GetToken implements TokenStore
+func (tc *TokenCache) GetToken(_ context.Context, key string) (*Token, error) {
+	token, _ := tc.Get(key)
+	return token, nil
+}
+
+// SetToken implements TokenStore
+func (tc *TokenCache) SetToken(_ context.Context, key string, token *Token) error {
+	tc.Set(key, token)
+	return nil
+}
+
+// DeleteToken implements TokenStore
+func (tc *TokenCache) DeleteToken(_ context.Context, key string) error {
+	tc.Delete(key)
+	return nil
+}
+
+// tokenStoreKey returns the key of the graph's token in the client's TokenStore
+func (c *TigerGraphClient) tokenStoreKey(ctx context.Context, graph string) (string, error) {
+	credentials, err := c.credentials(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return TokenStoreKey(c.BaseURL, credentials.Username, graph), nil
+}
+
+// storedToken returns the graph's unexpired token from the client's TokenStore, or else the
+// token from request, which is stored. Failures of the store are logged rather than failing
+// the call, so that an unavailable store only costs a token request.
+func (c *TigerGraphClient) storedToken(ctx context.Context, graph string, request func() (*Token, error)) (*Token, error) {
+	if c.TokenStore == nil {
+		return request()
+	}
+
+	key, err := c.tokenStoreKey(ctx, graph)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := c.TokenStore.GetToken(ctx, key)
+	if err != nil {
+		c.log(LogLevelWarn, "failed to get token from token store", "graph", graph, "error", err)
+	}
+	if err == nil && token != nil && token.Expires.After(c.now()) {
+		return token, nil
+	}
+
+	token, err = request()
+	if err != nil {
+		return nil, err
+	}
+
+	c.storeToken(ctx, graph, token)
+
+	return token, nil
+}
+
+// storeToken gives the token to the client's TokenStore, if it has one
+func (c *TigerGraphClient) storeToken(ctx context.Context, graph string, token *Token) {
+	if c.TokenStore == nil {
+		return
+	}
+
+	key, err := c.tokenStoreKey(ctx, graph)
+	if err == nil {
+		err = c.TokenStore.SetToken(ctx, key, token)
+	}
+	if err != nil {
+		c.log(LogLevelWarn, "failed to store token in token store", "graph", graph, "error", err)
+	}
+}
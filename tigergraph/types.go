@@ -29,6 +29,24 @@ type ResponseVertex[T any] struct {
 	Attributes T      `json:"attributes"`
 }
 
+type ResponseEdge[T any] struct {
+	EType      string `json:"e_type"`
+	FromID     string `json:"from_id"`
+	FromType   string `json:"from_type"`
+	ToID       string `json:"to_id"`
+	ToType     string `json:"to_type"`
+	Directed   bool   `json:"directed"`
+	Attributes T      `json:"attributes"`
+}
+
+// SameEndpoints reports whether two edges have the same type and connect the same vertices.
+// Edge types with a discriminator can have several such edges, told apart by their attributes.
+func (e ResponseEdge[T]) SameEndpoints(other ResponseEdge[T]) bool {
+	return e.EType == other.EType &&
+		e.FromType == other.FromType && e.FromID == other.FromID &&
+		e.ToType == other.ToType && e.ToID == other.ToID
+}
+
 type TigerGraphResponse[T any] struct {
 	Version Version `json:"version"`
 	Message string  `json:"message"`
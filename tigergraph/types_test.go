@@ -0,0 +1,64 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeResponseEdge(t *testing.T) {
+	type knows struct {
+		Since string `json:"since"`
+	}
+
+	type edgeSetResult struct {
+		Edges []ResponseEdge[knows] `json:"@@edges"`
+	}
+
+	body := `{
+		"version": {"edition": "enterprise", "api": "v2", "schema": 1},
+		"error": false,
+		"message": "",
+		"results": [{
+			"@@edges": [{
+				"e_type": "Knows",
+				"from_id": "1",
+				"from_type": "Person",
+				"to_id": "2",
+				"to_type": "Person",
+				"directed": true,
+				"attributes": {"since": "2020-01-01 00:00:00"}
+			}]
+		}]
+	}`
+
+	var response TigerGraphResponse[edgeSetResult]
+	assert.Nil(t, json.Unmarshal([]byte(body), &response))
+
+	assert.Len(t, response.Results, 1)
+	assert.Equal(t, []ResponseEdge[knows]{
+		{
+			EType:      "Knows",
+			FromID:     "1",
+			FromType:   "Person",
+			ToID:       "2",
+			ToType:     "Person",
+			Directed:   true,
+			Attributes: knows{Since: "2020-01-01 00:00:00"},
+		},
+	}, response.Results[0].Edges)
+}
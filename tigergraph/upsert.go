@@ -15,6 +15,7 @@ package tigergraph
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 )
 
@@ -40,23 +41,52 @@ type UpsertResponse struct {
 	Results []UpsertResponseResult `json:"results"`
 }
 
+// EstimatePayloadSize returns the size in bytes of the request body that would be sent
+// to TigerGraph for the given payload. This can be compared against the client's
+// MaxRequestSize to split large upserts into smaller batches before sending them.
+func EstimatePayloadSize(payload any) (int, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(payloadBytes), nil
+}
+
 // Upsert upserts data to the given graph.
 // https://docs.tigergraph.com/tigergraph-server/current/api/upsert-rest#_examples
-func (c *TigerGraphClient) Upsert(ctx context.Context, graphName string, data any) (*UpsertResponseResult, error) {
+//
+// Pass the Atomic option to apply a group of vertices and edges all or nothing.
+func (c *TigerGraphClient) Upsert(
+	ctx context.Context,
+	graphName string,
+	data any,
+	opts ...RequestOption,
+) (*UpsertResponseResult, error) {
 	responseResult := &UpsertResponse{}
+	queryURL := UpsertURL + "/" + graphName
+	opts = operationOptions("upsert", opts)
 
-	err := c.Post(ctx, UpsertURL+"/"+graphName, graphName, data, responseResult)
+	err := c.Post(ctx, queryURL, graphName, data, responseResult, opts...)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if responseResult.Error {
-		return nil, fmt.Errorf(
-			"TigerGraph returned an error when trying to upsert data. Message: %s",
+		return nil, newOperationError(operationName(opts, "upsert"), graphName, queryURL, fmt.Errorf(
+			"TigerGraph returned an error when trying to upsert data. Message: %s: %w",
 			responseResult.Message,
-		)
+			ErrTigerGraphError,
+		))
+	}
+
+	if len(responseResult.Results) != 1 {
+		return nil, newOperationError(operationName(opts, "upsert"), graphName, queryURL, ErrNotOneResult)
 	}
 
-	return &responseResult.Results[0], nil
+	result := &responseResult.Results[0]
+	c.recordUpsert(graphName, result)
+
+	return result, nil
 }
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+// UpsertEvent describes the outcome of a single upsert request.
+type UpsertEvent struct {
+	Graph            string
+	AcceptedVertices int
+	AcceptedEdges    int
+	SkippedVertices  int
+	SkippedEdges     int
+}
+
+// UpsertMetrics receives an event for every successful upsert, making entities skipped by
+// TigerGraph visible without every caller inspecting the returned result.
+//
+// Implementations must be safe to call from multiple goroutines.
+type UpsertMetrics interface {
+	UpsertCompleted(event UpsertEvent)
+}
+
+// NoopUpsertMetrics is an UpsertMetrics implementation that discards all events.
+// It is used when no UpsertMetrics is set on the client.
+type NoopUpsertMetrics struct{}
+
+// UpsertCompleted implements UpsertMetrics
+func (NoopUpsertMetrics) UpsertCompleted(UpsertEvent) {}
+
+func (c *TigerGraphClient) upsertMetrics() UpsertMetrics {
+	if c.UpsertMetrics == nil {
+		return NoopUpsertMetrics{}
+	}
+
+	return c.UpsertMetrics
+}
+
+func (c *TigerGraphClient) recordUpsert(graph string, result *UpsertResponseResult) {
+	c.upsertMetrics().UpsertCompleted(UpsertEvent{
+		Graph:            graph,
+		AcceptedVertices: result.AcceptedVertices,
+		AcceptedEdges:    result.AcceptedEdges,
+		SkippedVertices:  result.SkippedVertices,
+		SkippedEdges:     result.SkippedEdges,
+	})
+}
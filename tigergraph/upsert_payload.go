@@ -0,0 +1,127 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+)
+
+// UpsertPayload builds the body of an Upsert, wrapping each attribute value with WrapAttributes.
+// Payloads created with TigerGraphClient.NewUpsertPayload send times using the client's
+// DatetimeEncoding.
+//
+// Edge types with a discriminator (TigerGraph 3.9+) can have several edges between the same
+// pair of vertices. Adding more than one edge of a type between the same vertices sends them
+// as a list, with each edge distinguished by its discriminator attributes.
+type UpsertPayload struct {
+	vertices map[string]map[string]map[string]ValueWrapper
+	edges    map[edgeEndpoints][]map[string]ValueWrapper
+	order    []edgeEndpoints
+
+	datetimeEncoding DatetimeEncoding
+}
+
+type edgeEndpoints struct {
+	SourceType string
+	SourceID   string
+	EdgeType   string
+	TargetType string
+	TargetID   string
+}
+
+// NewUpsertPayload creates an empty UpsertPayload.
+func NewUpsertPayload() *UpsertPayload {
+	return &UpsertPayload{
+		vertices: make(map[string]map[string]map[string]ValueWrapper),
+		edges:    make(map[edgeEndpoints][]map[string]ValueWrapper),
+	}
+}
+
+// AddVertex adds a vertex with the given attributes. Adding the same vertex again replaces it.
+func (p *UpsertPayload) AddVertex(vertexType string, id string, attributes map[string]any) *UpsertPayload {
+	if p.vertices[vertexType] == nil {
+		p.vertices[vertexType] = make(map[string]map[string]ValueWrapper)
+	}
+	p.vertices[vertexType][id] = wrapAttributes(attributes, p.datetimeEncoding)
+
+	return p
+}
+
+// AddEdge adds an edge with the given attributes. For edge types with a discriminator the
+// attributes must include the discriminator attributes, and each call adds another edge
+// between the vertices; otherwise a later edge between the same vertices replaces an earlier one.
+func (p *UpsertPayload) AddEdge(
+	sourceType string,
+	sourceID string,
+	edgeType string,
+	targetType string,
+	targetID string,
+	attributes map[string]any,
+) *UpsertPayload {
+	endpoints := edgeEndpoints{sourceType, sourceID, edgeType, targetType, targetID}
+	if _, exists := p.edges[endpoints]; !exists {
+		p.order = append(p.order, endpoints)
+	}
+	p.edges[endpoints] = append(p.edges[endpoints], wrapAttributes(attributes, p.datetimeEncoding))
+
+	return p
+}
+
+// MarshalJSON implements json.Marshaler
+func (p *UpsertPayload) MarshalJSON() ([]byte, error) {
+	body := map[string]any{}
+
+	if len(p.vertices) > 0 {
+		body["vertices"] = p.vertices
+	}
+
+	if len(p.edges) > 0 {
+		edges := make(map[string]map[string]map[string]map[string]map[string]any)
+		for _, endpoints := range p.order {
+			bySourceID := edges[endpoints.SourceType]
+			if bySourceID == nil {
+				bySourceID = make(map[string]map[string]map[string]map[string]any)
+				edges[endpoints.SourceType] = bySourceID
+			}
+
+			byEdgeType := bySourceID[endpoints.SourceID]
+			if byEdgeType == nil {
+				byEdgeType = make(map[string]map[string]map[string]any)
+				bySourceID[endpoints.SourceID] = byEdgeType
+			}
+
+			byTargetType := byEdgeType[endpoints.EdgeType]
+			if byTargetType == nil {
+				byTargetType = make(map[string]map[string]any)
+				byEdgeType[endpoints.EdgeType] = byTargetType
+			}
+
+			byTargetID := byTargetType[endpoints.TargetType]
+			if byTargetID == nil {
+				byTargetID = make(map[string]any)
+				byTargetType[endpoints.TargetType] = byTargetID
+			}
+
+			attributes := p.edges[endpoints]
+			if len(attributes) == 1 {
+				byTargetID[endpoints.TargetID] = attributes[0]
+			} else {
+				byTargetID[endpoints.TargetID] = attributes
+			}
+		}
+		body["edges"] = edges
+	}
+
+	return json.Marshal(body)
+}
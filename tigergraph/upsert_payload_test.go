@@ -0,0 +1,59 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertPayloadMarshalJSON(t *testing.T) {
+	payload := NewUpsertPayload().
+		AddVertex("Person", "1", map[string]any{"name": "Ada"}).
+		AddEdge("Person", "1", "Knows", "Person", "2", map[string]any{"since": "2020"}).
+		AddEdge("Person", "1", "Called", "Person", "2", map[string]any{"at": "2023-06-01 09:00:00"}).
+		AddEdge("Person", "1", "Called", "Person", "2", map[string]any{"at": "2023-06-02 09:00:00"})
+
+	body, err := json.Marshal(payload)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"vertices": {"Person": {"1": {"name": {"value": "Ada"}}}},
+		"edges": {"Person": {"1": {
+			"Knows": {"Person": {"2": {"since": {"value": "2020"}}}},
+			"Called": {"Person": {"2": [
+				{"at": {"value": "2023-06-01 09:00:00"}},
+				{"at": {"value": "2023-06-02 09:00:00"}}
+			]}}
+		}}}
+	}`, string(body))
+}
+
+func TestUpsertPayloadMarshalJSONEmpty(t *testing.T) {
+	body, err := json.Marshal(NewUpsertPayload())
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{}`, string(body))
+}
+
+func TestEdgeFilter(t *testing.T) {
+	filter := edgeFilter(map[string]any{
+		"weight": 2,
+		"at":     time.Date(2023, 6, 1, 9, 0, 0, 0, time.UTC),
+		"kind":   "call",
+	}, DatetimeString)
+
+	assert.Equal(t, `at=="2023-06-01 09:00:00",kind=="call",weight==2`, filter)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+)
+
+const (
+	// ModulePath is the Go module path of this library
+	ModulePath = "github.com/adarga-ai/go-tigergraph"
+
+	// UserAgentProduct is the product name sent in the User-Agent header
+	UserAgentProduct = "go-tigergraph"
+
+	// DevelopmentVersion is reported as the library version when it cannot be found in the build info,
+	// e.g. when running this module's own tests
+	DevelopmentVersion = "devel"
+)
+
+var (
+	libraryVersionOnce sync.Once
+	libraryVersion     string
+)
+
+// LibraryVersion returns the version of this library compiled into the running binary.
+func LibraryVersion() string {
+	libraryVersionOnce.Do(func() {
+		libraryVersion = DevelopmentVersion
+
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+
+		for _, dep := range info.Deps {
+			if dep.Path == ModulePath {
+				libraryVersion = dep.Version
+				return
+			}
+		}
+	})
+
+	return libraryVersion
+}
+
+// WithAppName sets the application name sent in the User-Agent header.
+func WithAppName(appName string) Option {
+	return func(c *TigerGraphClient) {
+		c.AppName = appName
+	}
+}
+
+// WithUserAgent replaces the User-Agent sent with every request, which otherwise identifies
+// this library and its version.
+func WithUserAgent(userAgent string) Option {
+	return func(c *TigerGraphClient) {
+		c.UserAgent = userAgent
+	}
+}
+
+// userAgent returns the User-Agent sent with every request, e.g. "go-tigergraph/v1.2.0 (my-app)".
+func (c *TigerGraphClient) userAgent() string {
+	if c.UserAgent != "" {
+		return c.UserAgent
+	}
+
+	userAgent := fmt.Sprintf("%s/%s", UserAgentProduct, LibraryVersion())
+	if c.AppName != "" {
+		userAgent += fmt.Sprintf(" (%s)", c.AppName)
+	}
+
+	return userAgent
+}
+
+// setUserAgent stamps the request with the client's User-Agent, unless one has been set by the caller.
+func (c *TigerGraphClient) setUserAgent(req *http.Request) {
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgent())
+	}
+}
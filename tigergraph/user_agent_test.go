@@ -0,0 +1,38 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAgent(t *testing.T) {
+	client := NewClient("http://tg:9000")
+	assert.Equal(t, "go-tigergraph/"+DevelopmentVersion, client.userAgent())
+
+	client.AppName = "my-app"
+	assert.Equal(t, "go-tigergraph/"+DevelopmentVersion+" (my-app)", client.userAgent())
+
+	WithUserAgent("reporting-service/2.1")(client)
+	assert.Equal(t, "reporting-service/2.1", client.userAgent())
+
+	req, err := http.NewRequest(http.MethodGet, "http://tg:9000", http.NoBody)
+	assert.Nil(t, err)
+	req.Header.Set("User-Agent", "custom")
+	client.setUserAgent(req)
+	assert.Equal(t, "custom", req.Header.Get("User-Agent"))
+}
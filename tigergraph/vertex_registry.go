@@ -0,0 +1,170 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownVertexType represents a vertex whose type has no factory registered, and no
+// fallback is set
+var ErrUnknownVertexType = errors.New("no factory registered for vertex type")
+
+// VertexFactory returns a new pointer for a vertex of one type to be decoded into, e.g.
+// &ResponseVertex[Person]{}
+type VertexFactory func() any
+
+// VertexRegistry decodes vertices of mixed types, as printed by queries which return several
+// types in one set, into the Go type registered for each v_type, e.g.
+//
+//	registry := NewVertexRegistry()
+//	RegisterVertexType[Person](registry, "Person")
+//	RegisterVertexType[Company](registry, "Company")
+//
+//	vertices, err := registry.DecodeVertices(raw)
+//	for _, vertex := range vertices {
+//		switch v := vertex.(type) {
+//		case *ResponseVertex[Person]:
+//		case *ResponseVertex[Company]:
+//		}
+//	}
+//
+// Types are registered before use; a registry may then decode from multiple goroutines.
+type VertexRegistry struct {
+	factories map[string]VertexFactory
+	fallback  VertexFactory
+}
+
+// NewVertexRegistry creates an empty VertexRegistry. Types are added with Register or
+// RegisterVertexType.
+func NewVertexRegistry() *VertexRegistry {
+	return &VertexRegistry{
+		factories: make(map[string]VertexFactory),
+	}
+}
+
+// Register sets the factory for vertices of type vType.
+func (r *VertexRegistry) Register(vType string, factory VertexFactory) *VertexRegistry {
+	r.factories[vType] = factory
+	return r
+}
+
+// Fallback sets the factory for vertices whose type is not registered. Without a fallback,
+// such vertices fail to decode with ErrUnknownVertexType.
+func (r *VertexRegistry) Fallback(factory VertexFactory) *VertexRegistry {
+	r.fallback = factory
+	return r
+}
+
+// RegisterVertexType registers vertices of type vType to be decoded as *ResponseVertex[T].
+func RegisterVertexType[T any](r *VertexRegistry, vType string) *VertexRegistry {
+	return r.Register(vType, func() any {
+		return &ResponseVertex[T]{}
+	})
+}
+
+// DecodeVertex decodes a single vertex into a new value from the factory registered for its
+// v_type.
+func (r *VertexRegistry) DecodeVertex(data []byte) (any, error) {
+	var header struct {
+		VType string `json:"v_type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	factory, ok := r.factories[header.VType]
+	if !ok {
+		if r.fallback == nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownVertexType, header.VType)
+		}
+		factory = r.fallback
+	}
+
+	vertex := factory()
+	if err := json.Unmarshal(data, vertex); err != nil {
+		return nil, fmt.Errorf("failed to decode vertex of type %q into %T: %w", header.VType, vertex, err)
+	}
+
+	return vertex, nil
+}
+
+// DecodeVertices decodes a JSON array of vertices, each into a new value from the factory
+// registered for its v_type.
+func (r *VertexRegistry) DecodeVertices(data []byte) ([]any, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	vertices := make([]any, 0, len(raw))
+	for i, entry := range raw {
+		vertex, err := r.DecodeVertex(entry)
+		if err != nil {
+			return nil, fmt.Errorf("vertex %d: %w", i, err)
+		}
+		vertices = append(vertices, vertex)
+	}
+
+	return vertices, nil
+}
+
+// NewVertexList returns an empty VertexList decoded with this registry, e.g. to Register with
+// a MultiResult.
+func (r *VertexRegistry) NewVertexList() *VertexList {
+	return &VertexList{registry: r}
+}
+
+// VertexList is a JSON array of vertices of mixed types, decoded with a VertexRegistry.
+type VertexList struct {
+	Vertices []any
+
+	registry *VertexRegistry
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *VertexList) UnmarshalJSON(data []byte) error {
+	vertices, err := l.registry.DecodeVertices(data)
+	if err != nil {
+		return err
+	}
+
+	l.Vertices = vertices
+	return nil
+}
+
+// VerticesOf returns the vertices decoded as *ResponseVertex[T], e.g. registered with
+// RegisterVertexType[T], in order.
+func VerticesOf[T any](vertices []any) []ResponseVertex[T] {
+	result := make([]ResponseVertex[T], 0)
+	for _, vertex := range vertices {
+		if v, ok := AsVertex[T](vertex); ok {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// AsVertex returns the vertex if it was decoded as *ResponseVertex[T].
+func AsVertex[T any](vertex any) (ResponseVertex[T], bool) {
+	v, ok := vertex.(*ResponseVertex[T])
+	if !ok || v == nil {
+		return ResponseVertex[T]{}, false
+	}
+
+	return *v, true
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVertexRegistry(t *testing.T) { //nolint:funlen
+	type person struct {
+		Name string `json:"name"`
+	}
+	type company struct {
+		Name      string `json:"name"`
+		Employees int    `json:"employees"`
+	}
+
+	vertices := `[
+		{"v_id": "1", "v_type": "Person", "attributes": {"name": "Alice"}},
+		{"v_id": "2", "v_type": "Company", "attributes": {"name": "Adarga", "employees": 100}},
+		{"v_id": "3", "v_type": "Person", "attributes": {"name": "Bob"}}
+	]`
+
+	newRegistry := func() *VertexRegistry {
+		registry := NewVertexRegistry()
+		RegisterVertexType[person](registry, "Person")
+		RegisterVertexType[company](registry, "Company")
+		return registry
+	}
+
+	t.Run("decodes each vertex into its registered type", func(t *testing.T) {
+		decoded, err := newRegistry().DecodeVertices([]byte(vertices))
+		assert.Nil(t, err)
+		if !assert.Len(t, decoded, 3) {
+			return
+		}
+
+		assert.IsType(t, &ResponseVertex[person]{}, decoded[0])
+		assert.IsType(t, &ResponseVertex[company]{}, decoded[1])
+
+		assert.Equal(t, []ResponseVertex[person]{
+			{VID: "1", VType: "Person", Attributes: person{Name: "Alice"}},
+			{VID: "3", VType: "Person", Attributes: person{Name: "Bob"}},
+		}, VerticesOf[person](decoded))
+
+		adarga, ok := AsVertex[company](decoded[1])
+		assert.True(t, ok)
+		assert.Equal(t, 100, adarga.Attributes.Employees)
+
+		_, ok = AsVertex[company](decoded[0])
+		assert.False(t, ok)
+	})
+
+	t.Run("unknown types fail without a fallback", func(t *testing.T) {
+		registry := NewVertexRegistry()
+		RegisterVertexType[person](registry, "Person")
+
+		_, err := registry.DecodeVertices([]byte(vertices))
+		assert.ErrorIs(t, err, ErrUnknownVertexType)
+		assert.ErrorContains(t, err, `vertex 1`)
+		assert.ErrorContains(t, err, `"Company"`)
+	})
+
+	t.Run("unknown types use the fallback", func(t *testing.T) {
+		registry := NewVertexRegistry().Fallback(func() any {
+			return &ResponseVertex[map[string]any]{}
+		})
+		RegisterVertexType[person](registry, "Person")
+
+		decoded, err := registry.DecodeVertices([]byte(vertices))
+		assert.Nil(t, err)
+
+		companies := VerticesOf[map[string]any](decoded)
+		if assert.Len(t, companies, 1) {
+			assert.Equal(t, "Adarga", companies[0].Attributes["name"])
+		}
+	})
+
+	t.Run("mismatched attributes name the type", func(t *testing.T) {
+		registry := NewVertexRegistry()
+		RegisterVertexType[company](registry, "Person")
+		RegisterVertexType[company](registry, "Company")
+
+		_, err := registry.DecodeVertices([]byte(`[{"v_id": "1", "v_type": "Company", "attributes": {"employees": "many"}}]`))
+		assert.ErrorContains(t, err, `type "Company"`)
+	})
+
+	t.Run("vertex lists can be decoded within a multi result", func(t *testing.T) {
+		list := newRegistry().NewVertexList()
+		result := NewMultiResult().Register("entities", list)
+
+		body := `{"error": false, "results": [{"entities": ` + vertices + `}]}`
+		assert.Nil(t, json.Unmarshal([]byte(body), result))
+		assert.Len(t, list.Vertices, 3)
+		assert.Len(t, VerticesOf[company](list.Vertices), 1)
+	})
+}
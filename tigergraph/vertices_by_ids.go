@@ -0,0 +1,138 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+const (
+	// VerticesByIDsQueryName is the name of the installed query used by GetVerticesByIDs
+	VerticesByIDsQueryName = "get_vertices_by_ids"
+
+	graphNamePlaceholder = "{{GRAPH_NAME}}"
+)
+
+// VerticesByIDsQueryTemplate is the GSQL which installs the query used by GetVerticesByIDs,
+// with placeholders for the graph and query names
+//
+//go:embed gsql/vertices_by_ids_query.gsql
+var VerticesByIDsQueryTemplate string
+
+// VerticesByIDsPostBody is the request sent to the query used by GetVerticesByIDs
+type VerticesByIDsPostBody struct {
+	IDs        []string `json:"ids"`
+	VertexType string   `json:"vertex_type"`
+}
+
+// VerticesByIDsResponseResult is the result returned by the query used by GetVerticesByIDs
+type VerticesByIDsResponseResult[T any] struct {
+	Vertices []ResponseVertex[T] `json:"vertices"`
+}
+
+// VerticesByIDs is the result of GetVerticesByIDs
+type VerticesByIDs[T any] struct {
+	// Vertices maps the IDs which were found to their vertices
+	Vertices map[string]ResponseVertex[T]
+
+	// Missing are the requested IDs which were not found, in the order they were requested
+	Missing []string
+}
+
+// InstallVerticesByIDsQuery installs the query used by GetVerticesByIDs on the graph, if it is
+// not already installed. Installing a query can take several minutes, so this is best done
+// alongside migrations rather than before every read.
+func (c *TigerGraphClient) InstallVerticesByIDsQuery(ctx context.Context, graphName string) error {
+	installed, err := c.IsQueryInstalled(ctx, graphName, VerticesByIDsQueryName)
+	if err != nil {
+		return fmt.Errorf("failed to check whether query %s is installed: %w", VerticesByIDsQueryName, err)
+	}
+
+	if installed {
+		return nil
+	}
+
+	gsql := strings.NewReplacer(
+		graphNamePlaceholder, graphName,
+		latestMigrationQueryNamePlaceholder, VerticesByIDsQueryName,
+	).Replace(VerticesByIDsQueryTemplate)
+
+	if err = c.RunGSQL(ctx, gsql); err != nil {
+		return fmt.Errorf("failed to install query %s: %w", VerticesByIDsQueryName, err)
+	}
+
+	return nil
+}
+
+// GetVerticesByIDs fetches the vertices of the given type with the given IDs in a single
+// request, using the query installed by InstallVerticesByIDsQuery. IDs which do not exist are
+// returned in Missing rather than failing the request.
+func GetVerticesByIDs[T any](
+	ctx context.Context,
+	c *TigerGraphClient,
+	graphName string,
+	vertexType string,
+	ids []string,
+	opts ...RequestOption,
+) (*VerticesByIDs[T], error) {
+	result := &VerticesByIDs[T]{
+		Vertices: make(map[string]ResponseVertex[T], len(ids)),
+		Missing:  make([]string, 0),
+	}
+
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var response TigerGraphResponse[VerticesByIDsResponseResult[T]]
+	queryURL := fmt.Sprintf(QueryURLTemplate, graphName, VerticesByIDsQueryName)
+	err := c.Post(
+		ctx,
+		queryURL,
+		graphName,
+		VerticesByIDsPostBody{IDs: ids, VertexType: vertexType},
+		&response,
+		operationOptions("get vertices by ids", opts)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error {
+		return nil, newOperationError("get vertices by ids", graphName, queryURL, fmt.Errorf(
+			"failed to get vertices by ID. message: %s: %w", response.Message, ErrTigerGraphError,
+		))
+	}
+
+	if len(response.Results) != 1 {
+		return nil, newOperationError("get vertices by ids", graphName, queryURL, ErrNotOneResult)
+	}
+
+	for _, vertex := range response.Results[0].Vertices {
+		result.Vertices[vertex.VID] = vertex
+	}
+
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if _, found := result.Vertices[id]; !found && !seen[id] {
+			result.Missing = append(result.Missing, id)
+		}
+		seen[id] = true
+	}
+
+	return result, nil
+}
@@ -0,0 +1,474 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	walFileName        = "wal.log"
+	appliedFileName    = "applied.log"
+	deadLetterFileName = "dead_letter.log"
+
+	walFilePermissions = 0o600
+	walDirPermissions  = 0o700
+
+	// maxWALLineSize bounds the size of a single buffered write
+	maxWALLineSize = 64 * 1024 * 1024
+
+	// WALEntryUpsert is the kind of WAL entry holding an upsert payload
+	WALEntryUpsert = "upsert"
+
+	// WALEntryLoadingJob is the kind of WAL entry holding JSONL loading job lines
+	WALEntryLoadingJob = "loading_job"
+)
+
+var (
+	// ErrWALNotConfigured represents a buffered write being made on a client without a WAL
+	ErrWALNotConfigured = errors.New("no write-ahead log is configured on the client")
+
+	// ErrUnknownWALEntry represents an entry in the WAL with an unrecognised kind
+	ErrUnknownWALEntry = errors.New("unknown write-ahead log entry kind")
+
+	// ErrWALEntryNotFound represents a key with no entry waiting in the WAL
+	ErrWALEntryNotFound = errors.New("no write-ahead log entry is waiting with the key")
+)
+
+// WALEntry is a single buffered write in the write-ahead log.
+type WALEntry struct {
+	// Key identifies the write. Writes with the key of one which has already been sent, directly
+	// or by a replay, are skipped.
+	Key        string            `json:"key"`
+	Kind       string            `json:"kind"`
+	Graph      string            `json:"graph"`
+	LoadingJob string            `json:"loading_job,omitempty"`
+	Payload    json.RawMessage   `json:"payload,omitempty"`
+	Lines      []json.RawMessage `json:"lines,omitempty"`
+}
+
+// WALReplayError is returned by ReplayWAL when an entry could not be replayed.
+type WALReplayError struct {
+	Entry WALEntry
+	Err   error
+}
+
+// Error implements error
+func (e *WALReplayError) Error() string {
+	return fmt.Sprintf("failed to replay write-ahead log entry %s: %s", e.Entry.Key, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *WALReplayError) Unwrap() error {
+	return e.Err
+}
+
+// Rejected reports whether TigerGraph rejected the entry, rather than not being reachable. A
+// rejected entry fails every replay until it is removed with WAL.DeadLetter.
+func (e *WALReplayError) Rejected() bool {
+	return !isUnreachable(e.Err)
+}
+
+// WAL is an append-only, file backed log of writes which could not be sent to TigerGraph.
+// Buffered writes are replayed in the order they were made with ReplayWAL.
+type WAL struct {
+	dir string
+	mu  sync.Mutex
+
+	// applied holds the keys of writes which have been sent, and pending the keys of entries
+	// waiting to be replayed. Both are loaded by OpenWAL and kept in step with the files.
+	applied map[string]bool
+	pending map[string]bool
+
+	// writeMu orders buffered writes and replays, so that nothing is sent directly while
+	// older writes are waiting in the log
+	writeMu sync.Mutex
+}
+
+// OpenWAL opens the write-ahead log stored in dir, creating the directory if needed.
+func OpenWAL(dir string) (*WAL, error) {
+	if err := os.MkdirAll(dir, walDirPermissions); err != nil {
+		return nil, err
+	}
+
+	w := &WAL{dir: dir, applied: make(map[string]bool), pending: make(map[string]bool)}
+
+	appliedKeys, err := w.readLines(appliedFileName)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range appliedKeys {
+		w.applied[key] = true
+	}
+
+	entries, err := w.pendingEntries()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		w.pending[entry.Key] = true
+	}
+
+	return w, nil
+}
+
+// Append durably adds an entry to the end of the log. Entries with the key of a write which
+// has already been sent, or is already waiting in the log, are skipped.
+func (w *WAL) Append(entry WALEntry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.applied[entry.Key] || w.pending[entry.Key] {
+		return nil
+	}
+
+	if err := w.appendLine(walFileName, entry); err != nil {
+		return err
+	}
+	w.pending[entry.Key] = true
+
+	return nil
+}
+
+// Entries returns all entries in the log which have not yet been replayed.
+func (w *WAL) Entries() ([]WALEntry, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.pendingEntries()
+}
+
+// Len returns the number of entries which have not yet been replayed.
+func (w *WAL) Len() (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.pending), nil
+}
+
+// DeadLetter removes the entry with the given key from the log without sending it, e.g. an
+// entry TigerGraph rejects which would otherwise stop every replay. The entry is kept in the
+// WAL's dead letter file, returned by DeadLetters.
+func (w *WAL) DeadLetter(key string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entries, err := w.pendingEntries()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Key != key {
+			continue
+		}
+
+		if err = w.appendLine(deadLetterFileName, entry); err != nil {
+			return err
+		}
+
+		return w.markAppliedLocked(key)
+	}
+
+	return fmt.Errorf("key %s: %w", key, ErrWALEntryNotFound)
+}
+
+// DeadLetters returns the entries removed from the log with DeadLetter.
+func (w *WAL) DeadLetters() ([]WALEntry, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.readEntries(deadLetterFileName)
+}
+
+func (w *WAL) pendingEntries() ([]WALEntry, error) {
+	logged, err := w.readEntries(walFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(logged))
+	entries := make([]WALEntry, 0, len(logged))
+	for _, entry := range logged {
+		// Later duplicates of a key still waiting to be replayed are dropped too
+		if w.applied[entry.Key] || seen[entry.Key] {
+			continue
+		}
+
+		seen[entry.Key] = true
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (w *WAL) readEntries(name string) ([]WALEntry, error) {
+	lines, err := w.readLines(name)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]WALEntry, 0, len(lines))
+	for i, line := range lines {
+		var entry WALEntry
+		if err = json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("failed to read write-ahead log line %d: %w", i+1, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// isApplied reports whether a write with the key has already been sent
+func (w *WAL) isApplied(key string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.applied[key]
+}
+
+// markApplied records that the write with the key has been sent, so that later writes with
+// the same key are skipped
+func (w *WAL) markApplied(key string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.markAppliedLocked(key)
+}
+
+func (w *WAL) markAppliedLocked(key string) error {
+	if w.applied[key] {
+		return nil
+	}
+
+	if err := w.appendLine(appliedFileName, key); err != nil {
+		return err
+	}
+
+	w.applied[key] = true
+	delete(w.pending, key)
+
+	return nil
+}
+
+// truncate removes the log once every entry has been replayed, and compacts the keys of the
+// writes which have been sent. The keys are kept, so that writes buffered again with the same
+// key are still skipped.
+func (w *WAL) truncate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) > 0 {
+		return nil
+	}
+
+	if err := os.Remove(filepath.Join(w.dir, walFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	keys := make([]string, 0, len(w.applied))
+	for key := range w.applied {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	compacted := filepath.Join(w.dir, appliedFileName+".tmp")
+	if err := os.WriteFile(compacted, []byte(strings.Join(keys, "\n")+"\n"), walFilePermissions); err != nil {
+		return err
+	}
+
+	return os.Rename(compacted, filepath.Join(w.dir, appliedFileName))
+}
+
+func (w *WAL) appendLine(name string, value any) error {
+	var line []byte
+	if key, isString := value.(string); isString {
+		line = []byte(key)
+	} else {
+		var err error
+		if line, err = json.Marshal(value); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(filepath.Join(w.dir, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, walFilePermissions)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+func (w *WAL) readLines(name string) ([]string, error) {
+	file, err := os.Open(filepath.Join(w.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxWALLineSize)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, scanner.Err()
+}
+
+// isUnreachable reports whether an error means TigerGraph could not be reached, as opposed
+// to TigerGraph rejecting the request.
+func isUnreachable(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) || errors.Is(err, ErrServerBusy) || errors.Is(err, ErrCircuitOpen)
+}
+
+// BufferedUpsert upserts data to the given graph. If TigerGraph cannot be reached, or earlier
+// writes are still waiting in the client's WAL, the payload is appended to the WAL instead and
+// buffered is true. Writes with the key of one which has already been sent are skipped.
+func (c *TigerGraphClient) BufferedUpsert(ctx context.Context, graphName string, key string, data any) (bool, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return false, err
+	}
+
+	entry := WALEntry{Key: key, Kind: WALEntryUpsert, Graph: graphName, Payload: payload}
+	return c.bufferedWrite(ctx, entry)
+}
+
+// BufferedRunLoadingJobJSONL runs a loading job with the given lines, appending them to the
+// client's WAL if TigerGraph cannot be reached. See BufferedUpsert.
+func (c *TigerGraphClient) BufferedRunLoadingJobJSONL(
+	ctx context.Context,
+	graphName string,
+	loadingJobName string,
+	key string,
+	lines []any,
+) (bool, error) {
+	rawLines := make([]json.RawMessage, 0, len(lines))
+	for _, line := range lines {
+		lineBytes, err := json.Marshal(line)
+		if err != nil {
+			return false, ErrMarshallingJSONL
+		}
+		rawLines = append(rawLines, lineBytes)
+	}
+
+	entry := WALEntry{Key: key, Kind: WALEntryLoadingJob, Graph: graphName, LoadingJob: loadingJobName, Lines: rawLines}
+	return c.bufferedWrite(ctx, entry)
+}
+
+func (c *TigerGraphClient) bufferedWrite(ctx context.Context, entry WALEntry) (bool, error) {
+	if c.WAL == nil {
+		return false, ErrWALNotConfigured
+	}
+
+	// Writes must reach TigerGraph in order, so nothing is sent while older writes are buffered
+	c.WAL.writeMu.Lock()
+	defer c.WAL.writeMu.Unlock()
+
+	if c.WAL.isApplied(entry.Key) {
+		return false, nil
+	}
+
+	pending, err := c.WAL.Len()
+	if err != nil {
+		return false, err
+	}
+
+	if pending == 0 {
+		err = c.sendWALEntry(ctx, entry)
+		if err == nil {
+			return false, c.WAL.markApplied(entry.Key)
+		}
+		if !isUnreachable(err) {
+			return false, err
+		}
+	}
+
+	if err = c.WAL.Append(entry); err != nil {
+		return false, fmt.Errorf("failed to append to write-ahead log: %w", err)
+	}
+
+	return true, nil
+}
+
+// ReplayWAL sends the writes buffered in the client's WAL to TigerGraph, in the order they
+// were made. Replay stops at the first failure, leaving that entry and all later entries in
+// the WAL, and returns a *WALReplayError. An entry TigerGraph rejects can be removed with
+// WAL.DeadLetter, so that the entries after it can be replayed. The number of entries
+// replayed is returned.
+func (c *TigerGraphClient) ReplayWAL(ctx context.Context) (int, error) {
+	if c.WAL == nil {
+		return 0, ErrWALNotConfigured
+	}
+
+	c.WAL.writeMu.Lock()
+	defer c.WAL.writeMu.Unlock()
+
+	entries, err := c.WAL.Entries()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, entry := range entries {
+		if err = c.sendWALEntry(ctx, entry); err != nil {
+			return i, &WALReplayError{Entry: entry, Err: err}
+		}
+
+		if err = c.WAL.markApplied(entry.Key); err != nil {
+			return i, err
+		}
+	}
+
+	return len(entries), c.WAL.truncate()
+}
+
+func (c *TigerGraphClient) sendWALEntry(ctx context.Context, entry WALEntry) error {
+	switch entry.Kind {
+	case WALEntryUpsert:
+		_, err := c.Upsert(ctx, entry.Graph, entry.Payload)
+		return err
+	case WALEntryLoadingJob:
+		lines := make([]any, 0, len(entry.Lines))
+		for _, line := range entry.Lines {
+			lines = append(lines, line)
+		}
+		return c.RunLoadingJobJSONL(ctx, entry.Graph, entry.LoadingJob, lines)
+	default:
+		return fmt.Errorf("kind %s: %w", entry.Kind, ErrUnknownWALEntry)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Warmup prepares the client for use so that the first real request does not pay
+// the full cold start cost. It opens a pooled connection to both the RESTPP and the
+// GSQL server, pings the server and fetches tokens for each of the given graphs.
+func (c *TigerGraphClient) Warmup(ctx context.Context, graphs ...string) error {
+	if c.optionErr != nil {
+		return c.optionErr
+	}
+
+	baseURLs := []string{c.BaseURL}
+	if c.BaseFileURL != c.BaseURL {
+		baseURLs = append(baseURLs, c.BaseFileURL)
+	}
+
+	for _, baseURL := range baseURLs {
+		if err := c.openConnection(ctx, baseURL); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.Ping(ctx); err != nil {
+		return err
+	}
+
+	for _, graph := range graphs {
+		if err := c.Auth(ctx, graph); err != nil {
+			return fmt.Errorf("failed to fetch token for graph %s: %w", graph, err)
+		}
+	}
+
+	return nil
+}
+
+// openConnection sends a request to baseURL with the client's HTTP client, so that its
+// connection is left in the pool. Any response will do, as only the connection is wanted.
+func (c *TigerGraphClient) openConnection(ctx context.Context, baseURL string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient().Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w: %w", baseURL, ErrRequestFailed, err)
+	}
+
+	// The connection is only returned to the pool once the body has been read
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxPingResponseSize))
+	resp.Body.Close()
+
+	return nil
+}
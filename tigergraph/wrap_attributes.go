@@ -0,0 +1,87 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"time"
+)
+
+// ValueWrapper is an attribute value in an upsert payload, which RESTPP expects to be wrapped
+// in an object with a "value" key.
+type ValueWrapper struct {
+	Value any `json:"value"`
+}
+
+// WrapAttributes wraps dynamic attribute values, e.g. decoded from arbitrary JSON, for an upsert
+// payload. Values are converted to the form RESTPP accepts:
+//   - times are formatted as DATETIME strings in UTC; see TigerGraphClient.WrapAttributes for
+//     servers which expect epoch seconds
+//   - json.Number values are sent unchanged, so INT64 and UINT values decoded with UseNumber stay exact
+//   - slices are sent as LIST or SET values, and maps as UDT values, with their elements converted
+//   - nil values are left out, as RESTPP cannot upsert them
+//
+// Values which are already a ValueWrapper are kept as they are.
+func WrapAttributes(attributes map[string]any) map[string]ValueWrapper {
+	return wrapAttributes(attributes, DatetimeString)
+}
+
+func wrapAttributes(attributes map[string]any, encoding DatetimeEncoding) map[string]ValueWrapper {
+	wrapped := make(map[string]ValueWrapper, len(attributes))
+	for name, value := range attributes {
+		if value == nil {
+			continue
+		}
+
+		if wrapper, ok := value.(ValueWrapper); ok {
+			wrapped[name] = wrapper
+			continue
+		}
+
+		wrapped[name] = ValueWrapper{normaliseAttributeValue(value, encoding)}
+	}
+
+	return wrapped
+}
+
+func normaliseAttributeValue(value any, encoding DatetimeEncoding) any {
+	switch v := value.(type) {
+	case time.Time:
+		return encoding.Encode(v)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return encoding.Encode(*v)
+	case []any:
+		normalised := make([]any, len(v))
+		for i, element := range v {
+			normalised[i] = normaliseAttributeValue(element, encoding)
+		}
+		return normalised
+	case []string:
+		normalised := make([]any, len(v))
+		for i, element := range v {
+			normalised[i] = element
+		}
+		return normalised
+	case map[string]any:
+		normalised := make(map[string]any, len(v))
+		for key, element := range v {
+			normalised[key] = normaliseAttributeValue(element, encoding)
+		}
+		return normalised
+	default:
+		return v
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraph
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapAttributes(t *testing.T) {
+	decoder := json.NewDecoder(strings.NewReader(`{"exact": 12345678901234567890}`))
+	decoder.UseNumber()
+	var numbers map[string]any
+	assert.Nil(t, decoder.Decode(&numbers))
+
+	var dynamic map[string]any
+	assert.Nil(t, json.Unmarshal([]byte(`{
+		"name": "Ada",
+		"age": 36,
+		"score": 0.5,
+		"active": true,
+		"tags": ["a", "b"],
+		"address": {"street": "1 High St", "floor": 2},
+		"missing": null
+	}`), &dynamic))
+
+	dynamic["exact"] = numbers["exact"]
+	dynamic["born"] = time.Date(1815, 12, 10, 0, 0, 0, 0, time.UTC)
+	dynamic["prewrapped"] = ValueWrapper{"as is"}
+
+	body, err := json.Marshal(WrapAttributes(dynamic))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"name": {"value": "Ada"},
+		"age": {"value": 36},
+		"score": {"value": 0.5},
+		"active": {"value": true},
+		"tags": {"value": ["a", "b"]},
+		"address": {"value": {"street": "1 High St", "floor": 2}},
+		"exact": {"value": 12345678901234567890},
+		"born": {"value": "1815-12-10 00:00:00"},
+		"prewrapped": {"value": "as is"}
+	}`, string(body))
+	assert.Contains(t, string(body), `"exact":{"value":12345678901234567890}`)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraphtest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+)
+
+var (
+	// ErrNoLines represents a load benchmark given no lines to load
+	ErrNoLines = errors.New("at least one line is required to benchmark loading")
+
+	// ErrNoDuration represents a load benchmark given no duration to run for
+	ErrNoDuration = errors.New("a positive duration is required to benchmark loading")
+)
+
+// LoadBenchmark configures BenchmarkLoad.
+type LoadBenchmark struct {
+	Graph      string
+	LoadingJob string
+
+	// Lines are the JSONL lines loaded, cycled through for as long as the benchmark runs
+	Lines []any
+
+	// Concurrency is the number of batches loaded at a time, each by its own BatchLoader.
+	// Defaults to 1.
+	Concurrency int
+
+	// BatchSize is the number of lines loaded per request, or the starting size when tuning.
+	// Defaults to tigergraph.DefaultBatchSize.
+	BatchSize int
+
+	// Tuning, if set, auto-tunes the batch size of each loader as in production.
+	Tuning *tigergraph.BatchTuning
+
+	// Duration is how long batches are started for.
+	Duration time.Duration
+}
+
+// LoadBenchmarkResult is the throughput and latency measured by BenchmarkLoad.
+type LoadBenchmarkResult struct {
+	// Lines and Batches count the lines and batches loaded successfully
+	Lines   int
+	Batches int
+
+	// Failures counts the batches which failed, and FirstError is the first of their errors
+	Failures   int
+	FirstError error
+
+	Elapsed        time.Duration
+	LinesPerSecond float64
+
+	// Latency percentiles of the batches loaded successfully
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// loadRecorder collects the outcome of each batch from the benchmark's workers
+type loadRecorder struct {
+	mu        sync.Mutex
+	result    LoadBenchmarkResult
+	latencies []time.Duration
+}
+
+// BenchmarkLoad loads lines through the loading job, with the same BatchLoader used in
+// production, for the benchmark's duration, and reports the throughput and the latency of
+// each batch. Failed batches are counted rather than stopping the benchmark, so that error
+// rates under load can be measured. Batches cut short by the end of the benchmark or of ctx
+// are not counted.
+func BenchmarkLoad(ctx context.Context, client *tigergraph.TigerGraphClient, benchmark LoadBenchmark) (*LoadBenchmarkResult, error) {
+	if len(benchmark.Lines) == 0 {
+		return nil, ErrNoLines
+	}
+	if benchmark.Duration <= 0 {
+		return nil, ErrNoDuration
+	}
+
+	concurrency := benchmark.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	opts := []tigergraph.BatchOption{tigergraph.WithBatchSize(benchmark.BatchSize)}
+	if benchmark.Tuning != nil {
+		opts = append(opts, tigergraph.WithBatchTuning(*benchmark.Tuning))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, benchmark.Duration)
+	defer cancel()
+
+	recorder := &loadRecorder{}
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			loader := client.NewBatchLoader(benchmark.Graph, benchmark.LoadingJob, opts...)
+			// Workers start at different lines, so that they do not all load the same batch
+			next := worker * len(benchmark.Lines) / concurrency
+
+			for ctx.Err() == nil {
+				batch := cycle(benchmark.Lines, next, loader.BatchSize())
+				next = (next + len(batch)) % len(benchmark.Lines)
+
+				batchStart := time.Now()
+				err := loader.Load(ctx, batch)
+				if ctx.Err() != nil {
+					return
+				}
+
+				recorder.record(len(batch), time.Since(batchStart), err)
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	return recorder.summarise(time.Since(start)), nil
+}
+
+// cycle returns size lines starting from the line at start, wrapping around to the first line
+func cycle(lines []any, start int, size int) []any {
+	batch := make([]any, 0, size)
+	for i := 0; i < size; i++ {
+		batch = append(batch, lines[(start+i)%len(lines)])
+	}
+
+	return batch
+}
+
+func (r *loadRecorder) record(lines int, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil {
+		r.result.Failures++
+		if r.result.FirstError == nil {
+			r.result.FirstError = err
+		}
+		return
+	}
+
+	r.result.Lines += lines
+	r.result.Batches++
+	r.latencies = append(r.latencies, latency)
+}
+
+func (r *loadRecorder) summarise(elapsed time.Duration) *LoadBenchmarkResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := r.result
+	result.Elapsed = elapsed
+	if elapsed > 0 {
+		result.LinesPerSecond = float64(result.Lines) / elapsed.Seconds()
+	}
+
+	sort.Slice(r.latencies, func(i, j int) bool { return r.latencies[i] < r.latencies[j] })
+	result.P50 = percentile(r.latencies, 50)  //nolint:gomnd
+	result.P90 = percentile(r.latencies, 90)  //nolint:gomnd
+	result.P99 = percentile(r.latencies, 99)  //nolint:gomnd
+	result.Max = percentile(r.latencies, 100) //nolint:gomnd
+
+	return &result
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies, or zero if there
+// are none
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100 //nolint:gomnd
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraphtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 0, 10)
+	for i := 1; i <= 10; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	tests := []struct {
+		name      string
+		latencies []time.Duration
+		p         int
+		expected  time.Duration
+	}{
+		{name: "median", latencies: latencies, p: 50, expected: 5 * time.Millisecond},
+		{name: "rounds up to the nearest rank", latencies: latencies, p: 91, expected: 10 * time.Millisecond},
+		{name: "lowest", latencies: latencies, p: 0, expected: time.Millisecond},
+		{name: "max", latencies: latencies, p: 100, expected: 10 * time.Millisecond},
+		{name: "no latencies", p: 99, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, percentile(test.latencies, test.p))
+		})
+	}
+}
+
+func TestCycle(t *testing.T) {
+	lines := []any{0, 1, 2}
+
+	assert.Equal(t, []any{1, 2}, cycle(lines, 1, 2))
+	assert.Equal(t, []any{2, 0, 1, 2, 0}, cycle(lines, 2, 5))
+}
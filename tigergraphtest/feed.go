@@ -0,0 +1,130 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraphtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+)
+
+// AddTo adds the dataset's vertices and edges to the payload, e.g. one created with
+// TigerGraphClient.NewUpsertPayload, and returns it.
+func (d *Dataset) AddTo(payload *tigergraph.UpsertPayload) *tigergraph.UpsertPayload {
+	for _, vertex := range d.Vertices {
+		payload.AddVertex(vertex.Type, vertex.ID, vertex.Attributes)
+	}
+
+	for _, edge := range d.Edges {
+		payload.AddEdge(edge.FromType, edge.FromID, edge.Type, edge.ToType, edge.ToID, edge.Attributes)
+	}
+
+	return payload
+}
+
+// Upsert upserts the dataset to the graph in batches of at most batchSize vertices or edges.
+// Every vertex is upserted before any edge, so that edges never create their vertices with
+// default attributes. A batchSize of zero upserts the whole dataset at once.
+func Upsert(
+	ctx context.Context,
+	client *tigergraph.TigerGraphClient,
+	graph string,
+	dataset *Dataset,
+	batchSize int,
+	opts ...tigergraph.RequestOption,
+) error {
+	batches := make([]*Dataset, 0)
+	if batchSize <= 0 {
+		batches = append(batches, dataset)
+	} else {
+		for start := 0; start < len(dataset.Vertices); start += batchSize {
+			end := min(start+batchSize, len(dataset.Vertices))
+			batches = append(batches, &Dataset{Vertices: dataset.Vertices[start:end]})
+		}
+		for start := 0; start < len(dataset.Edges); start += batchSize {
+			end := min(start+batchSize, len(dataset.Edges))
+			batches = append(batches, &Dataset{Edges: dataset.Edges[start:end]})
+		}
+	}
+
+	for i, batch := range batches {
+		if _, err := client.Upsert(ctx, graph, batch.AddTo(client.NewUpsertPayload()), opts...); err != nil {
+			return fmt.Errorf("batch %d of %d: %w", i+1, len(batches), err)
+		}
+	}
+
+	return nil
+}
+
+// VertexLines returns the vertices of the type as JSON lines for a loading job, e.g. with
+// TigerGraphClient.RunLoadingJobJSONL. Each line holds the vertex's primary ID under "id"
+// and its attributes under their names, with times as DATETIME strings.
+func (d *Dataset) VertexLines(vertexType string) []any {
+	lines := make([]any, 0)
+	for _, vertex := range d.Vertices {
+		if vertex.Type != vertexType {
+			continue
+		}
+
+		line := jsonAttributes(vertex.Attributes)
+		line["id"] = vertex.ID
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// EdgeLines returns the edges of the type as JSON lines for a loading job. Each line holds
+// the IDs of the edge's vertices under "from" and "to", their types under "from_type" and
+// "to_type", and the edge's attributes under their names, with times as DATETIME strings.
+func (d *Dataset) EdgeLines(edgeType string) []any {
+	lines := make([]any, 0)
+	for _, edge := range d.Edges {
+		if edge.Type != edgeType {
+			continue
+		}
+
+		line := jsonAttributes(edge.Attributes)
+		line["from"] = edge.FromID
+		line["from_type"] = edge.FromType
+		line["to"] = edge.ToID
+		line["to_type"] = edge.ToType
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// jsonAttributes copies the attributes, formatting times as DATETIME strings
+func jsonAttributes(attributes map[string]any) map[string]any {
+	line := make(map[string]any, len(attributes))
+	for name, value := range attributes {
+		if t, ok := value.(time.Time); ok {
+			value = tigergraph.DatetimeString.Format(t)
+		}
+		line[name] = value
+	}
+
+	return line
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+// Package tigergraphtest provides utilities for testing against TigerGraph, such as
+// fabricating data which matches a graph's schema for load tests.
+package tigergraphtest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+)
+
+// AnyVertexType is the vertex type TigerGraph gives edge types which connect vertices of any type
+const AnyVertexType = "*"
+
+var (
+	// ErrUnknownType represents a count given for a type which is not in the schema
+	ErrUnknownType = errors.New("type is not in the schema")
+
+	// ErrNoEndpoints represents edges requested of a type whose vertex types have no vertices
+	// generated to connect
+	ErrNoEndpoints = errors.New("no vertices were generated for the edge type to connect")
+
+	// ErrTooManyEdges represents more edges requested of a type than there are distinct pairs
+	// of generated vertices to connect
+	ErrTooManyEdges = errors.New("more edges were requested than there are vertex pairs to connect")
+)
+
+// firstDatetime and lastDatetime bound the DATETIME values generated
+var (
+	firstDatetime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastDatetime  = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Vertex is a generated vertex
+type Vertex struct {
+	Type       string
+	ID         string
+	Attributes map[string]any
+}
+
+// Edge is a generated edge between two generated vertices
+type Edge struct {
+	Type       string
+	FromType   string
+	FromID     string
+	ToType     string
+	ToID       string
+	Attributes map[string]any
+}
+
+// Dataset is data generated to match a graph's schema, with vertices in the order of the
+// schema's vertex types and edges in the order of its edge types.
+type Dataset struct {
+	Vertices []Vertex
+	Edges    []Edge
+}
+
+// GenerateOption configures Generate.
+type GenerateOption func(*generator)
+
+// WithSeed seeds the random values generated, so that the same schema and counts generate the
+// same data. By default the seed is taken from the current time.
+func WithSeed(seed int64) GenerateOption {
+	return func(g *generator) {
+		g.rand = rand.New(rand.NewSource(seed)) //nolint:gosec
+	}
+}
+
+type generator struct {
+	rand *rand.Rand
+
+	// ids holds the IDs of the vertices generated of each type
+	ids map[string][]string
+}
+
+// Generate fabricates vertices and edges which are valid for the graph's schema: counts gives
+// the number of vertices or edges to generate of each vertex or edge type. Vertices have
+// unique primary IDs of their primary ID's type, and attributes of the types in the schema;
+// collection and user defined type attributes are left unset, to their defaults. Edges only
+// connect generated vertices of the types their edge type allows, and no two edges of a type
+// connect the same vertices.
+func Generate(
+	meta *tigergraph.GraphMetadataResponseResult,
+	counts map[string]int,
+	opts ...GenerateOption,
+) (*Dataset, error) {
+	g := &generator{ids: make(map[string][]string)}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.rand == nil {
+		g.rand = rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec
+	}
+
+	known := make(map[string]bool, len(meta.VertexTypes)+len(meta.EdgeTypes))
+	for _, vertexType := range meta.VertexTypes {
+		known[vertexType.Name] = true
+	}
+	for _, edgeType := range meta.EdgeTypes {
+		known[edgeType.Name] = true
+	}
+	for typeName := range counts {
+		if !known[typeName] {
+			return nil, fmt.Errorf("%q: %w", typeName, ErrUnknownType)
+		}
+	}
+
+	dataset := &Dataset{Vertices: make([]Vertex, 0), Edges: make([]Edge, 0)}
+
+	for _, vertexType := range meta.VertexTypes {
+		for i := 0; i < counts[vertexType.Name]; i++ {
+			dataset.Vertices = append(dataset.Vertices, g.vertex(vertexType, i))
+		}
+	}
+
+	for _, edgeType := range meta.EdgeTypes {
+		edges, err := g.edges(edgeType, counts[edgeType.Name])
+		if err != nil {
+			return nil, fmt.Errorf("edge type %q: %w", edgeType.Name, err)
+		}
+		dataset.Edges = append(dataset.Edges, edges...)
+	}
+
+	return dataset, nil
+}
+
+// vertex generates the i-th vertex of the type
+func (g *generator) vertex(vertexType tigergraph.GraphMetadataVertexType, i int) Vertex {
+	primaryID := vertexType.PrimaryID
+
+	id := fmt.Sprintf("%s_%d", vertexType.Name, i+1)
+	if isInteger(primaryID.AttributeType.Name) {
+		id = strconv.Itoa(i + 1)
+	}
+	g.ids[vertexType.Name] = append(g.ids[vertexType.Name], id)
+
+	attributes := g.attributes(vertexType.Attributes)
+	if primaryID.PrimaryIDAsAttribute {
+		attributes[primaryID.AttributeName] = id
+		if isInteger(primaryID.AttributeType.Name) {
+			attributes[primaryID.AttributeName] = i + 1
+		}
+	}
+
+	return Vertex{Type: vertexType.Name, ID: id, Attributes: attributes}
+}
+
+// endpointTypes is a pair of vertex types an edge type connects, and how many distinct edges
+// there can be between their generated vertices
+type endpointTypes struct {
+	from  string
+	to    string
+	pairs int
+}
+
+// edges generates count edges of the type between distinct pairs of generated vertices
+func (g *generator) edges(edgeType tigergraph.GraphMetadataEdgeType, count int) ([]Edge, error) {
+	edges := make([]Edge, 0, count)
+	if count == 0 {
+		return edges, nil
+	}
+
+	endpoints, total := g.endpointTypes(edgeType)
+	if total == 0 {
+		return nil, ErrNoEndpoints
+	}
+	if count > total {
+		return nil, fmt.Errorf("%d edges requested between %d vertex pairs: %w", count, total, ErrTooManyEdges)
+	}
+
+	used := make(map[[4]string]bool, count)
+	for len(edges) < count {
+		// Pick a pair of vertex types in proportion to the edges it can hold
+		pick := g.rand.Intn(total)
+		pair := endpoints[0]
+		for _, pair = range endpoints {
+			if pick < pair.pairs {
+				break
+			}
+			pick -= pair.pairs
+		}
+
+		fromIDs, toIDs := g.ids[pair.from], g.ids[pair.to]
+		fromID, toID := fromIDs[g.rand.Intn(len(fromIDs))], toIDs[g.rand.Intn(len(toIDs))]
+
+		key := [4]string{pair.from, fromID, pair.to, toID}
+		if used[key] {
+			continue
+		}
+		used[key] = true
+		if !edgeType.IsDirected {
+			used[[4]string{pair.to, toID, pair.from, fromID}] = true
+		}
+
+		edges = append(edges, Edge{
+			Type:       edgeType.Name,
+			FromType:   pair.from,
+			FromID:     fromID,
+			ToType:     pair.to,
+			ToID:       toID,
+			Attributes: g.attributes(edgeType.Attributes),
+		})
+	}
+
+	return edges, nil
+}
+
+// endpointTypes returns the pairs of vertex types with generated vertices which the edge type
+// connects, and the number of distinct edges there can be between them
+func (g *generator) endpointTypes(edgeType tigergraph.GraphMetadataEdgeType) ([]endpointTypes, int) {
+	pairs := edgeType.EdgePairs
+	if len(pairs) == 0 {
+		pairs = []tigergraph.GraphMetadataEdgePair{{From: edgeType.FromVertexTypeName, To: edgeType.ToVertexTypeName}}
+	}
+
+	endpoints := make([]endpointTypes, 0)
+	seen := make(map[[2]string]bool)
+	total := 0
+	for _, pair := range pairs {
+		for _, from := range g.vertexTypes(pair.From) {
+			for _, to := range g.vertexTypes(pair.To) {
+				if seen[[2]string{from, to}] || (!edgeType.IsDirected && seen[[2]string{to, from}]) {
+					continue
+				}
+				seen[[2]string{from, to}] = true
+
+				count := len(g.ids[from]) * len(g.ids[to])
+				if !edgeType.IsDirected && from == to {
+					// Undirected edges from a vertex to another of its type count once either way
+					count = len(g.ids[from]) * (len(g.ids[from]) + 1) / 2 //nolint:gomnd
+				}
+				if count == 0 {
+					continue
+				}
+
+				endpoints = append(endpoints, endpointTypes{from: from, to: to, pairs: count})
+				total += count
+			}
+		}
+	}
+
+	return endpoints, total
+}
+
+// vertexTypes returns the vertex type, or every vertex type generated for AnyVertexType
+func (g *generator) vertexTypes(vertexType string) []string {
+	if vertexType != AnyVertexType {
+		return []string{vertexType}
+	}
+
+	types := make([]string, 0, len(g.ids))
+	for generated := range g.ids {
+		types = append(types, generated)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// attributes generates a value for each attribute of a supported type
+func (g *generator) attributes(attributes []tigergraph.GraphMetadataAttribute) map[string]any {
+	values := make(map[string]any, len(attributes))
+	for _, attribute := range attributes {
+		if value, ok := g.value(attribute.AttributeType.Name); ok {
+			values[attribute.AttributeName] = value
+		}
+	}
+
+	return values
+}
+
+// value generates a random value of the TigerGraph type, or reports false for types which are
+// not generated
+func (g *generator) value(typeName string) (any, bool) {
+	switch typeName {
+	case "INT":
+		return g.rand.Intn(2_000_001) - 1_000_000, true
+	case "UINT":
+		return g.rand.Intn(1_000_001), true
+	case "FLOAT", "DOUBLE":
+		return g.rand.Float64() * 1000, true
+	case "BOOL":
+		return g.rand.Intn(2) == 1, true
+	case "STRING", "STRING COMPRESS":
+		return g.word(), true
+	case "DATETIME":
+		seconds := g.rand.Int63n(lastDatetime.Unix() - firstDatetime.Unix())
+		return firstDatetime.Add(time.Duration(seconds) * time.Second), true
+	default:
+		return nil, false
+	}
+}
+
+// word generates a random lower case word
+func (g *generator) word() string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+
+	word := make([]byte, 4+g.rand.Intn(8))
+	for i := range word {
+		word[i] = letters[g.rand.Intn(len(letters))]
+	}
+
+	return string(word)
+}
+
+func isInteger(typeName string) bool {
+	return typeName == "INT" || typeName == "UINT"
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2023 Adarga Limited
+
+Licensed under the Apache License, Version 2.0 (the "License"). You may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at:
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+package tigergraphtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adarga-ai/go-tigergraph/tigergraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSchema() *tigergraph.GraphMetadataResponseResult {
+	attribute := func(name string, typeName string) tigergraph.GraphMetadataAttribute {
+		return tigergraph.GraphMetadataAttribute{
+			AttributeName: name,
+			AttributeType: tigergraph.GraphMetadataAttributeType{Name: typeName},
+		}
+	}
+
+	return &tigergraph.GraphMetadataResponseResult{
+		GraphName: "MyGraph",
+		VertexTypes: []tigergraph.GraphMetadataVertexType{
+			{
+				Name: "Person",
+				PrimaryID: tigergraph.GraphMetadataVertexTypePrimaryID{
+					AttributeName:        "name",
+					AttributeType:        tigergraph.GraphMetadataAttributeType{Name: "STRING"},
+					PrimaryIDAsAttribute: true,
+				},
+				Attributes: []tigergraph.GraphMetadataAttribute{
+					attribute("age", "UINT"),
+					attribute("score", "DOUBLE"),
+					attribute("active", "BOOL"),
+					attribute("born", "DATETIME"),
+					attribute("tags", "LIST"),
+				},
+			},
+			{
+				Name: "Company",
+				PrimaryID: tigergraph.GraphMetadataVertexTypePrimaryID{
+					AttributeName: "id",
+					AttributeType: tigergraph.GraphMetadataAttributeType{Name: "INT"},
+				},
+			},
+		},
+		EdgeTypes: []tigergraph.GraphMetadataEdgeType{
+			{
+				Name:               "works_at",
+				IsDirected:         true,
+				FromVertexTypeName: "Person",
+				ToVertexTypeName:   "Company",
+				Attributes:         []tigergraph.GraphMetadataAttribute{attribute("since", "DATETIME")},
+			},
+			{
+				Name:               "knows",
+				FromVertexTypeName: "Person",
+				ToVertexTypeName:   "Person",
+			},
+			{
+				Name:               "related_to",
+				IsDirected:         true,
+				FromVertexTypeName: AnyVertexType,
+				ToVertexTypeName:   AnyVertexType,
+			},
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) { //nolint:funlen
+	counts := map[string]int{"Person": 5, "Company": 2, "works_at": 6, "knows": 15, "related_to": 10}
+
+	t.Run("generates vertices with unique IDs of their primary ID's type", func(t *testing.T) {
+		dataset, err := Generate(testSchema(), counts, WithSeed(1))
+		assert.Nil(t, err)
+		assert.Len(t, dataset.Vertices, 7)
+
+		ids := make(map[string]bool)
+		for _, vertex := range dataset.Vertices {
+			assert.False(t, ids[vertex.Type+vertex.ID])
+			ids[vertex.Type+vertex.ID] = true
+		}
+
+		person := dataset.Vertices[0]
+		assert.Equal(t, "Person", person.Type)
+		assert.Equal(t, "Person_1", person.ID)
+		assert.Equal(t, "Person_1", person.Attributes["name"])
+		assert.IsType(t, 0, person.Attributes["age"])
+		assert.GreaterOrEqual(t, person.Attributes["age"], 0)
+		assert.IsType(t, float64(0), person.Attributes["score"])
+		assert.IsType(t, false, person.Attributes["active"])
+		assert.IsType(t, time.Time{}, person.Attributes["born"])
+		assert.NotContains(t, person.Attributes, "tags")
+
+		company := dataset.Vertices[5]
+		assert.Equal(t, Vertex{Type: "Company", ID: "1", Attributes: map[string]any{}}, company)
+	})
+
+	t.Run("generates distinct edges between generated vertices of the allowed types", func(t *testing.T) {
+		dataset, err := Generate(testSchema(), counts, WithSeed(1))
+		assert.Nil(t, err)
+		assert.Len(t, dataset.Edges, 31)
+
+		vertices := make(map[string]bool)
+		for _, vertex := range dataset.Vertices {
+			vertices[vertex.Type+"/"+vertex.ID] = true
+		}
+
+		connected := make(map[string]bool)
+		for _, edge := range dataset.Edges {
+			assert.True(t, vertices[edge.FromType+"/"+edge.FromID])
+			assert.True(t, vertices[edge.ToType+"/"+edge.ToID])
+
+			key := edge.Type + edge.FromType + edge.FromID + edge.ToType + edge.ToID
+			reverse := edge.Type + edge.ToType + edge.ToID + edge.FromType + edge.FromID
+			assert.False(t, connected[key])
+			connected[key] = true
+			if edge.Type == "knows" {
+				assert.False(t, connected[reverse] && key != reverse)
+			}
+
+			switch edge.Type {
+			case "works_at":
+				assert.Equal(t, "Person", edge.FromType)
+				assert.Equal(t, "Company", edge.ToType)
+				assert.IsType(t, time.Time{}, edge.Attributes["since"])
+			case "knows":
+				assert.Equal(t, "Person", edge.FromType)
+				assert.Equal(t, "Person", edge.ToType)
+			}
+		}
+	})
+
+	t.Run("generates the same data from the same seed", func(t *testing.T) {
+		first, err := Generate(testSchema(), counts, WithSeed(42))
+		assert.Nil(t, err)
+		second, err := Generate(testSchema(), counts, WithSeed(42))
+		assert.Nil(t, err)
+		assert.Equal(t, first, second)
+	})
+
+	errorTests := []struct {
+		name     string
+		counts   map[string]int
+		expected error
+	}{
+		{name: "unknown types", counts: map[string]int{"Animal": 1}, expected: ErrUnknownType},
+		{name: "edges without vertices", counts: map[string]int{"Person": 1, "works_at": 1}, expected: ErrNoEndpoints},
+		{
+			name:     "more edges than vertex pairs",
+			counts:   map[string]int{"Person": 2, "Company": 1, "works_at": 3},
+			expected: ErrTooManyEdges,
+		},
+		{
+			// Two people can only know each other, or themselves, in three ways
+			name:     "more undirected edges than vertex pairs",
+			counts:   map[string]int{"Person": 2, "knows": 4},
+			expected: ErrTooManyEdges,
+		},
+	}
+
+	for _, test := range errorTests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Generate(testSchema(), test.counts)
+			assert.ErrorIs(t, err, test.expected)
+		})
+	}
+}
+
+func TestDatasetLines(t *testing.T) {
+	since := time.Date(2023, 6, 1, 9, 0, 0, 0, time.UTC)
+	dataset := &Dataset{
+		Vertices: []Vertex{
+			{Type: "Person", ID: "Person_1", Attributes: map[string]any{"age": 30}},
+			{Type: "Company", ID: "1", Attributes: map[string]any{}},
+		},
+		Edges: []Edge{
+			{
+				Type:       "works_at",
+				FromType:   "Person",
+				FromID:     "Person_1",
+				ToType:     "Company",
+				ToID:       "1",
+				Attributes: map[string]any{"since": since},
+			},
+		},
+	}
+
+	assert.Equal(t, []any{map[string]any{"id": "Person_1", "age": 30}}, dataset.VertexLines("Person"))
+	assert.Equal(t, []any{
+		map[string]any{
+			"from":      "Person_1",
+			"from_type": "Person",
+			"to":        "1",
+			"to_type":   "Company",
+			"since":     "2023-06-01 09:00:00",
+		},
+	}, dataset.EdgeLines("works_at"))
+	assert.Equal(t, []any{}, dataset.EdgeLines("knows"))
+}